@@ -9,6 +9,8 @@ import (
 
 	"github.com/filanov/netctrl-server/internal/config"
 	"github.com/filanov/netctrl-server/internal/server"
+	"github.com/filanov/netctrl-server/internal/storage"
+	"github.com/filanov/netctrl-server/internal/storage/hybrid"
 	"github.com/filanov/netctrl-server/internal/storage/postgres"
 )
 
@@ -28,9 +30,19 @@ func main() {
 	}
 
 	pgCfg := postgres.Config{
-		URL:            cfg.Database.URL,
-		MaxConnections: cfg.Database.MaxConnections,
-		MinConnections: cfg.Database.MinConnections,
+		URL:             cfg.Database.URL,
+		MaxConnections:  cfg.Database.MaxConnections,
+		MinConnections:  cfg.Database.MinConnections,
+		MaxConnIdleTime: cfg.Database.MaxConnIdleTime,
+		MaxConnLifetime: cfg.Database.MaxConnLifetime,
+		ConnectTimeout:  cfg.Database.ConnectTimeout,
+		WarmUp:          cfg.Database.WarmUp,
+
+		// The hybrid store serves from memory until the database is up
+		AllowUnavailable: cfg.Database.Hybrid,
+
+		MaxDescriptionBytes:       cfg.Database.MaxDescriptionBytes,
+		MaxNetworkInterfacesBytes: cfg.Agent.MaxNetworkInterfacesBytes,
 	}
 	store, err := postgres.New(ctx, pgCfg)
 	if err != nil {
@@ -39,8 +51,19 @@ func main() {
 
 	log.Println("PostgreSQL storage initialized")
 
+	var serverStore storage.Storage = store
+	var hybridStore *hybrid.Storage
+	if cfg.Database.Hybrid {
+		hybridStore = hybrid.New(ctx, store)
+		syncCtx, syncCancel := context.WithCancel(ctx)
+		defer syncCancel()
+		go hybridStore.Start(syncCtx, cfg.Database.SyncInterval)
+		serverStore = hybridStore
+		log.Printf("Hybrid storage enabled (sync interval: %v)", cfg.Database.SyncInterval)
+	}
+
 	// Create server
-	srv := server.New(cfg, store)
+	srv := server.New(cfg, serverStore)
 
 	// Setup signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -59,6 +82,11 @@ func main() {
 	case sig := <-sigChan:
 		log.Printf("Received signal: %v", sig)
 		srv.Stop()
+		if hybridStore != nil {
+			if err := hybridStore.Flush(ctx); err != nil {
+				log.Printf("Failed to flush hybrid storage: %v", err)
+			}
+		}
 		store.Close()
 	case err := <-errChan:
 		log.Fatalf("Server error: %v", err)
@@ -2,48 +2,222 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/filanov/netctrl-server/internal/service"
 )
 
 // Config represents the application configuration
 type Config struct {
-	Logging  LoggingConfig  `yaml:"logging"`
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	GRPC     GRPCConfig     `yaml:"grpc"`
-	Gateway  GatewayConfig  `yaml:"gateway"`
+	Logging    LoggingConfig    `yaml:"logging"`
+	Server     ServerConfig     `yaml:"server"`
+	Database   DatabaseConfig   `yaml:"database"`
+	GRPC       GRPCConfig       `yaml:"grpc"`
+	Gateway    GatewayConfig    `yaml:"gateway"`
+	Agent      AgentConfig      `yaml:"agent"`
+	Cluster    ClusterConfig    `yaml:"cluster"`
+	Events     EventsConfig     `yaml:"events"`
+	Validation ValidationConfig `yaml:"validation"`
 }
 
 // ServerConfig contains general server configuration
 type ServerConfig struct {
 	Environment string `yaml:"environment"`
+
+	// ShutdownDrain is how long the server reports NOT_SERVING before closing
+	// its listeners, giving load balancers time to stop routing to it
+	ShutdownDrain time.Duration `yaml:"shutdown_drain"`
+
+	// ShutdownTimeout bounds how long shutdown waits for instruction results
+	// being processed to be written
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
 }
 
 // GRPCConfig contains gRPC server configuration
 type GRPCConfig struct {
-	EnableReflection bool `yaml:"enable_reflection"`
-	Port             int  `yaml:"port"`
+	EnableReflection bool          `yaml:"enable_reflection"`
+	BindAddress      string        `yaml:"bind_address"`
+	Port             int           `yaml:"port"`
+	IdempotencyTTL   time.Duration `yaml:"idempotency_ttl"`
+
+	// DefaultTimeout bounds unary RPCs without an entry in MethodTimeouts;
+	// 0 leaves them unbounded
+	DefaultTimeout time.Duration `yaml:"default_timeout"`
+
+	// MethodTimeouts bounds individual unary RPCs, keyed by bare ("GetAgent")
+	// or full ("/netctrl.v1.AgentService/GetAgent") method name
+	MethodTimeouts map[string]time.Duration `yaml:"method_timeouts"`
 }
 
 // GatewayConfig contains HTTP gateway configuration
 type GatewayConfig struct {
-	EnableCORS bool `yaml:"enable_cors"`
-	Port       int  `yaml:"port"`
+	EnableCORS  bool             `yaml:"enable_cors"`
+	BindAddress string           `yaml:"bind_address"`
+	Port        int              `yaml:"port"`
+	TLS         GatewayTLSConfig `yaml:"tls"`
+
+	// RequestTimeout bounds requests that set neither Grpc-Timeout nor X-Request-Timeout
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+
+	// PathPrefix mounts all gateway routes, health endpoints included, under
+	// a path such as "/api/netctrl" for serving behind a reverse proxy
+	PathPrefix string `yaml:"path_prefix"`
+}
+
+// GatewayTLSConfig contains HTTPS configuration for the HTTP gateway
+type GatewayTLSConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+
+	// MinVersion is the minimum accepted TLS version ("1.2" or "1.3")
+	MinVersion string `yaml:"min_version"`
+
+	// RedirectPort serves plain HTTP redirects to HTTPS when set
+	RedirectPort int `yaml:"redirect_port"`
+}
+
+// NetworkTemplateConfig is a cluster network config template; an empty
+// template is not applied
+type NetworkTemplateConfig struct {
+	CIDR    string `yaml:"cidr"`
+	Gateway string `yaml:"gateway"`
+}
+
+// ClusterConfig contains cluster management configuration
+type ClusterConfig struct {
+	// IDScheme selects how new cluster IDs are generated: "uuid" or "ulid".
+	// IDPrefix is put before each ULID, e.g. "cl-"; UUIDs take no prefix.
+	IDScheme string `yaml:"id_scheme"`
+	IDPrefix string `yaml:"id_prefix"`
+}
+
+// AgentConfig contains agent management configuration
+type AgentConfig struct {
+	// DefaultClusterID receives agents registering without a cluster ID; the
+	// cluster is created on startup if missing. Empty rejects such agents.
+	DefaultClusterID string `yaml:"default_cluster_id"`
+
+	// DefaultNetworkConfig is given to the auto-created default cluster and,
+	// in development, to clusters created without a network config
+	DefaultNetworkConfig NetworkTemplateConfig `yaml:"default_network_config"`
+
+	// StrictIPUniqueness rejects agents reporting an IP already used in their cluster
+	StrictIPUniqueness bool `yaml:"strict_ip_uniqueness"`
+
+	// GatewayProbeInterval is how often agents probe their cluster gateway
+	GatewayProbeInterval time.Duration `yaml:"gateway_probe_interval"`
+
+	// HealthCheckInterval is how often spine agents report health
+	HealthCheckInterval time.Duration `yaml:"health_check_interval"`
+
+	// MaxNICs and MaxNetworkInterfacesBytes bound the hardware data an agent may report
+	MaxNICs                   int `yaml:"max_nics"`
+	MaxNetworkInterfacesBytes int `yaml:"max_network_interfaces_bytes"`
+
+	// MaxClockSkew bounds how far agent-supplied timestamps may differ from server time
+	MaxClockSkew time.Duration `yaml:"max_clock_skew"`
+
+	// MaxTotalAgents caps the number of agents across all clusters; 0 means unlimited
+	MaxTotalAgents int `yaml:"max_total_agents"`
+
+	// ClusterChangePolicy decides what happens when a registered agent
+	// re-registers into a different cluster: "allow" moves it, "reject"
+	// refuses, "require_reassign" refuses and asks for it to be unregistered first
+	ClusterChangePolicy string `yaml:"cluster_change_policy"`
+
+	// CIDRChangePolicy decides what happens when a cluster's CIDR changes so
+	// that registered agents' IPs fall outside it: "warn" applies the change
+	// and reports the agents, "reject" refuses it
+	CIDRChangePolicy string `yaml:"cidr_change_policy"`
+
+	// UnknownResultPolicy decides what happens to results of instruction types
+	// the server does not recognize: "ignore", "reject" or "store_raw"
+	UnknownResultPolicy string `yaml:"unknown_result_policy"`
+
+	// MaxActivitySubscribers caps concurrent TailAgentActivity streams; 0
+	// means unlimited. ActivitySubscriberPolicy decides what happens beyond
+	// the cap: "reject" refuses the new stream, "evict_slowest" ends the one
+	// furthest behind.
+	MaxActivitySubscribers   int    `yaml:"max_activity_subscribers"`
+	ActivitySubscriberPolicy string `yaml:"activity_subscriber_policy"`
+
+	// FleetReportSampleSize caps the agent IDs sampled per fleet report category
+	FleetReportSampleSize int `yaml:"fleet_report_sample_size"`
+
+	// Silence after which the monitor escalates an agent to inactive, then
+	// stale, then expired (eligible for cleanup)
+	InactiveThreshold time.Duration `yaml:"inactive_threshold"`
+	StaleThreshold    time.Duration `yaml:"stale_threshold"`
+	ExpiredThreshold  time.Duration `yaml:"expired_threshold"`
+
+	// MonitorUpdateWorkers bounds the concurrent status updates of a monitor cycle
+	MonitorUpdateWorkers int `yaml:"monitor_update_workers"`
+}
+
+// ValidationConfig contains request validation configuration
+type ValidationConfig struct {
+	// Strict rejects requests failing the relaxable checks with
+	// InvalidArgument; off, they are accepted and a warning is logged. The
+	// relaxable checks are an agent registering with an IP address that does
+	// not parse, and a cluster created or imported without a network config.
+	Strict bool `yaml:"strict"`
+}
+
+// EventsConfig contains agent lifecycle event publishing configuration
+type EventsConfig struct {
+	// Publisher selects where agent register, unregister and status change
+	// events go: "none", "log" or "webhook"
+	Publisher string `yaml:"publisher"`
+
+	// WebhookURL receives each event as a JSON POST when publisher is "webhook"
+	WebhookURL string `yaml:"webhook_url"`
+
+	// BufferSize bounds the events waiting to be published; events emitted
+	// while it is full are dropped
+	BufferSize int `yaml:"buffer_size"`
+
+	// PublishTimeout bounds publishing a single event
+	PublishTimeout time.Duration `yaml:"publish_timeout"`
 }
 
 // DatabaseConfig contains PostgreSQL database configuration
 type DatabaseConfig struct {
-	URL            string `yaml:"url"`
-	MinConnections int32  `yaml:"min_connections"`
-	MaxConnections int32  `yaml:"max_connections"`
+	URL             string `yaml:"url"`
+	MinConnections  int32  `yaml:"min_connections"`
+	MaxConnections  int32  `yaml:"max_connections"`
+	MaxConnIdleTime string `yaml:"max_conn_idle_time"`
+	MaxConnLifetime string `yaml:"max_conn_lifetime"`
+	ConnectTimeout  string `yaml:"connect_timeout"`
+
+	// WarmUp opens min_connections connections at startup so the first burst
+	// of load does not wait on connection setup
+	WarmUp bool `yaml:"warm_up"`
+
+	// Hybrid serves from memory and syncs writes to PostgreSQL, buffering
+	// them while the database is unreachable
+	Hybrid       bool          `yaml:"hybrid"`
+	SyncInterval time.Duration `yaml:"sync_interval"`
+
+	// ClusterCacheTTL caches cluster existence checks for this long; 0 disables the cache
+	ClusterCacheTTL time.Duration `yaml:"cluster_cache_ttl"`
+
+	// MaxDescriptionBytes bounds cluster descriptions written to the database
+	MaxDescriptionBytes int `yaml:"max_description_bytes"`
 }
 
 // LoggingConfig contains logging configuration
 type LoggingConfig struct {
 	Level  string `yaml:"level"`
 	Format string `yaml:"format"`
+
+	// LogRequests logs every unary RPC with the cluster it concerns
+	LogRequests bool `yaml:"log_requests"`
 }
 
 // Load reads configuration from a YAML file
@@ -61,6 +235,10 @@ func Load(path string) (*Config, error) {
 	// Apply defaults
 	applyDefaults(config)
 
+	if err := validate(config); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
@@ -80,6 +258,9 @@ func applyDefaults(config *Config) {
 	if config.Server.Environment == "" {
 		config.Server.Environment = "development"
 	}
+	if config.Server.ShutdownTimeout == 0 {
+		config.Server.ShutdownTimeout = 30 * time.Second
+	}
 
 	if config.GRPC.Port == 0 {
 		config.GRPC.Port = 9090
@@ -87,6 +268,9 @@ func applyDefaults(config *Config) {
 	if !config.GRPC.EnableReflection {
 		config.GRPC.EnableReflection = true
 	}
+	if config.GRPC.IdempotencyTTL == 0 {
+		config.GRPC.IdempotencyTTL = 10 * time.Minute
+	}
 
 	if config.Gateway.Port == 0 {
 		config.Gateway.Port = 8080
@@ -94,6 +278,56 @@ func applyDefaults(config *Config) {
 	if !config.Gateway.EnableCORS {
 		config.Gateway.EnableCORS = true
 	}
+	if config.Gateway.RequestTimeout == 0 {
+		config.Gateway.RequestTimeout = 30 * time.Second
+	}
+	config.Gateway.PathPrefix = strings.TrimRight(config.Gateway.PathPrefix, "/")
+	if config.Gateway.TLS.MinVersion == "" {
+		config.Gateway.TLS.MinVersion = "1.2"
+	}
+
+	if config.Agent.GatewayProbeInterval == 0 {
+		config.Agent.GatewayProbeInterval = 5 * time.Minute
+	}
+	if config.Agent.HealthCheckInterval == 0 {
+		config.Agent.HealthCheckInterval = 5 * time.Minute
+	}
+	if config.Agent.MaxNICs == 0 {
+		config.Agent.MaxNICs = 64
+	}
+	if config.Agent.MaxNetworkInterfacesBytes == 0 {
+		config.Agent.MaxNetworkInterfacesBytes = 1 << 20
+	}
+	if config.Agent.MaxClockSkew == 0 {
+		config.Agent.MaxClockSkew = 5 * time.Minute
+	}
+	if config.Agent.ClusterChangePolicy == "" {
+		config.Agent.ClusterChangePolicy = "allow"
+	}
+	if config.Agent.CIDRChangePolicy == "" {
+		config.Agent.CIDRChangePolicy = "warn"
+	}
+	if config.Agent.UnknownResultPolicy == "" {
+		config.Agent.UnknownResultPolicy = "ignore"
+	}
+	if config.Agent.ActivitySubscriberPolicy == "" {
+		config.Agent.ActivitySubscriberPolicy = "reject"
+	}
+	if config.Agent.FleetReportSampleSize == 0 {
+		config.Agent.FleetReportSampleSize = 10
+	}
+	if config.Agent.InactiveThreshold == 0 {
+		config.Agent.InactiveThreshold = 3 * time.Minute
+	}
+	if config.Agent.StaleThreshold == 0 {
+		config.Agent.StaleThreshold = time.Hour
+	}
+	if config.Agent.ExpiredThreshold == 0 {
+		config.Agent.ExpiredThreshold = 24 * time.Hour
+	}
+	if config.Agent.MonitorUpdateWorkers == 0 {
+		config.Agent.MonitorUpdateWorkers = 8
+	}
 
 	// Database configuration with environment variable override
 	if dbURL := os.Getenv("DATABASE_URL"); dbURL != "" {
@@ -105,6 +339,25 @@ func applyDefaults(config *Config) {
 	if config.Database.MinConnections == 0 {
 		config.Database.MinConnections = 20
 	}
+	if config.Database.SyncInterval == 0 {
+		config.Database.SyncInterval = 30 * time.Second
+	}
+	if config.Database.MaxDescriptionBytes == 0 {
+		config.Database.MaxDescriptionBytes = 4 << 10
+	}
+
+	if config.Cluster.IDScheme == "" {
+		config.Cluster.IDScheme = service.IDSchemeUUID
+	}
+	if config.Events.Publisher == "" {
+		config.Events.Publisher = "none"
+	}
+	if config.Events.BufferSize == 0 {
+		config.Events.BufferSize = 1024
+	}
+	if config.Events.PublishTimeout == 0 {
+		config.Events.PublishTimeout = 10 * time.Second
+	}
 
 	if config.Logging.Level == "" {
 		config.Logging.Level = "info"
@@ -113,3 +366,126 @@ func applyDefaults(config *Config) {
 		config.Logging.Format = "text"
 	}
 }
+
+// validate rejects configuration values that have no meaning
+func validate(config *Config) error {
+	switch config.Agent.ClusterChangePolicy {
+	case "allow", "reject", "require_reassign":
+	default:
+		return fmt.Errorf("invalid agent cluster_change_policy %q (expected allow, reject or require_reassign)", config.Agent.ClusterChangePolicy)
+	}
+	switch config.Agent.CIDRChangePolicy {
+	case "warn", "reject":
+	default:
+		return fmt.Errorf("invalid agent cidr_change_policy %q (expected warn or reject)", config.Agent.CIDRChangePolicy)
+	}
+	switch config.Agent.UnknownResultPolicy {
+	case "ignore", "reject", "store_raw":
+	default:
+		return fmt.Errorf("invalid agent unknown_result_policy %q (expected ignore, reject or store_raw)", config.Agent.UnknownResultPolicy)
+	}
+	switch config.Agent.ActivitySubscriberPolicy {
+	case "reject", "evict_slowest":
+	default:
+		return fmt.Errorf("invalid agent activity_subscriber_policy %q (expected reject or evict_slowest)", config.Agent.ActivitySubscriberPolicy)
+	}
+	if config.Agent.MaxActivitySubscribers < 0 {
+		return fmt.Errorf("invalid agent max_activity_subscribers %d (must not be negative)", config.Agent.MaxActivitySubscribers)
+	}
+	if config.Agent.FleetReportSampleSize < 0 {
+		return fmt.Errorf("invalid agent fleet_report_sample_size %d (must not be negative)", config.Agent.FleetReportSampleSize)
+	}
+	if prefix := config.Gateway.PathPrefix; prefix != "" && !strings.HasPrefix(prefix, "/") {
+		return fmt.Errorf("invalid gateway path_prefix %q (must start with /)", prefix)
+	}
+	if config.GRPC.DefaultTimeout < 0 {
+		return fmt.Errorf("invalid grpc default_timeout %v (must not be negative)", config.GRPC.DefaultTimeout)
+	}
+	for method, timeout := range config.GRPC.MethodTimeouts {
+		if timeout <= 0 {
+			return fmt.Errorf("invalid grpc method_timeouts entry %s: %v (must be positive)", method, timeout)
+		}
+	}
+	if _, err := service.NewIDGenerator(config.Cluster.IDScheme, config.Cluster.IDPrefix); err != nil {
+		return fmt.Errorf("invalid cluster id_scheme or id_prefix: %w", err)
+	}
+	if err := validateEvents(&config.Events); err != nil {
+		return err
+	}
+	if err := validateListeners(config); err != nil {
+		return err
+	}
+	return validateThresholds(&config.Agent)
+}
+
+// validateEvents rejects unknown publishers and webhook publishing without
+// an HTTP(S) URL to post to
+func validateEvents(events *EventsConfig) error {
+	switch events.Publisher {
+	case "none", "log":
+	case "webhook":
+		target, err := url.Parse(events.WebhookURL)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return fmt.Errorf("invalid events webhook_url %q (expected an http or https URL)", events.WebhookURL)
+		}
+	default:
+		return fmt.Errorf("invalid events publisher %q (expected none, log or webhook)", events.Publisher)
+	}
+	if events.BufferSize < 0 {
+		return fmt.Errorf("invalid events buffer_size %d (must not be negative)", events.BufferSize)
+	}
+	if events.PublishTimeout < 0 {
+		return fmt.Errorf("invalid events publish_timeout %v (must not be negative)", events.PublishTimeout)
+	}
+	return nil
+}
+
+// listener is a port the server binds, for detecting collisions
+type listener struct {
+	name        string
+	bindAddress string
+	port        int
+}
+
+// validateListeners rejects listeners that would bind the same port on
+// overlapping addresses, which otherwise fails at bind time with a less
+// obvious error. An empty bind address covers all interfaces.
+func validateListeners(config *Config) error {
+	listeners := []listener{
+		{"grpc port", config.GRPC.BindAddress, config.GRPC.Port},
+		{"gateway port", config.Gateway.BindAddress, config.Gateway.Port},
+	}
+	if config.Gateway.TLS.Enabled && config.Gateway.TLS.RedirectPort != 0 {
+		listeners = append(listeners, listener{"gateway tls redirect_port", config.Gateway.BindAddress, config.Gateway.TLS.RedirectPort})
+	}
+
+	for i, a := range listeners {
+		for _, b := range listeners[i+1:] {
+			overlapping := a.bindAddress == "" || b.bindAddress == "" || a.bindAddress == b.bindAddress
+			if a.port == b.port && overlapping {
+				return fmt.Errorf("%s and %s are both %d (must be distinct)", a.name, b.name, a.port)
+			}
+		}
+	}
+	return nil
+}
+
+// validateThresholds rejects escalation thresholds that would mark polling
+// agents inactive between polls or escalate them out of order
+func validateThresholds(agent *AgentConfig) error {
+	// An agent must be able to miss a poll, and the monitor a check, before
+	// being marked inactive
+	pollInterval := time.Duration(service.PollIntervalSeconds) * time.Second
+	minInactive := 2 * max(pollInterval, service.MonitorCheckInterval)
+	if agent.InactiveThreshold < minInactive {
+		return fmt.Errorf("agent inactive_threshold %v is too short: it must be at least %v (twice the longer of the %v poll interval and the %v monitor check interval) or polling agents are marked inactive",
+			agent.InactiveThreshold, minInactive, pollInterval, service.MonitorCheckInterval)
+	}
+	if agent.StaleThreshold <= agent.InactiveThreshold {
+		return fmt.Errorf("agent stale_threshold %v must exceed inactive_threshold %v", agent.StaleThreshold, agent.InactiveThreshold)
+	}
+	if agent.ExpiredThreshold <= agent.StaleThreshold {
+		return fmt.Errorf("agent expired_threshold %v must exceed stale_threshold %v", agent.ExpiredThreshold, agent.StaleThreshold)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/filanov/netctrl-server/internal/config"
+)
+
+var _ = Describe("Load", func() {
+	// load writes the YAML to a temporary file and loads it
+	load := func(yaml string) (*config.Config, error) {
+		path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		Expect(os.WriteFile(path, []byte(yaml), 0o600)).To(Succeed())
+		return config.Load(path)
+	}
+
+	It("should accept the default thresholds", func() {
+		cfg, err := load("agent: {}\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Agent.InactiveThreshold).To(Equal(3 * time.Minute))
+	})
+
+	It("should reject an inactive threshold shorter than two poll intervals", func() {
+		_, err := load("agent:\n  inactive_threshold: 90s\n")
+		Expect(err).To(MatchError(ContainSubstring("inactive_threshold 1m30s is too short")))
+	})
+
+	It("should reject thresholds that do not escalate in order", func() {
+		_, err := load("agent:\n  inactive_threshold: 2h\n")
+		Expect(err).To(MatchError(ContainSubstring("stale_threshold 1h0m0s must exceed inactive_threshold 2h0m0s")))
+
+		_, err = load("agent:\n  stale_threshold: 48h\n")
+		Expect(err).To(MatchError(ContainSubstring("expired_threshold 24h0m0s must exceed stale_threshold 48h0m0s")))
+	})
+
+	It("should accept the require_reassign cluster change policy", func() {
+		cfg, err := load("agent:\n  cluster_change_policy: require_reassign\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Agent.ClusterChangePolicy).To(Equal("require_reassign"))
+	})
+
+	It("should reject an unknown cluster change policy", func() {
+		_, err := load("agent:\n  cluster_change_policy: sometimes\n")
+		Expect(err).To(MatchError(ContainSubstring("cluster_change_policy")))
+	})
+
+	It("should default to UUID cluster IDs", func() {
+		cfg, err := load("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Cluster.IDScheme).To(Equal("uuid"))
+	})
+
+	It("should accept prefixed ULID cluster IDs", func() {
+		cfg, err := load("cluster:\n  id_scheme: ulid\n  id_prefix: cl-\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Cluster.IDPrefix).To(Equal("cl-"))
+	})
+
+	It("should reject a prefix on UUID cluster IDs", func() {
+		_, err := load("cluster:\n  id_prefix: cl-\n")
+		Expect(err).To(MatchError(ContainSubstring("id_prefix")))
+	})
+
+	It("should load per-method timeouts", func() {
+		cfg, err := load("grpc:\n  method_timeouts:\n    GetAgent: 500ms\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.GRPC.MethodTimeouts).To(HaveKeyWithValue("GetAgent", 500*time.Millisecond))
+	})
+
+	It("should reject a non-positive method timeout", func() {
+		_, err := load("grpc:\n  method_timeouts:\n    GetAgent: 0s\n")
+		Expect(err).To(MatchError(ContainSubstring("method_timeouts")))
+	})
+
+	It("should reject an unknown CIDR change policy", func() {
+		_, err := load("agent:\n  cidr_change_policy: ignore\n")
+		Expect(err).To(MatchError(ContainSubstring("cidr_change_policy")))
+	})
+
+	It("should reject an unknown result policy", func() {
+		_, err := load("agent:\n  unknown_result_policy: drop\n")
+		Expect(err).To(MatchError(ContainSubstring("unknown_result_policy")))
+	})
+
+	It("should strip a trailing slash from the gateway path prefix", func() {
+		cfg, err := load("gateway:\n  path_prefix: /api/netctrl/\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Gateway.PathPrefix).To(Equal("/api/netctrl"))
+	})
+
+	It("should reject a relative gateway path prefix", func() {
+		_, err := load("gateway:\n  path_prefix: api/netctrl\n")
+		Expect(err).To(MatchError(ContainSubstring("path_prefix")))
+	})
+
+	It("should reject gRPC and gateway ports that collide", func() {
+		_, err := load("grpc:\n  port: 8000\ngateway:\n  port: 8000\n")
+		Expect(err).To(MatchError(ContainSubstring("grpc port and gateway port are both 8000")))
+	})
+
+	It("should reject a redirect port that collides with the gateway port", func() {
+		_, err := load("gateway:\n  port: 8443\n  tls:\n    enabled: true\n    redirect_port: 8443\n")
+		Expect(err).To(MatchError(ContainSubstring("redirect_port")))
+	})
+
+	It("should accept the same port on distinct bind addresses", func() {
+		_, err := load("grpc:\n  bind_address: 127.0.0.1\n  port: 8000\ngateway:\n  bind_address: 10.0.0.1\n  port: 8000\n")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject an unknown activity subscriber policy", func() {
+		_, err := load("agent:\n  activity_subscriber_policy: drop_oldest\n")
+		Expect(err).To(MatchError(ContainSubstring("activity_subscriber_policy")))
+	})
+
+	It("should validate leniently unless strict validation is enabled", func() {
+		cfg, err := load("agent: {}\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validation.Strict).To(BeFalse())
+
+		cfg, err = load("validation:\n  strict: true\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Validation.Strict).To(BeTrue())
+	})
+
+	It("should not publish lifecycle events by default", func() {
+		cfg, err := load("events: {}\n")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Events.Publisher).To(Equal("none"))
+		Expect(cfg.Events.BufferSize).To(Equal(1024))
+	})
+
+	It("should reject an unknown event publisher", func() {
+		_, err := load("events:\n  publisher: kafka\n")
+		Expect(err).To(MatchError(ContainSubstring("events publisher")))
+	})
+
+	It("should require an HTTP URL for webhook event publishing", func() {
+		_, err := load("events:\n  publisher: webhook\n")
+		Expect(err).To(MatchError(ContainSubstring("webhook_url")))
+
+		_, err = load("events:\n  publisher: webhook\n  webhook_url: ftp://events.example.com\n")
+		Expect(err).To(MatchError(ContainSubstring("webhook_url")))
+
+		_, err = load("events:\n  publisher: webhook\n  webhook_url: https://events.example.com/netctrl\n")
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
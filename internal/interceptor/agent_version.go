@@ -0,0 +1,100 @@
+package interceptor
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AgentVersionHeader is the metadata key agents identify their version with
+const AgentVersionHeader = "agent-version"
+
+// MaxLoggedAgentVersions bounds how many distinct versions are remembered for
+// logging, so clients sending arbitrary values cannot grow memory
+const MaxLoggedAgentVersions = 256
+
+// AgentVersion rejects calls to agent RPCs that do not carry the agent
+// version header and logs each version the first time it is seen
+type AgentVersion struct {
+	methods map[string]bool
+
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// NewAgentVersion creates an agent version check for the given full method
+// names; other methods are not checked
+func NewAgentVersion(methods ...string) *AgentVersion {
+	m := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		m[method] = true
+	}
+	return &AgentVersion{
+		methods: m,
+		seen:    make(map[string]bool),
+	}
+}
+
+// UnaryServerInterceptor returns an interceptor enforcing the agent version
+// header on unary agent RPCs
+func (a *AgentVersion) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.check(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor enforcing the agent version
+// header on streaming agent RPCs
+func (a *AgentVersion) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.check(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// check verifies that calls to checked methods carry a version
+func (a *AgentVersion) check(ctx context.Context, method string) error {
+	if !a.methods[method] {
+		return nil
+	}
+
+	version := agentVersion(ctx)
+	if version == "" {
+		return status.Errorf(codes.InvalidArgument, "%s metadata is required", AgentVersionHeader)
+	}
+
+	a.mu.Lock()
+	first := !a.seen[version] && len(a.seen) < MaxLoggedAgentVersions
+	if first {
+		a.seen[version] = true
+	}
+	a.mu.Unlock()
+
+	if first {
+		log.Printf("First call from agent version %s (%s)", version, method)
+	}
+	return nil
+}
+
+// agentVersion extracts the agent version from incoming metadata
+func agentVersion(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(AgentVersionHeader)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,86 @@
+package interceptor_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/filanov/netctrl-server/internal/interceptor"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// contextStream is a server stream carrying a fixed context
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+var _ = Describe("AgentVersion", func() {
+	var (
+		agentVersion *interceptor.AgentVersion
+		calls        int
+		handler      grpc.UnaryHandler
+		versioned    context.Context
+	)
+
+	BeforeEach(func() {
+		agentVersion = interceptor.NewAgentVersion(
+			v1.AgentService_GetInstructions_FullMethodName,
+			v1.AgentService_SubmitInstructionResultStream_FullMethodName,
+		)
+		calls = 0
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			calls++
+			return &v1.GetInstructionsResponse{}, nil
+		}
+		versioned = metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(interceptor.AgentVersionHeader, "1.4.2"))
+	})
+
+	// unary calls the unary interceptor for a method
+	unary := func(ctx context.Context, method string) error {
+		_, err := agentVersion.UnaryServerInterceptor()(ctx, &v1.GetInstructionsRequest{}, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		return err
+	}
+
+	It("should reject agent calls without the version header", func() {
+		err := unary(context.Background(), v1.AgentService_GetInstructions_FullMethodName)
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(err.Error()).To(ContainSubstring(interceptor.AgentVersionHeader))
+		Expect(calls).To(BeZero())
+	})
+
+	It("should pass agent calls carrying the version header", func() {
+		Expect(unary(versioned, v1.AgentService_GetInstructions_FullMethodName)).To(Succeed())
+		Expect(calls).To(Equal(1))
+	})
+
+	It("should not check other methods", func() {
+		Expect(unary(context.Background(), v1.AgentService_ListAgents_FullMethodName)).To(Succeed())
+		Expect(unary(context.Background(), v1.ClusterService_ListClusters_FullMethodName)).To(Succeed())
+		Expect(calls).To(Equal(2))
+	})
+
+	It("should check streaming agent calls", func() {
+		streamHandler := func(srv interface{}, ss grpc.ServerStream) error {
+			calls++
+			return nil
+		}
+		stream := agentVersion.StreamServerInterceptor()
+		info := &grpc.StreamServerInfo{FullMethod: v1.AgentService_SubmitInstructionResultStream_FullMethodName, IsClientStream: true}
+
+		err := stream(nil, &contextStream{ctx: context.Background()}, info, streamHandler)
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(stream(nil, &contextStream{ctx: versioned}, info, streamHandler)).To(Succeed())
+		Expect(calls).To(Equal(1))
+	})
+})
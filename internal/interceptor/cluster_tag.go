@@ -0,0 +1,83 @@
+package interceptor
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// clusterIDKey is the context key of the cluster a call concerns
+type clusterIDKey struct{}
+
+// ClusterIDFromContext returns the cluster the current call concerns, or ""
+// if the call is not scoped to a cluster
+func ClusterIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(clusterIDKey{}).(string)
+	return id
+}
+
+// clusterScoped is implemented by requests carrying a cluster_id field
+type clusterScoped interface {
+	GetClusterId() string
+}
+
+// identified is implemented by requests carrying an id field
+type identified interface {
+	GetId() string
+}
+
+// ClusterTag records the cluster a call concerns in its context, so handlers
+// and logs can be filtered by tenant, and optionally logs every call with it
+type ClusterTag struct {
+	logger    *log.Logger
+	idMethods map[string]bool
+}
+
+// NewClusterTag creates a cluster tag. Requests with a cluster_id field are
+// tagged with it; for idMethods, whose id field is the cluster ID, the id is
+// used. When logger is non-nil each unary call is logged with its cluster.
+func NewClusterTag(logger *log.Logger, idMethods ...string) *ClusterTag {
+	m := make(map[string]bool, len(idMethods))
+	for _, method := range idMethods {
+		m[method] = true
+	}
+	return &ClusterTag{logger: logger, idMethods: m}
+}
+
+// UnaryServerInterceptor returns an interceptor tagging unary calls with
+// their cluster
+func (t *ClusterTag) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		clusterID := t.clusterID(info.FullMethod, req)
+		if clusterID != "" {
+			ctx = context.WithValue(ctx, clusterIDKey{}, clusterID)
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		if t.logger != nil {
+			if clusterID == "" {
+				clusterID = "-"
+			}
+			t.logger.Printf("RPC %s cluster=%s code=%s duration=%v",
+				info.FullMethod, clusterID, status.Code(err), time.Since(start).Round(time.Microsecond))
+		}
+		return resp, err
+	}
+}
+
+// clusterID extracts the cluster a request concerns
+func (t *ClusterTag) clusterID(method string, req interface{}) string {
+	if t.idMethods[method] {
+		if r, ok := req.(identified); ok {
+			return r.GetId()
+		}
+	}
+	if r, ok := req.(clusterScoped); ok {
+		return r.GetClusterId()
+	}
+	return ""
+}
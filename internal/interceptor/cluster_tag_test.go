@@ -0,0 +1,66 @@
+package interceptor_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+
+	"github.com/filanov/netctrl-server/internal/interceptor"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+var _ = Describe("ClusterTag", func() {
+	var (
+		logs      bytes.Buffer
+		clusterID string
+		handler   grpc.UnaryHandler
+	)
+
+	BeforeEach(func() {
+		logs.Reset()
+		clusterID = ""
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			clusterID = interceptor.ClusterIDFromContext(ctx)
+			return nil, nil
+		}
+	})
+
+	// call runs a request through a cluster tag logging to logs
+	call := func(method string, req interface{}) {
+		tag := interceptor.NewClusterTag(log.New(&logs, "", 0), v1.ClusterService_GetCluster_FullMethodName)
+		_, err := tag.UnaryServerInterceptor()(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("should tag and log a GetCluster call with the cluster ID", func() {
+		call(v1.ClusterService_GetCluster_FullMethodName, &v1.GetClusterRequest{Id: "cluster-42"})
+		Expect(clusterID).To(Equal("cluster-42"))
+		Expect(logs.String()).To(ContainSubstring(v1.ClusterService_GetCluster_FullMethodName))
+		Expect(logs.String()).To(ContainSubstring("cluster=cluster-42"))
+	})
+
+	It("should tag requests carrying a cluster_id field", func() {
+		call(v1.AgentService_ListAgents_FullMethodName, &v1.ListAgentsRequest{ClusterId: "cluster-7"})
+		Expect(clusterID).To(Equal("cluster-7"))
+		Expect(logs.String()).To(ContainSubstring("cluster=cluster-7"))
+	})
+
+	It("should not treat the id of other methods as a cluster ID", func() {
+		call(v1.AgentService_GetAgent_FullMethodName, &v1.GetAgentRequest{Id: "agent-1"})
+		Expect(clusterID).To(BeEmpty())
+		Expect(logs.String()).To(ContainSubstring("cluster=-"))
+	})
+
+	It("should tag without logging when no logger is given", func() {
+		tag := interceptor.NewClusterTag(nil, v1.ClusterService_GetCluster_FullMethodName)
+		_, err := tag.UnaryServerInterceptor()(context.Background(), &v1.GetClusterRequest{Id: "cluster-42"},
+			&grpc.UnaryServerInfo{FullMethod: v1.ClusterService_GetCluster_FullMethodName}, handler)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clusterID).To(Equal("cluster-42"))
+		Expect(logs.String()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,224 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// IdempotencyKeyHeader is the metadata key clients use to deduplicate retries
+const IdempotencyKeyHeader = "idempotency-key"
+
+// idempotencyEntry holds the outcome of a request for a given key
+type idempotencyEntry struct {
+	// fingerprint hashes the request that created the entry; a retry must
+	// carry the same request to share its outcome
+	fingerprint [sha256.Size]byte
+
+	done    chan struct{}
+	resp    interface{}
+	expires time.Time
+}
+
+// Idempotency caches responses of mutating RPCs by idempotency key so that
+// retries return the cached result instead of re-executing the handler
+type Idempotency struct {
+	ttl time.Duration
+	// gatewayHost is the address the in-process gateway connects from,
+	// besides loopback; only its x-forwarded-for metadata is trusted
+	gatewayHost string
+	methods     map[string]bool
+	entries     map[string]*idempotencyEntry
+	mu          sync.Mutex
+}
+
+// NewIdempotency creates an idempotency cache for the given full method
+// names. gatewayHost is the host the HTTP gateway dials the gRPC server on;
+// requests from it or from loopback are scoped by the client it forwarded for.
+func NewIdempotency(ttl time.Duration, gatewayHost string, methods ...string) *Idempotency {
+	m := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		m[method] = true
+	}
+	return &Idempotency{
+		ttl:         ttl,
+		gatewayHost: gatewayHost,
+		methods:     m,
+		entries:     make(map[string]*idempotencyEntry),
+	}
+}
+
+// UnaryServerInterceptor returns an interceptor deduplicating requests that
+// carry an idempotency key. Keys are scoped per method and caller, and failed
+// requests are not cached so they can be retried. Reusing a key with a
+// different request fails with InvalidArgument rather than returning the
+// response of the original request.
+func (i *Idempotency) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !i.methods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		key := idempotencyKey(ctx)
+		if key == "" {
+			return handler(ctx, req)
+		}
+		cacheKey := info.FullMethod + "/" + i.caller(ctx) + "/" + key
+		fingerprint, err := requestFingerprint(req)
+		if err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to fingerprint request: %v", err))
+		}
+
+		entry, owner := i.acquire(cacheKey, fingerprint)
+		if entry.fingerprint != fingerprint {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf(
+				"idempotency key %q was already used for a different request", key))
+		}
+		if !owner {
+			select {
+			case <-entry.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if entry.resp != nil {
+				return cloneResponse(entry.resp), nil
+			}
+			// The original request failed, execute this one
+			return handler(ctx, req)
+		}
+
+		resp, err := handler(ctx, req)
+		i.release(cacheKey, entry, resp, err)
+		return resp, err
+	}
+}
+
+// acquire returns the entry for a key, creating it if missing. owner reports
+// whether the caller created the entry and must execute the handler.
+func (i *Idempotency) acquire(cacheKey string, fingerprint [sha256.Size]byte) (*idempotencyEntry, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := time.Now()
+	i.evictExpired(now)
+
+	if entry, ok := i.entries[cacheKey]; ok {
+		return entry, false
+	}
+
+	entry := &idempotencyEntry{
+		fingerprint: fingerprint,
+		done:        make(chan struct{}),
+		expires:     now.Add(i.ttl),
+	}
+	i.entries[cacheKey] = entry
+	return entry, true
+}
+
+// release stores the handler outcome and wakes up waiting duplicates
+func (i *Idempotency) release(cacheKey string, entry *idempotencyEntry, resp interface{}, err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if err == nil {
+		entry.resp = resp
+		entry.expires = time.Now().Add(i.ttl)
+	} else {
+		delete(i.entries, cacheKey)
+	}
+	close(entry.done)
+}
+
+// evictExpired removes completed entries whose TTL has elapsed
+func (i *Idempotency) evictExpired(now time.Time) {
+	for key, entry := range i.entries {
+		select {
+		case <-entry.done:
+			if now.After(entry.expires) {
+				delete(i.entries, key)
+			}
+		default:
+		}
+	}
+}
+
+// idempotencyKey extracts the idempotency key from incoming metadata
+func idempotencyKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(IdempotencyKeyHeader)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// caller identifies the client sending a request. Ports are dropped so
+// retries over a new connection share their key. Requests from the gateway
+// are scoped by the client it forwarded for: the last x-forwarded-for entry,
+// which the gateway appends, since earlier entries come from the client.
+// Other callers could forge the header, so they are scoped by their address.
+func (i *Idempotency) caller(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	if !i.fromGateway(host) {
+		return host
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	forwarded := md.Get("x-forwarded-for")
+	if len(forwarded) == 0 {
+		return host
+	}
+	entries := strings.Split(forwarded[len(forwarded)-1], ",")
+	return strings.TrimSpace(entries[len(entries)-1])
+}
+
+// fromGateway reports whether a peer host is where the gateway connects from
+func (i *Idempotency) fromGateway(host string) bool {
+	if i.gatewayHost != "" && host == i.gatewayHost {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// requestFingerprint hashes the deterministic encoding of a request
+func requestFingerprint(req interface{}) ([sha256.Size]byte, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return [sha256.Size]byte{}, nil
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
+
+// cloneResponse copies cached proto responses so callers cannot mutate the cache
+func cloneResponse(resp interface{}) interface{} {
+	if msg, ok := resp.(proto.Message); ok {
+		return proto.Clone(msg)
+	}
+	return resp
+}
@@ -0,0 +1,183 @@
+package interceptor_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/filanov/netctrl-server/internal/interceptor"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+var _ = Describe("Idempotency", func() {
+	var (
+		unary   grpc.UnaryServerInterceptor
+		info    *grpc.UnaryServerInfo
+		calls   int
+		handler grpc.UnaryHandler
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		idempotency := interceptor.NewIdempotency(time.Minute, "192.0.2.1", v1.ClusterService_CreateCluster_FullMethodName)
+		unary = idempotency.UnaryServerInterceptor()
+		info = &grpc.UnaryServerInfo{FullMethod: v1.ClusterService_CreateCluster_FullMethodName}
+		calls = 0
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			calls++
+			return &v1.CreateClusterResponse{Cluster: &v1.Cluster{Id: "cluster-1"}}, nil
+		}
+		ctx = metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(interceptor.IdempotencyKeyHeader, "key-1"))
+	})
+
+	It("should execute the handler once for requests with the same key", func() {
+		req := &v1.CreateClusterRequest{Name: "test-cluster"}
+
+		resp1, err := unary(ctx, req, info, handler)
+		Expect(err).NotTo(HaveOccurred())
+		resp2, err := unary(ctx, req, info, handler)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(calls).To(Equal(1))
+		Expect(resp2.(*v1.CreateClusterResponse).Cluster.Id).To(Equal(resp1.(*v1.CreateClusterResponse).Cluster.Id))
+	})
+
+	It("should execute the handler for different keys", func() {
+		req := &v1.CreateClusterRequest{Name: "test-cluster"}
+		otherCtx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(interceptor.IdempotencyKeyHeader, "key-2"))
+
+		_, err := unary(ctx, req, info, handler)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = unary(otherCtx, req, info, handler)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(calls).To(Equal(2))
+	})
+
+	It("should scope keys per method", func() {
+		otherInfo := &grpc.UnaryServerInfo{FullMethod: v1.ClusterService_UpdateCluster_FullMethodName}
+
+		_, err := unary(ctx, &v1.CreateClusterRequest{}, info, handler)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = unary(ctx, &v1.UpdateClusterRequest{}, otherInfo, handler)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(calls).To(Equal(2))
+	})
+
+	It("should execute the handler for requests without a key", func() {
+		req := &v1.CreateClusterRequest{Name: "test-cluster"}
+
+		_, err := unary(context.Background(), req, info, handler)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = unary(context.Background(), req, info, handler)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(calls).To(Equal(2))
+	})
+
+	It("should not cache failed requests", func() {
+		failing := func(ctx context.Context, req interface{}) (interface{}, error) {
+			calls++
+			return nil, errors.New("boom")
+		}
+
+		_, err := unary(ctx, &v1.CreateClusterRequest{}, info, failing)
+		Expect(err).To(HaveOccurred())
+		_, err = unary(ctx, &v1.CreateClusterRequest{}, info, handler)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(calls).To(Equal(2))
+	})
+
+	It("should refuse a key reused with a different request", func() {
+		_, err := unary(ctx, &v1.CreateClusterRequest{Name: "test-cluster"}, info, handler)
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := unary(ctx, &v1.CreateClusterRequest{Name: "other-cluster"}, info, handler)
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		Expect(resp).To(BeNil())
+		Expect(calls).To(Equal(1))
+	})
+
+	Context("with several callers", func() {
+		// fromCaller returns a context carrying key-1 for a request from addr
+		fromCaller := func(addr string, md metadata.MD) context.Context {
+			tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+			Expect(err).NotTo(HaveOccurred())
+			md = metadata.Join(md, metadata.Pairs(interceptor.IdempotencyKeyHeader, "key-1"))
+			return metadata.NewIncomingContext(peer.NewContext(context.Background(), &peer.Peer{Addr: tcpAddr}), md)
+		}
+
+		It("should scope keys per caller", func() {
+			req := &v1.CreateClusterRequest{Name: "test-cluster"}
+
+			_, err := unary(fromCaller("10.0.0.1:40000", nil), req, info, handler)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = unary(fromCaller("10.0.0.2:40000", nil), req, info, handler)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should share a key across connections of the same caller", func() {
+			req := &v1.CreateClusterRequest{Name: "test-cluster"}
+
+			_, err := unary(fromCaller("10.0.0.1:40000", nil), req, info, handler)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = unary(fromCaller("10.0.0.1:40001", nil), req, info, handler)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(calls).To(Equal(1))
+		})
+
+		It("should scope gateway requests by the client forwarded for", func() {
+			req := &v1.CreateClusterRequest{Name: "test-cluster"}
+
+			_, err := unary(fromCaller("127.0.0.1:40000", metadata.Pairs("x-forwarded-for", "10.0.0.1")), req, info, handler)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = unary(fromCaller("127.0.0.1:40000", metadata.Pairs("x-forwarded-for", "10.0.0.2")), req, info, handler)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should scope gateway requests by the entry the gateway appended", func() {
+			req := &v1.CreateClusterRequest{Name: "test-cluster"}
+
+			// The first entries come from the client and may be forged
+			_, err := unary(fromCaller("127.0.0.1:40000", metadata.Pairs("x-forwarded-for", "10.0.0.9, 10.0.0.1")), req, info, handler)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = unary(fromCaller("127.0.0.1:40000", metadata.Pairs("x-forwarded-for", "10.0.0.9, 10.0.0.2")), req, info, handler)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(2))
+
+			_, err = unary(fromCaller("192.0.2.1:40000", metadata.Pairs("x-forwarded-for", "10.0.0.8, 10.0.0.1")), req, info, handler)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(2))
+		})
+
+		It("should ignore forwarded metadata on direct calls", func() {
+			req := &v1.CreateClusterRequest{Name: "test-cluster"}
+
+			_, err := unary(fromCaller("10.0.0.1:40000", nil), req, info, handler)
+			Expect(err).NotTo(HaveOccurred())
+			// Another client claims to be forwarded for the first one
+			_, err = unary(fromCaller("10.0.0.2:40000", metadata.Pairs("x-forwarded-for", "10.0.0.1")), req, info, handler)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(calls).To(Equal(2))
+		})
+	})
+})
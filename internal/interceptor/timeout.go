@@ -0,0 +1,85 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Timeouts bounds each unary call with a deadline chosen per method, falling
+// back to a default. A deadline set by the client still applies if earlier.
+type Timeouts struct {
+	defaultTimeout time.Duration
+	methods        map[string]time.Duration
+}
+
+// NewTimeouts creates per-method timeouts. Keys of methods are either full
+// method names ("/netctrl.v1.AgentService/GetAgent") or bare method names
+// ("GetAgent"); a zero defaultTimeout leaves other methods unbounded.
+func NewTimeouts(defaultTimeout time.Duration, methods map[string]time.Duration) *Timeouts {
+	m := make(map[string]time.Duration, len(methods))
+	for name, timeout := range methods {
+		m[name] = timeout
+	}
+	return &Timeouts{defaultTimeout: defaultTimeout, methods: m}
+}
+
+// UnaryServerInterceptor returns an interceptor applying the method's timeout
+func (t *Timeouts) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		timeout := t.timeout(info.FullMethod)
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		resp, err := handler(ctx, req)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			// Handlers report failed storage calls as Internal; the cause is the deadline
+			if code := status.Code(err); code == codes.Unknown || code == codes.Internal {
+				err = status.Errorf(codes.DeadlineExceeded, "%s exceeded its %v timeout", info.FullMethod, timeout)
+			}
+		}
+		return resp, err
+	}
+}
+
+// timeout returns the timeout configured for a full method name
+func (t *Timeouts) timeout(fullMethod string) time.Duration {
+	if timeout, ok := t.methods[fullMethod]; ok {
+		return timeout
+	}
+	if timeout, ok := t.methods[fullMethod[strings.LastIndex(fullMethod, "/")+1:]]; ok {
+		return timeout
+	}
+	return t.defaultTimeout
+}
+
+// UnknownMethods returns the configured method names matching none of the
+// services' methods, which are most likely typos
+func (t *Timeouts) UnknownMethods(services map[string]grpc.ServiceInfo) []string {
+	known := make(map[string]bool)
+	for service, info := range services {
+		for _, method := range info.Methods {
+			known["/"+service+"/"+method.Name] = true
+			known[method.Name] = true
+		}
+	}
+
+	var unknown []string
+	for name := range t.methods {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
@@ -0,0 +1,71 @@
+package interceptor_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/filanov/netctrl-server/internal/interceptor"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+var _ = Describe("Timeouts", func() {
+	var timeouts *interceptor.Timeouts
+
+	// slowHandler takes 200ms unless its context ends first
+	slowHandler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+			return "done", nil
+		case <-ctx.Done():
+			return nil, status.Errorf(codes.Internal, "failed to get agent: %v", ctx.Err())
+		}
+	}
+
+	call := func(method string) error {
+		_, err := timeouts.UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, slowHandler)
+		return err
+	}
+
+	BeforeEach(func() {
+		timeouts = interceptor.NewTimeouts(0, map[string]time.Duration{
+			"GetAgent": 20 * time.Millisecond,
+			v1.ClusterService_GetCluster_FullMethodName: 20 * time.Millisecond,
+			"ListAgents": time.Minute,
+		})
+	})
+
+	It("should fail a method exceeding its configured timeout with DeadlineExceeded", func() {
+		Expect(status.Code(call(v1.AgentService_GetAgent_FullMethodName))).To(Equal(codes.DeadlineExceeded))
+		Expect(status.Code(call(v1.ClusterService_GetCluster_FullMethodName))).To(Equal(codes.DeadlineExceeded))
+	})
+
+	It("should let other methods run to completion", func() {
+		Expect(call(v1.AgentService_ListAgents_FullMethodName)).To(Succeed())
+		Expect(call(v1.ClusterService_ListClusters_FullMethodName)).To(Succeed())
+	})
+
+	It("should bound unlisted methods by the default timeout", func() {
+		timeouts = interceptor.NewTimeouts(20*time.Millisecond, map[string]time.Duration{"ListAgents": time.Minute})
+		Expect(status.Code(call(v1.ClusterService_ListClusters_FullMethodName))).To(Equal(codes.DeadlineExceeded))
+		Expect(call(v1.AgentService_ListAgents_FullMethodName)).To(Succeed())
+	})
+
+	It("should report configured names matching no method", func() {
+		timeouts = interceptor.NewTimeouts(0, map[string]time.Duration{
+			"GetAgent":                              time.Second,
+			"GetAgnet":                              time.Second,
+			"/netctrl.v1.ClusterService/GetCluster": time.Second,
+		})
+		services := map[string]grpc.ServiceInfo{
+			"netctrl.v1.ClusterService": {Methods: []grpc.MethodInfo{{Name: "GetCluster"}}},
+			"netctrl.v1.AgentService":   {Methods: []grpc.MethodInfo{{Name: "GetAgent"}}},
+		}
+		Expect(timeouts.UnknownMethods(services)).To(Equal([]string{"GetAgnet"}))
+	})
+})
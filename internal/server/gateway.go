@@ -1,19 +1,27 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/grpclog"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/filanov/netctrl-server/internal/interceptor"
 	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
 )
 
@@ -29,58 +37,161 @@ func (f *filteredLogger) Write(p []byte) (n int, err error) {
 	return log.Writer().Write(p)
 }
 
+const (
+	// gatewayRegisterAttempts bounds how often a handler registration is tried
+	gatewayRegisterAttempts = 5
+
+	// gatewayRegisterBackoff is the delay before the first registration retry,
+	// doubled after each failed attempt
+	gatewayRegisterBackoff = 200 * time.Millisecond
+)
+
+// gatewayDialBackoff is the reconnect backoff of the gateway's gRPC client
+var gatewayDialBackoff = backoff.Config{
+	BaseDelay:  100 * time.Millisecond,
+	Multiplier: 1.6,
+	Jitter:     0.2,
+	MaxDelay:   5 * time.Second,
+}
+
+// init configures grpclog to filter out harmless errors. grpclog requires
+// its logger to be replaced before any gRPC call is made.
+func init() {
+	grpclog.SetLoggerV2(grpclog.NewLoggerV2(io.Discard, &filteredLogger{}, &filteredLogger{}))
+}
+
 // startGatewayServer starts the HTTP gateway server
 func (s *Server) startGatewayServer() error {
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
 	s.gatewayCancel = cancel
 
-	// Configure grpclog to filter out harmless errors
-	grpclog.SetLoggerV2(grpclog.NewLoggerV2(io.Discard, &filteredLogger{}, &filteredLogger{}))
-
 	// Create gRPC-Gateway mux
-	mux := runtime.NewServeMux()
+	mux := newGatewayMux()
 
-	// Connect to gRPC server
-	grpcAddr := fmt.Sprintf("localhost:%d", s.config.GRPC.Port)
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
-
-	// Register service handlers
-	if err := v1.RegisterClusterServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
-		return fmt.Errorf("failed to register cluster service handler: %w", err)
+	// Connect to gRPC server. The gateway may come up before the gRPC
+	// listener, so reconnect quickly rather than waiting out gRPC's default
+	// one second backoff.
+	grpcAddr := s.grpcDialAddress()
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           gatewayDialBackoff,
+			MinConnectTimeout: 5 * time.Second,
+		}),
 	}
 
-	if err := v1.RegisterAgentServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
-		return fmt.Errorf("failed to register agent service handler: %w", err)
+	// Register service handlers
+	registrations := []struct {
+		name     string
+		register func(context.Context, *runtime.ServeMux, string, []grpc.DialOption) error
+	}{
+		{"cluster", v1.RegisterClusterServiceHandlerFromEndpoint},
+		{"agent", v1.RegisterAgentServiceHandlerFromEndpoint},
+		{"health", v1.RegisterHealthServiceHandlerFromEndpoint},
 	}
-
-	if err := v1.RegisterHealthServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
-		return fmt.Errorf("failed to register health service handler: %w", err)
+	for _, r := range registrations {
+		err := retryWithBackoff(ctx, gatewayRegisterAttempts, gatewayRegisterBackoff, func() error {
+			return r.register(ctx, mux, grpcAddr, opts)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to register %s service handler: %w", r.name, err)
+		}
 	}
 
 	// Create HTTP server with middleware
-	handler := http.Handler(mux)
+	handler := requestTimeoutMiddleware(conditionalGetMiddleware(mux), s.config.Gateway.RequestTimeout)
+	handler = pathPrefixMiddleware(handler, s.config.Gateway.PathPrefix)
 	if s.config.Gateway.EnableCORS {
 		handler = corsMiddleware(handler)
 	}
 
-	addr := fmt.Sprintf(":%d", s.config.Gateway.Port)
+	addr := listenAddress(s.config.Gateway.BindAddress, s.config.Gateway.Port)
 	s.gatewayServer = &http.Server{
 		Addr:              addr,
 		Handler:           handler,
 		ReadHeaderTimeout: 10 * time.Second,
 	}
 
-	log.Printf("HTTP gateway listening on %s", addr)
+	tlsCfg := s.config.Gateway.TLS
+	if !tlsCfg.Enabled {
+		log.Printf("HTTP gateway listening on %s", addr)
+
+		// Start serving (blocking)
+		if err := s.gatewayServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("gateway server failed: %w", err)
+		}
+		return nil
+	}
+
+	minVersion, err := parseTLSVersion(tlsCfg.MinVersion)
+	if err != nil {
+		return err
+	}
+	s.gatewayServer.TLSConfig = &tls.Config{MinVersion: minVersion}
+
+	if tlsCfg.RedirectPort != 0 {
+		s.startRedirectServer(tlsCfg.RedirectPort)
+	}
+
+	log.Printf("HTTPS gateway listening on %s", addr)
 
 	// Start serving (blocking)
-	if err := s.gatewayServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if err := s.gatewayServer.ListenAndServeTLS(tlsCfg.CertFile, tlsCfg.KeyFile); err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("gateway server failed: %w", err)
 	}
 
 	return nil
 }
 
+// startRedirectServer serves plain HTTP on the given port, redirecting every
+// request to the HTTPS gateway
+func (s *Server) startRedirectServer(port int) {
+	addr := listenAddress(s.config.Gateway.BindAddress, port)
+	s.redirectServer = &http.Server{
+		Addr:              addr,
+		Handler:           httpsRedirectHandler(s.config.Gateway.Port),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("HTTP to HTTPS redirect listening on %s", addr)
+
+	go func() {
+		if err := s.redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("Redirect server failed: %v", err)
+		}
+	}()
+}
+
+// httpsRedirectHandler redirects requests to the same host and path on the
+// HTTPS port, preserving the method and body via 308
+func httpsRedirectHandler(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if httpsPort != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
+
+// parseTLSVersion converts a configured TLS version string to its constant
+func parseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported gateway TLS min_version %q (expected 1.2 or 1.3)", version)
+	}
+}
+
 // stopGatewayServer gracefully stops the HTTP gateway server
 func (s *Server) stopGatewayServer() {
 	if s.gatewayServer != nil {
@@ -94,17 +205,123 @@ func (s *Server) stopGatewayServer() {
 		log.Println("HTTP gateway stopped")
 	}
 
+	if s.redirectServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := s.redirectServer.Shutdown(ctx); err != nil {
+			log.Printf("Redirect server shutdown error: %v", err)
+		}
+	}
+
 	if s.gatewayCancel != nil {
 		s.gatewayCancel()
 	}
 }
 
+// headerMatcher forwards the Idempotency-Key and Agent-Version headers as
+// gRPC metadata in addition to the default permanent and Grpc-Metadata-
+// prefixed headers
+func headerMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, interceptor.IdempotencyKeyHeader) {
+		return interceptor.IdempotencyKeyHeader, true
+	}
+	if strings.EqualFold(key, interceptor.AgentVersionHeader) {
+		return interceptor.AgentVersionHeader, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// requestTimeoutHeader lets HTTP clients bound a request with a Go duration
+// ("500ms", "2s") or a number of seconds
+const requestTimeoutHeader = "X-Request-Timeout"
+
+// requestTimeoutMiddleware bounds the context of each request, and thereby its
+// outgoing gRPC call. X-Request-Timeout sets the deadline explicitly; a
+// Grpc-Timeout header is left to the gateway, which applies it natively.
+// Requests without either header get the default timeout, if non-zero.
+func requestTimeoutMiddleware(next http.Handler, defaultTimeout time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timeout := defaultTimeout
+		if value := r.Header.Get(requestTimeoutHeader); value != "" {
+			parsed, err := parseRequestTimeout(value)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			timeout = parsed
+		} else if r.Header.Get("Grpc-Timeout") != "" {
+			timeout = 0
+		}
+
+		if timeout > 0 {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseRequestTimeout parses an X-Request-Timeout value
+func parseRequestTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, convErr := strconv.ParseFloat(value, 64)
+		if convErr != nil {
+			return 0, fmt.Errorf("invalid %s header %q", requestTimeoutHeader, value)
+		}
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid %s header %q: must be positive", requestTimeoutHeader, value)
+	}
+	return timeout, nil
+}
+
+// pathPrefixMiddleware serves next under prefix, stripping it before routing
+// so the generated REST paths match. Requests outside the prefix get 404.
+func pathPrefixMiddleware(next http.Handler, prefix string) http.Handler {
+	if prefix == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, ok := stripPathPrefix(r.URL.Path, prefix)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		rawPath, _ := stripPathPrefix(r.URL.RawPath, prefix)
+
+		stripped := r.Clone(r.Context())
+		stripped.URL.Path = path
+		stripped.URL.RawPath = rawPath
+		next.ServeHTTP(w, stripped)
+	})
+}
+
+// stripPathPrefix removes prefix from path if path lies beneath it. The prefix
+// only matches whole segments, so "/api/netctrl" does not match
+// "/api/netctrlx".
+func stripPathPrefix(path, prefix string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, prefix)
+	if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+		return "", false
+	}
+	if rest == "" {
+		rest = "/"
+	}
+	return rest, true
+}
+
 // corsMiddleware adds CORS headers to responses
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key, Agent-Version, Grpc-Timeout, X-Request-Timeout, If-None-Match")
+		w.Header().Set("Access-Control-Expose-Headers", "ETag")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -114,3 +331,120 @@ func corsMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// newGatewayMux creates the gRPC-Gateway mux with the server's header
+// forwarding and JSON encoding
+func newGatewayMux() *runtime.ServeMux {
+	return runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, gatewayMarshaler()),
+		runtime.WithForwardResponseOption(etagResponseOption),
+	)
+}
+
+// etagged is implemented by responses carrying a resource version
+type etagged interface {
+	GetEtag() string
+}
+
+// etagResponseOption returns the version of responses that carry one as the
+// ETag header
+func etagResponseOption(ctx context.Context, w http.ResponseWriter, msg proto.Message) error {
+	if resp, ok := msg.(etagged); ok && resp.GetEtag() != "" {
+		w.Header().Set("ETag", strconv.Quote(resp.GetEtag()))
+	}
+	return nil
+}
+
+// conditionalGetMiddleware answers GET requests with 304 Not Modified when
+// If-None-Match names the ETag of the response, sparing the client the body
+func conditionalGetMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch := r.Header.Get("If-None-Match")
+		if r.Method != http.MethodGet || ifNoneMatch == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buffered := &bufferedResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(buffered, r)
+
+		if buffered.status == http.StatusOK && etagMatches(ifNoneMatch, w.Header().Get("ETag")) {
+			w.Header().Del("Content-Type")
+			w.Header().Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(buffered.status)
+		_, _ = w.Write(buffered.body.Bytes())
+	})
+}
+
+// etagMatches reports whether an If-None-Match value names etag
+func etagMatches(ifNoneMatch, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedResponseWriter holds a response back so it can be replaced
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponseWriter) WriteHeader(status int) {
+	b.status = status
+}
+
+func (b *bufferedResponseWriter) Write(p []byte) (int, error) {
+	return b.body.Write(p)
+}
+
+// gatewayMarshaler encodes responses and errors as protojson. Enums are always
+// written by name so clients can tell values apart, including ones added after
+// the client was built, and unknown request fields are ignored.
+func gatewayMarshaler() runtime.Marshaler {
+	return &runtime.HTTPBodyMarshaler{
+		Marshaler: &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				EmitUnpopulated: true,
+				UseEnumNumbers:  false,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		},
+	}
+}
+
+// retryWithBackoff calls fn until it succeeds, up to attempts times, doubling
+// the delay between attempts. It returns the last error once attempts are
+// exhausted or the context is done.
+func retryWithBackoff(ctx context.Context, attempts int, delay time.Duration, fn func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			return err
+		}
+
+		log.Printf("Attempt %d/%d failed, retrying in %s: %v", attempt, attempts, delay, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,479 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/filanov/netctrl-server/internal/config"
+	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// writeSelfSignedCert writes a self-signed certificate for 127.0.0.1 into dir
+// and returns the certificate and key paths along with a pool trusting it
+func writeSelfSignedCert(dir string) (string, string, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "netctrl-test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	Expect(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)).To(Succeed())
+	Expect(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)).To(Succeed())
+
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return certFile, keyFile, pool
+}
+
+// freePort returns a currently unused TCP port on the loopback interface
+func freePort() int {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+var _ = Describe("Gateway TLS", func() {
+	var (
+		s            *Server
+		port         int
+		redirectPort int
+		pool         *x509.CertPool
+	)
+
+	BeforeEach(func() {
+		var certFile, keyFile string
+		certFile, keyFile, pool = writeSelfSignedCert(GinkgoT().TempDir())
+		port = freePort()
+		redirectPort = freePort()
+
+		cfg := &config.Config{
+			GRPC: config.GRPCConfig{BindAddress: "127.0.0.1", Port: freePort()},
+			Gateway: config.GatewayConfig{
+				BindAddress: "127.0.0.1",
+				Port:        port,
+				TLS: config.GatewayTLSConfig{
+					Enabled:      true,
+					CertFile:     certFile,
+					KeyFile:      keyFile,
+					MinVersion:   "1.3",
+					RedirectPort: redirectPort,
+				},
+			},
+		}
+		s = New(cfg, mock.New())
+		go func() {
+			defer GinkgoRecover()
+			Expect(s.startGatewayServer()).To(Succeed())
+		}()
+		DeferCleanup(s.stopGatewayServer)
+	})
+
+	It("should serve HTTPS with the configured certificate", func() {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		}}
+		url := "https://127.0.0.1:" + strconv.Itoa(port) + "/api/v1/health"
+
+		var resp *http.Response
+		Eventually(func() error {
+			var err error
+			resp, err = client.Get(url)
+			return err
+		}).Should(Succeed())
+		defer resp.Body.Close()
+
+		Expect(resp.TLS).NotTo(BeNil())
+		Expect(resp.TLS.Version).To(Equal(uint16(tls.VersionTLS13)))
+	})
+
+	It("should reject clients below the minimum TLS version", func() {
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool, MaxVersion: tls.VersionTLS12},
+		}}
+		url := "https://127.0.0.1:" + strconv.Itoa(port) + "/api/v1/health"
+
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", "127.0.0.1:"+strconv.Itoa(port))
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}).Should(Succeed())
+
+		_, err := client.Get(url)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should redirect plain HTTP to HTTPS", func() {
+		client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}}
+		url := "http://127.0.0.1:" + strconv.Itoa(redirectPort) + "/api/v1/clusters?page_size=10"
+
+		var resp *http.Response
+		Eventually(func() error {
+			var err error
+			resp, err = client.Get(url)
+			return err
+		}).Should(Succeed())
+		defer resp.Body.Close()
+
+		Expect(resp.StatusCode).To(Equal(http.StatusPermanentRedirect))
+		Expect(resp.Header.Get("Location")).To(Equal("https://127.0.0.1:" + strconv.Itoa(port) + "/api/v1/clusters?page_size=10"))
+	})
+})
+
+var _ = Describe("parseTLSVersion", func() {
+	It("should default to TLS 1.2", func() {
+		Expect(parseTLSVersion("")).To(Equal(uint16(tls.VersionTLS12)))
+	})
+
+	It("should reject unknown versions", func() {
+		_, err := parseTLSVersion("1.0")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+// slowHealthServer answers health checks only after a delay, or fails with
+// the context error once the caller's deadline expires
+type slowHealthServer struct {
+	v1.UnimplementedHealthServiceServer
+	delay time.Duration
+}
+
+func (s *slowHealthServer) Check(ctx context.Context, req *v1.HealthCheckRequest) (*v1.HealthCheckResponse, error) {
+	select {
+	case <-time.After(s.delay):
+		return &v1.HealthCheckResponse{Status: v1.HealthStatus_HEALTH_STATUS_HEALTHY}, nil
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+var _ = Describe("Gateway request timeout", func() {
+	var (
+		handler    http.Handler
+		grpcServer *grpc.Server
+	)
+
+	BeforeEach(func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		grpcServer = grpc.NewServer()
+		v1.RegisterHealthServiceServer(grpcServer, &slowHealthServer{delay: 500 * time.Millisecond})
+		go func() {
+			_ = grpcServer.Serve(listener)
+		}()
+		DeferCleanup(grpcServer.Stop)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		mux := runtime.NewServeMux()
+		opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+		Expect(v1.RegisterHealthServiceHandlerFromEndpoint(ctx, mux, listener.Addr().String(), opts)).To(Succeed())
+
+		handler = requestTimeoutMiddleware(mux, 5*time.Second)
+	})
+
+	serve := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	It("should complete requests within the default timeout", func() {
+		Expect(serve("", "").Code).To(Equal(http.StatusOK))
+	})
+
+	It("should return 504 when X-Request-Timeout expires", func() {
+		Expect(serve("X-Request-Timeout", "50ms").Code).To(Equal(http.StatusGatewayTimeout))
+	})
+
+	It("should accept X-Request-Timeout in seconds", func() {
+		Expect(serve("X-Request-Timeout", "0.05").Code).To(Equal(http.StatusGatewayTimeout))
+	})
+
+	It("should return 504 when Grpc-Timeout expires", func() {
+		Expect(serve("Grpc-Timeout", "50m").Code).To(Equal(http.StatusGatewayTimeout))
+	})
+
+	It("should reject an invalid X-Request-Timeout", func() {
+		Expect(serve("X-Request-Timeout", "soon").Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should apply the default timeout when no header is set", func() {
+		handler = requestTimeoutMiddleware(handler, 50*time.Millisecond)
+		Expect(serve("", "").Code).To(Equal(http.StatusGatewayTimeout))
+	})
+})
+
+var _ = Describe("Gateway startup ordering", func() {
+	It("should connect once the gRPC server comes up after the gateway", func() {
+		port := freePort()
+		cfg := &config.Config{
+			GRPC:    config.GRPCConfig{BindAddress: "127.0.0.1", Port: freePort()},
+			Gateway: config.GatewayConfig{BindAddress: "127.0.0.1", Port: port},
+		}
+		s := New(cfg, mock.New())
+
+		go func() {
+			defer GinkgoRecover()
+			Expect(s.startGatewayServer()).To(Succeed())
+		}()
+		DeferCleanup(s.stopGatewayServer)
+
+		url := "http://127.0.0.1:" + strconv.Itoa(port) + "/api/v1/health"
+		Eventually(func() error {
+			resp, err := http.Get(url)
+			if err != nil {
+				return err
+			}
+			return resp.Body.Close()
+		}).Should(Succeed())
+
+		go func() {
+			defer GinkgoRecover()
+			Expect(s.startGRPCServer()).To(Succeed())
+		}()
+		DeferCleanup(s.stopGRPCServer)
+
+		Eventually(func() int {
+			resp, err := http.Get(url)
+			if err != nil {
+				return 0
+			}
+			defer resp.Body.Close()
+			return resp.StatusCode
+		}, 3*time.Second).Should(Equal(http.StatusOK))
+	})
+})
+
+var _ = Describe("retryWithBackoff", func() {
+	It("should retry until the call succeeds", func() {
+		calls := 0
+		err := retryWithBackoff(context.Background(), 5, time.Millisecond, func() error {
+			calls++
+			if calls < 3 {
+				return errors.New("connection refused")
+			}
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(Equal(3))
+	})
+
+	It("should give up after the attempt bound", func() {
+		calls := 0
+		err := retryWithBackoff(context.Background(), 3, time.Millisecond, func() error {
+			calls++
+			return errors.New("connection refused")
+		})
+		Expect(err).To(MatchError("connection refused"))
+		Expect(calls).To(Equal(3))
+	})
+
+	It("should stop retrying once the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		calls := 0
+		err := retryWithBackoff(ctx, 5, time.Hour, func() error {
+			calls++
+			return errors.New("connection refused")
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(calls).To(Equal(1))
+	})
+})
+
+var _ = Describe("Gateway path prefix", func() {
+	var handler http.Handler
+
+	BeforeEach(func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		grpcServer := grpc.NewServer()
+		v1.RegisterClusterServiceServer(grpcServer, service.NewClusterService(mock.New()))
+		v1.RegisterHealthServiceServer(grpcServer, service.NewHealthService())
+		go func() {
+			_ = grpcServer.Serve(listener)
+		}()
+		DeferCleanup(grpcServer.Stop)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		mux := newGatewayMux()
+		opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+		Expect(v1.RegisterClusterServiceHandlerFromEndpoint(ctx, mux, listener.Addr().String(), opts)).To(Succeed())
+		Expect(v1.RegisterHealthServiceHandlerFromEndpoint(ctx, mux, listener.Addr().String(), opts)).To(Succeed())
+		handler = pathPrefixMiddleware(mux, "/api/netctrl")
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	It("should route prefixed REST paths", func() {
+		rec := get("/api/netctrl/api/v1/clusters")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("clusters"))
+	})
+
+	It("should serve health endpoints under the prefix", func() {
+		Expect(get("/api/netctrl/api/v1/health").Code).To(Equal(http.StatusOK))
+	})
+
+	It("should not serve paths outside the prefix", func() {
+		Expect(get("/api/v1/clusters").Code).To(Equal(http.StatusNotFound))
+		Expect(get("/api/netctrlx/api/v1/clusters").Code).To(Equal(http.StatusNotFound))
+	})
+})
+
+var _ = Describe("Gateway JSON encoding", func() {
+	var (
+		handler http.Handler
+		store   *mock.Storage
+	)
+
+	BeforeEach(func() {
+		store = mock.New()
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		grpcServer := grpc.NewServer()
+		v1.RegisterAgentServiceServer(grpcServer, service.NewAgentService(store))
+		v1.RegisterClusterServiceServer(grpcServer, service.NewClusterService(store))
+		go func() {
+			_ = grpcServer.Serve(listener)
+		}()
+		DeferCleanup(grpcServer.Stop)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		mux := newGatewayMux()
+		opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+		Expect(v1.RegisterAgentServiceHandlerFromEndpoint(ctx, mux, listener.Addr().String(), opts)).To(Succeed())
+		Expect(v1.RegisterClusterServiceHandlerFromEndpoint(ctx, mux, listener.Addr().String(), opts)).To(Succeed())
+		handler = mux
+	})
+
+	It("should serialize enums by name", func() {
+		Expect(store.CreateCluster(context.Background(), &v1.Cluster{Id: "cluster-1", Name: "test"})).To(Succeed())
+		Expect(store.CreateAgent(context.Background(), &v1.Agent{
+			Id:        "agent-1",
+			ClusterId: "cluster-1",
+			Status:    v1.AgentStatus_AGENT_STATUS_INACTIVE,
+		})).To(Succeed())
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/agents/agent-1", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+
+		var body struct {
+			Agent map[string]any `json:"agent"`
+		}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+		Expect(body.Agent).To(HaveKeyWithValue("status", "AGENT_STATUS_INACTIVE"))
+		Expect(body.Agent).To(HaveKeyWithValue("role", "AGENT_ROLE_UNSPECIFIED"))
+	})
+
+	It("should answer a GetCluster with a matching If-None-Match with 304", func() {
+		now := timestamppb.Now()
+		Expect(store.CreateCluster(context.Background(), &v1.Cluster{Id: "cluster-1", Name: "test", CreatedAt: now, UpdatedAt: now})).To(Succeed())
+		conditional := conditionalGetMiddleware(handler)
+
+		get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/clusters/cluster-1", nil)
+			if ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			conditional.ServeHTTP(rec, req)
+			return rec
+		}
+
+		first := get("")
+		Expect(first.Code).To(Equal(http.StatusOK))
+		etag := first.Header().Get("ETag")
+		Expect(etag).NotTo(BeEmpty())
+
+		second := get(etag)
+		Expect(second.Code).To(Equal(http.StatusNotModified))
+		Expect(second.Body.Len()).To(BeZero())
+		Expect(second.Header().Get("ETag")).To(Equal(etag))
+
+		// A changed cluster is returned in full with its new ETag
+		Expect(store.UpdateCluster(context.Background(), &v1.Cluster{
+			Id: "cluster-1", Name: "renamed", CreatedAt: now, UpdatedAt: timestamppb.New(now.AsTime().Add(time.Second)),
+		})).To(Succeed())
+		third := get(etag)
+		Expect(third.Code).To(Equal(http.StatusOK))
+		Expect(third.Header().Get("ETag")).NotTo(Equal(etag))
+		Expect(third.Body.String()).To(ContainSubstring("renamed"))
+	})
+
+	DescribeTable("should serialize empty lists as [] rather than null",
+		func(path, field string) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			Expect(rec.Code).To(Equal(http.StatusOK))
+
+			var body map[string]json.RawMessage
+			Expect(json.Unmarshal(rec.Body.Bytes(), &body)).To(Succeed())
+			Expect(body).To(HaveKey(field))
+			Expect(string(body[field])).To(Equal("[]"))
+		},
+		Entry("clusters", "/api/v1/clusters", "clusters"),
+		Entry("agents", "/api/v1/agents", "agents"),
+		Entry("orphaned agents", "/api/v1/admin/orphaned-agents", "agents"),
+		Entry("matching clusters", "/api/v1/clusters:match?ip_address=10.0.0.5", "clusters"),
+	)
+})
@@ -4,28 +4,96 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"strconv"
 
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/filanov/netctrl-server/internal/interceptor"
 	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
 )
 
+// mutatingMethods lists the RPCs deduplicated by idempotency key
+var mutatingMethods = []string{
+	v1.ClusterService_CreateCluster_FullMethodName,
+	v1.ClusterService_UpdateCluster_FullMethodName,
+	v1.ClusterService_DeleteCluster_FullMethodName,
+	v1.ClusterService_DrainCluster_FullMethodName,
+	v1.ClusterService_UncordonCluster_FullMethodName,
+	v1.ClusterService_MoveCluster_FullMethodName,
+	v1.AgentService_RegisterAgent_FullMethodName,
+	v1.AgentService_UnregisterAgent_FullMethodName,
+	v1.AgentService_DecommissionAgent_FullMethodName,
+	v1.AgentService_RestartAgent_FullMethodName,
+	v1.AgentService_UpdateAgentConfig_FullMethodName,
+	v1.AgentService_TriggerHardwareCollection_FullMethodName,
+	v1.AgentService_EnqueueInstructionBySelector_FullMethodName,
+	v1.AgentService_SubmitInstructionResult_FullMethodName,
+	v1.AgentService_ReapOrphanedAgents_FullMethodName,
+	v1.AgentService_SetThrottleMode_FullMethodName,
+}
+
+// agentMethods lists the RPCs agents call, which must carry the agent version
+// header; operator-facing RPCs of the AgentService are exempt
+var agentMethods = []string{
+	v1.AgentService_RegisterAgent_FullMethodName,
+	v1.AgentService_GetInstructions_FullMethodName,
+	v1.AgentService_SubmitInstructionResult_FullMethodName,
+	v1.AgentService_SubmitInstructionResultStream_FullMethodName,
+}
+
+// clusterIDMethods lists the RPCs whose id field is a cluster ID; other RPCs
+// are tagged by their cluster_id field, if any
+var clusterIDMethods = []string{
+	v1.ClusterService_GetCluster_FullMethodName,
+	v1.ClusterService_UpdateCluster_FullMethodName,
+	v1.ClusterService_DeleteCluster_FullMethodName,
+	v1.ClusterService_DrainCluster_FullMethodName,
+	v1.ClusterService_UncordonCluster_FullMethodName,
+	v1.ClusterService_MoveCluster_FullMethodName,
+}
+
 // startGRPCServer starts the gRPC server
 func (s *Server) startGRPCServer() error {
-	addr := fmt.Sprintf(":%d", s.config.GRPC.Port)
+	addr := listenAddress(s.config.GRPC.BindAddress, s.config.GRPC.Port)
 	listener, err := net.Listen("tcp", addr)
 	if err != nil {
 		return fmt.Errorf("failed to listen on %s: %w", addr, err)
 	}
 
+	// The gateway dials the address it reaches this server on, so its
+	// requests arrive from that host
+	gatewayHost, _, _ := net.SplitHostPort(s.grpcDialAddress())
+	idempotency := interceptor.NewIdempotency(s.config.GRPC.IdempotencyTTL, gatewayHost, mutatingMethods...)
+	agentVersion := interceptor.NewAgentVersion(agentMethods...)
+	var requestLog *log.Logger
+	if s.config.Logging.LogRequests {
+		requestLog = log.Default()
+	}
+	clusterTag := interceptor.NewClusterTag(requestLog, clusterIDMethods...)
+	timeouts := interceptor.NewTimeouts(s.config.GRPC.DefaultTimeout, s.config.GRPC.MethodTimeouts)
+
 	// Create gRPC server with options
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			clusterTag.UnaryServerInterceptor(),
+			timeouts.UnaryServerInterceptor(),
+			agentVersion.UnaryServerInterceptor(),
+			idempotency.UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(agentVersion.StreamServerInterceptor()),
+	)
 
 	// Register services
 	v1.RegisterClusterServiceServer(grpcServer, s.clusterService)
 	v1.RegisterAgentServiceServer(grpcServer, s.agentService)
 	v1.RegisterHealthServiceServer(grpcServer, s.healthService)
+	healthpb.RegisterHealthServer(grpcServer, s.grpcHealth.Server())
+
+	for _, method := range timeouts.UnknownMethods(grpcServer.GetServiceInfo()) {
+		log.Printf("Warning: grpc.method_timeouts names unknown method %q", method)
+	}
 
 	// Enable reflection for grpcurl and other tools
 	if s.config.GRPC.EnableReflection {
@@ -44,6 +112,31 @@ func (s *Server) startGRPCServer() error {
 	return nil
 }
 
+// listenAddress joins a bind host and port; an empty host binds all interfaces
+func listenAddress(bindAddress string, port int) string {
+	return net.JoinHostPort(bindAddress, strconv.Itoa(port))
+}
+
+// grpcDialAddress returns the address the gateway uses to reach the gRPC
+// server, preferring localhost unless gRPC is bound to a specific interface
+func (s *Server) grpcDialAddress() string {
+	host := s.config.GRPC.BindAddress
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return listenAddress(host, s.config.GRPC.Port)
+}
+
+// forceStopGRPCServer stops the gRPC server without waiting for in-flight
+// calls, for when shutdown has already waited as long as allowed
+func (s *Server) forceStopGRPCServer() {
+	if s.grpcServer != nil {
+		log.Println("Force stopping gRPC server...")
+		s.grpcServer.Stop()
+		log.Println("gRPC server stopped")
+	}
+}
+
 // stopGRPCServer gracefully stops the gRPC server
 func (s *Server) stopGRPCServer() {
 	if s.grpcServer != nil {
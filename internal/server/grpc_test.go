@@ -0,0 +1,68 @@
+package server
+
+import (
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+
+	"github.com/filanov/netctrl-server/internal/config"
+)
+
+// nonLoopbackIPv4 returns an IPv4 address of a non-loopback interface, if any
+func nonLoopbackIPv4() string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return ""
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+			return ipNet.IP.String()
+		}
+	}
+	return ""
+}
+
+var _ = Describe("gRPC listen address", func() {
+	It("should bind all interfaces when no bind address is set", func() {
+		Expect(listenAddress("", 9090)).To(Equal(":9090"))
+	})
+
+	It("should only be reachable on the bound interface", func() {
+		otherIP := nonLoopbackIPv4()
+		if otherIP == "" {
+			Skip("no non-loopback interface available")
+		}
+
+		listener, err := net.Listen("tcp", listenAddress("127.0.0.1", 0))
+		Expect(err).NotTo(HaveOccurred())
+		grpcServer := grpc.NewServer()
+		go func() {
+			_ = grpcServer.Serve(listener)
+		}()
+		defer grpcServer.Stop()
+
+		_, port, err := net.SplitHostPort(listener.Addr().String())
+		Expect(err).NotTo(HaveOccurred())
+
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort("127.0.0.1", port), time.Second)
+		Expect(err).NotTo(HaveOccurred())
+		conn.Close()
+
+		_, err = net.DialTimeout("tcp", net.JoinHostPort(otherIP, port), time.Second)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should dial localhost when gRPC binds all interfaces", func() {
+		s := &Server{config: &config.Config{GRPC: config.GRPCConfig{BindAddress: "0.0.0.0", Port: 9090}}}
+		Expect(s.grpcDialAddress()).To(Equal("localhost:9090"))
+	})
+
+	It("should dial the bound interface when gRPC binds a specific address", func() {
+		s := &Server{config: &config.Config{GRPC: config.GRPCConfig{BindAddress: "10.0.0.5", Port: 9090}}}
+		Expect(s.grpcDialAddress()).To(Equal("10.0.0.5:9090"))
+	})
+})
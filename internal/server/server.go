@@ -2,8 +2,10 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"sync"
+	"time"
 
 	"net/http"
 
@@ -12,8 +14,17 @@ import (
 	"github.com/filanov/netctrl-server/internal/config"
 	"github.com/filanov/netctrl-server/internal/service"
 	"github.com/filanov/netctrl-server/internal/storage"
+	"github.com/filanov/netctrl-server/internal/storage/cache"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
 )
 
+// DefaultClusterName is the name given to an auto-created default cluster
+const DefaultClusterName = "unassigned"
+
+// DefaultShutdownTimeout bounds waiting for in-flight instruction results
+// when the configuration does not
+const DefaultShutdownTimeout = 30 * time.Second
+
 // Server orchestrates the gRPC and HTTP gateway servers
 type Server struct {
 	config         *config.Config
@@ -21,25 +32,83 @@ type Server struct {
 	clusterService *service.ClusterService
 	agentService   *service.AgentService
 	healthService  *service.HealthService
+	grpcHealth     *service.GRPCHealthReporter
 	agentMonitor   *service.AgentMonitor
+	reconciler     *service.ConfigReconciler
+	events         *service.EventDispatcher
 
-	grpcServer    *grpc.Server
-	gatewayServer *http.Server
-	gatewayCancel context.CancelFunc
-	monitorCtx    context.Context
-	monitorCancel context.CancelFunc
+	grpcServer     *grpc.Server
+	gatewayServer  *http.Server
+	redirectServer *http.Server
+	gatewayCancel  context.CancelFunc
+	monitorCtx     context.Context
+	monitorCancel  context.CancelFunc
 }
 
 // New creates a new server instance
 func New(cfg *config.Config, store storage.Storage) *Server {
 	monitorCtx, monitorCancel := context.WithCancel(context.Background())
+
+	monitorOpts := []service.AgentMonitorOption{
+		service.WithEscalationThresholds(cfg.Agent.InactiveThreshold, cfg.Agent.StaleThreshold, cfg.Agent.ExpiredThreshold),
+		service.WithUpdateWorkers(cfg.Agent.MonitorUpdateWorkers),
+	}
+
+	// Replicas sharing a database elect a single monitor; single-process
+	// backends monitor unconditionally
+	if locker, ok := store.(storage.Locker); ok {
+		monitorOpts = append(monitorOpts, service.WithLeaderLock(locker))
+	}
+
+	// Server stats include the database pool when the backend has one
+	var agentOpts []service.AgentServiceOption
+	if stater, ok := store.(storage.PoolStater); ok {
+		agentOpts = append(agentOpts, service.WithPoolStats(stater))
+	}
+
+	// Fleet hardware summaries aggregate in the database when it can
+	if summarizer, ok := store.(storage.HardwareSummarizer); ok {
+		agentOpts = append(agentOpts, service.WithHardwareSummarizer(summarizer))
+	}
+
+	// Registration storms check cluster existence on every request
+	if ttl := cfg.Database.ClusterCacheTTL; ttl > 0 {
+		store = cache.New(store, ttl)
+	}
+
+	agentOpts = append(agentOpts,
+		service.WithDefaultClusterID(cfg.Agent.DefaultClusterID),
+		service.WithStrictIPUniqueness(cfg.Agent.StrictIPUniqueness),
+		service.WithGatewayProbeInterval(cfg.Agent.GatewayProbeInterval),
+		service.WithHealthCheckInterval(cfg.Agent.HealthCheckInterval),
+		service.WithNetworkInterfaceLimits(cfg.Agent.MaxNICs, cfg.Agent.MaxNetworkInterfacesBytes),
+		service.WithMaxClockSkew(cfg.Agent.MaxClockSkew),
+		service.WithMaxTotalAgents(cfg.Agent.MaxTotalAgents),
+		service.WithClusterChangePolicy(service.ClusterChangePolicy(cfg.Agent.ClusterChangePolicy)),
+		service.WithUnknownResultPolicy(service.UnknownResultPolicy(cfg.Agent.UnknownResultPolicy)),
+		service.WithMaxActivitySubscribers(cfg.Agent.MaxActivitySubscribers, service.StreamLimitPolicy(cfg.Agent.ActivitySubscriberPolicy)),
+		service.WithFleetReportSampleSize(cfg.Agent.FleetReportSampleSize),
+		service.WithStrictValidation(cfg.Validation.Strict),
+	)
+
+	// Lifecycle events are published in the background when configured
+	events := eventDispatcher(cfg)
+	if events != nil {
+		agentOpts = append(agentOpts, service.WithEventDispatcher(events))
+		monitorOpts = append(monitorOpts, service.WithMonitorEventDispatcher(events))
+	}
+
+	agentService := service.NewAgentService(store, agentOpts...)
 	return &Server{
 		config:         cfg,
 		storage:        store,
-		clusterService: service.NewClusterService(store),
-		agentService:   service.NewAgentService(store),
+		clusterService: service.NewClusterService(store, clusterOptions(cfg)...),
+		agentService:   agentService,
 		healthService:  service.NewHealthService(),
-		agentMonitor:   service.NewAgentMonitor(store),
+		grpcHealth:     service.NewGRPCHealthReporter(store),
+		agentMonitor:   service.NewAgentMonitor(store, monitorOpts...),
+		reconciler:     service.NewConfigReconciler(store),
+		events:         events,
 		monitorCtx:     monitorCtx,
 		monitorCancel:  monitorCancel,
 	}
@@ -50,9 +119,24 @@ func (s *Server) Start() error {
 	var wg sync.WaitGroup
 	errChan := make(chan error, 2)
 
+	s.logStartupSummary()
+
+	// Agents registering without a cluster land in the default cluster
+	if id := s.config.Agent.DefaultClusterID; id != "" {
+		if _, err := s.clusterService.EnsureCluster(s.monitorCtx, id, DefaultClusterName); err != nil {
+			return fmt.Errorf("failed to ensure default cluster: %w", err)
+		}
+	}
+
 	// Start agent monitor
 	go s.agentMonitor.Start(s.monitorCtx)
 
+	// Start desired vs applied network config reconciliation
+	go s.reconciler.Start(s.monitorCtx)
+
+	// Start storage reachability reporting for grpc.health.v1
+	go s.grpcHealth.Start(s.monitorCtx)
+
 	// Start gRPC server
 	wg.Add(1)
 	go func() {
@@ -62,13 +146,9 @@ func (s *Server) Start() error {
 		}
 	}()
 
-	// Wait a bit for gRPC server to start before starting gateway
-	// This ensures the gateway can connect to gRPC
-	// In production, you might want a more robust health check
+	// The gateway may start before the gRPC listener is up; it retries
+	// handler registration and reconnects once gRPC is reachable
 	log.Println("Waiting for gRPC server to be ready...")
-	// Simple sleep is acceptable here as gateway needs gRPC to be available
-	// Alternative: implement proper health check polling
-	// time.Sleep(500 * time.Millisecond)
 
 	// Start HTTP gateway server
 	wg.Add(1)
@@ -99,12 +179,87 @@ func (s *Server) Start() error {
 func (s *Server) Stop() {
 	log.Println("Shutting down servers...")
 
+	// Report NOT_SERVING so load balancers drain traffic, and give them the
+	// configured drain period to notice before listeners close
+	s.grpcHealth.Shutdown()
+	s.healthService.Drain()
+	if drain := s.config.Server.ShutdownDrain; drain > 0 {
+		log.Printf("Draining for %s before closing listeners...", drain)
+		time.Sleep(drain)
+	}
+
 	// Stop agent monitor
 	if s.monitorCancel != nil {
 		s.monitorCancel()
 	}
 
+	// Let in-flight instruction results finish writing before storage is closed
+	timeout := s.config.Server.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	drained := true
+	if err := s.agentService.DrainResults(ctx); err != nil {
+		log.Printf("Warning: instruction results still in progress after %s: %v", timeout, err)
+		drained = false
+	}
+
 	s.stopGatewayServer()
-	s.stopGRPCServer()
+	if drained {
+		s.stopGRPCServer()
+	} else {
+		s.forceStopGRPCServer()
+	}
+
+	// Publish events queued by the last requests and monitor cycle
+	if s.events != nil {
+		if err := s.events.Close(ctx); err != nil {
+			log.Printf("Warning: lifecycle events still unpublished after %s: %v", timeout, err)
+		}
+	}
 	log.Println("Servers stopped successfully")
 }
+
+// eventDispatcher starts the configured lifecycle event publisher, or
+// returns nil when events are not published
+func eventDispatcher(cfg *config.Config) *service.EventDispatcher {
+	var publisher service.EventPublisher
+	switch cfg.Events.Publisher {
+	case "log":
+		publisher = service.LogPublisher{}
+	case "webhook":
+		publisher = service.NewWebhookPublisher(cfg.Events.WebhookURL)
+	default:
+		return nil
+	}
+	return service.NewEventDispatcher(publisher, cfg.Events.BufferSize, cfg.Events.PublishTimeout)
+}
+
+// clusterOptions configures the cluster service. The default network config
+// template is applied to the default cluster, and in development also to
+// clusters created without a network config.
+func clusterOptions(cfg *config.Config) []service.ClusterServiceOption {
+	opts := []service.ClusterServiceOption{
+		service.WithCIDRChangePolicy(service.CIDRChangePolicy(cfg.Agent.CIDRChangePolicy)),
+		service.WithStrictClusterValidation(cfg.Validation.Strict),
+	}
+
+	// The configuration is validated on load, so this only fails for a
+	// config built in code
+	if idGen, err := service.NewIDGenerator(cfg.Cluster.IDScheme, cfg.Cluster.IDPrefix); err != nil {
+		log.Printf("Warning: keeping UUID cluster IDs: %v", err)
+	} else {
+		opts = append(opts, service.WithIDGenerator(idGen))
+	}
+
+	template := cfg.Agent.DefaultNetworkConfig
+	if template.CIDR == "" && template.Gateway == "" {
+		return opts
+	}
+	networkConfig := &v1.NetworkConfig{Cidr: template.CIDR, Gateway: template.Gateway}
+	return append(opts,
+		service.WithDefaultNetworkConfig(networkConfig, cfg.Server.Environment == "development"),
+	)
+}
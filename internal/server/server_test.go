@@ -0,0 +1,180 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/filanov/netctrl-server/internal/config"
+	"github.com/filanov/netctrl-server/internal/interceptor"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+var _ = Describe("Shutdown drain", func() {
+	It("should report NOT_SERVING while draining and keep serving until the drain ends", func() {
+		cfg := &config.Config{
+			Server: config.ServerConfig{ShutdownDrain: time.Second},
+			GRPC:   config.GRPCConfig{BindAddress: "127.0.0.1", Port: freePort()},
+		}
+		s := New(cfg, mock.New())
+		ctx := context.Background()
+
+		go func() {
+			defer GinkgoRecover()
+			Expect(s.startGRPCServer()).To(Succeed())
+		}()
+
+		conn, err := grpc.NewClient(s.grpcDialAddress(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		Expect(err).NotTo(HaveOccurred())
+		defer conn.Close()
+		healthClient := healthpb.NewHealthClient(conn)
+		readyClient := v1.NewHealthServiceClient(conn)
+
+		s.grpcHealth.CheckOnce(ctx)
+		Eventually(func() (healthpb.HealthCheckResponse_ServingStatus, error) {
+			resp, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{})
+			return resp.GetStatus(), err
+		}).Should(Equal(healthpb.HealthCheckResponse_SERVING))
+
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			s.Stop()
+		}()
+
+		Eventually(func() (healthpb.HealthCheckResponse_ServingStatus, error) {
+			resp, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{})
+			return resp.GetStatus(), err
+		}).Should(Equal(healthpb.HealthCheckResponse_NOT_SERVING))
+
+		ready, err := readyClient.Ready(ctx, &v1.ReadinessCheckRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ready.Status).To(Equal(v1.ReadinessStatus_READINESS_STATUS_NOT_READY))
+		Expect(stopped).NotTo(BeClosed())
+
+		Eventually(stopped, 5*time.Second).Should(BeClosed())
+	})
+})
+
+// blockingUpdateStorage holds agent updates until released
+type blockingUpdateStorage struct {
+	*mock.Storage
+
+	entered     chan struct{}
+	enteredOnce sync.Once
+	release     chan struct{}
+}
+
+func (s *blockingUpdateStorage) UpdateAgent(ctx context.Context, agent *v1.Agent) error {
+	s.enteredOnce.Do(func() { close(s.entered) })
+	<-s.release
+	return s.Storage.UpdateAgent(ctx, agent)
+}
+
+var _ = Describe("Shutdown with results in progress", func() {
+	It("should finish writing a result submission before stopping", func() {
+		store := &blockingUpdateStorage{
+			Storage: mock.New(),
+			entered: make(chan struct{}),
+			release: make(chan struct{}),
+		}
+		ctx := context.Background()
+		now := timestamppb.Now()
+		Expect(store.CreateCluster(ctx, &v1.Cluster{Id: "cluster-1", Name: "test", CreatedAt: now, UpdatedAt: now})).To(Succeed())
+		Expect(store.CreateAgent(ctx, &v1.Agent{Id: "agent-1", ClusterId: "cluster-1", CreatedAt: now, UpdatedAt: now})).To(Succeed())
+
+		cfg := &config.Config{
+			Server: config.ServerConfig{ShutdownTimeout: 5 * time.Second},
+			GRPC:   config.GRPCConfig{BindAddress: "127.0.0.1", Port: freePort()},
+		}
+		s := New(cfg, store)
+		go func() {
+			defer GinkgoRecover()
+			Expect(s.startGRPCServer()).To(Succeed())
+		}()
+
+		conn, err := grpc.NewClient(s.grpcDialAddress(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		Expect(err).NotTo(HaveOccurred())
+		defer conn.Close()
+		client := v1.NewAgentServiceClient(conn)
+		agentCtx := metadata.AppendToOutgoingContext(ctx, interceptor.AgentVersionHeader, "1.0.0")
+
+		submitted := make(chan *v1.SubmitInstructionResultResponse, 1)
+		go func() {
+			defer GinkgoRecover()
+			var resp *v1.SubmitInstructionResultResponse
+			Eventually(func() error {
+				r, err := client.SubmitInstructionResult(agentCtx, &v1.SubmitInstructionResultRequest{
+					AgentId:       "agent-1",
+					InstructionId: "instruction-1",
+					Result: &v1.InstructionResult{
+						InstructionType: v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK,
+						Result: &v1.InstructionResult_HealthCheck{
+							HealthCheck: &v1.HealthCheckResult{Healthy: true},
+						},
+					},
+				})
+				resp = r
+				return err
+			}).Should(Succeed())
+			submitted <- resp
+		}()
+		Eventually(store.entered, 5*time.Second).Should(BeClosed())
+
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			s.Stop()
+		}()
+		Consistently(stopped, 300*time.Millisecond).ShouldNot(BeClosed())
+
+		close(store.release)
+		Eventually(stopped, 5*time.Second).Should(BeClosed())
+
+		// The write landed before Stop returned, i.e. before the store would be closed
+		agent, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agent.LastHealthyAt).NotTo(BeNil())
+		Eventually(submitted).Should(Receive(HaveField("Success", BeTrue())))
+	})
+})
+
+var _ = Describe("Startup summary", func() {
+	It("should log the effective configuration in one line", func() {
+		var buf bytes.Buffer
+		DeferCleanup(log.SetOutput, log.Writer())
+		log.SetOutput(&buf)
+
+		cfg := &config.Config{
+			Server:   config.ServerConfig{Environment: "production"},
+			GRPC:     config.GRPCConfig{BindAddress: "0.0.0.0", Port: 9090, EnableReflection: true},
+			Gateway:  config.GatewayConfig{Port: 8080, TLS: config.GatewayTLSConfig{Enabled: true}},
+			Database: config.DatabaseConfig{Hybrid: true, ClusterCacheTTL: 5 * time.Second},
+		}
+		New(cfg, mock.New()).logStartupSummary()
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(lines).To(HaveLen(1))
+		Expect(lines[0]).To(ContainSubstring("environment=production"))
+		Expect(lines[0]).To(ContainSubstring("grpc_addr=0.0.0.0:9090"))
+		Expect(lines[0]).To(ContainSubstring("gateway_addr=:8080"))
+		Expect(lines[0]).To(ContainSubstring("storage=hybrid"))
+		Expect(lines[0]).To(ContainSubstring("cluster_cache=5s"))
+		Expect(lines[0]).To(ContainSubstring("tls=on"))
+		Expect(lines[0]).To(ContainSubstring("reflection=true"))
+		Expect(lines[0]).To(ContainSubstring("auth=off"))
+		Expect(lines[0]).To(ContainSubstring("monitor_interval=30s"))
+	})
+})
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/filanov/netctrl-server/internal/config"
+	"github.com/filanov/netctrl-server/internal/service"
+)
+
+// logStartupSummary logs the effective configuration as a single line of
+// key=value pairs, so misconfiguration is visible at a glance
+func (s *Server) logStartupSummary() {
+	log.Printf("Startup config: %s", startupSummary(s.config))
+}
+
+// startupSummary renders the settings that most often explain unexpected
+// behavior. The server has no authentication, so auth is always off.
+func startupSummary(cfg *config.Config) string {
+	storageBackend := "postgres"
+	if cfg.Database.Hybrid {
+		storageBackend = "hybrid"
+	}
+
+	clusterCache := "off"
+	if cfg.Database.ClusterCacheTTL > 0 {
+		clusterCache = cfg.Database.ClusterCacheTTL.String()
+	}
+
+	tls := "off"
+	if cfg.Gateway.TLS.Enabled {
+		tls = "on"
+	}
+
+	fields := []string{
+		"environment=" + cfg.Server.Environment,
+		"grpc_addr=" + listenAddress(cfg.GRPC.BindAddress, cfg.GRPC.Port),
+		"gateway_addr=" + listenAddress(cfg.Gateway.BindAddress, cfg.Gateway.Port),
+		"storage=" + storageBackend,
+		"cluster_cache=" + clusterCache,
+		"tls=" + tls,
+		fmt.Sprintf("reflection=%t", cfg.GRPC.EnableReflection),
+		fmt.Sprintf("cors=%t", cfg.Gateway.EnableCORS),
+		"auth=off",
+		"events=" + cfg.Events.Publisher,
+		fmt.Sprintf("strict_validation=%t", cfg.Validation.Strict),
+		"monitor_interval=" + service.MonitorCheckInterval.String(),
+		"reconcile_interval=" + service.ReconcileInterval.String(),
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"sync"
+
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// ActivityBufferSize is the number of events buffered per tail subscriber;
+// events published while a slow subscriber's buffer is full are dropped
+const ActivityBufferSize = 64
+
+// StreamLimitPolicy decides what happens to a new activity tail once the
+// subscriber limit is reached
+type StreamLimitPolicy string
+
+const (
+	// StreamLimitReject refuses the new subscriber with ResourceExhausted
+	StreamLimitReject StreamLimitPolicy = "reject"
+
+	// StreamLimitEvictSlowest ends the subscriber with the most undelivered
+	// events to make room for the new one
+	StreamLimitEvictSlowest StreamLimitPolicy = "evict_slowest"
+)
+
+// errActivityLimit is returned by subscribe when the subscriber limit is
+// reached under StreamLimitReject
+var errActivityLimit = errors.New("activity subscriber limit reached")
+
+// activitySubscriber is one tail of an agent's activity. evicted is closed
+// when the subscriber is dropped to make room for another.
+type activitySubscriber struct {
+	events  chan *v1.ActivityEvent
+	evicted chan struct{}
+}
+
+// activityHub fans out agent activity events to the subscribers tailing
+// each agent. Publishing never blocks the poll or result path.
+type activityHub struct {
+	mu          sync.Mutex
+	subscribers map[string]map[*activitySubscriber]struct{}
+	count       int
+
+	// maxSubscribers bounds subscribers across all agents; 0 means unlimited
+	maxSubscribers int
+	limitPolicy    StreamLimitPolicy
+}
+
+func newActivityHub() *activityHub {
+	return &activityHub{
+		subscribers: make(map[string]map[*activitySubscriber]struct{}),
+		limitPolicy: StreamLimitReject,
+	}
+}
+
+// subscribe registers a subscriber for an agent's events and returns it
+// along with a function that unsubscribes it. At the subscriber limit it
+// fails with errActivityLimit or evicts the slowest subscriber, depending on
+// the limit policy.
+func (h *activityHub) subscribe(agentID string) (*activitySubscriber, func(), error) {
+	sub := &activitySubscriber{
+		events:  make(chan *v1.ActivityEvent, ActivityBufferSize),
+		evicted: make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	if h.maxSubscribers > 0 && h.count >= h.maxSubscribers {
+		if h.limitPolicy != StreamLimitEvictSlowest {
+			h.mu.Unlock()
+			return nil, nil, errActivityLimit
+		}
+		h.evictSlowest()
+	}
+	if h.subscribers[agentID] == nil {
+		h.subscribers[agentID] = make(map[*activitySubscriber]struct{})
+	}
+	h.subscribers[agentID][sub] = struct{}{}
+	h.count++
+	h.mu.Unlock()
+
+	return sub, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(agentID, sub)
+	}, nil
+}
+
+// evictSlowest drops the subscriber with the most buffered events. Callers
+// must hold mu.
+func (h *activityHub) evictSlowest() {
+	var slowest *activitySubscriber
+	var slowestAgent string
+	for agentID, subs := range h.subscribers {
+		for sub := range subs {
+			if slowest == nil || len(sub.events) > len(slowest.events) {
+				slowest, slowestAgent = sub, agentID
+			}
+		}
+	}
+	if slowest != nil {
+		h.remove(slowestAgent, slowest)
+		close(slowest.evicted)
+	}
+}
+
+// remove unregisters a subscriber if it is still registered. Callers must
+// hold mu.
+func (h *activityHub) remove(agentID string, sub *activitySubscriber) {
+	if _, ok := h.subscribers[agentID][sub]; !ok {
+		return
+	}
+	delete(h.subscribers[agentID], sub)
+	if len(h.subscribers[agentID]) == 0 {
+		delete(h.subscribers, agentID)
+	}
+	h.count--
+}
+
+// publish delivers an event to every subscriber tailing its agent
+func (h *activityHub) publish(event *v1.ActivityEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers[event.AgentId] {
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package service_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// activityStream is a server stream that forwards sent events to a channel
+type activityStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *v1.ActivityEvent
+}
+
+func (s *activityStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *activityStream) Send(event *v1.ActivityEvent) error {
+	s.events <- event
+	return nil
+}
+
+var _ = Describe("TailAgentActivity", func() {
+	var (
+		agentService *service.AgentService
+		store        *mock.Storage
+		ctx          context.Context
+		cancel       context.CancelFunc
+		stream       *activityStream
+		done         chan error
+		tailing      bool
+	)
+
+	// tail starts tailing an agent in the background
+	tail := func(agentID string) {
+		svc, s, result := agentService, stream, done
+		tailing = true
+		go func() {
+			defer GinkgoRecover()
+			result <- svc.TailAgentActivity(&v1.TailAgentActivityRequest{AgentId: agentID}, s)
+		}()
+	}
+
+	// nextEvent polls on behalf of the agent until the tail delivers an event,
+	// since the subscription is set up asynchronously
+	nextEvent := func() *v1.ActivityEvent {
+		var event *v1.ActivityEvent
+		Eventually(func() bool {
+			_, err := agentService.GetInstructions(context.Background(), &v1.GetInstructionsRequest{AgentId: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			select {
+			case event = <-stream.events:
+				return true
+			default:
+				return false
+			}
+		}).Should(BeTrue())
+		return event
+	}
+
+	BeforeEach(func() {
+		store = mock.New()
+		agentService = service.NewAgentService(store)
+		clusterService := service.NewClusterService(store)
+
+		ctx, cancel = context.WithCancel(context.Background())
+		stream = &activityStream{ctx: ctx, events: make(chan *v1.ActivityEvent, 64)}
+		done = make(chan error, 1)
+		tailing = false
+
+		cluster, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: cluster.Cluster.Id})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	// Every tail must stop once the client cancels
+	AfterEach(func() {
+		cancel()
+		if tailing {
+			Eventually(done).Should(Receive(BeNil()))
+		}
+	})
+
+	It("should emit a poll event when the agent polls", func() {
+		tail("agent-1")
+
+		event := nextEvent()
+		Expect(event.AgentId).To(Equal("agent-1"))
+		Expect(event.Type).To(Equal(v1.ActivityEventType_ACTIVITY_EVENT_TYPE_POLL))
+		Expect(event.Timestamp).NotTo(BeNil())
+	})
+
+	It("should emit an event when the agent submits a result", func() {
+		tail("agent-1")
+		nextEvent()
+
+		_, err := agentService.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+			AgentId:       "agent-1",
+			InstructionId: "instruction-1",
+			Result: &v1.InstructionResult{
+				InstructionType: v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK,
+				Result:          &v1.InstructionResult_HealthCheck{HealthCheck: &v1.HealthCheckResult{Healthy: true}},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(stream.events).Should(Receive(And(
+			HaveField("Type", v1.ActivityEventType_ACTIVITY_EVENT_TYPE_RESULT_SUBMITTED),
+			HaveField("InstructionId", "instruction-1"),
+		)))
+	})
+
+	It("should return NotFound for an unknown agent", func() {
+		err := agentService.TailAgentActivity(&v1.TailAgentActivityRequest{AgentId: "missing"}, stream)
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+
+	Context("with a subscriber limit", func() {
+		// secondTail starts another tail on its own stream and returns the
+		// channel its result is sent to
+		secondTail := func() (*activityStream, chan error) {
+			second := &activityStream{ctx: ctx, events: make(chan *v1.ActivityEvent, 64)}
+			result := make(chan error, 1)
+			svc := agentService
+			go func() {
+				defer GinkgoRecover()
+				result <- svc.TailAgentActivity(&v1.TailAgentActivityRequest{AgentId: "agent-1"}, second)
+			}()
+			return second, result
+		}
+
+		It("should reject subscribers beyond the limit", func() {
+			agentService = service.NewAgentService(store, service.WithMaxActivitySubscribers(1, service.StreamLimitReject))
+			tail("agent-1")
+			nextEvent()
+
+			_, result := secondTail()
+			var err error
+			Eventually(result).Should(Receive(&err))
+			Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+
+			// The first subscriber keeps receiving events
+			nextEvent()
+		})
+
+		It("should evict the slowest subscriber to make room when configured", func() {
+			agentService = service.NewAgentService(store, service.WithMaxActivitySubscribers(1, service.StreamLimitEvictSlowest))
+			tail("agent-1")
+			nextEvent()
+
+			second, result := secondTail()
+			DeferCleanup(func() {
+				Eventually(result).Should(Receive(BeNil()))
+			})
+
+			var err error
+			Eventually(done).Should(Receive(&err))
+			Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+			tailing = false
+
+			// The new subscriber took the evicted one's place
+			stream = second
+			nextEvent()
+		})
+	})
+})
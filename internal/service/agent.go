@@ -2,33 +2,318 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/filanov/netctrl-server/internal/storage"
 	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
 )
 
+const (
+	// DefaultGatewayProbeInterval is how often agents are asked to probe their cluster gateway
+	DefaultGatewayProbeInterval = 5 * time.Minute
+
+	// DefaultHealthCheckInterval is how often spine agents are asked to report health
+	DefaultHealthCheckInterval = 5 * time.Minute
+
+	// DefaultMaxNICs is the maximum number of NICs accepted in a hardware collection result
+	DefaultMaxNICs = 64
+
+	// DefaultMaxNetworkInterfacesBytes is the maximum serialized size of reported NIC data
+	DefaultMaxNetworkInterfacesBytes = 1 << 20
+
+	// MaxAgentMetrics is the maximum number of metrics accepted in a poll
+	MaxAgentMetrics = 64
+
+	// MaxAgentStatusIDs is the maximum number of agents in one GetAgentStatuses call
+	MaxAgentStatusIDs = 1000
+
+	// DefaultMaxClockSkew is how far agent-supplied timestamps may differ from server time
+	DefaultMaxClockSkew = 5 * time.Minute
+
+	// DefaultThrottledPollIntervalSeconds is the poll interval returned while throttled
+	DefaultThrottledPollIntervalSeconds = 300
+
+	// BackoffPollIntervalCapSeconds bounds how far a pending retry backoff
+	// stretches an agent's poll interval, so operator requests still arrive promptly
+	BackoffPollIntervalCapSeconds = 300
+
+	// TransientFailureBackoff delays retrying instructions that timed out or failed internally
+	TransientFailureBackoff = time.Minute
+
+	// PermissionFailureBackoff delays retrying instructions the agent lacked permission to run
+	PermissionFailureBackoff = 30 * time.Minute
+
+	// NotSupportedFailureBackoff delays retrying instructions the agent cannot run at all
+	NotSupportedFailureBackoff = 24 * time.Hour
+
+	// PartialCollectionBackoff delays retrying a hardware collection that
+	// failed on some NICs, which is typically a device permission problem
+	PartialCollectionBackoff = 30 * time.Minute
+)
+
+// ClusterChangePolicy decides how RegisterAgent treats a registered agent
+// re-registering into a different cluster
+type ClusterChangePolicy string
+
+const (
+	// ClusterChangeAllow moves the agent to the requested cluster
+	ClusterChangeAllow ClusterChangePolicy = "allow"
+
+	// ClusterChangeReject fails the registration with FailedPrecondition
+	ClusterChangeReject ClusterChangePolicy = "reject"
+
+	// ClusterChangeRequireReassign fails the registration like
+	// ClusterChangeReject, telling the agent to be reassigned first. There is
+	// no reassign RPC; reassigning means unregistering the agent so it can
+	// register into the new cluster.
+	ClusterChangeRequireReassign ClusterChangePolicy = "require_reassign"
+)
+
+// UnknownResultPolicy decides how SubmitInstructionResult treats results of
+// instruction types the server does not recognize
+type UnknownResultPolicy string
+
+const (
+	// UnknownResultIgnore logs the result and reports success
+	UnknownResultIgnore UnknownResultPolicy = "ignore"
+
+	// UnknownResultReject fails the submission so the agent knows the result
+	// was not understood
+	UnknownResultReject UnknownResultPolicy = "reject"
+
+	// UnknownResultStoreRaw keeps the raw result on the agent for later inspection
+	UnknownResultStoreRaw UnknownResultPolicy = "store_raw"
+)
+
+// MaxUnrecognizedResults caps the raw results kept per agent under
+// UnknownResultStoreRaw; the oldest are dropped first
+const MaxUnrecognizedResults = 10
+
 // AgentService implements the AgentService gRPC service
 type AgentService struct {
 	v1.UnimplementedAgentServiceServer
-	storage storage.Storage
+	storage              storage.Storage
+	defaultClusterID     string
+	strictIPUniqueness   bool
+	gatewayProbeInterval time.Duration
+	healthCheckInterval  time.Duration
+	maxNICs              int
+	maxNICBytes          int
+	maxClockSkew         time.Duration
+	maxTotalAgents       int
+	clusterChangePolicy  ClusterChangePolicy
+	unknownResultPolicy  UnknownResultPolicy
+
+	// Fleet-wide throttle mode, toggled at runtime via SetThrottleMode
+	throttleMu            sync.RWMutex
+	throttled             bool
+	throttledPollInterval int32
+
+	// Observed poll intervals
+	pollStats *pollStats
+
+	// clock times polls, timestamps and backoffs
+	clock Clock
+
+	// Subscribers tailing agent activity
+	activity *activityHub
+
+	// In-flight result submissions, drained on shutdown
+	results resultTracker
+
+	// Server stats sources; poolStats is nil for backends without a pool
+	startedAt time.Time
+	poolStats storage.PoolStater
+
+	// Aggregates NIC inventory in the backend; nil falls back to ListAgents
+	hardwareSummarizer storage.HardwareSummarizer
+
+	// Publishes agent lifecycle events downstream; nil emits nothing
+	events *EventDispatcher
+
+	// fleetReportSampleSize bounds the sample agent IDs per report category
+	fleetReportSampleSize int
+
+	// strictValidation rejects registrations with an invalid IP address
+	// instead of accepting them with a warning
+	strictValidation bool
+}
+
+// AgentServiceOption configures optional AgentService behavior
+type AgentServiceOption func(*AgentService)
+
+// WithDefaultClusterID assigns agents registering without a cluster ID to the
+// given cluster instead of rejecting them
+func WithDefaultClusterID(clusterID string) AgentServiceOption {
+	return func(s *AgentService) {
+		s.defaultClusterID = clusterID
+	}
+}
+
+// WithStrictIPUniqueness rejects registrations whose IP address collides with
+// another active agent in the same cluster instead of only logging a warning
+func WithStrictIPUniqueness(strict bool) AgentServiceOption {
+	return func(s *AgentService) {
+		s.strictIPUniqueness = strict
+	}
+}
+
+// WithGatewayProbeInterval sets how often agents probe their cluster gateway
+func WithGatewayProbeInterval(interval time.Duration) AgentServiceOption {
+	return func(s *AgentService) {
+		s.gatewayProbeInterval = interval
+	}
+}
+
+// WithHealthCheckInterval sets how often spine agents report health
+func WithHealthCheckInterval(interval time.Duration) AgentServiceOption {
+	return func(s *AgentService) {
+		s.healthCheckInterval = interval
+	}
+}
+
+// WithNetworkInterfaceLimits caps the number of NICs and the serialized size
+// of NIC data accepted from a hardware collection result
+func WithNetworkInterfaceLimits(maxNICs, maxBytes int) AgentServiceOption {
+	return func(s *AgentService) {
+		s.maxNICs = maxNICs
+		s.maxNICBytes = maxBytes
+	}
+}
+
+// WithMaxClockSkew sets how far agent-supplied timestamps may differ from
+// server time before the request is rejected
+func WithMaxClockSkew(skew time.Duration) AgentServiceOption {
+	return func(s *AgentService) {
+		s.maxClockSkew = skew
+	}
+}
+
+// WithMaxTotalAgents caps the number of agents across all clusters; new
+// registrations beyond the cap are rejected. Zero disables the cap.
+func WithMaxTotalAgents(max int) AgentServiceOption {
+	return func(s *AgentService) {
+		s.maxTotalAgents = max
+	}
+}
+
+// WithClusterChangePolicy sets how re-registrations into a different
+// cluster are handled; the default is ClusterChangeAllow
+func WithClusterChangePolicy(policy ClusterChangePolicy) AgentServiceOption {
+	return func(s *AgentService) {
+		s.clusterChangePolicy = policy
+	}
+}
+
+// WithUnknownResultPolicy sets how results of unrecognized instruction types
+// are handled; the default is UnknownResultIgnore
+func WithUnknownResultPolicy(policy UnknownResultPolicy) AgentServiceOption {
+	return func(s *AgentService) {
+		s.unknownResultPolicy = policy
+	}
+}
+
+// WithMaxActivitySubscribers caps concurrent TailAgentActivity streams across
+// all agents, each of which holds an event buffer; 0 means unlimited. policy
+// decides whether a subscriber beyond the cap is rejected or makes room by
+// evicting the slowest subscriber.
+func WithMaxActivitySubscribers(max int, policy StreamLimitPolicy) AgentServiceOption {
+	return func(s *AgentService) {
+		s.activity.maxSubscribers = max
+		s.activity.limitPolicy = policy
+	}
+}
+
+// WithClock replaces the clock the service reads time from
+func WithClock(clock Clock) AgentServiceOption {
+	return func(s *AgentService) {
+		s.clock = clock
+	}
+}
+
+// WithPoolStats reports the given connection pool in GetServerStats
+func WithPoolStats(stater storage.PoolStater) AgentServiceOption {
+	return func(s *AgentService) {
+		s.poolStats = stater
+	}
+}
+
+// WithHardwareSummarizer aggregates GetFleetHardwareSummary in the given
+// backend instead of loading every agent
+func WithHardwareSummarizer(summarizer storage.HardwareSummarizer) AgentServiceOption {
+	return func(s *AgentService) {
+		s.hardwareSummarizer = summarizer
+	}
+}
+
+// WithEventDispatcher publishes agent register, unregister and status
+// change events through the given dispatcher
+func WithEventDispatcher(dispatcher *EventDispatcher) AgentServiceOption {
+	return func(s *AgentService) {
+		s.events = dispatcher
+	}
+}
+
+// WithFleetReportSampleSize sets the default and maximum number of sample
+// agent IDs per fleet report category. Values below 1 keep the default.
+func WithFleetReportSampleSize(size int) AgentServiceOption {
+	return func(s *AgentService) {
+		if size > 0 {
+			s.fleetReportSampleSize = size
+		}
+	}
+}
+
+// WithStrictValidation rejects registrations reporting an invalid IP
+// address; otherwise they are accepted with a logged warning
+func WithStrictValidation(strict bool) AgentServiceOption {
+	return func(s *AgentService) {
+		s.strictValidation = strict
+	}
 }
 
 // NewAgentService creates a new agent service
-func NewAgentService(store storage.Storage) *AgentService {
-	return &AgentService{
-		storage: store,
+func NewAgentService(store storage.Storage, opts ...AgentServiceOption) *AgentService {
+	s := &AgentService{
+		storage:               store,
+		gatewayProbeInterval:  DefaultGatewayProbeInterval,
+		healthCheckInterval:   DefaultHealthCheckInterval,
+		maxNICs:               DefaultMaxNICs,
+		maxNICBytes:           DefaultMaxNetworkInterfacesBytes,
+		maxClockSkew:          DefaultMaxClockSkew,
+		clusterChangePolicy:   ClusterChangeAllow,
+		unknownResultPolicy:   UnknownResultIgnore,
+		pollStats:             newPollStats(),
+		clock:                 realClock{},
+		activity:              newActivityHub(),
+		fleetReportSampleSize: FleetReportSampleSize,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	s.startedAt = s.clock.Now()
+	return s
 }
 
 // RegisterAgent registers or updates an agent to a cluster
 func (s *AgentService) RegisterAgent(ctx context.Context, req *v1.RegisterAgentRequest) (*v1.RegisterAgentResponse, error) {
+	if req.ClusterId == "" && s.defaultClusterID != "" {
+		req.ClusterId = s.defaultClusterID
+	}
+
 	if err := s.validateRegisterRequest(req); err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
@@ -42,62 +327,139 @@ func (s *AgentService) RegisterAgent(ctx context.Context, req *v1.RegisterAgentR
 		return nil, status.Error(codes.NotFound, fmt.Sprintf("cluster %s not found", req.ClusterId))
 	}
 
-	now := timestamppb.Now()
+	if err := s.checkIPUniqueness(ctx, req); err != nil {
+		return nil, err
+	}
+
+	now := timestamppb.New(s.clock.Now())
+	source := registrationSource(ctx, now)
 
 	// Check if agent already exists
 	existingAgent, err := s.storage.GetAgent(ctx, req.Id)
 	if err == nil {
-		// Agent exists, update it
-		existingAgent.ClusterId = req.ClusterId
-		existingAgent.Hostname = req.Hostname
-		existingAgent.IpAddress = req.IpAddress
-		existingAgent.Version = req.Version
-		existingAgent.Status = v1.AgentStatus_AGENT_STATUS_ACTIVE
-		existingAgent.LastSeen = now
-		existingAgent.UpdatedAt = now
+		if existingAgent.ClusterId != req.ClusterId {
+			switch s.clusterChangePolicy {
+			case ClusterChangeReject:
+				return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf(
+					"agent %s is registered to cluster %s and may not re-register into cluster %s",
+					req.Id, existingAgent.ClusterId, req.ClusterId))
+			case ClusterChangeRequireReassign:
+				return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf(
+					"agent %s is registered to cluster %s; reassign it by unregistering it before registering into cluster %s",
+					req.Id, existingAgent.ClusterId, req.ClusterId))
+			}
+		}
+		if previous := existingAgent.LastRegistration; previous != nil && sourceHost(previous) != sourceHost(source) {
+			log.Printf("Warning: agent %s re-registered from %s, previously from %s",
+				req.Id, sourceHost(source), sourceHost(previous))
+		}
 
-		if err := s.storage.UpdateAgent(ctx, existingAgent); err != nil {
-			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to update agent: %v", err))
+		// Agent exists, update it
+		var previousStatus v1.AgentStatus
+		if _, err := s.updateAgent(ctx, existingAgent, func(agent *v1.Agent) bool {
+			agent.ClusterId = req.ClusterId
+			agent.Hostname = req.Hostname
+			agent.IpAddress = req.IpAddress
+			agent.Version = req.Version
+			agent.Role = req.Role
+			agent.Group = req.Group
+			previousStatus = agent.Status
+			agent.Status = v1.AgentStatus_AGENT_STATUS_ACTIVE
+			agent.LastSeen = now
+			agent.UpdatedAt = now
+			agent.LastRegistration = source
+			if agent.InstallMetadata == nil {
+				agent.InstallMetadata = installMetadata(req, now)
+			}
+			return true
+		}); err != nil {
+			return nil, storageWriteError("failed to update agent", err)
 		}
 
 		log.Printf("Agent re-registered: id=%s, cluster=%s, hostname=%s, ip=%s",
 			existingAgent.Id, existingAgent.ClusterId, existingAgent.Hostname, existingAgent.IpAddress)
+		s.emitLifecycle(LifecycleEvent{
+			Type:           LifecycleAgentRegistered,
+			AgentID:        existingAgent.Id,
+			ClusterID:      existingAgent.ClusterId,
+			PreviousStatus: previousStatus,
+			Status:         existingAgent.Status,
+		})
 
 		return &v1.RegisterAgentResponse{
 			Agent: existingAgent,
 		}, nil
 	}
 
-	// Agent doesn't exist, create new one
+	// Agent doesn't exist, create new one unless the fleet is at its cap
+	if err := s.checkTotalAgents(ctx); err != nil {
+		return nil, err
+	}
+
 	agent := &v1.Agent{
 		Id:        req.Id,
 		ClusterId: req.ClusterId,
 		Hostname:  req.Hostname,
 		IpAddress: req.IpAddress,
 		Version:   req.Version,
+		Role:      req.Role,
+		Group:     req.Group,
 		Status:    v1.AgentStatus_AGENT_STATUS_ACTIVE,
 		LastSeen:  now,
 		CreatedAt: now,
 		UpdatedAt: now,
+
+		LastRegistration: source,
+		InstallMetadata:  installMetadata(req, now),
 	}
 
 	if err := s.storage.CreateAgent(ctx, agent); err != nil {
-		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to create agent: %v", err))
+		return nil, storageWriteError("failed to create agent", err)
 	}
 
 	log.Printf("Agent registered: id=%s, cluster=%s, hostname=%s, ip=%s",
 		agent.Id, agent.ClusterId, agent.Hostname, agent.IpAddress)
+	s.emitLifecycle(LifecycleEvent{
+		Type:      LifecycleAgentRegistered,
+		AgentID:   agent.Id,
+		ClusterID: agent.ClusterId,
+		Status:    agent.Status,
+	})
 
 	return &v1.RegisterAgentResponse{
 		Agent: agent,
 	}, nil
 }
 
+// emitLifecycle queues a lifecycle event stamped with the current time
+func (s *AgentService) emitLifecycle(event LifecycleEvent) {
+	event.Time = s.clock.Now()
+	s.events.emit(event)
+}
+
+// installMetadata returns the install metadata supplied with a registration,
+// stamped with the server time, or nil if none was supplied
+func installMetadata(req *v1.RegisterAgentRequest, now *timestamppb.Timestamp) *v1.InstallMetadata {
+	supplied := req.InstallMetadata
+	if supplied == nil {
+		return nil
+	}
+	return &v1.InstallMetadata{
+		Method:            supplied.Method,
+		ProvisioningJobId: supplied.ProvisioningJobId,
+		InstalledBy:       supplied.InstalledBy,
+		RecordedAt:        now,
+	}
+}
+
 // GetAgent retrieves an agent by ID
 func (s *AgentService) GetAgent(ctx context.Context, req *v1.GetAgentRequest) (*v1.GetAgentResponse, error) {
 	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "agent ID is required")
 	}
+	if err := validateFieldMask(req.ReadMask, &v1.Agent{}); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
 	agent, err := s.storage.GetAgent(ctx, req.Id)
 	if err != nil {
@@ -105,174 +467,1436 @@ func (s *AgentService) GetAgent(ctx context.Context, req *v1.GetAgentRequest) (*
 	}
 
 	return &v1.GetAgentResponse{
-		Agent: agent,
+		Agent: applyFieldMask(agent, req.ReadMask),
 	}, nil
 }
 
-// ListAgents lists all agents, optionally filtered by cluster
+// ListAgents lists all agents, optionally filtered by cluster, group and port state
 func (s *AgentService) ListAgents(ctx context.Context, req *v1.ListAgentsRequest) (*v1.ListAgentsResponse, error) {
-	agents, err := s.storage.ListAgents(ctx, req.ClusterId)
+	if err := validateFieldMask(req.ReadMask, &v1.Agent{}); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.UnhealthyForSeconds < 0 {
+		return nil, status.Error(codes.InvalidArgument, "unhealthy_for_seconds must not be negative")
+	}
+	if req.RequireCluster && req.ClusterId != "" {
+		exists, err := s.storage.ClusterExists(ctx, req.ClusterId)
+		if err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to check cluster: %v", err))
+		}
+		if !exists {
+			return nil, status.Error(codes.NotFound, fmt.Sprintf("cluster not found: %s", req.ClusterId))
+		}
+	}
+
+	// Filters and pagination run in the backend; paginated or not, agents
+	// come back in (created_at, id) order
+	query := storage.AgentQuery{
+		ClusterID:     req.ClusterId,
+		Group:         req.Group,
+		DownPortsOnly: req.DownPortsOnly,
+	}
+	if req.UnhealthyForSeconds > 0 {
+		query.UnhealthyBefore = s.clock.Now().Add(-time.Duration(req.UnhealthyForSeconds) * time.Second)
+	}
+
+	paginated := req.PageSize != 0 || req.PageToken != ""
+	var size int
+	if paginated {
+		var err error
+		if size, err = pageSize(req.PageSize); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if err := agentPageQuery(&query, req.PageToken); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		// One extra agent tells whether another page follows
+		query.Limit = size + 1
+	}
+
+	agents, err := s.storage.ListAgentsPage(ctx, query)
 	if err != nil {
 		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to list agents: %v", err))
 	}
 
+	var nextPageToken string
+	if paginated && len(agents) > size {
+		agents = agents[:size]
+		nextPageToken = encodeAgentCursor(agents[size-1])
+	}
+
+	for i, agent := range agents {
+		agents[i] = applyFieldMask(agent, req.ReadMask)
+	}
+
 	return &v1.ListAgentsResponse{
-		Agents: agents,
+		Agents:        agents,
+		NextPageToken: nextPageToken,
 	}, nil
 }
 
-// UnregisterAgent removes an agent
+// GetAgentStatuses returns the statuses of the given agents without loading
+// the agents themselves; unknown IDs are omitted
+func (s *AgentService) GetAgentStatuses(ctx context.Context, req *v1.GetAgentStatusesRequest) (*v1.GetAgentStatusesResponse, error) {
+	if len(req.Ids) > MaxAgentStatusIDs {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("at most %d agent IDs may be requested, got %d", MaxAgentStatusIDs, len(req.Ids)))
+	}
+	if len(req.Ids) == 0 {
+		return &v1.GetAgentStatusesResponse{Statuses: map[string]v1.AgentStatus{}}, nil
+	}
+
+	statuses, err := s.storage.GetAgentStatuses(ctx, req.Ids)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to get agent statuses: %v", err))
+	}
+
+	return &v1.GetAgentStatusesResponse{Statuses: statuses}, nil
+}
+
+// UnregisterAgent removes an agent. An idempotent request for an agent that
+// is already gone succeeds, so a client retrying after a timeout does not see
+// NotFound for a delete that went through.
 func (s *AgentService) UnregisterAgent(ctx context.Context, req *v1.UnregisterAgentRequest) (*v1.UnregisterAgentResponse, error) {
 	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "agent ID is required")
 	}
 
 	if err := s.storage.DeleteAgent(ctx, req.Id); err != nil {
-		return nil, status.Error(codes.NotFound, fmt.Sprintf("agent not found: %s", req.Id))
+		if !req.Idempotent {
+			return nil, status.Error(codes.NotFound, fmt.Sprintf("agent not found: %s", req.Id))
+		}
+		// The delete may have failed for a reason other than the agent being
+		// absent; only report success once the agent is confirmed gone.
+		if _, getErr := s.storage.GetAgent(ctx, req.Id); getErr == nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to unregister agent: %v", err))
+		}
+	} else {
+		s.emitLifecycle(LifecycleEvent{Type: LifecycleAgentUnregistered, AgentID: req.Id})
 	}
+	s.pollStats.forget(req.Id)
 
 	return &v1.UnregisterAgentResponse{
 		Success: true,
 	}, nil
 }
 
-// GetInstructions polls for pending instructions and updates agent heartbeat
-func (s *AgentService) GetInstructions(ctx context.Context, req *v1.GetInstructionsRequest) (*v1.GetInstructionsResponse, error) {
-	if req.AgentId == "" {
+// DecommissionAgent marks an agent for decommission
+func (s *AgentService) DecommissionAgent(ctx context.Context, req *v1.DecommissionAgentRequest) (*v1.DecommissionAgentResponse, error) {
+	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "agent ID is required")
 	}
 
-	// Verify agent exists and update last_seen (implicit heartbeat)
-	agent, err := s.storage.GetAgent(ctx, req.AgentId)
+	agent, err := s.storage.GetAgent(ctx, req.Id)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, fmt.Sprintf("agent not found: %s", req.AgentId))
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("agent not found: %s", req.Id))
 	}
 
-	// Update agent's last_seen timestamp and set status to active
-	now := timestamppb.Now()
-	agent.LastSeen = now
-	agent.UpdatedAt = now
-	agent.Status = v1.AgentStatus_AGENT_STATUS_ACTIVE
-
-	if err := s.storage.UpdateAgent(ctx, agent); err != nil {
-		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to update agent heartbeat: %v", err))
+	marked, err := s.updateAgent(ctx, agent, func(agent *v1.Agent) bool {
+		if agent.Decommissioning {
+			return false
+		}
+		agent.Decommissioning = true
+		agent.UpdatedAt = timestamppb.New(s.clock.Now())
+		return true
+	})
+	if err != nil {
+		return nil, storageWriteError("failed to update agent", err)
+	}
+	if marked {
+		log.Printf("Agent %s marked for decommission", agent.Id)
 	}
 
-	// Generate instructions for the agent
-	instructions := s.generateInstructions(agent)
-
-	// Return instructions with default poll interval
-	return &v1.GetInstructionsResponse{
-		Instructions:        instructions,
-		PollIntervalSeconds: 60, // Default: poll every 60 seconds (1 minute)
-		ServerTime:          now,
+	return &v1.DecommissionAgentResponse{
+		Agent: agent,
 	}, nil
 }
 
-// SubmitInstructionResult processes the result of a completed instruction
-func (s *AgentService) SubmitInstructionResult(ctx context.Context, req *v1.SubmitInstructionResultRequest) (*v1.SubmitInstructionResultResponse, error) {
-	if req.AgentId == "" {
+// overridableInstructions lists the instruction types agent config overrides
+// may disable; the rest are operator-driven and always delivered
+var overridableInstructions = map[v1.InstructionType]bool{
+	v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE:     true,
+	v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK:         true,
+	v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY:        true,
+	v1.InstructionType_INSTRUCTION_TYPE_APPLY_NETWORK_CONFIG: true,
+}
+
+// UpdateAgentConfig replaces an agent's config overrides, which take
+// precedence over the fleet defaults on its next poll
+func (s *AgentService) UpdateAgentConfig(ctx context.Context, req *v1.UpdateAgentConfigRequest) (*v1.UpdateAgentConfigResponse, error) {
+	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "agent ID is required")
 	}
-	if req.InstructionId == "" {
-		return nil, status.Error(codes.InvalidArgument, "instruction ID is required")
-	}
-	if req.Result == nil {
-		return nil, status.Error(codes.InvalidArgument, "result is required")
+	if err := validateConfigOverrides(req.Overrides); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	// Verify agent exists
-	agent, err := s.storage.GetAgent(ctx, req.AgentId)
+	agent, err := s.storage.GetAgent(ctx, req.Id)
 	if err != nil {
-		return nil, status.Error(codes.NotFound, fmt.Sprintf("agent not found: %s", req.AgentId))
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("agent not found: %s", req.Id))
 	}
 
-	// Process the instruction result
-	if err := s.processInstructionResult(agent, req.Result); err != nil {
-		log.Printf("Failed to process instruction result for agent %s: %v", agent.Id, err)
-		return &v1.SubmitInstructionResultResponse{
-			Success: false,
-			Message: fmt.Sprintf("Failed to process result: %v", err),
-		}, nil
+	if _, err := s.updateAgent(ctx, agent, func(agent *v1.Agent) bool {
+		// Empty overrides are stored as none
+		agent.ConfigOverrides = req.Overrides
+		if proto.Size(req.Overrides) == 0 {
+			agent.ConfigOverrides = nil
+		}
+		agent.UpdatedAt = timestamppb.New(s.clock.Now())
+		return true
+	}); err != nil {
+		return nil, storageWriteError("failed to update agent", err)
 	}
 
-	// Update agent in storage with the processed result
-	agent.UpdatedAt = timestamppb.Now()
-	if err := s.storage.UpdateAgent(ctx, agent); err != nil {
-		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to update agent: %v", err))
-	}
+	log.Printf("Agent %s config overrides updated: poll_interval=%ds, disabled=%v", agent.Id,
+		agent.GetConfigOverrides().GetPollIntervalSeconds(), agent.GetConfigOverrides().GetDisabledInstructions())
 
-	return &v1.SubmitInstructionResultResponse{
-		Success: true,
-		Message: "Result processed successfully",
+	return &v1.UpdateAgentConfigResponse{
+		Agent: agent,
 	}, nil
 }
 
-// processInstructionResult processes the result from an instruction execution
-func (s *AgentService) processInstructionResult(agent *v1.Agent, result *v1.InstructionResult) error {
-	// Process based on instruction type
-	switch result.InstructionType {
-	case v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE:
-		hwResult := result.GetHardwareCollection()
-		if hwResult == nil {
-			return fmt.Errorf("hardware collection result is missing")
+// validateConfigOverrides rejects overrides that would poll an agent faster
+// than the default or disable instructions that cannot be disabled
+func validateConfigOverrides(overrides *v1.AgentConfigOverrides) error {
+	interval := overrides.GetPollIntervalSeconds()
+	if interval != 0 && interval < PollIntervalSeconds {
+		return fmt.Errorf("poll interval override %ds is below the default of %ds", interval, PollIntervalSeconds)
+	}
+	for _, instructionType := range overrides.GetDisabledInstructions() {
+		if !overridableInstructions[instructionType] {
+			return fmt.Errorf("instruction type %v cannot be disabled", instructionType)
 		}
+	}
+	return nil
+}
 
-		// Update agent with hardware information (even if empty)
-		agent.NetworkInterfaces = hwResult.NetworkInterfaces
-		agent.HardwareCollected = true
-
-		if len(hwResult.NetworkInterfaces) > 0 {
-			log.Printf("Hardware collected for agent %s: %d NICs", agent.Id, len(hwResult.NetworkInterfaces))
-		} else {
-			log.Printf("Hardware collected for agent %s: no Mellanox NICs found", agent.Id)
+// instructionDisabled reports whether the agent's overrides disable an
+// instruction type
+func instructionDisabled(agent *v1.Agent, instructionType v1.InstructionType) bool {
+	for _, disabled := range agent.GetConfigOverrides().GetDisabledInstructions() {
+		if disabled == instructionType {
+			return true
 		}
+	}
+	return false
+}
 
-	case v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK:
-		healthResult := result.GetHealthCheck()
-		if healthResult == nil {
-			return fmt.Errorf("health check result is missing")
-		}
-		log.Printf("Health check from agent %s: healthy=%v", agent.Id, healthResult.Healthy)
+// RestartAgent flags an agent to be sent a restart instruction on its next
+// poll. The flag stays set until the agent confirms initiating the restart.
+func (s *AgentService) RestartAgent(ctx context.Context, req *v1.RestartAgentRequest) (*v1.RestartAgentResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent ID is required")
+	}
 
-	default:
-		log.Printf("Unknown instruction type: %v", result.InstructionType)
+	agent, err := s.storage.GetAgent(ctx, req.Id)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("agent not found: %s", req.Id))
+	}
+	if agent.Decommissioning {
+		return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("agent %s is being decommissioned", req.Id))
+	}
+
+	if err := s.requestRestart(ctx, agent); err != nil {
+		return nil, storageWriteError("failed to update agent", err)
 	}
 
+	return &v1.RestartAgentResponse{
+		Agent: agent,
+	}, nil
+}
+
+// requestRestart marks an agent for restart unless it already is
+func (s *AgentService) requestRestart(ctx context.Context, agent *v1.Agent) error {
+	marked, err := s.updateAgent(ctx, agent, func(agent *v1.Agent) bool {
+		if agent.RestartRequested {
+			return false
+		}
+		agent.RestartRequested = true
+		agent.UpdatedAt = timestamppb.New(s.clock.Now())
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if marked {
+		log.Printf("Agent %s marked for restart", agent.Id)
+	}
 	return nil
 }
 
-// generateInstructions creates instructions for an agent based on its state
-func (s *AgentService) generateInstructions(agent *v1.Agent) []*v1.Instruction {
-	var instructions []*v1.Instruction
+// TriggerHardwareCollection clears the hardware-collected flag of the selected
+// agents so their next poll requests a fresh hardware collection
+func (s *AgentService) TriggerHardwareCollection(ctx context.Context, req *v1.TriggerHardwareCollectionRequest) (*v1.TriggerHardwareCollectionResponse, error) {
+	if (req.ClusterId == "") == (len(req.AgentIds) == 0) {
+		return nil, status.Error(codes.InvalidArgument, "exactly one of cluster ID or agent IDs is required")
+	}
 
-	// Request hardware collection if not yet completed
-	if !agent.HardwareCollected {
-		instruction := &v1.Instruction{
-			Id:        uuid.New().String(),
-			Type:      v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE,
-			Payload:   `{}`,
-			CreatedAt: timestamppb.Now(),
+	var agents []*v1.Agent
+	if req.ClusterId != "" {
+		exists, err := s.storage.ClusterExists(ctx, req.ClusterId)
+		if err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to check cluster existence: %v", err))
+		}
+		if !exists {
+			return nil, status.Error(codes.NotFound, fmt.Sprintf("cluster %s not found", req.ClusterId))
+		}
+		agents, err = s.storage.ListAgents(ctx, req.ClusterId)
+		if err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to list agents: %v", err))
+		}
+	} else {
+		// Resolve every agent first so an unknown ID triggers nothing
+		for _, id := range req.AgentIds {
+			agent, err := s.storage.GetAgent(ctx, id)
+			if err != nil {
+				return nil, status.Error(codes.NotFound, fmt.Sprintf("agent not found: %s", id))
+			}
+			agents = append(agents, agent)
 		}
-		instructions = append(instructions, instruction)
-		log.Printf("Requesting hardware collection from agent %s", agent.Id)
 	}
 
-	// Future: Add other instruction types here
-	// - Health checks
-	// - Command execution
-	// - Configuration updates
+	triggered := make([]string, 0, len(agents))
+	for _, agent := range agents {
+		if err := s.requestHardwareCollection(ctx, agent); err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to update agent %s: %v", agent.Id, err))
+		}
+		triggered = append(triggered, agent.Id)
+	}
 
-	return instructions
+	log.Printf("Hardware collection triggered for %d agents", len(triggered))
+
+	return &v1.TriggerHardwareCollectionResponse{
+		AgentIds: triggered,
+	}, nil
 }
 
-// validateRegisterRequest validates the agent registration request
-func (s *AgentService) validateRegisterRequest(req *v1.RegisterAgentRequest) error {
-	if req.Id == "" {
-		return fmt.Errorf("agent ID is required")
+// requestHardwareCollection makes an agent collect hardware on its next
+// poll, lifting any backoff from a failed collection
+func (s *AgentService) requestHardwareCollection(ctx context.Context, agent *v1.Agent) error {
+	_, err := s.updateAgent(ctx, agent, func(agent *v1.Agent) bool {
+		if !agent.HardwareCollected && !clearRetryBackoff(agent, v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE) {
+			return false
+		}
+		agent.HardwareCollected = false
+		agent.UpdatedAt = timestamppb.New(s.clock.Now())
+		return true
+	})
+	return err
+}
+
+// FindOrphanedAgents lists agents referencing clusters that no longer exist
+func (s *AgentService) FindOrphanedAgents(ctx context.Context, req *v1.FindOrphanedAgentsRequest) (*v1.FindOrphanedAgentsResponse, error) {
+	agents, err := s.storage.ListOrphanedAgents(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to find orphaned agents: %v", err))
 	}
-	if req.ClusterId == "" {
-		return fmt.Errorf("cluster ID is required")
+
+	return &v1.FindOrphanedAgentsResponse{
+		Agents: agents,
+	}, nil
+}
+
+// ReapOrphanedAgents deletes agents referencing clusters that no longer exist
+func (s *AgentService) ReapOrphanedAgents(ctx context.Context, req *v1.ReapOrphanedAgentsRequest) (*v1.ReapOrphanedAgentsResponse, error) {
+	agents, err := s.storage.ListOrphanedAgents(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to find orphaned agents: %v", err))
+	}
+
+	deleted := make([]string, 0, len(agents))
+	for _, agent := range agents {
+		if err := s.storage.DeleteAgent(ctx, agent.Id); err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to delete orphaned agent %s: %v", agent.Id, err))
+		}
+		s.pollStats.forget(agent.Id)
+		s.emitLifecycle(LifecycleEvent{
+			Type:           LifecycleAgentUnregistered,
+			AgentID:        agent.Id,
+			ClusterID:      agent.ClusterId,
+			PreviousStatus: agent.Status,
+		})
+		deleted = append(deleted, agent.Id)
+		log.Printf("Reaped orphaned agent %s (missing cluster %s)", agent.Id, agent.ClusterId)
+	}
+
+	return &v1.ReapOrphanedAgentsResponse{
+		AgentIds: deleted,
+	}, nil
+}
+
+// GetInstructions polls for pending instructions and updates agent heartbeat
+func (s *AgentService) GetInstructions(ctx context.Context, req *v1.GetInstructionsRequest) (*v1.GetInstructionsResponse, error) {
+	if req.AgentId == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent ID is required")
+	}
+	if err := validateMetrics(req.Metrics); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.ReportedPollIntervalSeconds < 0 {
+		return nil, status.Error(codes.InvalidArgument, "reported poll interval must not be negative")
+	}
+
+	// Verify agent exists and update last_seen (implicit heartbeat)
+	agent, err := s.storage.GetAgent(ctx, req.AgentId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("agent not found: %s", req.AgentId))
+	}
+
+	// Update agent's last_seen timestamp and set status to active. Operator
+	// requests landing since the read are picked up on a conflicting write.
+	now := timestamppb.New(s.clock.Now())
+	var (
+		previousStatus v1.AgentStatus
+		backoff        int32
+	)
+	if _, err := s.updateAgent(ctx, agent, func(agent *v1.Agent) bool {
+		agent.LastSeen = now
+		agent.UpdatedAt = now
+		previousStatus = agent.Status
+		agent.Status = v1.AgentStatus_AGENT_STATUS_ACTIVE
+
+		// Metrics are a transient snapshot: each report replaces the previous one
+		if len(req.Metrics) > 0 {
+			agent.Metrics = req.Metrics
+			agent.MetricsReportedAt = now
+		}
+
+		// Record the instructed and reported cadence and what determined the
+		// instructed one; the monitor flags agents polling far off the instructed interval
+		agent.InstructedPollIntervalSeconds, agent.PollIntervalSource, backoff = s.agentPollInterval(agent)
+		if req.ReportedPollIntervalSeconds > 0 {
+			agent.ReportedPollIntervalSeconds = req.ReportedPollIntervalSeconds
+		}
+		return true
+	}); err != nil {
+		if s.clusterDeleted(ctx, agent.ClusterId) {
+			return s.clusterDeletedResponse(agent, now), nil
+		}
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to update agent heartbeat: %v", err))
+	}
+	s.pollStats.record(agent.Id, agent.ClusterId, s.clock.Now())
+	if previousStatus != agent.Status {
+		s.emitLifecycle(LifecycleEvent{
+			Type:           LifecycleAgentStatusChanged,
+			AgentID:        agent.Id,
+			ClusterID:      agent.ClusterId,
+			PreviousStatus: previousStatus,
+			Status:         agent.Status,
+		})
+	}
+	s.activity.publish(&v1.ActivityEvent{
+		AgentId:   agent.Id,
+		Type:      v1.ActivityEventType_ACTIVITY_EVENT_TYPE_POLL,
+		Timestamp: now,
+	})
+
+	cluster, err := s.storage.GetCluster(ctx, agent.ClusterId)
+	if err != nil {
+		if s.clusterDeleted(ctx, agent.ClusterId) {
+			return s.clusterDeletedResponse(agent, now), nil
+		}
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to get agent cluster: %v", err))
+	}
+
+	instructions := s.instructionsFor(agent, cluster)
+	if len(instructions) > 0 {
+		issued := make([]v1.InstructionType, 0, len(instructions))
+		for _, instruction := range instructions {
+			issued = append(issued, instruction.Type)
+		}
+		s.activity.publish(&v1.ActivityEvent{
+			AgentId:          agent.Id,
+			Type:             v1.ActivityEventType_ACTIVITY_EVENT_TYPE_INSTRUCTIONS_ISSUED,
+			Timestamp:        now,
+			InstructionTypes: issued,
+		})
+	}
+
+	// Return instructions with the poll interval, stretched while throttled
+	return &v1.GetInstructionsResponse{
+		Instructions:        instructions,
+		PollIntervalSeconds: agent.InstructedPollIntervalSeconds,
+		ServerTime:          now,
+		BackoffSeconds:      backoff,
+	}, nil
+}
+
+// PreviewInstructions returns the instructions an agent would receive on a
+// poll now, for debugging why it is or is not getting an instruction. Unlike
+// GetInstructions it records no poll: last_seen, status and the agent's
+// activity stay untouched.
+func (s *AgentService) PreviewInstructions(ctx context.Context, req *v1.PreviewInstructionsRequest) (*v1.GetInstructionsResponse, error) {
+	if req.AgentId == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent ID is required")
+	}
+
+	agent, err := s.storage.GetAgent(ctx, req.AgentId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("agent not found: %s", req.AgentId))
+	}
+
+	now := timestamppb.New(s.clock.Now())
+	cluster, err := s.storage.GetCluster(ctx, agent.ClusterId)
+	if err != nil {
+		if s.clusterDeleted(ctx, agent.ClusterId) {
+			return s.clusterDeletedResponse(agent, now), nil
+		}
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to get agent cluster: %v", err))
+	}
+
+	pollInterval, _, backoff := s.agentPollInterval(agent)
+	return &v1.GetInstructionsResponse{
+		Instructions:        s.instructionsFor(agent, cluster),
+		PollIntervalSeconds: pollInterval,
+		ServerTime:          now,
+		BackoffSeconds:      backoff,
+	}, nil
+}
+
+// persistentInstructions are issued from agent or cluster state that stays
+// set until the agent reports the instruction's result, so every poll,
+// including the first after downtime, redelivers them
+var persistentInstructions = map[v1.InstructionType]bool{
+	v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE:     true,
+	v1.InstructionType_INSTRUCTION_TYPE_APPLY_NETWORK_CONFIG: true,
+	v1.InstructionType_INSTRUCTION_TYPE_DRAIN:                true,
+	v1.InstructionType_INSTRUCTION_TYPE_DECOMMISSION:         true,
+	v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT:        true,
+}
+
+// instructionsFor selects the instructions for an agent's poll; a
+// decommissioning agent only cleans up, an agent due to restart only restarts
+// and a cordoned cluster only drains
+func (s *AgentService) instructionsFor(agent *v1.Agent, cluster *v1.Cluster) []*v1.Instruction {
+	var instructions []*v1.Instruction
+	switch {
+	case agent.Decommissioning:
+		instructions = s.decommissionInstructions(agent)
+	case agent.RestartRequested:
+		instructions = s.restartInstructions(agent)
+	case cluster.Cordoned:
+		instructions = s.drainInstructions(agent)
+	default:
+		instructions = s.generateInstructions(agent, cluster)
+	}
+
+	for _, instruction := range instructions {
+		instruction.Persistent = persistentInstructions[instruction.Type]
+	}
+	return instructions
+}
+
+// clusterDeleted reports whether the cluster no longer exists. Deleting a
+// cluster deletes its agents, but an agent may be mid-poll when that happens.
+func (s *AgentService) clusterDeleted(ctx context.Context, clusterID string) bool {
+	exists, err := s.storage.ClusterExists(ctx, clusterID)
+	return err == nil && !exists
+}
+
+// clusterDeletedPayload is the payload of a CLUSTER_DELETED instruction
+type clusterDeletedPayload struct {
+	ClusterID string `json:"cluster_id"`
+}
+
+// clusterDeletedResponse answers a poll by an agent whose cluster is gone
+// with only a CLUSTER_DELETED instruction, telling it to unregister instead
+// of retrying
+func (s *AgentService) clusterDeletedResponse(agent *v1.Agent, now *timestamppb.Timestamp) *v1.GetInstructionsResponse {
+	log.Printf("Agent %s belongs to deleted cluster %s", agent.Id, agent.ClusterId)
+
+	payload, _ := json.Marshal(clusterDeletedPayload{ClusterID: agent.ClusterId})
+	pollInterval, backoff := s.pollInterval()
+	return &v1.GetInstructionsResponse{
+		Instructions: []*v1.Instruction{
+			{
+				Id:        uuid.New().String(),
+				Type:      v1.InstructionType_INSTRUCTION_TYPE_CLUSTER_DELETED,
+				Payload:   string(payload),
+				CreatedAt: now,
+			},
+		},
+		PollIntervalSeconds: pollInterval,
+		ServerTime:          now,
+		BackoffSeconds:      backoff,
+	}
+}
+
+// TailAgentActivity streams an agent's activity until the client cancels
+func (s *AgentService) TailAgentActivity(req *v1.TailAgentActivityRequest, stream v1.AgentService_TailAgentActivityServer) error {
+	if req.AgentId == "" {
+		return status.Error(codes.InvalidArgument, "agent ID is required")
+	}
+
+	ctx := stream.Context()
+	if _, err := s.storage.GetAgent(ctx, req.AgentId); err != nil {
+		return status.Error(codes.NotFound, fmt.Sprintf("agent not found: %s", req.AgentId))
+	}
+
+	sub, unsubscribe, err := s.activity.subscribe(req.AgentId)
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, fmt.Sprintf("%v: at most %d concurrent activity tails", err, s.activity.maxSubscribers))
+	}
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.evicted:
+			return status.Error(codes.ResourceExhausted, "activity tail evicted to make room for a new subscriber")
+		case event := <-sub.events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// SetThrottleMode turns fleet-wide throttling on or off
+func (s *AgentService) SetThrottleMode(ctx context.Context, req *v1.SetThrottleModeRequest) (*v1.SetThrottleModeResponse, error) {
+	interval := req.PollIntervalSeconds
+	if interval == 0 {
+		interval = DefaultThrottledPollIntervalSeconds
+	}
+	if interval <= PollIntervalSeconds {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("throttled poll interval must exceed the normal interval of %d seconds", PollIntervalSeconds))
+	}
+
+	s.throttleMu.Lock()
+	s.throttled = req.Enabled
+	s.throttledPollInterval = interval
+	s.throttleMu.Unlock()
+
+	if req.Enabled {
+		log.Printf("Throttle mode enabled: agents poll every %d seconds", interval)
+	} else {
+		log.Println("Throttle mode disabled")
+	}
+
+	return &v1.SetThrottleModeResponse{
+		Enabled:             req.Enabled,
+		PollIntervalSeconds: interval,
+	}, nil
+}
+
+// pollInterval returns the poll interval and retry backoff handed to agents
+func (s *AgentService) pollInterval() (int32, int32) {
+	s.throttleMu.RLock()
+	defer s.throttleMu.RUnlock()
+
+	if !s.throttled {
+		return PollIntervalSeconds, 0
+	}
+	return s.throttledPollInterval, s.throttledPollInterval
+}
+
+// agentPollInterval returns the effective poll interval handed to an agent,
+// the factor that determined it and the retry backoff. The first factor that
+// applies wins: throttle mode, then a pending retry backoff, then the agent's
+// override, then the default.
+func (s *AgentService) agentPollInterval(agent *v1.Agent) (int32, v1.PollIntervalSource, int32) {
+	interval, backoff := s.pollInterval()
+
+	// The backoff is only non-zero while the fleet is throttled
+	if backoff > 0 {
+		return interval, v1.PollIntervalSource_POLL_INTERVAL_SOURCE_THROTTLE, backoff
+	}
+	if retry := s.retryPollInterval(agent); retry > 0 {
+		return retry, v1.PollIntervalSource_POLL_INTERVAL_SOURCE_BACKOFF, backoff
+	}
+	if override := agent.GetConfigOverrides().GetPollIntervalSeconds(); override > interval {
+		return override, v1.PollIntervalSource_POLL_INTERVAL_SOURCE_AGENT_OVERRIDE, backoff
+	}
+	return interval, v1.PollIntervalSource_POLL_INTERVAL_SOURCE_DEFAULT, backoff
+}
+
+// retryPollInterval returns the seconds until the agent's earliest pending
+// retry, bounded by the default and BackoffPollIntervalCapSeconds, or 0 when
+// no instruction is backing off
+func (s *AgentService) retryPollInterval(agent *v1.Agent) int32 {
+	now := s.clock.Now()
+	var earliest time.Time
+	for _, outcome := range agent.LastOutcomes {
+		retryAfter := outcome.GetRetryAfter()
+		if retryAfter == nil || !now.Before(retryAfter.AsTime()) {
+			continue
+		}
+		if earliest.IsZero() || retryAfter.AsTime().Before(earliest) {
+			earliest = retryAfter.AsTime()
+		}
+	}
+	if earliest.IsZero() {
+		return 0
+	}
+
+	seconds := int32(math.Ceil(earliest.Sub(now).Seconds()))
+	return min(max(seconds, PollIntervalSeconds), BackoffPollIntervalCapSeconds)
+}
+
+// GetClusterPollStats reports min/avg/max intervals between agent polls in a cluster
+func (s *AgentService) GetClusterPollStats(ctx context.Context, req *v1.GetClusterPollStatsRequest) (*v1.GetClusterPollStatsResponse, error) {
+	if req.ClusterId == "" {
+		return nil, status.Error(codes.InvalidArgument, "cluster ID is required")
+	}
+
+	exists, err := s.storage.ClusterExists(ctx, req.ClusterId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to check cluster existence: %v", err))
+	}
+	if !exists {
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("cluster not found: %s", req.ClusterId))
+	}
+
+	summary := s.pollStats.summarize(req.ClusterId)
+	configured, _ := s.pollInterval()
+	return &v1.GetClusterPollStatsResponse{
+		ClusterId:                 req.ClusterId,
+		AgentCount:                int32(summary.agents),
+		SampleCount:               int32(summary.samples),
+		MinIntervalSeconds:        summary.min.Seconds(),
+		AvgIntervalSeconds:        summary.avg().Seconds(),
+		MaxIntervalSeconds:        summary.max.Seconds(),
+		ConfiguredIntervalSeconds: configured,
+	}, nil
+}
+
+// GetClusterInstructionSummary aggregates the most recent result of an
+// instruction type across a cluster's agents
+func (s *AgentService) GetClusterInstructionSummary(ctx context.Context, req *v1.GetClusterInstructionSummaryRequest) (*v1.GetClusterInstructionSummaryResponse, error) {
+	if req.ClusterId == "" {
+		return nil, status.Error(codes.InvalidArgument, "cluster ID is required")
+	}
+	if req.InstructionType == v1.InstructionType_INSTRUCTION_TYPE_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "instruction type is required")
+	}
+
+	exists, err := s.storage.ClusterExists(ctx, req.ClusterId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to check cluster existence: %v", err))
+	}
+	if !exists {
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("cluster not found: %s", req.ClusterId))
+	}
+
+	agents, err := s.storage.ListAgents(ctx, req.ClusterId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to list agents: %v", err))
+	}
+
+	resp := &v1.GetClusterInstructionSummaryResponse{
+		ClusterId:       req.ClusterId,
+		InstructionType: req.InstructionType,
+	}
+	for _, agent := range agents {
+		outcome := lastOutcome(agent, req.InstructionType)
+		switch {
+		case outcome == nil:
+			resp.NoResult++
+		case outcome.Success:
+			resp.Succeeded++
+		default:
+			resp.Failed++
+		}
+	}
+	return resp, nil
+}
+
+// lastOutcome returns the agent's last outcome for an instruction type, if any
+func lastOutcome(agent *v1.Agent, instructionType v1.InstructionType) *v1.InstructionOutcome {
+	for _, outcome := range agent.LastOutcomes {
+		if outcome.InstructionType == instructionType {
+			return outcome
+		}
+	}
+	return nil
+}
+
+// SubmitInstructionResult processes the result of a completed instruction
+func (s *AgentService) SubmitInstructionResult(ctx context.Context, req *v1.SubmitInstructionResultRequest) (*v1.SubmitInstructionResultResponse, error) {
+	if req.AgentId == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent ID is required")
+	}
+	if req.InstructionId == "" {
+		return nil, status.Error(codes.InvalidArgument, "instruction ID is required")
+	}
+	if req.Result == nil {
+		return nil, status.Error(codes.InvalidArgument, "result is required")
+	}
+	if err := s.checkClockSkew(req.Result.CompletedAt); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if !s.results.begin() {
+		return nil, status.Error(codes.Unavailable, "server is shutting down")
+	}
+	defer s.results.end()
+
+	// Verify agent exists
+	agent, err := s.storage.GetAgent(ctx, req.AgentId)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("agent not found: %s", req.AgentId))
+	}
+
+	s.activity.publish(&v1.ActivityEvent{
+		AgentId:         agent.Id,
+		Type:            v1.ActivityEventType_ACTIVITY_EVENT_TYPE_RESULT_SUBMITTED,
+		Timestamp:       timestamppb.New(s.clock.Now()),
+		InstructionId:   req.InstructionId,
+		InstructionType: req.Result.InstructionType,
+	})
+
+	// Process the instruction result and update the agent in storage;
+	// updated_at always uses the server clock, never the agent-supplied
+	// completion time. A write conflicting with an operator request made
+	// since the read processes the result again on the fresh agent.
+	var processErr error
+	decommissioned := false
+	_, err = s.updateAgent(ctx, agent, func(agent *v1.Agent) bool {
+		if processErr = s.processInstructionResult(agent, req.InstructionId, req.Result); processErr != nil {
+			return false
+		}
+		// A confirmed decommission removes the agent instead of updating it
+		if agent.Decommissioning && req.Result.GetDecommission().GetSuccess() {
+			decommissioned = true
+			return false
+		}
+
+		agent.UpdatedAt = timestamppb.New(s.clock.Now())
+		if outcome := recordOutcome(agent, req.Result, agent.UpdatedAt); outcome != nil {
+			backoff := failureBackoff(outcome.FailureReason)
+			if backoff == 0 && partialCollection(req.Result.GetHardwareCollection()) {
+				backoff = PartialCollectionBackoff
+			}
+			if backoff > 0 {
+				outcome.RetryAfter = timestamppb.New(s.clock.Now().Add(backoff))
+			}
+		}
+		return true
+	})
+	if processErr != nil {
+		log.Printf("Failed to process instruction result for agent %s: %v", agent.Id, processErr)
+		return &v1.SubmitInstructionResultResponse{
+			Success: false,
+			Message: fmt.Sprintf("Failed to process result: %v", processErr),
+		}, nil
+	}
+	if err != nil {
+		return nil, storageWriteError("failed to update agent", err)
+	}
+
+	if decommissioned {
+		if err := s.storage.DeleteAgent(ctx, agent.Id); err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to unregister decommissioned agent: %v", err))
+		}
+		s.pollStats.forget(agent.Id)
+		s.emitLifecycle(LifecycleEvent{
+			Type:           LifecycleAgentUnregistered,
+			AgentID:        agent.Id,
+			ClusterID:      agent.ClusterId,
+			PreviousStatus: agent.Status,
+		})
+		log.Printf("Agent %s decommissioned and unregistered", agent.Id)
+
+		return &v1.SubmitInstructionResultResponse{
+			Success: true,
+			Message: "Agent decommissioned",
+		}, nil
+	}
+
+	return &v1.SubmitInstructionResultResponse{
+		Success: true,
+		Message: "Result processed successfully",
+	}, nil
+}
+
+// checkClockSkew rejects an agent-supplied timestamp too far from server time,
+// which indicates a misconfigured agent clock
+func (s *AgentService) checkClockSkew(ts *timestamppb.Timestamp) error {
+	if ts == nil || s.maxClockSkew <= 0 {
+		return nil
+	}
+	if err := ts.CheckValid(); err != nil {
+		return fmt.Errorf("invalid completion timestamp: %v", err)
+	}
+
+	skew := s.clock.Now().Sub(ts.AsTime())
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > s.maxClockSkew {
+		return fmt.Errorf("completion timestamp is %v from server time, exceeding the allowed skew of %v", skew.Round(time.Second), s.maxClockSkew)
+	}
+	return nil
+}
+
+// processInstructionResult processes the result from an instruction execution
+func (s *AgentService) processInstructionResult(agent *v1.Agent, instructionID string, result *v1.InstructionResult) error {
+	// A failure reason means the instruction did not run; there is no payload to apply
+	if result.FailureReason != v1.FailureReason_FAILURE_REASON_UNSPECIFIED {
+		log.Printf("Agent %s failed instruction %v: %v", agent.Id, result.InstructionType, result.FailureReason)
+		return nil
+	}
+
+	// Process based on instruction type
+	switch result.InstructionType {
+	case v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE:
+		hwResult := result.GetHardwareCollection()
+		if hwResult == nil {
+			return fmt.Errorf("hardware collection result is missing")
+		}
+		if err := s.validateNetworkInterfaces(hwResult.NetworkInterfaces); err != nil {
+			return err
+		}
+		if len(hwResult.FailedNics) > s.maxNICs {
+			return fmt.Errorf("hardware collection reports %d failed NICs, exceeding the limit of %d", len(hwResult.FailedNics), s.maxNICs)
+		}
+
+		// Update agent with hardware information (even if empty); a partial
+		// collection keeps the NICs that were collected but is retried later
+		agent.NetworkInterfaces = hwResult.NetworkInterfaces
+		agent.FailedNics = hwResult.FailedNics
+		agent.HardwareCollected = !partialCollection(hwResult)
+
+		if partialCollection(hwResult) {
+			log.Printf("Partial hardware collection for agent %s: %d NICs collected, %d failed",
+				agent.Id, len(hwResult.NetworkInterfaces), len(hwResult.FailedNics))
+		} else if len(hwResult.NetworkInterfaces) > 0 {
+			log.Printf("Hardware collected for agent %s: %d NICs", agent.Id, len(hwResult.NetworkInterfaces))
+		} else {
+			log.Printf("Hardware collected for agent %s: no Mellanox NICs found", agent.Id)
+		}
+
+	case v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK:
+		healthResult := result.GetHealthCheck()
+		if healthResult == nil {
+			return fmt.Errorf("health check result is missing")
+		}
+		if healthResult.Healthy {
+			agent.LastHealthyAt = timestamppb.New(s.clock.Now())
+		}
+		log.Printf("Health check from agent %s: healthy=%v", agent.Id, healthResult.Healthy)
+
+	case v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY:
+		probeResult := result.GetGatewayProbe()
+		if probeResult == nil {
+			return fmt.Errorf("gateway probe result is missing")
+		}
+
+		agent.LastGatewayProbe = probeResult
+		agent.LastGatewayProbeAt = timestamppb.New(s.clock.Now())
+		log.Printf("Gateway probe from agent %s: gateway=%s, reachable=%v, latency=%.1fms",
+			agent.Id, probeResult.Gateway, probeResult.Reachable, probeResult.LatencyMs)
+
+	case v1.InstructionType_INSTRUCTION_TYPE_APPLY_NETWORK_CONFIG:
+		configResult := result.GetNetworkConfig()
+		if configResult == nil {
+			return fmt.Errorf("network config result is missing")
+		}
+
+		if configResult.AppliedConfig != nil {
+			agent.AppliedNetworkConfig = configResult.AppliedConfig
+		}
+		if !configResult.Success {
+			log.Printf("Agent %s failed to apply network config: %s", agent.Id, configResult.ErrorMessage)
+			break
+		}
+
+		// The reconciler re-flags the agent if the applied config still differs
+		agent.ConfigDrifted = false
+		log.Printf("Agent %s applied network config", agent.Id)
+
+	case v1.InstructionType_INSTRUCTION_TYPE_DECOMMISSION:
+		decommissionResult := result.GetDecommission()
+		if decommissionResult == nil {
+			return fmt.Errorf("decommission result is missing")
+		}
+		if !decommissionResult.Success {
+			log.Printf("Agent %s failed decommission cleanup: %s", agent.Id, decommissionResult.ErrorMessage)
+		}
+
+	case v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT:
+		restartResult := result.GetRestartAgent()
+		if restartResult == nil {
+			return fmt.Errorf("restart result is missing")
+		}
+		if !restartResult.Initiated {
+			log.Printf("Agent %s failed to initiate restart: %s", agent.Id, restartResult.ErrorMessage)
+			break
+		}
+
+		agent.RestartRequested = false
+		agent.LastRestartAt = timestamppb.New(s.clock.Now())
+		log.Printf("Agent %s initiated restart", agent.Id)
+
+	default:
+		return s.handleUnrecognizedResult(agent, instructionID, result)
+	}
+
+	return nil
+}
+
+// handleUnrecognizedResult applies the unknown result policy to a result of
+// an instruction type the server does not recognize
+func (s *AgentService) handleUnrecognizedResult(agent *v1.Agent, instructionID string, result *v1.InstructionResult) error {
+	switch s.unknownResultPolicy {
+	case UnknownResultReject:
+		return fmt.Errorf("unrecognized instruction type %v", result.InstructionType)
+
+	case UnknownResultStoreRaw:
+		// proto.Marshal keeps fields unknown to this server, unlike JSON
+		payload, err := proto.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode unrecognized result: %w", err)
+		}
+		agent.UnrecognizedResults = append(agent.UnrecognizedResults, &v1.RawInstructionResult{
+			InstructionId:   instructionID,
+			InstructionType: result.InstructionType,
+			Payload:         payload,
+			ReceivedAt:      timestamppb.New(s.clock.Now()),
+		})
+		if excess := len(agent.UnrecognizedResults) - MaxUnrecognizedResults; excess > 0 {
+			agent.UnrecognizedResults = agent.UnrecognizedResults[excess:]
+		}
+		log.Printf("Stored raw result of unknown instruction type %v from agent %s", result.InstructionType, agent.Id)
+
+	default:
+		log.Printf("Unknown instruction type: %v", result.InstructionType)
+	}
+	return nil
+}
+
+// recordOutcome replaces the agent's last outcome for the result's
+// instruction type and returns it; results of unknown types are not recorded
+func recordOutcome(agent *v1.Agent, result *v1.InstructionResult, receivedAt *timestamppb.Timestamp) *v1.InstructionOutcome {
+	var success bool
+	switch result.InstructionType {
+	case v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE:
+		success = !partialCollection(result.GetHardwareCollection())
+	case v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK:
+		success = result.GetHealthCheck().GetHealthy()
+	case v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY:
+		success = result.GetGatewayProbe().GetReachable()
+	case v1.InstructionType_INSTRUCTION_TYPE_APPLY_NETWORK_CONFIG:
+		success = result.GetNetworkConfig().GetSuccess()
+	case v1.InstructionType_INSTRUCTION_TYPE_DECOMMISSION:
+		success = result.GetDecommission().GetSuccess()
+	case v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT:
+		success = result.GetRestartAgent().GetInitiated()
+	default:
+		return nil
+	}
+	if result.FailureReason != v1.FailureReason_FAILURE_REASON_UNSPECIFIED {
+		success = false
+	}
+
+	outcome := &v1.InstructionOutcome{
+		InstructionType: result.InstructionType,
+		Success:         success,
+		ReceivedAt:      receivedAt,
+		FailureReason:   result.FailureReason,
+	}
+	for i, existing := range agent.LastOutcomes {
+		if existing.InstructionType == result.InstructionType {
+			agent.LastOutcomes[i] = outcome
+			return outcome
+		}
+	}
+	agent.LastOutcomes = append(agent.LastOutcomes, outcome)
+	return outcome
+}
+
+// partialCollection reports whether a hardware collection missed some NICs
+func partialCollection(result *v1.HardwareCollectionResult) bool {
+	return result.GetPartial() || len(result.GetFailedNics()) > 0
+}
+
+// failureBackoff returns how long to wait before re-issuing an instruction
+// that failed for the given reason. Failures without a reason are retried on
+// the next poll, as before agents reported reasons.
+func failureBackoff(reason v1.FailureReason) time.Duration {
+	switch reason {
+	case v1.FailureReason_FAILURE_REASON_TIMEOUT, v1.FailureReason_FAILURE_REASON_INTERNAL:
+		return TransientFailureBackoff
+	case v1.FailureReason_FAILURE_REASON_PERMISSION_DENIED:
+		return PermissionFailureBackoff
+	case v1.FailureReason_FAILURE_REASON_NOT_SUPPORTED:
+		return NotSupportedFailureBackoff
+	default:
+		return 0
+	}
+}
+
+// inBackoff reports whether the agent's last failure of an instruction type
+// asked for a retry time that has not yet passed
+func (s *AgentService) inBackoff(agent *v1.Agent, instructionType v1.InstructionType) bool {
+	retryAfter := lastOutcome(agent, instructionType).GetRetryAfter()
+	return retryAfter != nil && s.clock.Now().Before(retryAfter.AsTime())
+}
+
+// clearRetryBackoff drops the retry backoff of an instruction type so it is
+// issued on the next poll, reporting whether there was one
+func clearRetryBackoff(agent *v1.Agent, instructionType v1.InstructionType) bool {
+	outcome := lastOutcome(agent, instructionType)
+	if outcome.GetRetryAfter() == nil {
+		return false
+	}
+	outcome.RetryAfter = nil
+	return true
+}
+
+// validateNetworkInterfaces rejects NIC data exceeding the configured limits
+// so a misbehaving agent cannot bloat storage
+func (s *AgentService) validateNetworkInterfaces(nics []*v1.MellanoxNIC) error {
+	if len(nics) > s.maxNICs {
+		return fmt.Errorf("hardware collection reports %d NICs, exceeding the limit of %d", len(nics), s.maxNICs)
+	}
+
+	data, err := json.Marshal(nics)
+	if err != nil {
+		return fmt.Errorf("failed to serialize network interfaces: %w", err)
+	}
+	if len(data) > s.maxNICBytes {
+		return fmt.Errorf("hardware collection data is %d bytes, exceeding the limit of %d bytes", len(data), s.maxNICBytes)
+	}
+
+	for _, nic := range nics {
+		if err := validatePorts(nic); err != nil {
+			return err
+		}
+	}
+
+	return validateUniqueNICs(nics)
+}
+
+// validateUniqueNICs rejects a collection in which two NICs share a PCI
+// address or serial number, or two ports share a PCI address; either points
+// at a bug in the agent's collection rather than real hardware
+func validateUniqueNICs(nics []*v1.MellanoxNIC) error {
+	nicPCI := make(map[string]string, len(nics))
+	serials := make(map[string]string, len(nics))
+	portPCI := make(map[string]string)
+	for _, nic := range nics {
+		if err := claimUnique(nicPCI, "PCI address", nic.PciAddress, nic.DeviceName); err != nil {
+			return err
+		}
+		if err := claimUnique(serials, "serial number", nic.SerialNumber, nic.DeviceName); err != nil {
+			return err
+		}
+		for _, port := range nic.Ports {
+			owner := fmt.Sprintf("%s port %d", nic.DeviceName, port.Number)
+			if err := claimUnique(portPCI, "port PCI address", port.PciAddress, owner); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// claimUnique records owner as the holder of value, failing if another owner
+// already holds it. Empty values are not reported by every agent and are
+// skipped; PCI addresses and serial numbers are compared case-insensitively.
+func claimUnique(seen map[string]string, kind, value, owner string) error {
+	if value == "" {
+		return nil
+	}
+	key := strings.ToLower(value)
+	if previous, ok := seen[key]; ok {
+		return fmt.Errorf("duplicate %s %s reported by %s and %s", kind, value, previous, owner)
+	}
+	seen[key] = owner
+	return nil
+}
+
+// validateMetrics bounds the metrics snapshot reported on poll and rejects
+// values that cannot be stored as JSON
+func validateMetrics(metrics map[string]float64) error {
+	if len(metrics) > MaxAgentMetrics {
+		return fmt.Errorf("poll reports %d metrics, exceeding the limit of %d", len(metrics), MaxAgentMetrics)
+	}
+	for name, value := range metrics {
+		if name == "" {
+			return fmt.Errorf("metric name is required")
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Errorf("metric %s has non-finite value", name)
+		}
+	}
+	return nil
+}
+
+// validatePorts checks that a NIC's port list is consistent with its reported
+// port count, that port numbers are 1-based and unique and that MAC addresses
+// are valid, normalizing them to lowercase colon-separated form
+func validatePorts(nic *v1.MellanoxNIC) error {
+	if nic.PortCount != 0 && int(nic.PortCount) != len(nic.Ports) {
+		return fmt.Errorf("NIC %s reports %d ports but includes details for %d", nic.DeviceName, nic.PortCount, len(nic.Ports))
+	}
+
+	seen := make(map[int32]bool, len(nic.Ports))
+	for _, port := range nic.Ports {
+		if port.Number < 1 {
+			return fmt.Errorf("NIC %s has invalid port number %d", nic.DeviceName, port.Number)
+		}
+		if seen[port.Number] {
+			return fmt.Errorf("NIC %s reports port %d more than once", nic.DeviceName, port.Number)
+		}
+		seen[port.Number] = true
+
+		mac, err := normalizeMAC(port.MacAddress)
+		if err != nil {
+			return fmt.Errorf("NIC %s port %d: %w", nic.DeviceName, port.Number, err)
+		}
+		port.MacAddress = mac
+	}
+
+	// Older agents may omit the count; derive it from the port details
+	if nic.PortCount == 0 {
+		nic.PortCount = int32(len(nic.Ports))
+	}
+
+	return nil
+}
+
+// normalizeMAC canonicalizes a MAC address reported in any format accepted by
+// net.ParseMAC (colons, dashes, dots, any case) to lowercase colon-separated
+// form. An empty address is left empty.
+func normalizeMAC(mac string) (string, error) {
+	if mac == "" {
+		return "", nil
+	}
+	addr, err := net.ParseMAC(mac)
+	if err != nil {
+		return "", fmt.Errorf("invalid MAC address %q", mac)
+	}
+	return addr.String(), nil
+}
+
+// generateInstructions creates instructions for an agent based on its state
+func (s *AgentService) generateInstructions(agent *v1.Agent, cluster *v1.Cluster) []*v1.Instruction {
+	var instructions []*v1.Instruction
+
+	// Request hardware collection if not yet completed
+	if !agent.HardwareCollected {
+		instruction := &v1.Instruction{
+			Id:        uuid.New().String(),
+			Type:      v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE,
+			Payload:   `{}`,
+			CreatedAt: timestamppb.New(s.clock.Now()),
+		}
+		instructions = append(instructions, instruction)
+		log.Printf("Requesting hardware collection from agent %s", agent.Id)
+	}
+
+	// Spine agents aggregate traffic for the fabric and report health periodically
+	if instruction := s.healthCheckInstruction(agent); instruction != nil {
+		instructions = append(instructions, instruction)
+	}
+
+	// Periodically probe the cluster gateway when one is configured
+	if instruction := s.gatewayProbeInstruction(agent, cluster); instruction != nil {
+		instructions = append(instructions, instruction)
+	}
+
+	// Push the desired network config to agents flagged as drifted
+	if instruction := s.applyNetworkConfigInstruction(agent, cluster); instruction != nil {
+		instructions = append(instructions, instruction)
+	}
+
+	// Future: Add other instruction types here
+	// - Command execution
+
+	// Hold back instructions whose last failure is still backing off and
+	// those disabled for the agent
+	ready := instructions[:0]
+	for _, instruction := range instructions {
+		if s.inBackoff(agent, instruction.Type) || instructionDisabled(agent, instruction.Type) {
+			continue
+		}
+		ready = append(ready, instruction)
+	}
+	return ready
+}
+
+// healthCheckInstruction returns a HEALTH_CHECK instruction for spine agents
+// whose last health result is older than the health check interval
+func (s *AgentService) healthCheckInstruction(agent *v1.Agent) *v1.Instruction {
+	if agent.Role != v1.AgentRole_AGENT_ROLE_SPINE {
+		return nil
+	}
+
+	lastResult := lastOutcome(agent, v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK).GetReceivedAt()
+	if lastResult != nil && s.clock.Now().Sub(lastResult.AsTime()) < s.healthCheckInterval {
+		return nil
+	}
+
+	return &v1.Instruction{
+		Id:        uuid.New().String(),
+		Type:      v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK,
+		Payload:   `{}`,
+		CreatedAt: timestamppb.New(s.clock.Now()),
+	}
+}
+
+// gatewayProbePayload is the payload of a PROBE_GATEWAY instruction
+type gatewayProbePayload struct {
+	Gateway string `json:"gateway"`
+}
+
+// gatewayProbeInstruction returns a PROBE_GATEWAY instruction when the cluster
+// has a gateway and the agent's last probe is older than the probe interval
+func (s *AgentService) gatewayProbeInstruction(agent *v1.Agent, cluster *v1.Cluster) *v1.Instruction {
+	gateway := cluster.GetNetworkConfig().GetGateway()
+	if gateway == "" {
+		return nil
+	}
+
+	if agent.LastGatewayProbeAt != nil && s.clock.Now().Sub(agent.LastGatewayProbeAt.AsTime()) < s.gatewayProbeInterval {
+		return nil
+	}
+
+	payload, err := json.Marshal(gatewayProbePayload{Gateway: gateway})
+	if err != nil {
+		log.Printf("Failed to build gateway probe payload for agent %s: %v", agent.Id, err)
+		return nil
+	}
+
+	return &v1.Instruction{
+		Id:        uuid.New().String(),
+		Type:      v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY,
+		Payload:   string(payload),
+		CreatedAt: timestamppb.New(s.clock.Now()),
+	}
+}
+
+// networkConfigPayload is the payload of an APPLY_NETWORK_CONFIG instruction
+type networkConfigPayload struct {
+	CIDR    string `json:"cidr"`
+	Gateway string `json:"gateway"`
+}
+
+// applyNetworkConfigInstruction returns an APPLY_NETWORK_CONFIG instruction
+// when the reconciler has flagged the agent as drifted from its cluster config
+func (s *AgentService) applyNetworkConfigInstruction(agent *v1.Agent, cluster *v1.Cluster) *v1.Instruction {
+	if !agent.ConfigDrifted || cluster.NetworkConfig == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(networkConfigPayload{
+		CIDR:    cluster.NetworkConfig.Cidr,
+		Gateway: cluster.NetworkConfig.Gateway,
+	})
+	if err != nil {
+		log.Printf("Failed to build network config payload for agent %s: %v", agent.Id, err)
+		return nil
+	}
+
+	log.Printf("Requesting network config apply from drifted agent %s", agent.Id)
+	return &v1.Instruction{
+		Id:        uuid.New().String(),
+		Type:      v1.InstructionType_INSTRUCTION_TYPE_APPLY_NETWORK_CONFIG,
+		Payload:   string(payload),
+		CreatedAt: timestamppb.New(s.clock.Now()),
+	}
+}
+
+// drainInstructions creates the instructions sent to agents of a cordoned cluster
+func (s *AgentService) drainInstructions(agent *v1.Agent) []*v1.Instruction {
+	log.Printf("Requesting drain from agent %s", agent.Id)
+	return []*v1.Instruction{
+		{
+			Id:        uuid.New().String(),
+			Type:      v1.InstructionType_INSTRUCTION_TYPE_DRAIN,
+			Payload:   `{}`,
+			CreatedAt: timestamppb.New(s.clock.Now()),
+		},
+	}
+}
+
+// decommissionInstructions returns the cleanup instruction sent to an agent
+// marked for decommission, in place of all other instructions
+func (s *AgentService) decommissionInstructions(agent *v1.Agent) []*v1.Instruction {
+	log.Printf("Requesting decommission cleanup from agent %s", agent.Id)
+	return []*v1.Instruction{
+		{
+			Id:        uuid.New().String(),
+			Type:      v1.InstructionType_INSTRUCTION_TYPE_DECOMMISSION,
+			Payload:   `{}`,
+			CreatedAt: timestamppb.New(s.clock.Now()),
+		},
+	}
+}
+
+// restartInstructions returns the restart instruction sent to an agent an
+// operator asked to restart, in place of all other instructions, which would
+// be cut short by the restart
+func (s *AgentService) restartInstructions(agent *v1.Agent) []*v1.Instruction {
+	log.Printf("Requesting restart from agent %s", agent.Id)
+	return []*v1.Instruction{
+		{
+			Id:        uuid.New().String(),
+			Type:      v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT,
+			Payload:   `{}`,
+			CreatedAt: timestamppb.New(s.clock.Now()),
+		},
+	}
+}
+
+// checkIPUniqueness detects another active agent in the same cluster reporting
+// the same IP address. In strict mode the registration is rejected, otherwise
+// the collision is only logged.
+func (s *AgentService) checkIPUniqueness(ctx context.Context, req *v1.RegisterAgentRequest) error {
+	if req.IpAddress == "" {
+		return nil
+	}
+
+	agents, err := s.storage.ListAgentsByIP(ctx, req.ClusterId, req.IpAddress)
+	if err != nil {
+		return status.Error(codes.Internal, fmt.Sprintf("failed to check IP uniqueness: %v", err))
+	}
+
+	for _, agent := range agents {
+		if agent.Id == req.Id || agent.Status != v1.AgentStatus_AGENT_STATUS_ACTIVE {
+			continue
+		}
+		if s.strictIPUniqueness {
+			return conflictError(ResourceTypeAgent, agent.Id,
+				fmt.Sprintf("IP address %s is already used by agent %s in cluster %s", req.IpAddress, agent.Id, req.ClusterId))
+		}
+		log.Printf("Warning: agent %s reports IP %s already used by agent %s in cluster %s",
+			req.Id, req.IpAddress, agent.Id, req.ClusterId)
+	}
+
+	return nil
+}
+
+// checkTotalAgents rejects a new agent once the global agent cap is reached
+func (s *AgentService) checkTotalAgents(ctx context.Context) error {
+	if s.maxTotalAgents <= 0 {
+		return nil
+	}
+
+	count, err := s.storage.CountAgents(ctx)
+	if err != nil {
+		return status.Error(codes.Internal, fmt.Sprintf("failed to count agents: %v", err))
+	}
+	if count >= s.maxTotalAgents {
+		return status.Error(codes.ResourceExhausted,
+			fmt.Sprintf("agent limit reached: %d agents registered, maximum is %d", count, s.maxTotalAgents))
+	}
+	return nil
+}
+
+// validateRegisterRequest validates the agent registration request
+func (s *AgentService) validateRegisterRequest(req *v1.RegisterAgentRequest) error {
+	if req.Id == "" {
+		return fmt.Errorf("agent ID is required")
+	}
+	if req.ClusterId == "" {
+		return fmt.Errorf("cluster ID is required")
+	}
+	if req.IpAddress != "" && net.ParseIP(req.IpAddress) == nil {
+		return enforce(s.strictValidation, "registration of agent "+req.Id,
+			fmt.Errorf("invalid IP address %q", req.IpAddress))
 	}
 	return nil
 }
@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/filanov/netctrl-server/internal/storage"
@@ -18,20 +21,136 @@ const (
 
 	// MonitorCheckInterval is how often the monitor checks agent states
 	MonitorCheckInterval = 30 * time.Second
+
+	// DefaultStaleThreshold is how long an agent may be silent before it is marked stale
+	DefaultStaleThreshold = time.Hour
+
+	// DefaultExpiredThreshold is how long an agent may be silent before it is
+	// marked expired and eligible for cleanup
+	DefaultExpiredThreshold = 24 * time.Hour
+
+	// PollIntervalDriftFactor is how far, as a ratio in either direction, an
+	// agent's reported poll interval may be from the instructed one before the
+	// monitor flags it
+	PollIntervalDriftFactor = 2
+
+	// DefaultMonitorUpdateWorkers is how many agent status updates a check
+	// cycle writes concurrently
+	DefaultMonitorUpdateWorkers = 8
+
+	// AgentMonitorLockKey identifies the leader lock shared by monitor replicas
+	AgentMonitorLockKey int64 = 0x6e6574637472 // "netctr"
 )
 
 // AgentMonitor monitors agent health and updates their status
 type AgentMonitor struct {
 	storage storage.Storage
 	stopCh  chan struct{}
+
+	// locker elects a single monitoring replica; nil runs unconditionally
+	locker storage.Locker
+	lock   storage.Lock
+
+	// Silence after which agents escalate to each status, timed by clock
+	inactiveThreshold time.Duration
+	staleThreshold    time.Duration
+	expiredThreshold  time.Duration
+	clock             Clock
+
+	// updateWorkers bounds the concurrent status updates of a check cycle
+	updateWorkers int
+
+	// checkMu serializes check cycles, which share lastStatus
+	checkMu    sync.Mutex
+	lastStatus map[string]v1.AgentStatus
+
+	// Cumulative status transitions, for alerting on transition storms
+	inactiveTransitions atomic.Uint64
+	activeTransitions   atomic.Uint64
+
+	// Publishes escalations downstream; nil emits nothing
+	events *EventDispatcher
+}
+
+// escalationRank orders the statuses the monitor escalates silent agents
+// through; the monitor never moves an agent to a lower rank
+var escalationRank = map[v1.AgentStatus]int{
+	v1.AgentStatus_AGENT_STATUS_ACTIVE:   1,
+	v1.AgentStatus_AGENT_STATUS_INACTIVE: 2,
+	v1.AgentStatus_AGENT_STATUS_STALE:    3,
+	v1.AgentStatus_AGENT_STATUS_EXPIRED:  4,
+}
+
+// AgentMonitorOption configures optional AgentMonitor behavior
+type AgentMonitorOption func(*AgentMonitor)
+
+// WithLeaderLock makes the monitor check agents only while it holds the
+// shared leader lock, so that among several replicas only one is active and
+// the others stand by
+func WithLeaderLock(locker storage.Locker) AgentMonitorOption {
+	return func(m *AgentMonitor) {
+		m.locker = locker
+	}
+}
+
+// WithEscalationThresholds sets how long an agent may be silent before it
+// is marked inactive, stale and expired. Zero keeps the default for that status.
+func WithEscalationThresholds(inactive, stale, expired time.Duration) AgentMonitorOption {
+	return func(m *AgentMonitor) {
+		if inactive > 0 {
+			m.inactiveThreshold = inactive
+		}
+		if stale > 0 {
+			m.staleThreshold = stale
+		}
+		if expired > 0 {
+			m.expiredThreshold = expired
+		}
+	}
+}
+
+// WithUpdateWorkers sets how many agent status updates a check cycle writes
+// concurrently. Values below 1 keep the default.
+func WithUpdateWorkers(workers int) AgentMonitorOption {
+	return func(m *AgentMonitor) {
+		if workers > 0 {
+			m.updateWorkers = workers
+		}
+	}
+}
+
+// WithMonitorClock replaces the clock agent silence is measured against
+func WithMonitorClock(clock Clock) AgentMonitorOption {
+	return func(m *AgentMonitor) {
+		m.clock = clock
+	}
+}
+
+// WithMonitorEventDispatcher publishes the status changes the monitor makes
+// through the given dispatcher
+func WithMonitorEventDispatcher(dispatcher *EventDispatcher) AgentMonitorOption {
+	return func(m *AgentMonitor) {
+		m.events = dispatcher
+	}
 }
 
 // NewAgentMonitor creates a new agent monitor
-func NewAgentMonitor(store storage.Storage) *AgentMonitor {
-	return &AgentMonitor{
-		storage: store,
-		stopCh:  make(chan struct{}),
+func NewAgentMonitor(store storage.Storage, opts ...AgentMonitorOption) *AgentMonitor {
+	m := &AgentMonitor{
+		storage:    store,
+		stopCh:     make(chan struct{}),
+		lastStatus: make(map[string]v1.AgentStatus),
+
+		inactiveThreshold: time.Duration(PollIntervalSeconds*InactiveThresholdMultiplier) * time.Second,
+		staleThreshold:    DefaultStaleThreshold,
+		expiredThreshold:  DefaultExpiredThreshold,
+		clock:             realClock{},
+		updateWorkers:     DefaultMonitorUpdateWorkers,
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
 }
 
 // Start begins the agent monitoring loop
@@ -39,6 +158,7 @@ func (m *AgentMonitor) Start(ctx context.Context) {
 	log.Println("Starting agent monitor...")
 	ticker := time.NewTicker(MonitorCheckInterval)
 	defer ticker.Stop()
+	defer m.releaseLeadership()
 
 	for {
 		select {
@@ -49,11 +169,57 @@ func (m *AgentMonitor) Start(ctx context.Context) {
 			log.Println("Agent monitor stopped")
 			return
 		case <-ticker.C:
-			m.checkAgentStates(ctx)
+			if m.IsLeader(ctx) {
+				m.checkAgentStates(ctx)
+			}
 		}
 	}
 }
 
+// IsLeader reports whether this monitor may check agents, taking the leader
+// lock if it is free. Monitors without a leader lock always lead.
+func (m *AgentMonitor) IsLeader(ctx context.Context) bool {
+	if m.locker == nil {
+		return true
+	}
+
+	if m.lock != nil {
+		err := m.lock.Check(ctx)
+		if err == nil {
+			return true
+		}
+		log.Printf("Agent monitor lost leadership: %v", err)
+		m.releaseLeadership()
+	}
+
+	lock, err := m.locker.TryLock(ctx, AgentMonitorLockKey)
+	if err != nil {
+		log.Printf("Agent monitor failed to take leader lock: %v", err)
+		return false
+	}
+	if lock == nil {
+		return false
+	}
+
+	log.Println("Agent monitor acquired leadership")
+	m.lock = lock
+	return true
+}
+
+// releaseLeadership gives up the leader lock, if held, so a standby replica can take over
+func (m *AgentMonitor) releaseLeadership() {
+	if m.lock == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := m.lock.Unlock(ctx); err != nil {
+		log.Printf("Agent monitor failed to release leader lock: %v", err)
+	}
+	m.lock = nil
+}
+
 // Stop stops the agent monitor
 func (m *AgentMonitor) Stop() {
 	close(m.stopCh)
@@ -64,8 +230,55 @@ func (m *AgentMonitor) CheckAgentStatesOnce(ctx context.Context) {
 	m.checkAgentStates(ctx)
 }
 
-// checkAgentStates checks all agents and updates their status based on last_seen
+// TransitionCounts returns the cumulative number of active agents marked
+// inactive (or further) and of agents observed returning to active since the
+// monitor started
+func (m *AgentMonitor) TransitionCounts() (inactive, active uint64) {
+	return m.inactiveTransitions.Load(), m.activeTransitions.Load()
+}
+
+// statusForSilence returns the status an agent silent for the given time
+// escalates to
+func (m *AgentMonitor) statusForSilence(silence time.Duration) v1.AgentStatus {
+	switch {
+	case silence > m.expiredThreshold:
+		return v1.AgentStatus_AGENT_STATUS_EXPIRED
+	case silence > m.staleThreshold:
+		return v1.AgentStatus_AGENT_STATUS_STALE
+	case silence > m.inactiveThreshold:
+		return v1.AgentStatus_AGENT_STATUS_INACTIVE
+	default:
+		return v1.AgentStatus_AGENT_STATUS_ACTIVE
+	}
+}
+
+// pollIntervalDrifted reports whether an agent's reported poll interval is
+// more than PollIntervalDriftFactor off the interval it was instructed to use
+func pollIntervalDrifted(agent *v1.Agent) bool {
+	reported, instructed := agent.ReportedPollIntervalSeconds, agent.InstructedPollIntervalSeconds
+	if reported <= 0 || instructed <= 0 {
+		return false
+	}
+	return reported > instructed*PollIntervalDriftFactor || reported*PollIntervalDriftFactor < instructed
+}
+
+// statusUpdate is an escalation or poll interval drift change decided by a
+// check cycle
+type statusUpdate struct {
+	agent    *v1.Agent
+	previous v1.AgentStatus
+	flagged  bool // newly flagged for poll interval drift
+	err      error
+}
+
+// checkAgentStates checks all agents, escalates the status of silent ones
+// based on last_seen and flags agents polling off their instructed interval.
+// Cycles never overlap: the monitor loop runs them
+// inline and checkMu serializes direct calls.
 func (m *AgentMonitor) checkAgentStates(ctx context.Context) {
+	m.checkMu.Lock()
+	defer m.checkMu.Unlock()
+
 	// List all agents
 	agents, err := m.storage.ListAgents(ctx, "")
 	if err != nil {
@@ -73,26 +286,115 @@ func (m *AgentMonitor) checkAgentStates(ctx context.Context) {
 		return
 	}
 
-	// Calculate inactivity threshold: 3 poll intervals
-	inactiveThreshold := time.Duration(PollIntervalSeconds*InactiveThresholdMultiplier) * time.Second
-	now := time.Now()
+	now := m.clock.Now()
+
+	var markedInactive, markedStale, markedExpired, reactivated uint64
+	seen := make(map[string]v1.AgentStatus, len(agents))
+	var updates []*statusUpdate
 
 	for _, agent := range agents {
-		if agent.LastSeen == nil {
+		// An agent seen escalated last cycle that is active again was revived by a poll
+		if escalationRank[m.lastStatus[agent.Id]] > escalationRank[v1.AgentStatus_AGENT_STATUS_ACTIVE] &&
+			agent.Status == v1.AgentStatus_AGENT_STATUS_ACTIVE {
+			reactivated++
+		}
+		seen[agent.Id] = agent.Status
+
+		// Statuses only escalate here; polls bring agents back to active
+		previous := agent.Status
+		if current, tracked := escalationRank[agent.Status]; agent.LastSeen != nil && tracked {
+			if target := m.statusForSilence(now.Sub(agent.LastSeen.AsTime())); escalationRank[target] > current {
+				agent.Status = target
+			}
+		}
+
+		drifted := pollIntervalDrifted(agent)
+		if agent.Status == previous && drifted == agent.PollIntervalDrifted {
 			continue
 		}
+		flagged := drifted && !agent.PollIntervalDrifted
+		agent.PollIntervalDrifted = drifted
+		updates = append(updates, &statusUpdate{agent: agent, previous: previous, flagged: flagged})
+	}
 
-		lastSeenTime := agent.LastSeen.AsTime()
-		timeSinceLastSeen := now.Sub(lastSeenTime)
+	m.applyUpdates(ctx, updates)
 
-		// Check if agent should be marked as inactive
-		if timeSinceLastSeen > inactiveThreshold && agent.Status == v1.AgentStatus_AGENT_STATUS_ACTIVE {
-			log.Printf("Marking agent %s as inactive (last seen: %v ago)", agent.Id, timeSinceLastSeen)
-			agent.Status = v1.AgentStatus_AGENT_STATUS_INACTIVE
+	for _, update := range updates {
+		// An agent written since the listing, by a poll or an operator
+		// request, is left as written and re-evaluated next cycle
+		if errors.Is(update.err, storage.ErrConflict) {
+			continue
+		}
+		if update.err != nil {
+			log.Printf("Failed to update agent %s status: %v", update.agent.Id, update.err)
+			continue
+		}
+		seen[update.agent.Id] = update.agent.Status
 
-			if err := m.storage.UpdateAgent(ctx, agent); err != nil {
-				log.Printf("Failed to update agent %s status: %v", agent.Id, err)
-			}
+		if update.flagged {
+			log.Printf("Warning: agent %s polls every %ds but was instructed to poll every %ds",
+				update.agent.Id, update.agent.ReportedPollIntervalSeconds, update.agent.InstructedPollIntervalSeconds)
 		}
+		if update.agent.Status == update.previous {
+			continue
+		}
+		m.events.emit(LifecycleEvent{
+			Type:           LifecycleAgentStatusChanged,
+			AgentID:        update.agent.Id,
+			ClusterID:      update.agent.ClusterId,
+			PreviousStatus: update.previous,
+			Status:         update.agent.Status,
+			Time:           now,
+		})
+
+		if update.previous == v1.AgentStatus_AGENT_STATUS_ACTIVE {
+			markedInactive++
+		}
+		switch update.agent.Status {
+		case v1.AgentStatus_AGENT_STATUS_STALE:
+			markedStale++
+		case v1.AgentStatus_AGENT_STATUS_EXPIRED:
+			markedExpired++
+		}
+	}
+
+	m.lastStatus = seen
+	m.inactiveTransitions.Add(markedInactive)
+	m.activeTransitions.Add(reactivated)
+
+	// One aggregated line per cycle keeps transition storms readable
+	if markedInactive > 0 {
+		log.Printf("%d agents marked inactive this cycle", markedInactive)
+	}
+	if markedStale > 0 {
+		log.Printf("%d agents marked stale this cycle", markedStale)
+	}
+	if markedExpired > 0 {
+		log.Printf("%d agents marked expired and eligible for cleanup this cycle", markedExpired)
+	}
+	if reactivated > 0 {
+		log.Printf("%d agents reactivated this cycle", reactivated)
+	}
+}
+
+// applyUpdates writes the escalated statuses using at most updateWorkers
+// concurrent storage updates, recording each update's error
+func (m *AgentMonitor) applyUpdates(ctx context.Context, updates []*statusUpdate) {
+	jobs := make(chan *statusUpdate)
+	var wg sync.WaitGroup
+	for i := 0; i < min(m.updateWorkers, len(updates)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for update := range jobs {
+				update.err = m.storage.UpdateAgent(ctx, update.agent)
+			}
+		}()
+	}
+
+	for _, update := range updates {
+		jobs <- update
 	}
+	close(jobs)
+	wg.Wait()
 }
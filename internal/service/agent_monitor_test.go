@@ -3,6 +3,7 @@ package service_test
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -10,6 +11,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage"
 	"github.com/filanov/netctrl-server/internal/storage/mock"
 	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
 )
@@ -128,6 +130,35 @@ var _ = Describe("AgentMonitor", func() {
 			Expect(updatedAgent.Status).To(Equal(v1.AgentStatus_AGENT_STATUS_INACTIVE))
 		})
 
+		It("should leave an agent written since the listing to the next cycle", func() {
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+
+			agent, err := storage.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			agent.LastSeen = timestamppb.New(time.Now().Add(-181 * time.Second))
+			Expect(storage.UpdateAgent(ctx, agent)).To(Succeed())
+
+			// An operator asks for a restart after the monitor listed the agent
+			interleaving := &interleavingStorage{Storage: storage, interleave: func() {
+				_, err := agentService.RestartAgent(ctx, &v1.RestartAgentRequest{Id: "agent-1"})
+				Expect(err).NotTo(HaveOccurred())
+			}}
+			service.NewAgentMonitor(interleaving).CheckAgentStatesOnce(ctx)
+
+			agent, err = storage.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agent.RestartRequested).To(BeTrue())
+			Expect(agent.Status).To(Equal(v1.AgentStatus_AGENT_STATUS_ACTIVE))
+
+			monitor.CheckAgentStatesOnce(ctx)
+
+			agent, err = storage.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agent.RestartRequested).To(BeTrue())
+			Expect(agent.Status).To(Equal(v1.AgentStatus_AGENT_STATUS_INACTIVE))
+		})
+
 		It("should handle multiple agents with different states", func() {
 			// Register multiple agents
 			for i := 1; i <= 3; i++ {
@@ -170,4 +201,330 @@ var _ = Describe("AgentMonitor", func() {
 			Expect(updated3.Status).To(Equal(v1.AgentStatus_AGENT_STATUS_ACTIVE))
 		})
 	})
+
+	Describe("Transition counters", func() {
+		registerStale := func(count int) {
+			for i := 1; i <= count; i++ {
+				id := fmt.Sprintf("agent-%d", i)
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+					Id:        id,
+					ClusterId: testClusterId,
+					Hostname:  fmt.Sprintf("node%d", i),
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				agent, err := storage.GetAgent(ctx, id)
+				Expect(err).NotTo(HaveOccurred())
+				agent.LastSeen = timestamppb.New(time.Now().Add(-200 * time.Second))
+				Expect(storage.UpdateAgent(ctx, agent)).To(Succeed())
+			}
+		}
+
+		It("should count agents marked inactive in a cycle", func() {
+			registerStale(3)
+
+			monitor.CheckAgentStatesOnce(ctx)
+
+			inactive, active := monitor.TransitionCounts()
+			Expect(inactive).To(Equal(uint64(3)))
+			Expect(active).To(BeZero())
+
+			// Already inactive agents are not counted again
+			monitor.CheckAgentStatesOnce(ctx)
+			inactive, _ = monitor.TransitionCounts()
+			Expect(inactive).To(Equal(uint64(3)))
+		})
+
+		It("should count agents returning to active after polling", func() {
+			registerStale(2)
+			monitor.CheckAgentStatesOnce(ctx)
+
+			_, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			monitor.CheckAgentStatesOnce(ctx)
+
+			inactive, active := monitor.TransitionCounts()
+			Expect(inactive).To(Equal(uint64(2)))
+			Expect(active).To(Equal(uint64(1)))
+		})
+	})
+})
+
+var _ = Describe("AgentMonitor escalation", func() {
+	var (
+		store        *mock.Storage
+		monitor      *service.AgentMonitor
+		agentService *service.AgentService
+		ctx          context.Context
+		clock        *service.FakeClock
+	)
+
+	// advance moves the clock forward and runs a check cycle
+	advance := func(d time.Duration) v1.AgentStatus {
+		clock.Advance(d)
+		monitor.CheckAgentStatesOnce(ctx)
+		agent, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		return agent.Status
+	}
+
+	BeforeEach(func() {
+		store = mock.New()
+		ctx = context.Background()
+		clock = service.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		monitor = service.NewAgentMonitor(store,
+			service.WithEscalationThresholds(3*time.Minute, time.Hour, 24*time.Hour),
+			service.WithMonitorClock(clock),
+		)
+		agentService = service.NewAgentService(store, service.WithClock(clock))
+
+		cluster, err := service.NewClusterService(store).CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: cluster.Cluster.Id})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should walk a silent agent through each status as time advances", func() {
+		Expect(advance(2 * time.Minute)).To(Equal(v1.AgentStatus_AGENT_STATUS_ACTIVE))
+		Expect(advance(2 * time.Minute)).To(Equal(v1.AgentStatus_AGENT_STATUS_INACTIVE))
+		Expect(advance(30 * time.Minute)).To(Equal(v1.AgentStatus_AGENT_STATUS_INACTIVE))
+		Expect(advance(30 * time.Minute)).To(Equal(v1.AgentStatus_AGENT_STATUS_STALE))
+		Expect(advance(12 * time.Hour)).To(Equal(v1.AgentStatus_AGENT_STATUS_STALE))
+		Expect(advance(12 * time.Hour)).To(Equal(v1.AgentStatus_AGENT_STATUS_EXPIRED))
+		Expect(advance(48 * time.Hour)).To(Equal(v1.AgentStatus_AGENT_STATUS_EXPIRED))
+	})
+
+	It("should escalate straight to the status the silence warrants", func() {
+		Expect(advance(2 * time.Hour)).To(Equal(v1.AgentStatus_AGENT_STATUS_STALE))
+
+		inactive, _ := monitor.TransitionCounts()
+		Expect(inactive).To(Equal(uint64(1)))
+	})
+
+	It("should count a stale agent that polls again as reactivated", func() {
+		Expect(advance(2 * time.Hour)).To(Equal(v1.AgentStatus_AGENT_STATUS_STALE))
+
+		_, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-1"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(advance(0)).To(Equal(v1.AgentStatus_AGENT_STATUS_ACTIVE))
+		_, active := monitor.TransitionCounts()
+		Expect(active).To(Equal(uint64(1)))
+	})
+})
+
+var _ = Describe("AgentMonitor poll interval drift", func() {
+	var (
+		store        *mock.Storage
+		monitor      *service.AgentMonitor
+		agentService *service.AgentService
+		ctx          context.Context
+	)
+
+	// pollReporting polls as agent-1 reporting the given interval, runs a
+	// check cycle and returns the stored agent
+	pollReporting := func(interval int32) *v1.Agent {
+		_, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{
+			AgentId:                     "agent-1",
+			ReportedPollIntervalSeconds: interval,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		monitor.CheckAgentStatesOnce(ctx)
+
+		agent, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		return agent
+	}
+
+	BeforeEach(func() {
+		store = mock.New()
+		ctx = context.Background()
+		monitor = service.NewAgentMonitor(store)
+		agentService = service.NewAgentService(store)
+
+		cluster, err := service.NewClusterService(store).CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: cluster.Cluster.Id})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should record the instructed and reported intervals", func() {
+		agent := pollReporting(60)
+		Expect(agent.InstructedPollIntervalSeconds).To(BeEquivalentTo(service.PollIntervalSeconds))
+		Expect(agent.ReportedPollIntervalSeconds).To(BeEquivalentTo(60))
+		Expect(agent.PollIntervalDrifted).To(BeFalse())
+	})
+
+	It("should tolerate an interval close to the instructed one", func() {
+		Expect(pollReporting(90).PollIntervalDrifted).To(BeFalse())
+		Expect(pollReporting(45).PollIntervalDrifted).To(BeFalse())
+	})
+
+	It("should flag an agent reporting a wildly different interval", func() {
+		Expect(pollReporting(3600).PollIntervalDrifted).To(BeTrue())
+		Expect(pollReporting(5).PollIntervalDrifted).To(BeTrue())
+	})
+
+	It("should clear the flag once the agent follows the instructed interval", func() {
+		Expect(pollReporting(3600).PollIntervalDrifted).To(BeTrue())
+		Expect(pollReporting(60).PollIntervalDrifted).To(BeFalse())
+	})
+
+	It("should not flag agents that do not report an interval", func() {
+		Expect(pollReporting(0).PollIntervalDrifted).To(BeFalse())
+	})
+
+	It("should reject a negative reported interval", func() {
+		_, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{
+			AgentId:                     "agent-1",
+			ReportedPollIntervalSeconds: -1,
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+// fakeLocker is an in-memory storage.Locker shared by monitor replicas
+type fakeLocker struct {
+	holder *fakeLock
+}
+
+type fakeLock struct {
+	locker *fakeLocker
+	lost   bool
+}
+
+func (l *fakeLocker) TryLock(ctx context.Context, key int64) (storage.Lock, error) {
+	if l.holder != nil {
+		return nil, nil
+	}
+	l.holder = &fakeLock{locker: l}
+	return l.holder, nil
+}
+
+func (l *fakeLock) Check(ctx context.Context) error {
+	if l.lost {
+		return fmt.Errorf("connection lost")
+	}
+	return nil
+}
+
+func (l *fakeLock) Unlock(ctx context.Context) error {
+	if l.locker.holder == l {
+		l.locker.holder = nil
+	}
+	return nil
+}
+
+var _ = Describe("AgentMonitor leader election", func() {
+	var (
+		locker  *fakeLocker
+		leader  *service.AgentMonitor
+		standby *service.AgentMonitor
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		store := mock.New()
+		locker = &fakeLocker{}
+		leader = service.NewAgentMonitor(store, service.WithLeaderLock(locker))
+		standby = service.NewAgentMonitor(store, service.WithLeaderLock(locker))
+		ctx = context.Background()
+	})
+
+	It("should always lead without a leader lock", func() {
+		Expect(service.NewAgentMonitor(mock.New()).IsLeader(ctx)).To(BeTrue())
+	})
+
+	It("should let only one replica lead", func() {
+		Expect(leader.IsLeader(ctx)).To(BeTrue())
+		Expect(standby.IsLeader(ctx)).To(BeFalse())
+		Expect(leader.IsLeader(ctx)).To(BeTrue())
+	})
+
+	It("should let a standby take over once the leader loses its lock", func() {
+		Expect(leader.IsLeader(ctx)).To(BeTrue())
+		Expect(standby.IsLeader(ctx)).To(BeFalse())
+
+		// A dropped session releases the lock server-side
+		locker.holder.lost = true
+		locker.holder = nil
+
+		Expect(standby.IsLeader(ctx)).To(BeTrue())
+		Expect(leader.IsLeader(ctx)).To(BeFalse())
+	})
+})
+
+// concurrencyTrackingStorage records the peak number of concurrent agent updates
+type concurrencyTrackingStorage struct {
+	*mock.Storage
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (s *concurrencyTrackingStorage) UpdateAgent(ctx context.Context, agent *v1.Agent) error {
+	s.mu.Lock()
+	s.inFlight++
+	s.peak = max(s.peak, s.inFlight)
+	s.mu.Unlock()
+
+	// Hold the slot briefly so updates overlap
+	time.Sleep(time.Millisecond)
+
+	s.mu.Lock()
+	s.inFlight--
+	s.mu.Unlock()
+	return s.Storage.UpdateAgent(ctx, agent)
+}
+
+var _ = Describe("AgentMonitor update workers", func() {
+	const agentCount = 200
+
+	var (
+		store *concurrencyTrackingStorage
+		ctx   context.Context
+	)
+
+	BeforeEach(func() {
+		store = &concurrencyTrackingStorage{Storage: mock.New()}
+		ctx = context.Background()
+
+		cluster, err := service.NewClusterService(store).CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+		Expect(err).NotTo(HaveOccurred())
+		agentService := service.NewAgentService(store)
+		for i := 0; i < agentCount; i++ {
+			id := fmt.Sprintf("agent-%d", i)
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: id, ClusterId: cluster.Cluster.Id})
+			Expect(err).NotTo(HaveOccurred())
+
+			agent, err := store.GetAgent(ctx, id)
+			Expect(err).NotTo(HaveOccurred())
+			agent.LastSeen = timestamppb.New(time.Now().Add(-10 * time.Minute))
+			Expect(store.Storage.UpdateAgent(ctx, agent)).To(Succeed())
+		}
+	})
+
+	It("should transition every silent agent in one cycle with bounded concurrency", func() {
+		monitor := service.NewAgentMonitor(store, service.WithUpdateWorkers(4))
+		monitor.CheckAgentStatesOnce(ctx)
+
+		agents, err := store.ListAgents(ctx, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agents).To(HaveLen(agentCount))
+		for _, agent := range agents {
+			Expect(agent.Status).To(Equal(v1.AgentStatus_AGENT_STATUS_INACTIVE))
+		}
+
+		inactive, _ := monitor.TransitionCounts()
+		Expect(inactive).To(BeEquivalentTo(agentCount))
+		Expect(store.peak).To(BeNumerically(">", 1))
+		Expect(store.peak).To(BeNumerically("<=", 4))
+	})
+
+	It("should update sequentially with a single worker", func() {
+		service.NewAgentMonitor(store, service.WithUpdateWorkers(1)).CheckAgentStatesOnce(ctx)
+		Expect(store.peak).To(Equal(1))
+	})
 })
@@ -2,29 +2,81 @@ package service_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage"
 	"github.com/filanov/netctrl-server/internal/storage/mock"
 	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
 )
 
+// clusterDeletingStorage deletes an agent's cluster right after the agent is
+// fetched, as if the cluster were deleted while the agent was polling
+type clusterDeletingStorage struct {
+	*mock.Storage
+}
+
+func (s *clusterDeletingStorage) GetAgent(ctx context.Context, id string) (*v1.Agent, error) {
+	agent, err := s.Storage.GetAgent(ctx, id)
+	if err == nil {
+		_ = s.Storage.DeleteCluster(ctx, agent.ClusterId)
+	}
+	return agent, err
+}
+
+// interleavingStorage runs a request once right before the next agent write,
+// as if it landed while the writer held a stale copy of the agent
+type interleavingStorage struct {
+	*mock.Storage
+	interleave func()
+}
+
+func (s *interleavingStorage) UpdateAgent(ctx context.Context, agent *v1.Agent) error {
+	if interleave := s.interleave; interleave != nil {
+		s.interleave = nil
+		interleave()
+	}
+	return s.Storage.UpdateAgent(ctx, agent)
+}
+
+// pageRecordingStorage records the agent queries ListAgents sends the backend
+type pageRecordingStorage struct {
+	*mock.Storage
+	queries []storage.AgentQuery
+}
+
+func (s *pageRecordingStorage) ListAgentsPage(ctx context.Context, query storage.AgentQuery) ([]*v1.Agent, error) {
+	s.queries = append(s.queries, query)
+	return s.Storage.ListAgentsPage(ctx, query)
+}
+
 var _ = Describe("AgentService", func() {
 	var (
 		agentService   *service.AgentService
 		clusterService *service.ClusterService
+		store          *mock.Storage
 		ctx            context.Context
 		testClusterId  string
 	)
 
 	BeforeEach(func() {
-		storage := mock.New()
-		agentService = service.NewAgentService(storage)
-		clusterService = service.NewClusterService(storage)
+		store = mock.New()
+		agentService = service.NewAgentService(store)
+		clusterService = service.NewClusterService(store)
 		ctx = context.Background()
 
 		// Create a test cluster
@@ -38,6 +90,29 @@ var _ = Describe("AgentService", func() {
 	})
 
 	Describe("RegisterAgent", func() {
+		Context("with an invalid IP address", func() {
+			req := func() *v1.RegisterAgentRequest {
+				return &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId, IpAddress: "10.0.1.300"}
+			}
+
+			It("should accept the registration when validation is lenient", func() {
+				resp, err := agentService.RegisterAgent(ctx, req())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Agent.IpAddress).To(Equal("10.0.1.300"))
+			})
+
+			It("should reject the registration when validation is strict", func() {
+				agentService = service.NewAgentService(store, service.WithStrictValidation(true))
+
+				_, err := agentService.RegisterAgent(ctx, req())
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err).To(MatchError(ContainSubstring(`invalid IP address "10.0.1.300"`)))
+
+				_, err = store.GetAgent(ctx, "agent-1")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
 		It("should register a new agent successfully", func() {
 			req := &v1.RegisterAgentRequest{
 				Id:        "agent-1",
@@ -116,6 +191,29 @@ var _ = Describe("AgentService", func() {
 			Expect(st.Message()).To(ContainSubstring("cluster ID is required"))
 		})
 
+		It("should assign agents without a cluster ID to the default cluster", func() {
+			defaultService := service.NewAgentService(store, service.WithDefaultClusterID(testClusterId))
+			resp, err := defaultService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+				Id:       "agent-1",
+				Hostname: "node1",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Agent.ClusterId).To(Equal(testClusterId))
+		})
+
+		It("should keep an explicit cluster ID when a default is configured", func() {
+			createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "explicit"})
+			Expect(err).NotTo(HaveOccurred())
+
+			defaultService := service.NewAgentService(store, service.WithDefaultClusterID(testClusterId))
+			resp, err := defaultService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+				Id:        "agent-1",
+				ClusterId: createResp.Cluster.Id,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Agent.ClusterId).To(Equal(createResp.Cluster.Id))
+		})
+
 		It("should return error when cluster does not exist", func() {
 			req := &v1.RegisterAgentRequest{
 				Id:        "agent-1",
@@ -131,6 +229,284 @@ var _ = Describe("AgentService", func() {
 			Expect(st.Message()).To(ContainSubstring("cluster"))
 			Expect(st.Message()).To(ContainSubstring("not found"))
 		})
+
+		Context("with a colliding IP address", func() {
+			BeforeEach(func() {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+					Id:        "agent-1",
+					ClusterId: testClusterId,
+					IpAddress: "10.0.1.1",
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should allow the registration when strict mode is off", func() {
+				resp, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+					Id:        "agent-2",
+					ClusterId: testClusterId,
+					IpAddress: "10.0.1.1",
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Agent.Id).To(Equal("agent-2"))
+			})
+
+			It("should reject the registration when strict mode is on", func() {
+				strictService := service.NewAgentService(store, service.WithStrictIPUniqueness(true))
+				_, err := strictService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+					Id:        "agent-2",
+					ClusterId: testClusterId,
+					IpAddress: "10.0.1.1",
+				})
+				Expect(err).To(HaveOccurred())
+				st, ok := status.FromError(err)
+				Expect(ok).To(BeTrue())
+				Expect(st.Code()).To(Equal(codes.AlreadyExists))
+
+				conflict := conflictingResource(err)
+				Expect(conflict.ResourceType).To(Equal(service.ResourceTypeAgent))
+				Expect(conflict.ResourceName).To(Equal("agent-1"))
+			})
+
+			It("should allow the same agent to re-register in strict mode", func() {
+				strictService := service.NewAgentService(store, service.WithStrictIPUniqueness(true))
+				_, err := strictService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+					Id:        "agent-1",
+					ClusterId: testClusterId,
+					IpAddress: "10.0.1.1",
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("re-registering into a different cluster", func() {
+			var otherClusterId string
+
+			BeforeEach(func() {
+				createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "other-cluster"})
+				Expect(err).NotTo(HaveOccurred())
+				otherClusterId = createResp.Cluster.Id
+
+				_, err = agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should move the agent by default", func() {
+				resp, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: otherClusterId})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Agent.ClusterId).To(Equal(otherClusterId))
+			})
+
+			It("should move the agent under the allow policy", func() {
+				allowService := service.NewAgentService(store, service.WithClusterChangePolicy(service.ClusterChangeAllow))
+				_, err := allowService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: otherClusterId})
+				Expect(err).NotTo(HaveOccurred())
+
+				agent, err := store.GetAgent(ctx, "agent-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.ClusterId).To(Equal(otherClusterId))
+			})
+
+			It("should refuse the move under the reject policy", func() {
+				rejectService := service.NewAgentService(store, service.WithClusterChangePolicy(service.ClusterChangeReject))
+				_, err := rejectService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: otherClusterId})
+				Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+
+				agent, err := store.GetAgent(ctx, "agent-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.ClusterId).To(Equal(testClusterId))
+			})
+
+			It("should point at reassignment under the require_reassign policy", func() {
+				reassignService := service.NewAgentService(store, service.WithClusterChangePolicy(service.ClusterChangeRequireReassign))
+				_, err := reassignService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: otherClusterId})
+				Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+				Expect(err).To(MatchError(ContainSubstring("reassign it by unregistering it")))
+
+				_, err = reassignService.UnregisterAgent(ctx, &v1.UnregisterAgentRequest{Id: "agent-1"})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = reassignService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: otherClusterId})
+				Expect(err).NotTo(HaveOccurred())
+
+				agent, err := store.GetAgent(ctx, "agent-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.ClusterId).To(Equal(otherClusterId))
+			})
+
+			It("should still allow re-registering into the same cluster under the reject policy", func() {
+				rejectService := service.NewAgentService(store, service.WithClusterChangePolicy(service.ClusterChangeReject))
+				_, err := rejectService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Context("registration source", func() {
+			// fromPeer returns a context for a request arriving from addr
+			fromPeer := func(addr string, md metadata.MD) context.Context {
+				tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+				Expect(err).NotTo(HaveOccurred())
+				return metadata.NewIncomingContext(peer.NewContext(ctx, &peer.Peer{Addr: tcpAddr}), md)
+			}
+
+			It("should record where the agent registered from", func() {
+				regCtx := fromPeer("10.1.2.3:40000", metadata.Pairs("user-agent", "netctrl-agent/1.0"))
+				_, err := agentService.RegisterAgent(regCtx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+
+				resp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: "agent-1"})
+				Expect(err).NotTo(HaveOccurred())
+				source := resp.Agent.LastRegistration
+				Expect(source).NotTo(BeNil())
+				Expect(source.PeerAddress).To(Equal("10.1.2.3:40000"))
+				Expect(source.UserAgent).To(Equal("netctrl-agent/1.0"))
+				Expect(source.ForwardedFor).To(BeEmpty())
+				Expect(source.RegisteredAt).NotTo(BeNil())
+			})
+
+			It("should record the forwarded client of gateway registrations", func() {
+				regCtx := fromPeer("127.0.0.1:50000", metadata.Pairs(
+					"x-forwarded-for", "192.0.2.10",
+					"grpcgateway-user-agent", "curl/8.0",
+				))
+				_, err := agentService.RegisterAgent(regCtx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+
+				agent, err := store.GetAgent(ctx, "agent-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.LastRegistration.ForwardedFor).To(Equal("192.0.2.10"))
+				Expect(agent.LastRegistration.UserAgent).To(Equal("curl/8.0"))
+			})
+
+			It("should update the source on re-registration", func() {
+				_, err := agentService.RegisterAgent(fromPeer("10.1.2.3:40000", nil),
+					&v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = agentService.RegisterAgent(fromPeer("10.9.9.9:41000", nil),
+					&v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+
+				agent, err := store.GetAgent(ctx, "agent-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.LastRegistration.PeerAddress).To(Equal("10.9.9.9:41000"))
+			})
+		})
+
+		Context("install metadata", func() {
+			original := &v1.InstallMetadata{
+				Method:            "ansible",
+				ProvisioningJobId: "job-1234",
+				InstalledBy:       "provisioner@example.com",
+			}
+
+			It("should record install metadata at first registration", func() {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+					Id:              "agent-1",
+					ClusterId:       testClusterId,
+					InstallMetadata: original,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				resp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: "agent-1"})
+				Expect(err).NotTo(HaveOccurred())
+				installed := resp.Agent.InstallMetadata
+				Expect(installed).NotTo(BeNil())
+				Expect(installed.Method).To(Equal("ansible"))
+				Expect(installed.ProvisioningJobId).To(Equal("job-1234"))
+				Expect(installed.InstalledBy).To(Equal("provisioner@example.com"))
+				Expect(installed.RecordedAt).NotTo(BeNil())
+			})
+
+			It("should preserve the original metadata across re-registrations", func() {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+					Id:              "agent-1",
+					ClusterId:       testClusterId,
+					InstallMetadata: original,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				first, err := store.GetAgent(ctx, "agent-1")
+				Expect(err).NotTo(HaveOccurred())
+				recordedAt := first.InstallMetadata.RecordedAt.AsTime()
+
+				_, err = agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+					Id:        "agent-1",
+					ClusterId: testClusterId,
+					InstallMetadata: &v1.InstallMetadata{
+						Method:            "manual",
+						ProvisioningJobId: "job-9999",
+						InstalledBy:       "someone-else",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+
+				resp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: "agent-1"})
+				Expect(err).NotTo(HaveOccurred())
+				installed := resp.Agent.InstallMetadata
+				Expect(installed.Method).To(Equal("ansible"))
+				Expect(installed.ProvisioningJobId).To(Equal("job-1234"))
+				Expect(installed.InstalledBy).To(Equal("provisioner@example.com"))
+				Expect(installed.RecordedAt.AsTime()).To(Equal(recordedAt))
+			})
+
+			It("should record metadata supplied after a registration without it", func() {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+				agent, err := store.GetAgent(ctx, "agent-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.InstallMetadata).To(BeNil())
+
+				_, err = agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+					Id:              "agent-1",
+					ClusterId:       testClusterId,
+					InstallMetadata: original,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				agent, err = store.GetAgent(ctx, "agent-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.InstallMetadata.ProvisioningJobId).To(Equal("job-1234"))
+			})
+		})
+
+		Context("with a global agent cap", func() {
+			var (
+				cappedService  *service.AgentService
+				otherClusterId string
+			)
+
+			BeforeEach(func() {
+				cappedService = service.NewAgentService(store, service.WithMaxTotalAgents(2))
+
+				createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "other-cluster"})
+				Expect(err).NotTo(HaveOccurred())
+				otherClusterId = createResp.Cluster.Id
+
+				_, err = cappedService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = cappedService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-2", ClusterId: otherClusterId})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should reject new agents once the cap is reached across clusters", func() {
+				_, err := cappedService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-3", ClusterId: otherClusterId})
+				Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+
+				_, err = store.GetAgent(ctx, "agent-3")
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should still allow existing agents to re-register", func() {
+				_, err := cappedService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: otherClusterId})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should admit new agents again after one is unregistered", func() {
+				_, err := cappedService.UnregisterAgent(ctx, &v1.UnregisterAgentRequest{Id: "agent-2"})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = cappedService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-3", ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
 	})
 
 	Describe("GetAgent", func() {
@@ -171,6 +547,71 @@ var _ = Describe("AgentService", func() {
 			Expect(ok).To(BeTrue())
 			Expect(st.Code()).To(Equal(codes.InvalidArgument))
 		})
+
+		Context("with a read mask", func() {
+			BeforeEach(func() {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+					Id:        "agent-1",
+					ClusterId: testClusterId,
+					Hostname:  "node1",
+					IpAddress: "10.0.1.1",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				agent, err := store.GetAgent(ctx, "agent-1")
+				Expect(err).NotTo(HaveOccurred())
+				agent.NetworkInterfaces = []*v1.MellanoxNIC{{DeviceName: "mlx5_0", PortCount: 2}}
+				agent.HardwareCollected = true
+				Expect(store.UpdateAgent(ctx, agent)).To(Succeed())
+			})
+
+			It("should return only the requested fields", func() {
+				getResp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{
+					Id:       "agent-1",
+					ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "status"}},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getResp.Agent.Id).To(Equal("agent-1"))
+				Expect(getResp.Agent.Status).To(Equal(v1.AgentStatus_AGENT_STATUS_ACTIVE))
+				Expect(getResp.Agent.Hostname).To(BeEmpty())
+				Expect(getResp.Agent.NetworkInterfaces).To(BeEmpty())
+				Expect(getResp.Agent.HardwareCollected).To(BeFalse())
+
+				// The stored agent must be left intact
+				stored, err := store.GetAgent(ctx, "agent-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stored.NetworkInterfaces).To(HaveLen(1))
+			})
+
+			It("should return the full agent when the mask is omitted", func() {
+				getResp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: "agent-1"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getResp.Agent.Hostname).To(Equal("node1"))
+				Expect(getResp.Agent.NetworkInterfaces).To(HaveLen(1))
+			})
+
+			It("should reject unknown fields", func() {
+				_, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{
+					Id:       "agent-1",
+					ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "bogus"}},
+				})
+				Expect(err).To(HaveOccurred())
+				st, ok := status.FromError(err)
+				Expect(ok).To(BeTrue())
+				Expect(st.Code()).To(Equal(codes.InvalidArgument))
+			})
+
+			It("should trim every agent in a listing", func() {
+				listResp, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{
+					ReadMask: &fieldmaskpb.FieldMask{Paths: []string{"id", "status"}},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(listResp.Agents).To(HaveLen(1))
+				Expect(listResp.Agents[0].Id).To(Equal("agent-1"))
+				Expect(listResp.Agents[0].Status).To(Equal(v1.AgentStatus_AGENT_STATUS_ACTIVE))
+				Expect(listResp.Agents[0].NetworkInterfaces).To(BeEmpty())
+			})
+		})
 	})
 
 	Describe("ListAgents", func() {
@@ -237,6 +678,303 @@ var _ = Describe("AgentService", func() {
 			Expect(listResp.Agents).To(HaveLen(1))
 			Expect(listResp.Agents[0].ClusterId).To(Equal(testClusterId))
 		})
+
+		It("should filter agents by group", func() {
+			for id, group := range map[string]string{"agent-1": "rack-a", "agent-2": "rack-b", "agent-3": "rack-a"} {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+					Id:        id,
+					ClusterId: testClusterId,
+					Group:     group,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			listResp, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{ClusterId: testClusterId, Group: "rack-a"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listResp.Agents).To(HaveLen(2))
+			for _, agent := range listResp.Agents {
+				Expect(agent.Group).To(Equal("rack-a"))
+			}
+		})
+
+		It("should page through agents without duplicates or gaps under concurrent inserts", func() {
+			for i := 1; i <= 5; i++ {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: fmt.Sprintf("agent-%d", i), ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			first, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{PageSize: 2})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.Agents).To(HaveLen(2))
+			Expect(first.NextPageToken).NotTo(BeEmpty())
+
+			// An agent registered mid-listing sorts after every existing agent
+			_, err = agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-0", ClusterId: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+
+			seen := []string{first.Agents[0].Id, first.Agents[1].Id}
+			token := first.NextPageToken
+			for token != "" {
+				page, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{PageSize: 2, PageToken: token})
+				Expect(err).NotTo(HaveOccurred())
+				for _, agent := range page.Agents {
+					seen = append(seen, agent.Id)
+				}
+				token = page.NextPageToken
+			}
+
+			Expect(seen).To(Equal([]string{"agent-1", "agent-2", "agent-3", "agent-4", "agent-5", "agent-0"}))
+		})
+
+		It("should order agents the same with and without pagination", func() {
+			clock := service.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+			agentService = service.NewAgentService(store, service.WithClock(clock))
+			for _, id := range []string{"agent-3", "agent-1", "agent-5", "agent-2", "agent-4"} {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: id, ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+				clock.Advance(time.Second)
+			}
+
+			all, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			unpaginated := make([]string, 0, len(all.Agents))
+			for _, agent := range all.Agents {
+				unpaginated = append(unpaginated, agent.Id)
+			}
+
+			var paginated []string
+			token := ""
+			for {
+				page, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{PageSize: 2, PageToken: token})
+				Expect(err).NotTo(HaveOccurred())
+				for _, agent := range page.Agents {
+					paginated = append(paginated, agent.Id)
+				}
+				if token = page.NextPageToken; token == "" {
+					break
+				}
+			}
+
+			Expect(unpaginated).To(Equal([]string{"agent-3", "agent-1", "agent-5", "agent-2", "agent-4"}))
+			Expect(paginated).To(Equal(unpaginated))
+		})
+
+		It("should have the backend read one page with its filters", func() {
+			recording := &pageRecordingStorage{Storage: store}
+			pagedService := service.NewAgentService(recording)
+			for i := 1; i <= 3; i++ {
+				_, err := pagedService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+					Id: fmt.Sprintf("agent-%d", i), ClusterId: testClusterId, Group: "rack-a",
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			first, err := pagedService.ListAgents(ctx, &v1.ListAgentsRequest{ClusterId: testClusterId, Group: "rack-a", PageSize: 2})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.Agents).To(HaveLen(2))
+			second, err := pagedService.ListAgents(ctx, &v1.ListAgentsRequest{
+				ClusterId: testClusterId, Group: "rack-a", PageSize: 2, PageToken: first.NextPageToken,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.Agents).To(HaveLen(1))
+			Expect(second.NextPageToken).To(BeEmpty())
+
+			Expect(recording.queries).To(HaveLen(2))
+			Expect(recording.queries[0]).To(Equal(storage.AgentQuery{ClusterID: testClusterId, Group: "rack-a", Limit: 3}))
+			Expect(recording.queries[1].AfterID).To(Equal(first.Agents[1].Id))
+			Expect(recording.queries[1].AfterCreatedAt).To(BeTemporally("==", first.Agents[1].CreatedAt.AsTime()))
+			Expect(recording.queries[1].Limit).To(Equal(3))
+		})
+
+		It("should return an empty list for an unknown cluster by default", func() {
+			resp, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{ClusterId: "no-such-cluster"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Agents).To(BeEmpty())
+		})
+
+		It("should fail with NotFound for an unknown cluster when the cluster is required", func() {
+			_, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{ClusterId: "no-such-cluster", RequireCluster: true})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+
+			resp, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{ClusterId: testClusterId, RequireCluster: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Agents).To(BeEmpty())
+		})
+
+		It("should reject an invalid page token", func() {
+			_, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{PageSize: 2, PageToken: "not-a-token"})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+
+		It("should move an agent to the group given on re-registration", func() {
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId, Group: "rack-a"})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId, Group: "rack-b"})
+			Expect(err).NotTo(HaveOccurred())
+
+			listResp, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{Group: "rack-a"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listResp.Agents).To(BeEmpty())
+
+			listResp, err = agentService.ListAgents(ctx, &v1.ListAgentsRequest{Group: "rack-b"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listResp.Agents).To(HaveLen(1))
+		})
+
+		Context("filtering by health", func() {
+			var clock *service.FakeClock
+
+			// reportHealth submits a health check result for the agent
+			reportHealth := func(agentID string, healthy bool) {
+				resp, err := agentService.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+					AgentId:       agentID,
+					InstructionId: "health-" + agentID,
+					Result: &v1.InstructionResult{
+						InstructionType: v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK,
+						Result: &v1.InstructionResult_HealthCheck{
+							HealthCheck: &v1.HealthCheckResult{Healthy: healthy},
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Success).To(BeTrue())
+			}
+
+			BeforeEach(func() {
+				clock = service.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+				agentService = service.NewAgentService(store, service.WithClock(clock))
+
+				for _, id := range []string{"agent-healthy", "agent-unhealthy", "agent-never-healthy", "agent-unreachable"} {
+					_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: id, ClusterId: testClusterId})
+					Expect(err).NotTo(HaveOccurred())
+				}
+				reportHealth("agent-healthy", true)
+				reportHealth("agent-unhealthy", true)
+				reportHealth("agent-unreachable", true)
+
+				clock.Advance(10 * time.Minute)
+				reportHealth("agent-healthy", true)
+				reportHealth("agent-unhealthy", false)
+				reportHealth("agent-never-healthy", false)
+
+				unreachable, err := store.GetAgent(ctx, "agent-unreachable")
+				Expect(err).NotTo(HaveOccurred())
+				unreachable.Status = v1.AgentStatus_AGENT_STATUS_INACTIVE
+				Expect(store.UpdateAgent(ctx, unreachable)).To(Succeed())
+			})
+
+			It("should record when the agent was last healthy", func() {
+				getResp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: "agent-healthy"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getResp.Agent.LastHealthyAt.AsTime()).To(Equal(clock.Now()))
+
+				getResp, err = agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: "agent-unhealthy"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getResp.Agent.LastHealthyAt.AsTime()).To(Equal(clock.Now().Add(-10 * time.Minute)))
+			})
+
+			It("should list active agents without a recent healthy check", func() {
+				resp, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{UnhealthyForSeconds: 300})
+				Expect(err).NotTo(HaveOccurred())
+
+				var ids []string
+				for _, agent := range resp.Agents {
+					ids = append(ids, agent.Id)
+				}
+				Expect(ids).To(ConsistOf("agent-unhealthy", "agent-never-healthy"))
+			})
+
+			It("should not list agents unhealthy for less than the threshold", func() {
+				resp, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{UnhealthyForSeconds: 3600})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Agents).To(BeEmpty())
+			})
+
+			It("should reject a negative threshold", func() {
+				_, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{UnhealthyForSeconds: -1})
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			})
+		})
+	})
+
+	Describe("GetAgentStatuses", func() {
+		BeforeEach(func() {
+			for _, id := range []string{"agent-active", "agent-inactive"} {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: id, ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			agent, err := store.GetAgent(ctx, "agent-inactive")
+			Expect(err).NotTo(HaveOccurred())
+			agent.Status = v1.AgentStatus_AGENT_STATUS_INACTIVE
+			Expect(store.UpdateAgent(ctx, agent)).To(Succeed())
+		})
+
+		It("should return statuses of existing agents and omit missing ones", func() {
+			resp, err := agentService.GetAgentStatuses(ctx, &v1.GetAgentStatusesRequest{
+				Ids: []string{"agent-active", "agent-missing", "agent-inactive"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Statuses).To(Equal(map[string]v1.AgentStatus{
+				"agent-active":   v1.AgentStatus_AGENT_STATUS_ACTIVE,
+				"agent-inactive": v1.AgentStatus_AGENT_STATUS_INACTIVE,
+			}))
+		})
+
+		It("should return an empty map for no IDs", func() {
+			resp, err := agentService.GetAgentStatuses(ctx, &v1.GetAgentStatusesRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Statuses).To(BeEmpty())
+		})
+
+		It("should reject too many IDs", func() {
+			ids := make([]string, service.MaxAgentStatusIDs+1)
+			for i := range ids {
+				ids[i] = fmt.Sprintf("agent-%d", i)
+			}
+			_, err := agentService.GetAgentStatuses(ctx, &v1.GetAgentStatusesRequest{Ids: ids})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("Orphaned agents", func() {
+		BeforeEach(func() {
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+				Id:        "agent-healthy",
+				ClusterId: testClusterId,
+				Hostname:  "node1",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Seed an agent referencing a cluster that no longer exists
+			Expect(store.CreateAgent(ctx, &v1.Agent{
+				Id:        "agent-orphan",
+				ClusterId: "deleted-cluster",
+				Hostname:  "node2",
+				Status:    v1.AgentStatus_AGENT_STATUS_ACTIVE,
+			})).To(Succeed())
+		})
+
+		It("should find agents whose cluster is missing", func() {
+			resp, err := agentService.FindOrphanedAgents(ctx, &v1.FindOrphanedAgentsRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Agents).To(HaveLen(1))
+			Expect(resp.Agents[0].Id).To(Equal("agent-orphan"))
+		})
+
+		It("should reap only orphaned agents", func() {
+			resp, err := agentService.ReapOrphanedAgents(ctx, &v1.ReapOrphanedAgentsRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.AgentIds).To(ConsistOf("agent-orphan"))
+
+			_, err = store.GetAgent(ctx, "agent-orphan")
+			Expect(err).To(HaveOccurred())
+			_, err = store.GetAgent(ctx, "agent-healthy")
+			Expect(err).NotTo(HaveOccurred())
+
+			findResp, err := agentService.FindOrphanedAgents(ctx, &v1.FindOrphanedAgentsRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(findResp.Agents).To(BeEmpty())
+		})
 	})
 
 	Describe("UnregisterAgent", func() {
@@ -271,6 +1009,48 @@ var _ = Describe("AgentService", func() {
 			Expect(st.Code()).To(Equal(codes.NotFound))
 		})
 
+		It("should succeed for non-existent agent when idempotent", func() {
+			req := &v1.UnregisterAgentRequest{Id: "non-existent", Idempotent: true}
+			resp, err := agentService.UnregisterAgent(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Success).To(BeTrue())
+		})
+
+		It("should succeed when an idempotent unregister is retried", func() {
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+				Id:        "agent-1",
+				ClusterId: testClusterId,
+				Hostname:  "node1",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			req := &v1.UnregisterAgentRequest{Id: "agent-1", Idempotent: true}
+			_, err = agentService.UnregisterAgent(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := agentService.UnregisterAgent(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Success).To(BeTrue())
+		})
+
+		It("should return NotFound when a strict unregister is retried", func() {
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+				Id:        "agent-1",
+				ClusterId: testClusterId,
+				Hostname:  "node1",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			req := &v1.UnregisterAgentRequest{Id: "agent-1"}
+			_, err = agentService.UnregisterAgent(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = agentService.UnregisterAgent(ctx, req)
+			st, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(st.Code()).To(Equal(codes.NotFound))
+		})
+
 		It("should return error when ID is empty", func() {
 			req := &v1.UnregisterAgentRequest{Id: ""}
 			_, err := agentService.UnregisterAgent(ctx, req)
@@ -313,6 +1093,49 @@ var _ = Describe("AgentService", func() {
 			Expect(resp.ServerTime).NotTo(BeNil())
 		})
 
+		Context("when the agent's cluster is deleted", func() {
+			expectClusterDeleted := func(resp *v1.GetInstructionsResponse, clusterID string) {
+				Expect(resp.Instructions).To(HaveLen(1))
+				Expect(resp.Instructions[0].Type).To(Equal(v1.InstructionType_INSTRUCTION_TYPE_CLUSTER_DELETED))
+
+				var payload map[string]string
+				Expect(json.Unmarshal([]byte(resp.Instructions[0].Payload), &payload)).To(Succeed())
+				Expect(payload).To(HaveKeyWithValue("cluster_id", clusterID))
+			}
+
+			It("should tell an agent polling while its cluster is deleted", func() {
+				deletingService := service.NewAgentService(&clusterDeletingStorage{Storage: store})
+
+				resp, err := deletingService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				expectClusterDeleted(resp, testClusterId)
+			})
+
+			It("should tell an orphaned agent its cluster is gone", func() {
+				now := timestamppb.Now()
+				Expect(store.CreateAgent(ctx, &v1.Agent{
+					Id:        "agent-orphan",
+					ClusterId: "deleted-cluster",
+					Status:    v1.AgentStatus_AGENT_STATUS_ACTIVE,
+					LastSeen:  now,
+					CreatedAt: now,
+					UpdatedAt: now,
+				})).To(Succeed())
+
+				resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-orphan"})
+				Expect(err).NotTo(HaveOccurred())
+				expectClusterDeleted(resp, "deleted-cluster")
+			})
+
+			It("should return NotFound once the agent itself is deleted", func() {
+				_, err := clusterService.DeleteCluster(ctx, &v1.DeleteClusterRequest{Id: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentId})
+				Expect(status.Code(err)).To(Equal(codes.NotFound))
+			})
+		})
+
 		It("should update agent's last_seen timestamp", func() {
 			// Get agent's initial last_seen
 			getReq := &v1.GetAgentRequest{Id: agentId}
@@ -358,37 +1181,321 @@ var _ = Describe("AgentService", func() {
 			Expect(st.Code()).To(Equal(codes.InvalidArgument))
 		})
 
-	})
+		It("should send spine agents a health check in addition to hardware collection", func() {
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+				Id:        "agent-spine",
+				ClusterId: testClusterId,
+				Role:      v1.AgentRole_AGENT_ROLE_SPINE,
+			})
+			Expect(err).NotTo(HaveOccurred())
 
-	Describe("SubmitInstructionResult", func() {
-		var agentId string
+			resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-spine"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Instructions).To(HaveLen(2))
+			Expect(resp.Instructions[0].Type).To(Equal(v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE))
+			Expect(resp.Instructions[1].Type).To(Equal(v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK))
+		})
 
-		BeforeEach(func() {
-			// Register an agent first
-			registerReq := &v1.RegisterAgentRequest{
-				Id:        "agent-submit-test",
+		It("should send spine agents a health check again only once the interval passes", func() {
+			clock := service.NewFakeClock(time.Now())
+			clockedService := service.NewAgentService(store, service.WithClock(clock))
+			_, err := clockedService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+				Id:        "agent-spine",
 				ClusterId: testClusterId,
-				Hostname:  "test-node",
-				IpAddress: "10.0.1.1",
-			}
-			resp, err := agentService.RegisterAgent(ctx, registerReq)
+				Role:      v1.AgentRole_AGENT_ROLE_SPINE,
+			})
 			Expect(err).NotTo(HaveOccurred())
-			agentId = resp.Agent.Id
-		})
 
-		It("should accept and process hardware collection result", func() {
-			req := &v1.SubmitInstructionResultRequest{
-				AgentId:       agentId,
-				InstructionId: "instruction-123",
+			// checksHealth reports whether the next poll asks for a health check
+			checksHealth := func() bool {
+				resp, err := clockedService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-spine"})
+				Expect(err).NotTo(HaveOccurred())
+				for _, instruction := range resp.Instructions {
+					if instruction.Type == v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK {
+						return true
+					}
+				}
+				return false
+			}
+
+			Expect(checksHealth()).To(BeTrue())
+			_, err = clockedService.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+				AgentId:       "agent-spine",
+				InstructionId: "health-1",
 				Result: &v1.InstructionResult{
-					InstructionType: v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE,
-					Result: &v1.InstructionResult_HardwareCollection{
-						HardwareCollection: &v1.HardwareCollectionResult{
-							NetworkInterfaces: []*v1.MellanoxNIC{},
-						},
+					InstructionType: v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK,
+					Result: &v1.InstructionResult_HealthCheck{
+						HealthCheck: &v1.HealthCheckResult{Healthy: true},
 					},
 				},
-			}
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(checksHealth()).To(BeFalse())
+			clock.Advance(service.DefaultHealthCheckInterval - time.Second)
+			Expect(checksHealth()).To(BeFalse())
+			clock.Advance(time.Second)
+			Expect(checksHealth()).To(BeTrue())
+		})
+
+		It("should not send leaf agents a health check", func() {
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+				Id:        "agent-leaf",
+				ClusterId: testClusterId,
+				Role:      v1.AgentRole_AGENT_ROLE_LEAF,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-leaf"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Instructions).To(HaveLen(1))
+			Expect(resp.Instructions[0].Type).To(Equal(v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE))
+		})
+
+		Context("in throttle mode", func() {
+			BeforeEach(func() {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+					Id:        "agent-2",
+					ClusterId: testClusterId,
+					Hostname:  "node2",
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should not request backoff by default", func() {
+				resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.PollIntervalSeconds).To(Equal(int32(service.PollIntervalSeconds)))
+				Expect(resp.BackoffSeconds).To(BeZero())
+			})
+
+			It("should increase the poll interval for all agents", func() {
+				throttleResp, err := agentService.SetThrottleMode(ctx, &v1.SetThrottleModeRequest{Enabled: true})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(throttleResp.PollIntervalSeconds).To(Equal(int32(service.DefaultThrottledPollIntervalSeconds)))
+
+				for _, id := range []string{agentId, "agent-2"} {
+					resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: id})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(resp.PollIntervalSeconds).To(Equal(int32(service.DefaultThrottledPollIntervalSeconds)))
+					Expect(resp.BackoffSeconds).To(Equal(int32(service.DefaultThrottledPollIntervalSeconds)))
+				}
+			})
+
+			It("should use a custom throttled interval and restore the default when disabled", func() {
+				_, err := agentService.SetThrottleMode(ctx, &v1.SetThrottleModeRequest{Enabled: true, PollIntervalSeconds: 600})
+				Expect(err).NotTo(HaveOccurred())
+
+				resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.PollIntervalSeconds).To(Equal(int32(600)))
+
+				_, err = agentService.SetThrottleMode(ctx, &v1.SetThrottleModeRequest{Enabled: false})
+				Expect(err).NotTo(HaveOccurred())
+
+				resp, err = agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.PollIntervalSeconds).To(Equal(int32(service.PollIntervalSeconds)))
+				Expect(resp.BackoffSeconds).To(BeZero())
+			})
+
+			It("should reject a throttled interval not above the normal interval", func() {
+				_, err := agentService.SetThrottleMode(ctx, &v1.SetThrottleModeRequest{Enabled: true, PollIntervalSeconds: 30})
+				Expect(err).To(HaveOccurred())
+				st, ok := status.FromError(err)
+				Expect(ok).To(BeTrue())
+				Expect(st.Code()).To(Equal(codes.InvalidArgument))
+			})
+		})
+
+		Context("with reported metrics", func() {
+			It("should store the metrics snapshot on the agent", func() {
+				_, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{
+					AgentId: agentId,
+					Metrics: map[string]float64{"cpu": 0.42, "memory": 0.7, "load": 1.5},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				getResp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getResp.Agent.Metrics).To(Equal(map[string]float64{"cpu": 0.42, "memory": 0.7, "load": 1.5}))
+				Expect(getResp.Agent.MetricsReportedAt).NotTo(BeNil())
+			})
+
+			It("should replace the snapshot on the next heartbeat", func() {
+				_, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{
+					AgentId: agentId,
+					Metrics: map[string]float64{"cpu": 0.42, "load": 1.5},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{
+					AgentId: agentId,
+					Metrics: map[string]float64{"cpu": 0.9},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				getResp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getResp.Agent.Metrics).To(Equal(map[string]float64{"cpu": 0.9}))
+			})
+
+			It("should reject non-finite metric values", func() {
+				_, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{
+					AgentId: agentId,
+					Metrics: map[string]float64{"cpu": math.NaN()},
+				})
+				Expect(err).To(HaveOccurred())
+				st, ok := status.FromError(err)
+				Expect(ok).To(BeTrue())
+				Expect(st.Code()).To(Equal(codes.InvalidArgument))
+			})
+		})
+
+		Context("when the cluster has a gateway", func() {
+			BeforeEach(func() {
+				_, err := clusterService.UpdateCluster(ctx, &v1.UpdateClusterRequest{
+					Id: testClusterId,
+					NetworkConfig: &v1.NetworkConfig{
+						Cidr:    "10.0.1.0/24",
+						Gateway: "10.0.1.254",
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should issue a gateway probe carrying the cluster gateway", func() {
+				resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentId})
+				Expect(err).NotTo(HaveOccurred())
+
+				var probe *v1.Instruction
+				for _, instruction := range resp.Instructions {
+					if instruction.Type == v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY {
+						probe = instruction
+					}
+				}
+				Expect(probe).NotTo(BeNil())
+
+				var payload map[string]string
+				Expect(json.Unmarshal([]byte(probe.Payload), &payload)).To(Succeed())
+				Expect(payload).To(HaveKeyWithValue("gateway", "10.0.1.254"))
+			})
+
+			It("should not issue a gateway probe again within the probe interval", func() {
+				_, err := agentService.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+					AgentId:       agentId,
+					InstructionId: "probe-1",
+					Result: &v1.InstructionResult{
+						InstructionType: v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY,
+						Result: &v1.InstructionResult_GatewayProbe{
+							GatewayProbe: &v1.GatewayProbeResult{Gateway: "10.0.1.254", Reachable: true},
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				for _, instruction := range resp.Instructions {
+					Expect(instruction.Type).NotTo(Equal(v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY))
+				}
+			})
+
+			It("should issue a gateway probe again once the probe interval passes", func() {
+				clock := service.NewFakeClock(time.Now())
+				clockedService := service.NewAgentService(store, service.WithClock(clock))
+
+				// probes reports whether the next poll asks for a gateway probe
+				probes := func() bool {
+					resp, err := clockedService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentId})
+					Expect(err).NotTo(HaveOccurred())
+					for _, instruction := range resp.Instructions {
+						if instruction.Type == v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY {
+							return true
+						}
+					}
+					return false
+				}
+
+				_, err := clockedService.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+					AgentId:       agentId,
+					InstructionId: "probe-1",
+					Result: &v1.InstructionResult{
+						InstructionType: v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY,
+						Result: &v1.InstructionResult_GatewayProbe{
+							GatewayProbe: &v1.GatewayProbeResult{Gateway: "10.0.1.254", Reachable: true},
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				clock.Advance(service.DefaultGatewayProbeInterval - time.Second)
+				Expect(probes()).To(BeFalse())
+				clock.Advance(time.Second)
+				Expect(probes()).To(BeTrue())
+			})
+		})
+
+		It("should not issue a gateway probe when the cluster has no gateway", func() {
+			resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentId})
+			Expect(err).NotTo(HaveOccurred())
+			for _, instruction := range resp.Instructions {
+				Expect(instruction.Type).NotTo(Equal(v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY))
+			}
+		})
+
+		It("should only return a drain instruction when the cluster is cordoned", func() {
+			drainResp, err := clusterService.DrainCluster(ctx, &v1.DrainClusterRequest{Id: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(drainResp.AgentCount).To(Equal(int32(1)))
+
+			resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Instructions).To(HaveLen(1))
+			Expect(resp.Instructions[0].Type).To(Equal(v1.InstructionType_INSTRUCTION_TYPE_DRAIN))
+		})
+
+		It("should resume instruction generation after the cluster is uncordoned", func() {
+			_, err := clusterService.DrainCluster(ctx, &v1.DrainClusterRequest{Id: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = clusterService.UncordonCluster(ctx, &v1.UncordonClusterRequest{Id: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Instructions).To(HaveLen(1))
+			Expect(resp.Instructions[0].Type).To(Equal(v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE))
+		})
+	})
+
+	Describe("SubmitInstructionResult", func() {
+		var agentId string
+
+		BeforeEach(func() {
+			// Register an agent first
+			registerReq := &v1.RegisterAgentRequest{
+				Id:        "agent-submit-test",
+				ClusterId: testClusterId,
+				Hostname:  "test-node",
+				IpAddress: "10.0.1.1",
+			}
+			resp, err := agentService.RegisterAgent(ctx, registerReq)
+			Expect(err).NotTo(HaveOccurred())
+			agentId = resp.Agent.Id
+		})
+
+		It("should accept and process hardware collection result", func() {
+			req := &v1.SubmitInstructionResultRequest{
+				AgentId:       agentId,
+				InstructionId: "instruction-123",
+				Result: &v1.InstructionResult{
+					InstructionType: v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE,
+					Result: &v1.InstructionResult_HardwareCollection{
+						HardwareCollection: &v1.HardwareCollectionResult{
+							NetworkInterfaces: []*v1.MellanoxNIC{},
+						},
+					},
+				},
+			}
 
 			resp, err := agentService.SubmitInstructionResult(ctx, req)
 			Expect(err).NotTo(HaveOccurred())
@@ -416,6 +1523,545 @@ var _ = Describe("AgentService", func() {
 			Expect(resp.Success).To(BeTrue())
 		})
 
+		It("should reject a hardware collection result exceeding the NIC count cap", func() {
+			limitedService := service.NewAgentService(store, service.WithNetworkInterfaceLimits(2, service.DefaultMaxNetworkInterfacesBytes))
+			nics := []*v1.MellanoxNIC{
+				{DeviceName: "mlx5_0"},
+				{DeviceName: "mlx5_1"},
+				{DeviceName: "mlx5_2"},
+			}
+			req := &v1.SubmitInstructionResultRequest{
+				AgentId:       agentId,
+				InstructionId: "instruction-123",
+				Result: &v1.InstructionResult{
+					InstructionType: v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE,
+					Result: &v1.InstructionResult_HardwareCollection{
+						HardwareCollection: &v1.HardwareCollectionResult{NetworkInterfaces: nics},
+					},
+				},
+			}
+
+			resp, err := limitedService.SubmitInstructionResult(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Success).To(BeFalse())
+			Expect(resp.Message).To(ContainSubstring("exceeding the limit of 2"))
+
+			getResp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: agentId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getResp.Agent.HardwareCollected).To(BeFalse())
+			Expect(getResp.Agent.NetworkInterfaces).To(BeEmpty())
+		})
+
+		It("should reject a hardware collection result exceeding the size cap", func() {
+			limitedService := service.NewAgentService(store, service.WithNetworkInterfaceLimits(service.DefaultMaxNICs, 64))
+			req := &v1.SubmitInstructionResultRequest{
+				AgentId:       agentId,
+				InstructionId: "instruction-123",
+				Result: &v1.InstructionResult{
+					InstructionType: v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE,
+					Result: &v1.InstructionResult_HardwareCollection{
+						HardwareCollection: &v1.HardwareCollectionResult{
+							NetworkInterfaces: []*v1.MellanoxNIC{{DeviceName: "mlx5_0", PartNumber: "MCX623106AN-CDAT", SerialNumber: "MT2110X00001"}},
+						},
+					},
+				},
+			}
+
+			resp, err := limitedService.SubmitInstructionResult(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Success).To(BeFalse())
+			Expect(resp.Message).To(ContainSubstring("bytes"))
+		})
+
+		It("should store the gateway probe result on the agent", func() {
+			req := &v1.SubmitInstructionResultRequest{
+				AgentId:       agentId,
+				InstructionId: "instruction-789",
+				Result: &v1.InstructionResult{
+					InstructionType: v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY,
+					Result: &v1.InstructionResult_GatewayProbe{
+						GatewayProbe: &v1.GatewayProbeResult{
+							Gateway:   "10.0.1.254",
+							Reachable: true,
+							LatencyMs: 1.5,
+						},
+					},
+				},
+			}
+
+			resp, err := agentService.SubmitInstructionResult(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Success).To(BeTrue())
+
+			getResp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: agentId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getResp.Agent.LastGatewayProbe).NotTo(BeNil())
+			Expect(getResp.Agent.LastGatewayProbe.Reachable).To(BeTrue())
+			Expect(getResp.Agent.LastGatewayProbe.LatencyMs).To(Equal(1.5))
+			Expect(getResp.Agent.LastGatewayProbeAt).NotTo(BeNil())
+		})
+
+		Context("with an agent-supplied completion time", func() {
+			healthResult := func(completedAt time.Time) *v1.SubmitInstructionResultRequest {
+				return &v1.SubmitInstructionResultRequest{
+					AgentId:       agentId,
+					InstructionId: "instruction-skew",
+					Result: &v1.InstructionResult{
+						InstructionType: v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK,
+						Result:          &v1.InstructionResult_HealthCheck{HealthCheck: &v1.HealthCheckResult{Healthy: true}},
+						CompletedAt:     timestamppb.New(completedAt),
+					},
+				}
+			}
+
+			It("should accept a timestamp within the allowed skew", func() {
+				resp, err := agentService.SubmitInstructionResult(ctx, healthResult(time.Now().Add(-time.Minute)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Success).To(BeTrue())
+			})
+
+			It("should reject a timestamp beyond the allowed skew", func() {
+				_, err := agentService.SubmitInstructionResult(ctx, healthResult(time.Now().Add(time.Hour)))
+				Expect(err).To(HaveOccurred())
+				st, ok := status.FromError(err)
+				Expect(ok).To(BeTrue())
+				Expect(st.Code()).To(Equal(codes.InvalidArgument))
+				Expect(st.Message()).To(ContainSubstring("skew"))
+			})
+
+			It("should honor a configured skew", func() {
+				tightService := service.NewAgentService(store, service.WithMaxClockSkew(10*time.Second))
+				_, err := tightService.SubmitInstructionResult(ctx, healthResult(time.Now().Add(-time.Minute)))
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should use the server clock for updated_at", func() {
+				_, err := agentService.SubmitInstructionResult(ctx, healthResult(time.Now().Add(-4*time.Minute)))
+				Expect(err).NotTo(HaveOccurred())
+
+				getResp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getResp.Agent.UpdatedAt.AsTime()).To(BeTemporally("~", time.Now(), 5*time.Second))
+			})
+		})
+
+		Context("with an unrecognized instruction type", func() {
+			const unknownType = v1.InstructionType(99)
+
+			// submitUnknown submits a result of an instruction type the server does not know
+			submitUnknown := func(svc *service.AgentService, instructionID string) *v1.SubmitInstructionResultResponse {
+				resp, err := svc.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+					AgentId:       agentId,
+					InstructionId: instructionID,
+					Result:        &v1.InstructionResult{InstructionType: unknownType},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				return resp
+			}
+
+			It("should accept and discard the result by default", func() {
+				resp := submitUnknown(agentService, "instruction-unknown")
+				Expect(resp.Success).To(BeTrue())
+
+				agent, err := store.GetAgent(ctx, agentId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.UnrecognizedResults).To(BeEmpty())
+			})
+
+			It("should fail the submission under the reject policy", func() {
+				rejectService := service.NewAgentService(store, service.WithUnknownResultPolicy(service.UnknownResultReject))
+
+				resp := submitUnknown(rejectService, "instruction-unknown")
+				Expect(resp.Success).To(BeFalse())
+				Expect(resp.Message).To(ContainSubstring("unrecognized instruction type"))
+
+				agent, err := store.GetAgent(ctx, agentId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.UnrecognizedResults).To(BeEmpty())
+			})
+
+			It("should keep the raw payload under the store_raw policy", func() {
+				storeService := service.NewAgentService(store, service.WithUnknownResultPolicy(service.UnknownResultStoreRaw))
+
+				resp := submitUnknown(storeService, "instruction-unknown")
+				Expect(resp.Success).To(BeTrue())
+
+				agent, err := store.GetAgent(ctx, agentId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.UnrecognizedResults).To(HaveLen(1))
+				raw := agent.UnrecognizedResults[0]
+				Expect(raw.InstructionId).To(Equal("instruction-unknown"))
+				Expect(raw.InstructionType).To(Equal(unknownType))
+				Expect(raw.ReceivedAt).NotTo(BeNil())
+
+				var decoded v1.InstructionResult
+				Expect(proto.Unmarshal(raw.Payload, &decoded)).To(Succeed())
+				Expect(decoded.InstructionType).To(Equal(unknownType))
+			})
+
+			It("should keep only the most recent raw results", func() {
+				storeService := service.NewAgentService(store, service.WithUnknownResultPolicy(service.UnknownResultStoreRaw))
+
+				for i := 0; i < service.MaxUnrecognizedResults+2; i++ {
+					Expect(submitUnknown(storeService, fmt.Sprintf("instruction-%d", i)).Success).To(BeTrue())
+				}
+
+				agent, err := store.GetAgent(ctx, agentId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.UnrecognizedResults).To(HaveLen(service.MaxUnrecognizedResults))
+				Expect(agent.UnrecognizedResults[0].InstructionId).To(Equal("instruction-2"))
+			})
+		})
+
+		Context("with a failure reason", func() {
+			var clock *service.FakeClock
+
+			// failHardware reports the hardware collection as failed for the given reason
+			failHardware := func(reason v1.FailureReason) {
+				resp, err := agentService.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+					AgentId:       agentId,
+					InstructionId: "instruction-failed",
+					Result: &v1.InstructionResult{
+						InstructionType: v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE,
+						FailureReason:   reason,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Success).To(BeTrue())
+			}
+
+			// collectsHardwareAfter advances the clock and reports whether the
+			// next poll asks for hardware collection
+			collectsHardwareAfter := func(elapsed time.Duration) bool {
+				clock.Advance(elapsed)
+				resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				for _, instruction := range resp.Instructions {
+					if instruction.Type == v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE {
+						return true
+					}
+				}
+				return false
+			}
+
+			BeforeEach(func() {
+				clock = service.NewFakeClock(time.Now())
+				agentService = service.NewAgentService(store, service.WithClock(clock))
+			})
+
+			It("should record a failed outcome without marking hardware collected", func() {
+				failHardware(v1.FailureReason_FAILURE_REASON_PERMISSION_DENIED)
+
+				agent, err := store.GetAgent(ctx, agentId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.HardwareCollected).To(BeFalse())
+				Expect(agent.LastOutcomes).To(HaveLen(1))
+				Expect(agent.LastOutcomes[0].Success).To(BeFalse())
+				Expect(agent.LastOutcomes[0].FailureReason).To(Equal(v1.FailureReason_FAILURE_REASON_PERMISSION_DENIED))
+				Expect(agent.LastOutcomes[0].RetryAfter.AsTime()).To(BeTemporally("==", clock.Now().Add(service.PermissionFailureBackoff)))
+			})
+
+			It("should retry a timed out instruction after a short backoff", func() {
+				failHardware(v1.FailureReason_FAILURE_REASON_TIMEOUT)
+
+				Expect(collectsHardwareAfter(0)).To(BeFalse())
+				Expect(collectsHardwareAfter(service.TransientFailureBackoff)).To(BeTrue())
+			})
+
+			It("should back off much longer from an unsupported instruction", func() {
+				failHardware(v1.FailureReason_FAILURE_REASON_NOT_SUPPORTED)
+
+				Expect(collectsHardwareAfter(service.TransientFailureBackoff)).To(BeFalse())
+				Expect(collectsHardwareAfter(service.PermissionFailureBackoff)).To(BeFalse())
+				Expect(collectsHardwareAfter(service.NotSupportedFailureBackoff)).To(BeTrue())
+			})
+
+			It("should let a triggered collection skip the backoff", func() {
+				failHardware(v1.FailureReason_FAILURE_REASON_NOT_SUPPORTED)
+
+				_, err := agentService.TriggerHardwareCollection(ctx, &v1.TriggerHardwareCollectionRequest{AgentIds: []string{agentId}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(collectsHardwareAfter(0)).To(BeTrue())
+			})
+		})
+
+		Context("with a partial collection", func() {
+			var clock *service.FakeClock
+
+			// submitCollection reports a hardware collection result
+			submitCollection := func(result *v1.HardwareCollectionResult) {
+				resp, err := agentService.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+					AgentId:       agentId,
+					InstructionId: "instruction-partial",
+					Result: &v1.InstructionResult{
+						InstructionType: v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE,
+						Result:          &v1.InstructionResult_HardwareCollection{HardwareCollection: result},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Success).To(BeTrue())
+			}
+
+			// collectsHardware reports whether the next poll asks for hardware collection
+			collectsHardware := func() bool {
+				resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				for _, instruction := range resp.Instructions {
+					if instruction.Type == v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE {
+						return true
+					}
+				}
+				return false
+			}
+
+			partial := &v1.HardwareCollectionResult{
+				NetworkInterfaces: []*v1.MellanoxNIC{{DeviceName: "mlx5_0", PciAddress: "0000:03:00.0"}},
+				Partial:           true,
+				FailedNics: []*v1.NICCollectionFailure{
+					{DeviceName: "mlx5_1", PciAddress: "0000:04:00.0", Error: "permission denied"},
+				},
+			}
+
+			BeforeEach(func() {
+				clock = service.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+				agentService = service.NewAgentService(store, service.WithClock(clock))
+			})
+
+			It("should store the collected NICs and record the failed ones", func() {
+				submitCollection(partial)
+
+				agent, err := store.GetAgent(ctx, agentId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.HardwareCollected).To(BeFalse())
+				Expect(agent.NetworkInterfaces).To(HaveLen(1))
+				Expect(agent.NetworkInterfaces[0].DeviceName).To(Equal("mlx5_0"))
+				Expect(agent.FailedNics).To(HaveLen(1))
+				Expect(agent.FailedNics[0].DeviceName).To(Equal("mlx5_1"))
+				Expect(agent.FailedNics[0].Error).To(Equal("permission denied"))
+				Expect(agent.LastOutcomes).To(ContainElement(HaveField("Success", BeFalse())))
+			})
+
+			It("should treat failed NICs as partial even without the flag", func() {
+				submitCollection(&v1.HardwareCollectionResult{FailedNics: partial.FailedNics})
+
+				agent, err := store.GetAgent(ctx, agentId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.HardwareCollected).To(BeFalse())
+				Expect(agent.FailedNics).To(HaveLen(1))
+			})
+
+			It("should retry the collection after a backoff", func() {
+				submitCollection(partial)
+				Expect(collectsHardware()).To(BeFalse())
+
+				clock.Advance(service.PartialCollectionBackoff + time.Second)
+				Expect(collectsHardware()).To(BeTrue())
+			})
+
+			It("should clear the failures once a full collection succeeds", func() {
+				submitCollection(partial)
+				submitCollection(&v1.HardwareCollectionResult{
+					NetworkInterfaces: []*v1.MellanoxNIC{
+						{DeviceName: "mlx5_0", PciAddress: "0000:03:00.0"},
+						{DeviceName: "mlx5_1", PciAddress: "0000:04:00.0"},
+					},
+				})
+
+				agent, err := store.GetAgent(ctx, agentId)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.HardwareCollected).To(BeTrue())
+				Expect(agent.NetworkInterfaces).To(HaveLen(2))
+				Expect(agent.FailedNics).To(BeEmpty())
+			})
+		})
+
+		Context("with multi-port NICs", func() {
+			submitNICs := func(id string, nics []*v1.MellanoxNIC) *v1.SubmitInstructionResultResponse {
+				resp, err := agentService.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+					AgentId:       id,
+					InstructionId: "instruction-ports",
+					Result: &v1.InstructionResult{
+						InstructionType: v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE,
+						Result: &v1.InstructionResult_HardwareCollection{
+							HardwareCollection: &v1.HardwareCollectionResult{NetworkInterfaces: nics},
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				return resp
+			}
+
+			It("should store per-port link state", func() {
+				resp := submitNICs(agentId, []*v1.MellanoxNIC{{
+					DeviceName: "mlx5_0",
+					PortCount:  2,
+					Ports: []*v1.MellanoxPort{
+						{Number: 1, State: v1.PortState_PORT_STATE_UP, Speed: v1.PortSpeed_PORT_SPEED_100G},
+						{Number: 2, State: v1.PortState_PORT_STATE_DOWN, Speed: v1.PortSpeed_PORT_SPEED_25G},
+					},
+				}})
+				Expect(resp.Success).To(BeTrue())
+
+				getResp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getResp.Agent.NetworkInterfaces).To(HaveLen(1))
+				ports := getResp.Agent.NetworkInterfaces[0].Ports
+				Expect(ports).To(HaveLen(2))
+				Expect(ports[0].State).To(Equal(v1.PortState_PORT_STATE_UP))
+				Expect(ports[0].Speed).To(Equal(v1.PortSpeed_PORT_SPEED_100G))
+				Expect(ports[1].State).To(Equal(v1.PortState_PORT_STATE_DOWN))
+				Expect(ports[1].Speed).To(Equal(v1.PortSpeed_PORT_SPEED_25G))
+			})
+
+			It("should derive the port count when omitted", func() {
+				resp := submitNICs(agentId, []*v1.MellanoxNIC{{
+					DeviceName: "mlx5_0",
+					Ports:      []*v1.MellanoxPort{{Number: 1}, {Number: 2}},
+				}})
+				Expect(resp.Success).To(BeTrue())
+
+				getResp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getResp.Agent.NetworkInterfaces[0].PortCount).To(Equal(int32(2)))
+			})
+
+			It("should normalize MAC addresses reported in different formats", func() {
+				resp := submitNICs(agentId, []*v1.MellanoxNIC{{
+					DeviceName: "mlx5_0",
+					Ports: []*v1.MellanoxPort{
+						{Number: 1, MacAddress: "0C:42:A1:5E:00:01"},
+						{Number: 2, MacAddress: "0c-42-a1-5e-00-02"},
+						{Number: 3, MacAddress: "0c42.a15e.0003"},
+						{Number: 4},
+					},
+				}})
+				Expect(resp.Success).To(BeTrue())
+
+				getResp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				ports := getResp.Agent.NetworkInterfaces[0].Ports
+				Expect(ports[0].MacAddress).To(Equal("0c:42:a1:5e:00:01"))
+				Expect(ports[1].MacAddress).To(Equal("0c:42:a1:5e:00:02"))
+				Expect(ports[2].MacAddress).To(Equal("0c:42:a1:5e:00:03"))
+				Expect(ports[3].MacAddress).To(BeEmpty())
+			})
+
+			It("should reject an invalid MAC address", func() {
+				resp := submitNICs(agentId, []*v1.MellanoxNIC{{
+					DeviceName: "mlx5_0",
+					Ports:      []*v1.MellanoxPort{{Number: 1, MacAddress: "0c:42:a1:zz:00:01"}},
+				}})
+				Expect(resp.Success).To(BeFalse())
+				Expect(resp.Message).To(ContainSubstring("invalid MAC address"))
+
+				getResp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getResp.Agent.HardwareCollected).To(BeFalse())
+			})
+
+			It("should reject a port count that does not match the port details", func() {
+				resp := submitNICs(agentId, []*v1.MellanoxNIC{{
+					DeviceName: "mlx5_0",
+					PortCount:  2,
+					Ports:      []*v1.MellanoxPort{{Number: 1}},
+				}})
+				Expect(resp.Success).To(BeFalse())
+				Expect(resp.Message).To(ContainSubstring("reports 2 ports"))
+			})
+
+			It("should reject duplicate or invalid port numbers", func() {
+				resp := submitNICs(agentId, []*v1.MellanoxNIC{{
+					DeviceName: "mlx5_0",
+					Ports:      []*v1.MellanoxPort{{Number: 1}, {Number: 1}},
+				}})
+				Expect(resp.Success).To(BeFalse())
+				Expect(resp.Message).To(ContainSubstring("more than once"))
+
+				resp = submitNICs(agentId, []*v1.MellanoxNIC{{
+					DeviceName: "mlx5_0",
+					Ports:      []*v1.MellanoxPort{{Number: 0}},
+				}})
+				Expect(resp.Success).To(BeFalse())
+				Expect(resp.Message).To(ContainSubstring("invalid port number"))
+			})
+
+			It("should reject NICs sharing a PCI address", func() {
+				resp := submitNICs(agentId, []*v1.MellanoxNIC{
+					{DeviceName: "mlx5_0", PciAddress: "0000:03:00.0", SerialNumber: "MT0001"},
+					{DeviceName: "mlx5_1", PciAddress: "0000:03:00.0", SerialNumber: "MT0002"},
+				})
+				Expect(resp.Success).To(BeFalse())
+				Expect(resp.Message).To(ContainSubstring("duplicate PCI address 0000:03:00.0 reported by mlx5_0 and mlx5_1"))
+
+				getResp, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: agentId})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getResp.Agent.HardwareCollected).To(BeFalse())
+				Expect(getResp.Agent.NetworkInterfaces).To(BeEmpty())
+			})
+
+			It("should reject NICs sharing a serial number", func() {
+				resp := submitNICs(agentId, []*v1.MellanoxNIC{
+					{DeviceName: "mlx5_0", PciAddress: "0000:03:00.0", SerialNumber: "MT0001"},
+					{DeviceName: "mlx5_1", PciAddress: "0000:04:00.0", SerialNumber: "mt0001"},
+				})
+				Expect(resp.Success).To(BeFalse())
+				Expect(resp.Message).To(ContainSubstring("duplicate serial number"))
+			})
+
+			It("should reject ports sharing a PCI address", func() {
+				resp := submitNICs(agentId, []*v1.MellanoxNIC{{
+					DeviceName: "mlx5_0",
+					Ports: []*v1.MellanoxPort{
+						{Number: 1, PciAddress: "0000:03:00.0"},
+						{Number: 2, PciAddress: "0000:03:00.0"},
+					},
+				}})
+				Expect(resp.Success).To(BeFalse())
+				Expect(resp.Message).To(ContainSubstring("duplicate port PCI address"))
+			})
+
+			It("should accept NICs without PCI addresses or serial numbers", func() {
+				resp := submitNICs(agentId, []*v1.MellanoxNIC{
+					{DeviceName: "mlx5_0"},
+					{DeviceName: "mlx5_1"},
+				})
+				Expect(resp.Success).To(BeTrue())
+			})
+
+			It("should list only agents with down ports", func() {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+					Id:        "agent-ports-up",
+					ClusterId: testClusterId,
+					Hostname:  "healthy-node",
+					IpAddress: "10.0.1.2",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(submitNICs(agentId, []*v1.MellanoxNIC{{
+					DeviceName: "mlx5_0",
+					Ports: []*v1.MellanoxPort{
+						{Number: 1, State: v1.PortState_PORT_STATE_UP},
+						{Number: 2, State: v1.PortState_PORT_STATE_DOWN},
+					},
+				}}).Success).To(BeTrue())
+				Expect(submitNICs("agent-ports-up", []*v1.MellanoxNIC{{
+					DeviceName: "mlx5_0",
+					Ports: []*v1.MellanoxPort{
+						{Number: 1, State: v1.PortState_PORT_STATE_UP},
+						{Number: 2, State: v1.PortState_PORT_STATE_UP},
+					},
+				}}).Success).To(BeTrue())
+
+				listResp, err := agentService.ListAgents(ctx, &v1.ListAgentsRequest{
+					ClusterId:     testClusterId,
+					DownPortsOnly: true,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(listResp.Agents).To(HaveLen(1))
+				Expect(listResp.Agents[0].Id).To(Equal(agentId))
+			})
+		})
+
 		It("should return error when agent ID is missing", func() {
 			req := &v1.SubmitInstructionResultRequest{
 				InstructionId: "instruction-123",
@@ -475,4 +2121,842 @@ var _ = Describe("AgentService", func() {
 			Expect(st.Code()).To(Equal(codes.NotFound))
 		})
 	})
+
+	Describe("GetClusterPollStats", func() {
+		var clock *service.FakeClock
+
+		// pollAfter advances the clock and polls as the given agent
+		pollAfter := func(agentID string, elapsed time.Duration) {
+			clock.Advance(elapsed)
+			_, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentID})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		BeforeEach(func() {
+			clock = service.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+			agentService = service.NewAgentService(store, service.WithClock(clock))
+
+			for _, id := range []string{"agent-fast", "agent-slow"} {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: id, ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should aggregate observed intervals across the cluster's agents", func() {
+			pollAfter("agent-fast", 0)
+			pollAfter("agent-slow", 0)
+			pollAfter("agent-fast", 10*time.Second)
+			pollAfter("agent-fast", 20*time.Second)
+			pollAfter("agent-slow", 90*time.Second)
+
+			resp, err := agentService.GetClusterPollStats(ctx, &v1.GetClusterPollStatsRequest{ClusterId: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.AgentCount).To(Equal(int32(2)))
+			Expect(resp.SampleCount).To(Equal(int32(3)))
+			Expect(resp.MinIntervalSeconds).To(Equal(10.0))
+			Expect(resp.MaxIntervalSeconds).To(Equal(120.0))
+			Expect(resp.AvgIntervalSeconds).To(Equal(50.0))
+			Expect(resp.ConfiguredIntervalSeconds).To(Equal(int32(service.PollIntervalSeconds)))
+		})
+
+		It("should only keep a bounded window of intervals per agent", func() {
+			pollAfter("agent-fast", 0)
+			pollAfter("agent-fast", time.Hour)
+			for i := 0; i < service.PollStatsWindow; i++ {
+				pollAfter("agent-fast", time.Minute)
+			}
+
+			resp, err := agentService.GetClusterPollStats(ctx, &v1.GetClusterPollStatsRequest{ClusterId: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.SampleCount).To(Equal(int32(service.PollStatsWindow)))
+			Expect(resp.MaxIntervalSeconds).To(Equal(60.0))
+		})
+
+		It("should drop the history of unregistered agents", func() {
+			pollAfter("agent-fast", 0)
+			pollAfter("agent-fast", time.Minute)
+
+			_, err := agentService.UnregisterAgent(ctx, &v1.UnregisterAgentRequest{Id: "agent-fast"})
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := agentService.GetClusterPollStats(ctx, &v1.GetClusterPollStatsRequest{ClusterId: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.AgentCount).To(BeZero())
+			Expect(resp.SampleCount).To(BeZero())
+		})
+
+		It("should return NotFound for an unknown cluster", func() {
+			_, err := agentService.GetClusterPollStats(ctx, &v1.GetClusterPollStatsRequest{ClusterId: "missing"})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("GetClusterInstructionSummary", func() {
+		submitHealth := func(agentID string, healthy bool) {
+			resp, err := agentService.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+				AgentId:       agentID,
+				InstructionId: "instruction-health",
+				Result: &v1.InstructionResult{
+					InstructionType: v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK,
+					Result:          &v1.InstructionResult_HealthCheck{HealthCheck: &v1.HealthCheckResult{Healthy: healthy}},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Success).To(BeTrue())
+		}
+
+		summarize := func(instructionType v1.InstructionType) *v1.GetClusterInstructionSummaryResponse {
+			resp, err := agentService.GetClusterInstructionSummary(ctx, &v1.GetClusterInstructionSummaryRequest{
+				ClusterId:       testClusterId,
+				InstructionType: instructionType,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return resp
+		}
+
+		BeforeEach(func() {
+			for _, id := range []string{"agent-1", "agent-2", "agent-3", "agent-4"} {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: id, ClusterId: testClusterId})
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should aggregate the most recent result of each agent", func() {
+			submitHealth("agent-1", true)
+			submitHealth("agent-2", false)
+			submitHealth("agent-3", false)
+			submitHealth("agent-3", true)
+
+			resp := summarize(v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK)
+			Expect(resp.Succeeded).To(Equal(int32(2)))
+			Expect(resp.Failed).To(Equal(int32(1)))
+			Expect(resp.NoResult).To(Equal(int32(1)))
+		})
+
+		It("should keep outcomes of different instruction types apart", func() {
+			submitHealth("agent-1", true)
+			_, err := agentService.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+				AgentId:       "agent-1",
+				InstructionId: "instruction-probe",
+				Result: &v1.InstructionResult{
+					InstructionType: v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY,
+					Result:          &v1.InstructionResult_GatewayProbe{GatewayProbe: &v1.GatewayProbeResult{Reachable: false}},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			probe := summarize(v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY)
+			Expect(probe.Failed).To(Equal(int32(1)))
+			Expect(probe.NoResult).To(Equal(int32(3)))
+			Expect(summarize(v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK).Succeeded).To(Equal(int32(1)))
+		})
+
+		It("should return NotFound for an unknown cluster", func() {
+			_, err := agentService.GetClusterInstructionSummary(ctx, &v1.GetClusterInstructionSummaryRequest{
+				ClusterId:       "missing",
+				InstructionType: v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK,
+			})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+
+		It("should require an instruction type", func() {
+			_, err := agentService.GetClusterInstructionSummary(ctx, &v1.GetClusterInstructionSummaryRequest{ClusterId: testClusterId})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("TriggerHardwareCollection", func() {
+		var otherClusterId string
+
+		// collectInstructions returns the COLLECT_HARDWARE instructions of a poll
+		collectInstructions := func(agentID string) []*v1.Instruction {
+			resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentID})
+			Expect(err).NotTo(HaveOccurred())
+
+			var instructions []*v1.Instruction
+			for _, instruction := range resp.Instructions {
+				if instruction.Type == v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE {
+					instructions = append(instructions, instruction)
+				}
+			}
+			return instructions
+		}
+
+		BeforeEach(func() {
+			createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "other-cluster"})
+			Expect(err).NotTo(HaveOccurred())
+			otherClusterId = createResp.Cluster.Id
+
+			for id, clusterID := range map[string]string{"agent-1": testClusterId, "agent-2": testClusterId, "agent-3": otherClusterId} {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: id, ClusterId: clusterID})
+				Expect(err).NotTo(HaveOccurred())
+
+				agent, err := store.GetAgent(ctx, id)
+				Expect(err).NotTo(HaveOccurred())
+				agent.HardwareCollected = true
+				Expect(store.UpdateAgent(ctx, agent)).To(Succeed())
+				Expect(collectInstructions(id)).To(BeEmpty())
+			}
+		})
+
+		It("should make every agent of a cluster collect hardware on its next poll", func() {
+			resp, err := agentService.TriggerHardwareCollection(ctx, &v1.TriggerHardwareCollectionRequest{ClusterId: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.AgentIds).To(ConsistOf("agent-1", "agent-2"))
+
+			Expect(collectInstructions("agent-1")).To(HaveLen(1))
+			Expect(collectInstructions("agent-2")).To(HaveLen(1))
+			Expect(collectInstructions("agent-3")).To(BeEmpty())
+		})
+
+		It("should make only the listed agents collect hardware", func() {
+			resp, err := agentService.TriggerHardwareCollection(ctx, &v1.TriggerHardwareCollectionRequest{
+				AgentIds: []string{"agent-1", "agent-3"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.AgentIds).To(ConsistOf("agent-1", "agent-3"))
+
+			Expect(collectInstructions("agent-1")).To(HaveLen(1))
+			Expect(collectInstructions("agent-2")).To(BeEmpty())
+			Expect(collectInstructions("agent-3")).To(HaveLen(1))
+		})
+
+		It("should trigger nothing when an agent ID is unknown", func() {
+			_, err := agentService.TriggerHardwareCollection(ctx, &v1.TriggerHardwareCollectionRequest{
+				AgentIds: []string{"agent-1", "missing"},
+			})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+			Expect(collectInstructions("agent-1")).To(BeEmpty())
+		})
+
+		It("should require exactly one selector", func() {
+			_, err := agentService.TriggerHardwareCollection(ctx, &v1.TriggerHardwareCollectionRequest{})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			_, err = agentService.TriggerHardwareCollection(ctx, &v1.TriggerHardwareCollectionRequest{
+				ClusterId: testClusterId,
+				AgentIds:  []string{"agent-1"},
+			})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+
+		It("should return NotFound for an unknown cluster", func() {
+			_, err := agentService.TriggerHardwareCollection(ctx, &v1.TriggerHardwareCollectionRequest{ClusterId: "missing"})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("DecommissionAgent", func() {
+		submitDecommission := func(result *v1.DecommissionResult) *v1.SubmitInstructionResultResponse {
+			resp, err := agentService.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+				AgentId:       "agent-1",
+				InstructionId: "instruction-decommission",
+				Result: &v1.InstructionResult{
+					InstructionType: v1.InstructionType_INSTRUCTION_TYPE_DECOMMISSION,
+					Result:          &v1.InstructionResult_Decommission{Decommission: result},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return resp
+		}
+
+		BeforeEach(func() {
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should only send the decommission instruction to a flagged agent", func() {
+			resp, err := agentService.DecommissionAgent(ctx, &v1.DecommissionAgentRequest{Id: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Agent.Decommissioning).To(BeTrue())
+
+			poll, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(poll.Instructions).To(HaveLen(1))
+			Expect(poll.Instructions[0].Type).To(Equal(v1.InstructionType_INSTRUCTION_TYPE_DECOMMISSION))
+		})
+
+		It("should unregister the agent once cleanup succeeds", func() {
+			_, err := agentService.DecommissionAgent(ctx, &v1.DecommissionAgentRequest{Id: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(submitDecommission(&v1.DecommissionResult{Success: true}).Success).To(BeTrue())
+
+			_, err = store.GetAgent(ctx, "agent-1")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should keep the agent decommissioning when cleanup fails", func() {
+			_, err := agentService.DecommissionAgent(ctx, &v1.DecommissionAgentRequest{Id: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			submitDecommission(&v1.DecommissionResult{Success: false, ErrorMessage: "interface busy"})
+
+			agent, err := store.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agent.Decommissioning).To(BeTrue())
+		})
+
+		It("should not unregister an agent that was not marked for decommission", func() {
+			submitDecommission(&v1.DecommissionResult{Success: true})
+
+			_, err := store.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should return NotFound for an unknown agent", func() {
+			_, err := agentService.DecommissionAgent(ctx, &v1.DecommissionAgentRequest{Id: "missing"})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("PreviewInstructions", func() {
+		var clock *service.FakeClock
+
+		// storedAgent writes an agent of the test cluster last seen an hour
+		// ago, modified by mutate
+		storedAgent := func(mutate func(*v1.Agent)) *v1.Agent {
+			seen := timestamppb.New(clock.Now().Add(-time.Hour))
+			agent := &v1.Agent{
+				Id:        "agent-1",
+				ClusterId: testClusterId,
+				Status:    v1.AgentStatus_AGENT_STATUS_INACTIVE,
+				LastSeen:  seen,
+				CreatedAt: seen,
+				UpdatedAt: seen,
+			}
+			mutate(agent)
+			Expect(store.CreateAgent(ctx, agent)).To(Succeed())
+			return agent
+		}
+
+		previewTypes := func() []v1.InstructionType {
+			resp, err := agentService.PreviewInstructions(ctx, &v1.PreviewInstructionsRequest{AgentId: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			types := make([]v1.InstructionType, 0, len(resp.Instructions))
+			for _, instruction := range resp.Instructions {
+				types = append(types, instruction.Type)
+			}
+			return types
+		}
+
+		BeforeEach(func() {
+			clock = service.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+			agentService = service.NewAgentService(store, service.WithClock(clock))
+		})
+
+		DescribeTable("should apply the instruction generation rules",
+			func(mutate func(*v1.Agent), expected []v1.InstructionType) {
+				storedAgent(mutate)
+				Expect(previewTypes()).To(Equal(expected))
+			},
+			Entry("hardware not yet collected", func(a *v1.Agent) {},
+				[]v1.InstructionType{v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE}),
+			Entry("hardware collected", func(a *v1.Agent) { a.HardwareCollected = true },
+				[]v1.InstructionType{}),
+			Entry("hardware collection backing off after a failure", func(a *v1.Agent) {
+				a.LastOutcomes = []*v1.InstructionOutcome{{
+					InstructionType: v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE,
+					RetryAfter:      timestamppb.New(time.Date(2026, 1, 1, 13, 0, 0, 0, time.UTC)),
+				}}
+			}, []v1.InstructionType{}),
+			Entry("spine agent", func(a *v1.Agent) {
+				a.HardwareCollected = true
+				a.Role = v1.AgentRole_AGENT_ROLE_SPINE
+			}, []v1.InstructionType{v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK}),
+			Entry("decommissioning agent", func(a *v1.Agent) { a.Decommissioning = true },
+				[]v1.InstructionType{v1.InstructionType_INSTRUCTION_TYPE_DECOMMISSION}),
+			Entry("agent due to restart", func(a *v1.Agent) { a.RestartRequested = true },
+				[]v1.InstructionType{v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT}),
+		)
+
+		It("should hand the poll interval a poll would", func() {
+			storedAgent(func(a *v1.Agent) {})
+			resp, err := agentService.PreviewInstructions(ctx, &v1.PreviewInstructionsRequest{AgentId: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.PollIntervalSeconds).To(BeEquivalentTo(service.PollIntervalSeconds))
+
+			Expect(store.DeleteAgent(ctx, "agent-1")).To(Succeed())
+			storedAgent(func(a *v1.Agent) {
+				a.LastOutcomes = []*v1.InstructionOutcome{{
+					InstructionType: v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE,
+					RetryAfter:      timestamppb.New(clock.Now().Add(time.Hour)),
+				}}
+			})
+			resp, err = agentService.PreviewInstructions(ctx, &v1.PreviewInstructionsRequest{AgentId: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.PollIntervalSeconds).To(BeEquivalentTo(service.BackoffPollIntervalCapSeconds))
+		})
+
+		It("should drain agents of a cordoned cluster", func() {
+			storedAgent(func(a *v1.Agent) {})
+			_, err := clusterService.DrainCluster(ctx, &v1.DrainClusterRequest{Id: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(previewTypes()).To(Equal([]v1.InstructionType{v1.InstructionType_INSTRUCTION_TYPE_DRAIN}))
+		})
+
+		It("should match what the agent receives when it polls", func() {
+			storedAgent(func(a *v1.Agent) {})
+			preview := previewTypes()
+
+			poll, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(poll.Instructions).To(HaveLen(len(preview)))
+			for i, instruction := range poll.Instructions {
+				Expect(instruction.Type).To(Equal(preview[i]))
+			}
+		})
+
+		It("should not record a poll", func() {
+			stored := storedAgent(func(a *v1.Agent) {})
+			lastSeen := stored.LastSeen.AsTime()
+			previewTypes()
+
+			agent, err := store.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agent.LastSeen.AsTime()).To(Equal(lastSeen))
+			Expect(agent.Status).To(Equal(v1.AgentStatus_AGENT_STATUS_INACTIVE))
+		})
+
+		It("should return NotFound for an unknown agent", func() {
+			_, err := agentService.PreviewInstructions(ctx, &v1.PreviewInstructionsRequest{AgentId: "missing"})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("UpdateAgentConfig", func() {
+		BeforeEach(func() {
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		poll := func() *v1.GetInstructionsResponse {
+			resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			return resp
+		}
+
+		It("should hand the agent its poll interval override instead of the default", func() {
+			Expect(poll().PollIntervalSeconds).To(BeEquivalentTo(service.PollIntervalSeconds))
+
+			resp, err := agentService.UpdateAgentConfig(ctx, &v1.UpdateAgentConfigRequest{
+				Id:        "agent-1",
+				Overrides: &v1.AgentConfigOverrides{PollIntervalSeconds: 300},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Agent.ConfigOverrides.GetPollIntervalSeconds()).To(Equal(int32(300)))
+
+			Expect(poll().PollIntervalSeconds).To(Equal(int32(300)))
+			agent, err := store.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agent.InstructedPollIntervalSeconds).To(Equal(int32(300)))
+		})
+
+		It("should keep a longer throttled interval over the override", func() {
+			_, err := agentService.UpdateAgentConfig(ctx, &v1.UpdateAgentConfigRequest{
+				Id:        "agent-1",
+				Overrides: &v1.AgentConfigOverrides{PollIntervalSeconds: 120},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = agentService.SetThrottleMode(ctx, &v1.SetThrottleModeRequest{Enabled: true, PollIntervalSeconds: 600})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(poll().PollIntervalSeconds).To(Equal(int32(600)))
+		})
+
+		It("should skip disabled instruction types", func() {
+			Expect(poll().Instructions).To(ContainElement(HaveField("Type", v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE)))
+
+			_, err := agentService.UpdateAgentConfig(ctx, &v1.UpdateAgentConfigRequest{
+				Id: "agent-1",
+				Overrides: &v1.AgentConfigOverrides{
+					DisabledInstructions: []v1.InstructionType{v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(poll().Instructions).To(BeEmpty())
+		})
+
+		It("should clear the overrides when given empty ones", func() {
+			_, err := agentService.UpdateAgentConfig(ctx, &v1.UpdateAgentConfigRequest{
+				Id:        "agent-1",
+				Overrides: &v1.AgentConfigOverrides{PollIntervalSeconds: 300},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			resp, err := agentService.UpdateAgentConfig(ctx, &v1.UpdateAgentConfigRequest{Id: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Agent.ConfigOverrides).To(BeNil())
+			Expect(poll().PollIntervalSeconds).To(BeEquivalentTo(service.PollIntervalSeconds))
+		})
+
+		DescribeTable("should reject invalid overrides",
+			func(overrides *v1.AgentConfigOverrides, message string) {
+				_, err := agentService.UpdateAgentConfig(ctx, &v1.UpdateAgentConfigRequest{Id: "agent-1", Overrides: overrides})
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("poll interval below the default", &v1.AgentConfigOverrides{PollIntervalSeconds: 10}, "below the default"),
+			Entry("negative poll interval", &v1.AgentConfigOverrides{PollIntervalSeconds: -1}, "below the default"),
+			Entry("operator-driven instruction disabled", &v1.AgentConfigOverrides{
+				DisabledInstructions: []v1.InstructionType{v1.InstructionType_INSTRUCTION_TYPE_DECOMMISSION},
+			}, "cannot be disabled"),
+		)
+
+		It("should return NotFound for an unknown agent", func() {
+			_, err := agentService.UpdateAgentConfig(ctx, &v1.UpdateAgentConfigRequest{Id: "missing"})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("Effective poll interval", func() {
+		var clock *service.FakeClock
+
+		// failHardware reports the hardware collection as denied, backing it
+		// off for PermissionFailureBackoff
+		failHardware := func() {
+			_, err := agentService.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+				AgentId:       "agent-1",
+				InstructionId: "instruction-failed",
+				Result: &v1.InstructionResult{
+					InstructionType: v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE,
+					FailureReason:   v1.FailureReason_FAILURE_REASON_PERMISSION_DENIED,
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		// poll polls as the agent and returns the interval it was handed
+		poll := func() int32 {
+			resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			return resp.PollIntervalSeconds
+		}
+
+		BeforeEach(func() {
+			clock = service.NewFakeClock(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+			agentService = service.NewAgentService(store, service.WithClock(clock))
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		DescribeTable("should record the resolved interval and the factor that drove it",
+			func(override, throttled int32, backingOff bool, expected int32, source v1.PollIntervalSource) {
+				if override > 0 {
+					_, err := agentService.UpdateAgentConfig(ctx, &v1.UpdateAgentConfigRequest{
+						Id:        "agent-1",
+						Overrides: &v1.AgentConfigOverrides{PollIntervalSeconds: override},
+					})
+					Expect(err).NotTo(HaveOccurred())
+				}
+				if throttled > 0 {
+					_, err := agentService.SetThrottleMode(ctx, &v1.SetThrottleModeRequest{Enabled: true, PollIntervalSeconds: throttled})
+					Expect(err).NotTo(HaveOccurred())
+				}
+				if backingOff {
+					failHardware()
+				}
+
+				Expect(poll()).To(Equal(expected))
+
+				agent, err := agentService.GetAgent(ctx, &v1.GetAgentRequest{Id: "agent-1"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agent.Agent.InstructedPollIntervalSeconds).To(Equal(expected))
+				Expect(agent.Agent.PollIntervalSource).To(Equal(source))
+			},
+			Entry("default", int32(0), int32(0), false, int32(service.PollIntervalSeconds), v1.PollIntervalSource_POLL_INTERVAL_SOURCE_DEFAULT),
+			Entry("agent override over the default", int32(120), int32(0), false, int32(120), v1.PollIntervalSource_POLL_INTERVAL_SOURCE_AGENT_OVERRIDE),
+			Entry("backoff over the default", int32(0), int32(0), true, int32(service.BackoffPollIntervalCapSeconds), v1.PollIntervalSource_POLL_INTERVAL_SOURCE_BACKOFF),
+			Entry("backoff over the override", int32(900), int32(0), true, int32(service.BackoffPollIntervalCapSeconds), v1.PollIntervalSource_POLL_INTERVAL_SOURCE_BACKOFF),
+			Entry("throttle over the backoff", int32(0), int32(600), true, int32(600), v1.PollIntervalSource_POLL_INTERVAL_SOURCE_THROTTLE),
+			Entry("throttle over a shorter override", int32(120), int32(600), false, int32(600), v1.PollIntervalSource_POLL_INTERVAL_SOURCE_THROTTLE),
+			Entry("throttle over a longer override", int32(900), int32(600), false, int32(600), v1.PollIntervalSource_POLL_INTERVAL_SOURCE_THROTTLE),
+			Entry("throttle over everything", int32(900), int32(600), true, int32(600), v1.PollIntervalSource_POLL_INTERVAL_SOURCE_THROTTLE),
+		)
+
+		It("should poll again when the earliest retry is due", func() {
+			failHardware()
+
+			clock.Advance(service.PermissionFailureBackoff - 90*time.Second)
+			Expect(poll()).To(Equal(int32(90)))
+
+			clock.Advance(90 * time.Second)
+			Expect(poll()).To(BeEquivalentTo(service.PollIntervalSeconds))
+			agent, err := store.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agent.PollIntervalSource).To(Equal(v1.PollIntervalSource_POLL_INTERVAL_SOURCE_DEFAULT))
+		})
+
+		It("should fall back to the default once throttling ends", func() {
+			_, err := agentService.SetThrottleMode(ctx, &v1.SetThrottleModeRequest{Enabled: true, PollIntervalSeconds: 600})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(poll()).To(Equal(int32(600)))
+
+			_, err = agentService.SetThrottleMode(ctx, &v1.SetThrottleModeRequest{Enabled: false})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(poll()).To(BeEquivalentTo(service.PollIntervalSeconds))
+
+			agent, err := store.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agent.InstructedPollIntervalSeconds).To(BeEquivalentTo(service.PollIntervalSeconds))
+			Expect(agent.PollIntervalSource).To(Equal(v1.PollIntervalSource_POLL_INTERVAL_SOURCE_DEFAULT))
+		})
+	})
+
+	Describe("Persistent instructions", func() {
+		var clock *service.FakeClock
+
+		poll := func() []*v1.Instruction {
+			resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			return resp.Instructions
+		}
+
+		// goOffline leaves the agent silent long enough for the monitor to mark it stale
+		goOffline := func() {
+			clock.Advance(2 * time.Hour)
+			service.NewAgentMonitor(store, service.WithMonitorClock(clock)).CheckAgentStatesOnce(ctx)
+			agent, err := store.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agent.Status).To(Equal(v1.AgentStatus_AGENT_STATUS_STALE))
+		}
+
+		BeforeEach(func() {
+			clock = service.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+			agentService = service.NewAgentService(store, service.WithClock(clock))
+
+			gatewayCluster := &v1.Cluster{
+				Id:            "gateway-cluster",
+				Name:          "gateway-cluster",
+				NetworkConfig: &v1.NetworkConfig{Cidr: "10.0.0.0/24", Gateway: "10.0.0.1"},
+			}
+			Expect(store.CreateCluster(ctx, gatewayCluster)).To(Succeed())
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: gatewayCluster.Id})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should redeliver a persistent instruction requested while the agent was offline", func() {
+			goOffline()
+			_, err := agentService.RestartAgent(ctx, &v1.RestartAgentRequest{Id: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			for range 2 {
+				instructions := poll()
+				Expect(instructions).To(HaveLen(1))
+				Expect(instructions[0].Type).To(Equal(v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT))
+				Expect(instructions[0].Persistent).To(BeTrue())
+			}
+		})
+
+		It("should not make up periodic instructions missed during downtime", func() {
+			instructions := poll()
+			Expect(instructions).To(ConsistOf(
+				SatisfyAll(HaveField("Type", v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE), HaveField("Persistent", true)),
+				SatisfyAll(HaveField("Type", v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY), HaveField("Persistent", false)),
+			))
+
+			agent, err := store.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			agent.LastGatewayProbeAt = timestamppb.New(clock.Now())
+			Expect(store.UpdateAgent(ctx, agent)).To(Succeed())
+
+			// Two hours span many probe intervals, yet the agent gets one probe
+			goOffline()
+			instructions = poll()
+			Expect(instructions).To(HaveLen(2))
+			Expect(instructions).To(ContainElement(SatisfyAll(
+				HaveField("Type", v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY),
+				HaveField("Persistent", false),
+			)))
+		})
+	})
+
+	Describe("RestartAgent", func() {
+		var clock *service.FakeClock
+
+		submitRestart := func(result *v1.RestartAgentResult) *v1.SubmitInstructionResultResponse {
+			resp, err := agentService.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+				AgentId:       "agent-1",
+				InstructionId: "instruction-restart",
+				Result: &v1.InstructionResult{
+					InstructionType: v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT,
+					Result:          &v1.InstructionResult_RestartAgent{RestartAgent: result},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return resp
+		}
+
+		BeforeEach(func() {
+			clock = service.NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+			agentService = service.NewAgentService(store, service.WithClock(clock))
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should deliver only the restart instruction on the next poll", func() {
+			resp, err := agentService.RestartAgent(ctx, &v1.RestartAgentRequest{Id: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Agent.RestartRequested).To(BeTrue())
+
+			poll, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(poll.Instructions).To(HaveLen(1))
+			Expect(poll.Instructions[0].Type).To(Equal(v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT))
+		})
+
+		It("should record the restart time once the agent initiates the restart", func() {
+			_, err := agentService.RestartAgent(ctx, &v1.RestartAgentRequest{Id: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			clock.Advance(time.Minute)
+			Expect(submitRestart(&v1.RestartAgentResult{Initiated: true}).Success).To(BeTrue())
+
+			agent, err := store.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agent.RestartRequested).To(BeFalse())
+			Expect(agent.LastRestartAt.AsTime()).To(Equal(clock.Now()))
+
+			poll, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			for _, instruction := range poll.Instructions {
+				Expect(instruction.Type).NotTo(Equal(v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT))
+			}
+		})
+
+		It("should keep the restart requested when the agent fails to initiate it", func() {
+			_, err := agentService.RestartAgent(ctx, &v1.RestartAgentRequest{Id: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			submitRestart(&v1.RestartAgentResult{Initiated: false, ErrorMessage: "permission denied"})
+
+			agent, err := store.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agent.RestartRequested).To(BeTrue())
+			Expect(agent.LastRestartAt).To(BeNil())
+		})
+
+		It("should refuse to restart an agent being decommissioned", func() {
+			_, err := agentService.DecommissionAgent(ctx, &v1.DecommissionAgentRequest{Id: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = agentService.RestartAgent(ctx, &v1.RestartAgentRequest{Id: "agent-1"})
+			Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		})
+
+		It("should return NotFound for an unknown agent", func() {
+			_, err := agentService.RestartAgent(ctx, &v1.RestartAgentRequest{Id: "missing"})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("Concurrent updates", func() {
+		var (
+			interleaving *interleavingStorage
+			polling      *service.AgentService
+		)
+
+		BeforeEach(func() {
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: testClusterId})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Start from collected hardware so a re-collection request shows
+			agent, err := store.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			agent.HardwareCollected = true
+			Expect(store.UpdateAgent(ctx, agent)).To(Succeed())
+
+			interleaving = &interleavingStorage{Storage: store}
+			polling = service.NewAgentService(interleaving)
+		})
+
+		DescribeTable("should keep an operator request landing during a poll",
+			func(request func() error, kept func(*v1.Agent) bool) {
+				interleaving.interleave = func() {
+					Expect(request()).To(Succeed())
+				}
+
+				_, err := polling.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-1"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(interleaving.interleave).To(BeNil())
+
+				agent, err := store.GetAgent(ctx, "agent-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(kept(agent)).To(BeTrue())
+				Expect(agent.LastSeen).NotTo(BeNil())
+			},
+			Entry("restart", func() error {
+				_, err := agentService.RestartAgent(ctx, &v1.RestartAgentRequest{Id: "agent-1"})
+				return err
+			}, func(a *v1.Agent) bool { return a.RestartRequested }),
+			Entry("decommission", func() error {
+				_, err := agentService.DecommissionAgent(ctx, &v1.DecommissionAgentRequest{Id: "agent-1"})
+				return err
+			}, func(a *v1.Agent) bool { return a.Decommissioning }),
+			Entry("hardware re-collection", func() error {
+				_, err := agentService.TriggerHardwareCollection(ctx, &v1.TriggerHardwareCollectionRequest{AgentIds: []string{"agent-1"}})
+				return err
+			}, func(a *v1.Agent) bool { return !a.HardwareCollected }),
+			Entry("config overrides", func() error {
+				_, err := agentService.UpdateAgentConfig(ctx, &v1.UpdateAgentConfigRequest{
+					Id: "agent-1", Overrides: &v1.AgentConfigOverrides{PollIntervalSeconds: 2 * service.PollIntervalSeconds},
+				})
+				return err
+			}, func(a *v1.Agent) bool {
+				return a.GetConfigOverrides().GetPollIntervalSeconds() == 2*service.PollIntervalSeconds
+			}),
+		)
+
+		It("should hand out an instruction requested during the poll", func() {
+			interleaving.interleave = func() {
+				_, err := agentService.RestartAgent(ctx, &v1.RestartAgentRequest{Id: "agent-1"})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			poll, err := polling.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(poll.Instructions).To(HaveLen(1))
+			Expect(poll.Instructions[0].Type).To(Equal(v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT))
+		})
+
+		It("should keep an operator request landing while a result is recorded", func() {
+			interleaving.interleave = func() {
+				_, err := agentService.DecommissionAgent(ctx, &v1.DecommissionAgentRequest{Id: "agent-1"})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			resp, err := polling.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+				AgentId:       "agent-1",
+				InstructionId: "instruction-1",
+				Result: &v1.InstructionResult{
+					InstructionType: v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK,
+					Result:          &v1.InstructionResult_HealthCheck{HealthCheck: &v1.HealthCheckResult{Healthy: true}},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Success).To(BeTrue())
+
+			agent, err := store.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agent.Decommissioning).To(BeTrue())
+			Expect(agent.LastHealthyAt).NotTo(BeNil())
+		})
+
+		It("should reject a stale write without changing the stored agent", func() {
+			stale, err := store.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = agentService.RestartAgent(ctx, &v1.RestartAgentRequest{Id: "agent-1"})
+			Expect(err).NotTo(HaveOccurred())
+
+			stale.Hostname = "stale"
+			Expect(store.UpdateAgent(ctx, stale)).To(MatchError(storage.ErrConflict))
+
+			agent, err := store.GetAgent(ctx, "agent-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agent.RestartRequested).To(BeTrue())
+			Expect(agent.Hostname).NotTo(Equal("stale"))
+		})
+	})
 })
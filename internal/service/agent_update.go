@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/filanov/netctrl-server/internal/storage"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// maxAgentUpdateAttempts bounds how often an agent update is reapplied when
+// concurrent writes keep changing the agent under it
+const maxAgentUpdateAttempts = 5
+
+// updateAgent applies change to agent and writes it back. When the write
+// conflicts with one made since agent was read, agent is refreshed from
+// storage and change applied again, so an update never erases fields it did
+// not set, such as an operator's restart request landing during a poll.
+// change reports whether the agent needs writing and may run more than once.
+// updateAgent reports whether the agent was written.
+func (s *AgentService) updateAgent(ctx context.Context, agent *v1.Agent, change func(*v1.Agent) bool) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		if !change(agent) {
+			return false, nil
+		}
+		err := s.storage.UpdateAgent(ctx, agent)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, storage.ErrConflict) || attempt == maxAgentUpdateAttempts {
+			return false, err
+		}
+
+		current, err := s.storage.GetAgent(ctx, agent.Id)
+		if err != nil {
+			return false, err
+		}
+		proto.Reset(agent)
+		proto.Merge(agent, current)
+	}
+}
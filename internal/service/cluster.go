@@ -2,51 +2,140 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log"
+	"net"
+	"sort"
+	"strings"
 
-	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/filanov/netctrl-server/internal/storage"
 	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
 )
 
+// CIDRChangePolicy decides how UpdateCluster treats a CIDR change that
+// leaves registered agents' IPs outside the new subnet
+type CIDRChangePolicy string
+
+const (
+	// CIDRChangeWarn applies the change and lists the agents in the response
+	CIDRChangeWarn CIDRChangePolicy = "warn"
+
+	// CIDRChangeReject fails the update with FailedPrecondition
+	CIDRChangeReject CIDRChangePolicy = "reject"
+)
+
 // ClusterService implements the cluster management service
 type ClusterService struct {
 	v1.UnimplementedClusterServiceServer
 	storage storage.Storage
+	idGen   IDGenerator
+	clock   Clock
+
+	// defaultNetworkConfig is copied into auto-created clusters, and into
+	// created clusters lacking one when networkConfigForRequests is set
+	defaultNetworkConfig     *v1.NetworkConfig
+	networkConfigForRequests bool
+
+	cidrChangePolicy CIDRChangePolicy
+
+	// strictValidation rejects clusters created without a network config
+	// instead of accepting them with a warning
+	strictValidation bool
+}
+
+// ClusterServiceOption configures optional ClusterService behavior
+type ClusterServiceOption func(*ClusterService)
+
+// WithIDGenerator replaces the UUID generator used for new cluster IDs. It
+// also validates the IDs callers choose when moving or importing clusters.
+func WithIDGenerator(gen IDGenerator) ClusterServiceOption {
+	return func(s *ClusterService) {
+		s.idGen = gen
+	}
+}
+
+// WithClusterClock replaces the clock cluster timestamps are taken from
+func WithClusterClock(clock Clock) ClusterServiceOption {
+	return func(s *ClusterService) {
+		s.clock = clock
+	}
+}
+
+// WithDefaultNetworkConfig gives clusters created by EnsureCluster a copy of
+// template as their network config. With forRequests, CreateCluster requests
+// without a network config get it too, which suits development setups where
+// clusters are created ad hoc.
+func WithDefaultNetworkConfig(template *v1.NetworkConfig, forRequests bool) ClusterServiceOption {
+	return func(s *ClusterService) {
+		s.defaultNetworkConfig = template
+		s.networkConfigForRequests = forRequests
+	}
+}
+
+// WithCIDRChangePolicy sets how UpdateCluster treats a CIDR change that
+// leaves registered agents' IPs outside the new subnet
+func WithCIDRChangePolicy(policy CIDRChangePolicy) ClusterServiceOption {
+	return func(s *ClusterService) {
+		s.cidrChangePolicy = policy
+	}
+}
+
+// WithStrictClusterValidation rejects clusters created or imported without
+// a network config; otherwise they are accepted with a logged warning
+func WithStrictClusterValidation(strict bool) ClusterServiceOption {
+	return func(s *ClusterService) {
+		s.strictValidation = strict
+	}
 }
 
 // NewClusterService creates a new cluster service instance
-func NewClusterService(store storage.Storage) *ClusterService {
-	return &ClusterService{
-		storage: store,
+func NewClusterService(store storage.Storage, opts ...ClusterServiceOption) *ClusterService {
+	s := &ClusterService{
+		storage:          store,
+		idGen:            UUIDGenerator{},
+		clock:            realClock{},
+		cidrChangePolicy: CIDRChangeWarn,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // CreateCluster creates a new cluster
 func (s *ClusterService) CreateCluster(ctx context.Context, req *v1.CreateClusterRequest) (*v1.CreateClusterResponse, error) {
+	networkConfig := req.NetworkConfig
+	if networkConfig == nil && s.networkConfigForRequests {
+		networkConfig = s.newDefaultNetworkConfig()
+	}
+
 	// Validate request
-	if err := s.validateCreateRequest(req); err != nil {
+	if err := s.validateCreateRequest(req.Name, networkConfig, req.AdditionalNetworks); err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
 	// Create cluster entity
-	now := timestamppb.Now()
+	now := timestamppb.New(s.clock.Now())
 	cluster := &v1.Cluster{
-		Id:          uuid.New().String(),
-		Name:        req.Name,
-		Description: req.Description,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		Id:                 s.idGen.NewID(),
+		Name:               req.Name,
+		Description:        req.Description,
+		NetworkConfig:      networkConfig,
+		AdditionalNetworks: req.AdditionalNetworks,
+		CreatedAt:          now,
+		UpdatedAt:          now,
 	}
 
 	// Store cluster
 	if err := s.storage.CreateCluster(ctx, cluster); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create cluster: %v", err)
+		return nil, storageWriteError("failed to create cluster", err)
 	}
 
 	log.Printf("Cluster created: id=%s, name=%s", cluster.Id, cluster.Name)
@@ -56,6 +145,41 @@ func (s *ClusterService) CreateCluster(ctx context.Context, req *v1.CreateCluste
 	}, nil
 }
 
+// EnsureCluster creates a cluster with the given ID and name unless one
+// already exists, returning the existing or new cluster
+func (s *ClusterService) EnsureCluster(ctx context.Context, id, name string) (*v1.Cluster, error) {
+	exists, err := s.storage.ClusterExists(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check cluster existence: %w", err)
+	}
+	if exists {
+		return s.storage.GetCluster(ctx, id)
+	}
+
+	networkConfig := s.newDefaultNetworkConfig()
+	if networkConfig != nil {
+		if err := validateNetworkConfig(networkConfig); err != nil {
+			return nil, fmt.Errorf("invalid default network config: %w", err)
+		}
+	}
+
+	now := timestamppb.New(s.clock.Now())
+	cluster := &v1.Cluster{
+		Id:            id,
+		Name:          name,
+		NetworkConfig: networkConfig,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.storage.CreateCluster(ctx, cluster); err != nil {
+		return nil, fmt.Errorf("failed to create cluster: %w", err)
+	}
+
+	log.Printf("Cluster created: id=%s, name=%s", cluster.Id, cluster.Name)
+
+	return cluster, nil
+}
+
 // GetCluster retrieves a cluster by ID
 func (s *ClusterService) GetCluster(ctx context.Context, req *v1.GetClusterRequest) (*v1.GetClusterResponse, error) {
 	if req.Id == "" {
@@ -67,14 +191,49 @@ func (s *ClusterService) GetCluster(ctx context.Context, req *v1.GetClusterReque
 		return nil, status.Errorf(codes.NotFound, "cluster not found: %v", err)
 	}
 
+	if !req.IncludeAgents {
+		return &v1.GetClusterResponse{
+			Cluster: cluster,
+			Etag:    clusterETag(cluster),
+		}, nil
+	}
+
+	// The ETag only versions the cluster, so it is left empty rather than
+	// letting a conditional GET hide changed agents
+	agents, err := s.storage.ListAgents(ctx, req.Id)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to list agents: %v", err))
+	}
+	if req.AgentStatus != v1.AgentStatus_AGENT_STATUS_UNSPECIFIED {
+		filtered := make([]*v1.Agent, 0, len(agents))
+		for _, agent := range agents {
+			if agent.Status == req.AgentStatus {
+				filtered = append(filtered, agent)
+			}
+		}
+		agents = filtered
+	}
+
 	return &v1.GetClusterResponse{
 		Cluster: cluster,
+		Agents:  agents,
 	}, nil
 }
 
+// clusterETag hashes the deterministic encoding of a cluster, which includes
+// updated_at, into an opaque version string
+func clusterETag(cluster *v1.Cluster) string {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(cluster)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
 // ListClusters lists all clusters
 func (s *ClusterService) ListClusters(ctx context.Context, req *v1.ListClustersRequest) (*v1.ListClustersResponse, error) {
-	clusters, err := s.storage.ListClusters(ctx)
+	clusters, err := s.storage.ListClusters(ctx, req.NamePrefix)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to list clusters: %v", err)
 	}
@@ -84,17 +243,22 @@ func (s *ClusterService) ListClusters(ctx context.Context, req *v1.ListClustersR
 	}, nil
 }
 
-// UpdateCluster updates an existing cluster
+// UpdateCluster updates an existing cluster. A CIDR change that leaves
+// registered agents outside the new subnet is applied with the agents listed
+// in the response, or rejected under CIDRChangeReject.
 func (s *ClusterService) UpdateCluster(ctx context.Context, req *v1.UpdateClusterRequest) (*v1.UpdateClusterResponse, error) {
 	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "cluster ID is required")
 	}
 
-	// Get existing cluster
-	cluster, err := s.storage.GetCluster(ctx, req.Id)
+	// Get existing cluster, editing a copy so a rejected update leaves the
+	// in-memory backends' stored cluster untouched
+	stored, err := s.storage.GetCluster(ctx, req.Id)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "cluster not found: %v", err)
 	}
+	cluster := proto.Clone(stored).(*v1.Cluster)
+	previousCIDR := cluster.GetNetworkConfig().GetCidr()
 
 	// Update fields
 	if req.Name != "" {
@@ -103,19 +267,74 @@ func (s *ClusterService) UpdateCluster(ctx context.Context, req *v1.UpdateCluste
 	if req.Description != "" {
 		cluster.Description = req.Description
 	}
+	if req.NetworkConfig != nil {
+		if err := validateNetworkConfig(req.NetworkConfig); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		cluster.NetworkConfig = req.NetworkConfig
+	}
+	if len(req.AdditionalNetworks) > 0 {
+		cluster.AdditionalNetworks = req.AdditionalNetworks
+	}
+	if req.NetworkConfig != nil || len(req.AdditionalNetworks) > 0 {
+		if err := validateAdditionalNetworks(cluster.NetworkConfig, cluster.AdditionalNetworks); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	var outOfRange []string
+	if cidr := cluster.GetNetworkConfig().GetCidr(); cidr != "" && cidr != previousCIDR {
+		outOfRange, err = s.agentsOutsideCIDR(ctx, cluster.Id, cidr)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to check agent IPs against the new CIDR: %v", err)
+		}
+		if len(outOfRange) > 0 {
+			if s.cidrChangePolicy == CIDRChangeReject {
+				return nil, status.Errorf(codes.FailedPrecondition,
+					"changing cluster %s CIDR to %s would leave agents outside it: %s",
+					cluster.Id, cidr, strings.Join(outOfRange, ", "))
+			}
+			log.Printf("Warning: cluster %s CIDR changed from %q to %s, leaving %d agents outside it: %s",
+				cluster.Id, previousCIDR, cidr, len(outOfRange), strings.Join(outOfRange, ", "))
+		}
+	}
 
-	cluster.UpdatedAt = timestamppb.Now()
+	cluster.UpdatedAt = timestamppb.New(s.clock.Now())
 
 	// Store updated cluster
 	if err := s.storage.UpdateCluster(ctx, cluster); err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to update cluster: %v", err)
+		return nil, storageWriteError("failed to update cluster", err)
 	}
 
 	return &v1.UpdateClusterResponse{
-		Cluster: cluster,
+		Cluster:            cluster,
+		OutOfRangeAgentIds: outOfRange,
 	}, nil
 }
 
+// agentsOutsideCIDR returns the sorted IDs of the cluster's agents whose
+// reported IP address is not in cidr; agents without a valid IP are skipped
+func (s *ClusterService) agentsOutsideCIDR(ctx context.Context, clusterID, cidr string) ([]string, error) {
+	_, subnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	agents, err := s.storage.ListAgents(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	var outside []string
+	for _, agent := range agents {
+		ip := net.ParseIP(agent.IpAddress)
+		if ip != nil && !subnet.Contains(ip) {
+			outside = append(outside, agent.Id)
+		}
+	}
+	sort.Strings(outside)
+	return outside, nil
+}
+
 // DeleteCluster deletes a cluster by ID
 func (s *ClusterService) DeleteCluster(ctx context.Context, req *v1.DeleteClusterRequest) (*v1.DeleteClusterResponse, error) {
 	if req.Id == "" {
@@ -131,15 +350,230 @@ func (s *ClusterService) DeleteCluster(ctx context.Context, req *v1.DeleteCluste
 	}, nil
 }
 
-// validateCreateRequest validates the create cluster request
-func (s *ClusterService) validateCreateRequest(req *v1.CreateClusterRequest) error {
-	if req.Name == "" {
+// DrainCluster cordons a cluster so its agents receive a drain instruction
+// instead of regular instructions on their next poll
+func (s *ClusterService) DrainCluster(ctx context.Context, req *v1.DrainClusterRequest) (*v1.DrainClusterResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "cluster ID is required")
+	}
+
+	cluster, err := s.setCordoned(ctx, req.Id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	agents, err := s.storage.ListAgents(ctx, cluster.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list agents: %v", err)
+	}
+
+	log.Printf("Cluster drained: id=%s, agents=%d", cluster.Id, len(agents))
+
+	return &v1.DrainClusterResponse{
+		Cluster:    cluster,
+		AgentCount: int32(len(agents)),
+	}, nil
+}
+
+// UncordonCluster lifts the cordon so instruction generation resumes
+func (s *ClusterService) UncordonCluster(ctx context.Context, req *v1.UncordonClusterRequest) (*v1.UncordonClusterResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "cluster ID is required")
+	}
+
+	cluster, err := s.setCordoned(ctx, req.Id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Cluster uncordoned: id=%s", cluster.Id)
+
+	return &v1.UncordonClusterResponse{
+		Cluster: cluster,
+	}, nil
+}
+
+// MoveCluster re-keys a cluster and all of its agents to a new cluster ID
+func (s *ClusterService) MoveCluster(ctx context.Context, req *v1.MoveClusterRequest) (*v1.MoveClusterResponse, error) {
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "cluster ID is required")
+	}
+	if err := s.idGen.Validate(req.NewId); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid new cluster ID: %v", err)
+	}
+	if req.NewId == req.Id {
+		return nil, status.Error(codes.InvalidArgument, "new cluster ID must differ from the current ID")
+	}
+
+	exists, err := s.storage.ClusterExists(ctx, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check cluster existence: %v", err)
+	}
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "cluster %s not found", req.Id)
+	}
+
+	exists, err = s.storage.ClusterExists(ctx, req.NewId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check cluster existence: %v", err)
+	}
+	if exists {
+		return nil, conflictError(ResourceTypeCluster, req.NewId, fmt.Sprintf("cluster %s already exists", req.NewId))
+	}
+
+	if err := s.storage.MoveCluster(ctx, req.Id, req.NewId); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to move cluster: %v", err)
+	}
+
+	cluster, err := s.storage.GetCluster(ctx, req.NewId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get moved cluster: %v", err)
+	}
+
+	agents, err := s.storage.ListAgents(ctx, req.NewId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list agents: %v", err)
+	}
+
+	log.Printf("Cluster moved: old_id=%s, new_id=%s, agents=%d", req.Id, req.NewId, len(agents))
+
+	return &v1.MoveClusterResponse{
+		Cluster:    cluster,
+		AgentCount: int32(len(agents)),
+	}, nil
+}
+
+// FindMatchingClusters lists the clusters whose primary network CIDR contains
+// the given IP address; clusters without a CIDR never match
+func (s *ClusterService) FindMatchingClusters(ctx context.Context, req *v1.FindMatchingClustersRequest) (*v1.FindMatchingClustersResponse, error) {
+	if req.IpAddress == "" {
+		return nil, status.Error(codes.InvalidArgument, "IP address is required")
+	}
+	ip := net.ParseIP(req.IpAddress)
+	if ip == nil {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid IP address %q", req.IpAddress))
+	}
+
+	clusters, err := s.storage.ListClusters(ctx, "")
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list clusters: %v", err)
+	}
+
+	matches := make([]*v1.Cluster, 0)
+	for _, cluster := range clusters {
+		cidr := cluster.GetNetworkConfig().GetCidr()
+		if cidr == "" {
+			continue
+		}
+		_, subnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Printf("Warning: cluster %s has invalid CIDR %q: %v", cluster.Id, cidr, err)
+			continue
+		}
+		if subnet.Contains(ip) {
+			matches = append(matches, cluster)
+		}
+	}
+
+	return &v1.FindMatchingClustersResponse{
+		Clusters: matches,
+	}, nil
+}
+
+// setCordoned updates the cordon flag of a cluster
+func (s *ClusterService) setCordoned(ctx context.Context, id string, cordoned bool) (*v1.Cluster, error) {
+	cluster, err := s.storage.GetCluster(ctx, id)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "cluster not found: %v", err)
+	}
+
+	cluster.Cordoned = cordoned
+	cluster.UpdatedAt = timestamppb.New(s.clock.Now())
+
+	if err := s.storage.UpdateCluster(ctx, cluster); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update cluster: %v", err)
+	}
+
+	return cluster, nil
+}
+
+// newDefaultNetworkConfig returns a copy of the default network config, or
+// nil when none is configured
+func (s *ClusterService) newDefaultNetworkConfig() *v1.NetworkConfig {
+	if s.defaultNetworkConfig == nil {
+		return nil
+	}
+	return proto.Clone(s.defaultNetworkConfig).(*v1.NetworkConfig)
+}
+
+// validateCreateRequest validates the create cluster request, with the
+// network config the cluster will be created with
+func (s *ClusterService) validateCreateRequest(name string, networkConfig *v1.NetworkConfig, additionalNetworks []*v1.NetworkConfig) error {
+	if name == "" {
 		return fmt.Errorf("cluster name is required")
 	}
 
-	if len(req.Name) > 255 {
+	if len(name) > 255 {
 		return fmt.Errorf("cluster name must be less than 255 characters")
 	}
 
+	if networkConfig != nil {
+		if err := validateNetworkConfig(networkConfig); err != nil {
+			return err
+		}
+	} else if err := enforce(s.strictValidation, fmt.Sprintf("cluster %q", name),
+		fmt.Errorf("network config is required")); err != nil {
+		return err
+	}
+
+	return validateAdditionalNetworks(networkConfig, additionalNetworks)
+}
+
+// validateAdditionalNetworks validates each additional network and requires
+// names unique among the cluster's networks, including the primary
+func validateAdditionalNetworks(primary *v1.NetworkConfig, networks []*v1.NetworkConfig) error {
+	names := make(map[string]bool, len(networks)+1)
+	if name := primary.GetName(); name != "" {
+		names[name] = true
+	}
+
+	for i, network := range networks {
+		if network.GetName() == "" {
+			return fmt.Errorf("additional network %d: name is required", i)
+		}
+		if names[network.Name] {
+			return fmt.Errorf("duplicate network name %q", network.Name)
+		}
+		names[network.Name] = true
+
+		if err := validateNetworkConfig(network); err != nil {
+			return fmt.Errorf("network %q: %w", network.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// validateNetworkConfig validates the CIDR and that the gateway lies within it
+func validateNetworkConfig(networkConfig *v1.NetworkConfig) error {
+	var subnet *net.IPNet
+	if networkConfig.Cidr != "" {
+		var err error
+		_, subnet, err = net.ParseCIDR(networkConfig.Cidr)
+		if err != nil {
+			return fmt.Errorf("invalid network CIDR %q", networkConfig.Cidr)
+		}
+	}
+
+	if networkConfig.Gateway != "" {
+		gateway := net.ParseIP(networkConfig.Gateway)
+		if gateway == nil {
+			return fmt.Errorf("invalid network gateway %q", networkConfig.Gateway)
+		}
+		if subnet != nil && !subnet.Contains(gateway) {
+			return fmt.Errorf("network gateway %s is outside CIDR %s", networkConfig.Gateway, networkConfig.Cidr)
+		}
+	}
+
 	return nil
 }
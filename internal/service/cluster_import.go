@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// ImportClusters creates each streamed cluster and its agents as the record
+// arrives, so memory stays bounded by one record however long the stream.
+// A record that fails validation or storage is reported in the summary and
+// leaves nothing behind; the import continues with the next record.
+func (s *ClusterService) ImportClusters(stream grpc.ClientStreamingServer[v1.ClusterWithAgents, v1.ImportSummary]) error {
+	summary := &v1.ImportSummary{}
+
+	for index := int32(0); ; index++ {
+		record, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		result := &v1.ImportRecordResult{Index: index, ClusterId: record.Id}
+		clusterID, agents, err := s.importCluster(stream.Context(), record)
+		if err != nil {
+			result.Error = err.Error()
+			summary.ClustersFailed++
+			log.Printf("Cluster import record %d (%s) rejected: %v", index, record.Name, err)
+		} else {
+			result.ClusterId = clusterID
+			result.Success = true
+			result.AgentsImported = agents
+			summary.ClustersImported++
+			summary.AgentsImported += agents
+		}
+		summary.Results = append(summary.Results, result)
+	}
+
+	log.Printf("Cluster import finished: %d clusters and %d agents imported, %d clusters rejected",
+		summary.ClustersImported, summary.AgentsImported, summary.ClustersFailed)
+
+	return stream.SendAndClose(summary)
+}
+
+// importCluster validates and creates one imported cluster with its agents,
+// returning the cluster ID and the number of agents created. If an agent
+// cannot be stored the cluster, and with it the agents already created, is
+// deleted again.
+func (s *ClusterService) importCluster(ctx context.Context, record *v1.ClusterWithAgents) (string, int32, error) {
+	if err := s.validateCreateRequest(record.Name, record.NetworkConfig, record.AdditionalNetworks); err != nil {
+		return "", 0, err
+	}
+	if err := s.validateImportedAgents(ctx, record.Agents); err != nil {
+		return "", 0, err
+	}
+
+	clusterID := record.Id
+	if clusterID == "" {
+		clusterID = s.idGen.NewID()
+	} else {
+		if err := s.idGen.Validate(clusterID); err != nil {
+			return "", 0, fmt.Errorf("invalid cluster ID: %w", err)
+		}
+		exists, err := s.storage.ClusterExists(ctx, clusterID)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to check cluster existence: %w", err)
+		}
+		if exists {
+			return "", 0, fmt.Errorf("cluster %s already exists", clusterID)
+		}
+	}
+
+	now := timestamppb.New(s.clock.Now())
+	cluster := &v1.Cluster{
+		Id:                 clusterID,
+		Name:               record.Name,
+		Description:        record.Description,
+		NetworkConfig:      record.NetworkConfig,
+		AdditionalNetworks: record.AdditionalNetworks,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := s.storage.CreateCluster(ctx, cluster); err != nil {
+		return "", 0, fmt.Errorf("failed to create cluster: %w", err)
+	}
+
+	for _, imported := range record.Agents {
+		agent := &v1.Agent{
+			Id:        imported.Id,
+			ClusterId: clusterID,
+			Hostname:  imported.Hostname,
+			IpAddress: imported.IpAddress,
+			Version:   imported.Version,
+			Group:     imported.Group,
+			Status:    v1.AgentStatus_AGENT_STATUS_INACTIVE,
+			LastSeen:  now,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err := s.storage.CreateAgent(ctx, agent); err != nil {
+			if deleteErr := s.storage.DeleteCluster(ctx, clusterID); deleteErr != nil {
+				log.Printf("Warning: failed to roll back imported cluster %s: %v", clusterID, deleteErr)
+			}
+			return "", 0, fmt.Errorf("failed to create agent %s: %w", imported.Id, err)
+		}
+	}
+
+	log.Printf("Cluster imported: id=%s, name=%s, agents=%d", clusterID, record.Name, len(record.Agents))
+
+	return clusterID, int32(len(record.Agents)), nil
+}
+
+// validateImportedAgents requires each imported agent to have an ID that is
+// unique within the record and not yet registered
+func (s *ClusterService) validateImportedAgents(ctx context.Context, agents []*v1.ImportedAgent) error {
+	seen := make(map[string]bool, len(agents))
+	for i, agent := range agents {
+		if agent.Id == "" {
+			return fmt.Errorf("agent %d: ID is required", i)
+		}
+		if seen[agent.Id] {
+			return fmt.Errorf("agent %s is listed more than once", agent.Id)
+		}
+		seen[agent.Id] = true
+
+		if _, err := s.storage.GetAgent(ctx, agent.Id); err == nil {
+			return fmt.Errorf("agent %s already exists", agent.Id)
+		}
+	}
+	return nil
+}
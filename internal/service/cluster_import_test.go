@@ -0,0 +1,143 @@
+package service_test
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+
+	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// importStream is a client stream that replays queued records and captures the summary
+type importStream struct {
+	grpc.ServerStream
+	records []*v1.ClusterWithAgents
+	summary *v1.ImportSummary
+}
+
+func (s *importStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *importStream) Recv() (*v1.ClusterWithAgents, error) {
+	if len(s.records) == 0 {
+		return nil, io.EOF
+	}
+	record := s.records[0]
+	s.records = s.records[1:]
+	return record, nil
+}
+
+func (s *importStream) SendAndClose(summary *v1.ImportSummary) error {
+	s.summary = summary
+	return nil
+}
+
+var _ = Describe("ImportClusters", func() {
+	var (
+		clusterService *service.ClusterService
+		store          *mock.Storage
+		ctx            context.Context
+	)
+
+	BeforeEach(func() {
+		store = mock.New()
+		clusterService = service.NewClusterService(store)
+		ctx = context.Background()
+	})
+
+	importRecords := func(records ...*v1.ClusterWithAgents) *v1.ImportSummary {
+		stream := &importStream{records: records}
+		Expect(clusterService.ImportClusters(stream)).To(Succeed())
+		Expect(stream.summary).NotTo(BeNil())
+		return stream.summary
+	}
+
+	It("should report an invalid record and import the others", func() {
+		keptID := uuid.New().String()
+		summary := importRecords(
+			&v1.ClusterWithAgents{
+				Id:            keptID,
+				Name:          "rack-a",
+				NetworkConfig: &v1.NetworkConfig{Cidr: "10.0.0.0/24", Gateway: "10.0.0.1"},
+				Agents: []*v1.ImportedAgent{
+					{Id: "agent-a1", Hostname: "a1", IpAddress: "10.0.0.11"},
+					{Id: "agent-a2", Hostname: "a2", IpAddress: "10.0.0.12"},
+				},
+			},
+			&v1.ClusterWithAgents{
+				Name:          "rack-bad",
+				NetworkConfig: &v1.NetworkConfig{Cidr: "10.1.0.0/24", Gateway: "10.2.0.1"},
+				Agents:        []*v1.ImportedAgent{{Id: "agent-bad"}},
+			},
+			&v1.ClusterWithAgents{
+				Name:   "rack-c",
+				Agents: []*v1.ImportedAgent{{Id: "agent-c1", Hostname: "c1"}},
+			},
+		)
+
+		Expect(summary.ClustersImported).To(Equal(int32(2)))
+		Expect(summary.ClustersFailed).To(Equal(int32(1)))
+		Expect(summary.AgentsImported).To(Equal(int32(3)))
+		Expect(summary.Results).To(HaveLen(3))
+
+		Expect(summary.Results[0].Success).To(BeTrue())
+		Expect(summary.Results[0].ClusterId).To(Equal(keptID))
+		Expect(summary.Results[0].AgentsImported).To(Equal(int32(2)))
+
+		Expect(summary.Results[1].Index).To(Equal(int32(1)))
+		Expect(summary.Results[1].Success).To(BeFalse())
+		Expect(summary.Results[1].Error).To(ContainSubstring("outside CIDR"))
+
+		Expect(summary.Results[2].Success).To(BeTrue())
+		Expect(summary.Results[2].ClusterId).NotTo(BeEmpty())
+
+		agent, err := store.GetAgent(ctx, "agent-a1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agent.ClusterId).To(Equal(keptID))
+		Expect(agent.Status).To(Equal(v1.AgentStatus_AGENT_STATUS_INACTIVE))
+
+		_, err = store.GetAgent(ctx, "agent-bad")
+		Expect(err).To(HaveOccurred())
+		clusters, err := store.ListClusters(ctx, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clusters).To(HaveLen(2))
+	})
+
+	It("should reject records whose cluster or agents already exist", func() {
+		existingID := uuid.New().String()
+		Expect(store.CreateCluster(ctx, &v1.Cluster{Id: existingID, Name: "existing"})).To(Succeed())
+		Expect(store.CreateAgent(ctx, &v1.Agent{Id: "agent-existing", ClusterId: existingID})).To(Succeed())
+
+		summary := importRecords(
+			&v1.ClusterWithAgents{Id: existingID, Name: "duplicate"},
+			&v1.ClusterWithAgents{Name: "takes-agent", Agents: []*v1.ImportedAgent{{Id: "agent-existing"}}},
+			&v1.ClusterWithAgents{Name: "repeats-agent", Agents: []*v1.ImportedAgent{{Id: "agent-x"}, {Id: "agent-x"}}},
+			&v1.ClusterWithAgents{Id: "not-a-uuid", Name: "bad-id"},
+		)
+
+		Expect(summary.ClustersImported).To(BeZero())
+		Expect(summary.ClustersFailed).To(Equal(int32(4)))
+		Expect(summary.Results[0].Error).To(ContainSubstring("already exists"))
+		Expect(summary.Results[1].Error).To(ContainSubstring("agent agent-existing already exists"))
+		Expect(summary.Results[2].Error).To(ContainSubstring("more than once"))
+		Expect(summary.Results[3].Error).To(ContainSubstring("not a UUID"))
+
+		agent, err := store.GetAgent(ctx, "agent-existing")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agent.ClusterId).To(Equal(existingID))
+	})
+
+	It("should return an empty summary for an empty stream", func() {
+		summary := importRecords()
+		Expect(summary.ClustersImported).To(BeZero())
+		Expect(summary.ClustersFailed).To(BeZero())
+		Expect(summary.Results).To(BeEmpty())
+	})
+})
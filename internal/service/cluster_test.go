@@ -2,7 +2,12 @@ package service_test
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"google.golang.org/grpc/codes"
@@ -13,19 +18,54 @@ import (
 	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
 )
 
+// sequenceGenerator is a deterministic IDGenerator producing prefix-1, prefix-2, ...
+type sequenceGenerator struct {
+	prefix string
+	next   int
+}
+
+func (g *sequenceGenerator) NewID() string {
+	g.next++
+	return g.prefix + strconv.Itoa(g.next)
+}
+
+func (g *sequenceGenerator) Validate(id string) error {
+	if _, err := strconv.Atoi(strings.TrimPrefix(id, g.prefix)); err != nil || !strings.HasPrefix(id, g.prefix) {
+		return fmt.Errorf("%q is not a sequence ID", id)
+	}
+	return nil
+}
+
 var _ = Describe("ClusterService", func() {
 	var (
 		clusterService *service.ClusterService
+		store          *mock.Storage
 		ctx            context.Context
 	)
 
 	BeforeEach(func() {
-		storage := mock.New()
-		clusterService = service.NewClusterService(storage)
+		store = mock.New()
+		clusterService = service.NewClusterService(store)
 		ctx = context.Background()
 	})
 
 	Describe("CreateCluster", func() {
+		It("should require a network config only when validation is strict", func() {
+			_, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "lenient"})
+			Expect(err).NotTo(HaveOccurred())
+
+			clusterService = service.NewClusterService(store, service.WithStrictClusterValidation(true))
+			_, err = clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "strict"})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err).To(MatchError(ContainSubstring("network config is required")))
+
+			_, err = clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{
+				Name:          "strict",
+				NetworkConfig: &v1.NetworkConfig{Cidr: "10.0.0.0/24", Gateway: "10.0.0.1"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
 		It("should create a cluster with valid name", func() {
 			req := &v1.CreateClusterRequest{
 				Name:        "test-cluster",
@@ -41,6 +81,21 @@ var _ = Describe("ClusterService", func() {
 			Expect(resp.Cluster.UpdatedAt).NotTo(BeNil())
 		})
 
+		It("should take timestamps from the service clock", func() {
+			clock := service.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+			clockedService := service.NewClusterService(store, service.WithClusterClock(clock))
+
+			resp, err := clockedService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Cluster.CreatedAt.AsTime()).To(Equal(clock.Now()))
+
+			clock.Advance(time.Hour)
+			updateResp, err := clockedService.UpdateCluster(ctx, &v1.UpdateClusterRequest{Id: resp.Cluster.Id, Description: "updated"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updateResp.Cluster.UpdatedAt.AsTime()).To(Equal(clock.Now()))
+			Expect(updateResp.Cluster.CreatedAt.AsTime()).To(Equal(clock.Now().Add(-time.Hour)))
+		})
+
 		It("should return error when name is missing", func() {
 			req := &v1.CreateClusterRequest{
 				Description: "Test Description",
@@ -65,6 +120,90 @@ var _ = Describe("ClusterService", func() {
 			Expect(resp.Cluster.Name).To(Equal("minimal-cluster"))
 		})
 
+		It("should create cluster with a network config", func() {
+			req := &v1.CreateClusterRequest{
+				Name: "network-cluster",
+				NetworkConfig: &v1.NetworkConfig{
+					Cidr:    "10.0.0.0/24",
+					Gateway: "10.0.0.1",
+				},
+			}
+
+			resp, err := clusterService.CreateCluster(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Cluster.NetworkConfig.Gateway).To(Equal("10.0.0.1"))
+		})
+
+		It("should return error when the gateway is outside the CIDR", func() {
+			req := &v1.CreateClusterRequest{
+				Name: "network-cluster",
+				NetworkConfig: &v1.NetworkConfig{
+					Cidr:    "10.0.0.0/24",
+					Gateway: "10.0.1.1",
+				},
+			}
+
+			_, err := clusterService.CreateCluster(ctx, req)
+			Expect(err).To(HaveOccurred())
+			st, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(st.Code()).To(Equal(codes.InvalidArgument))
+			Expect(st.Message()).To(ContainSubstring("outside CIDR"))
+		})
+
+		Context("with additional networks", func() {
+			var req *v1.CreateClusterRequest
+
+			BeforeEach(func() {
+				req = &v1.CreateClusterRequest{
+					Name:          "multi-network-cluster",
+					NetworkConfig: &v1.NetworkConfig{Cidr: "10.0.0.0/24", Gateway: "10.0.0.1", Name: "primary"},
+					AdditionalNetworks: []*v1.NetworkConfig{
+						{Name: "management", Purpose: "management", Cidr: "192.168.0.0/24", Gateway: "192.168.0.1"},
+						{Name: "data", Purpose: "storage", Cidr: "172.16.0.0/16", Gateway: "172.16.0.1"},
+					},
+				}
+			})
+
+			It("should create a cluster with two named networks", func() {
+				resp, err := clusterService.CreateCluster(ctx, req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Cluster.NetworkConfig.Cidr).To(Equal("10.0.0.0/24"))
+				Expect(resp.Cluster.AdditionalNetworks).To(HaveLen(2))
+				Expect(resp.Cluster.AdditionalNetworks[0].Name).To(Equal("management"))
+				Expect(resp.Cluster.AdditionalNetworks[1].Purpose).To(Equal("storage"))
+
+				getResp, err := clusterService.GetCluster(ctx, &v1.GetClusterRequest{Id: resp.Cluster.Id})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getResp.Cluster.AdditionalNetworks).To(HaveLen(2))
+			})
+
+			It("should check each network's gateway against its own CIDR", func() {
+				req.AdditionalNetworks[1].Gateway = "192.168.0.1"
+
+				_, err := clusterService.CreateCluster(ctx, req)
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring(`network "data"`))
+				Expect(err.Error()).To(ContainSubstring("outside CIDR"))
+			})
+
+			It("should require a name for each additional network", func() {
+				req.AdditionalNetworks[0].Name = ""
+
+				_, err := clusterService.CreateCluster(ctx, req)
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring("name is required"))
+			})
+
+			It("should reject duplicate network names, including the primary's", func() {
+				req.AdditionalNetworks[1].Name = "primary"
+
+				_, err := clusterService.CreateCluster(ctx, req)
+				Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+				Expect(err.Error()).To(ContainSubstring("duplicate network name"))
+			})
+		})
+
 		It("should return error when name is too long", func() {
 			longName := string(make([]byte, 256))
 			for i := range longName {
@@ -81,6 +220,97 @@ var _ = Describe("ClusterService", func() {
 			Expect(st.Code()).To(Equal(codes.InvalidArgument))
 			Expect(st.Message()).To(ContainSubstring("less than 255 characters"))
 		})
+
+		It("should assign IDs from an injected generator", func() {
+			clusterService = service.NewClusterService(store, service.WithIDGenerator(&sequenceGenerator{prefix: "cl-"}))
+
+			first, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "first"})
+			Expect(err).NotTo(HaveOccurred())
+			second, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "second"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(first.Cluster.Id).To(Equal("cl-1"))
+			Expect(second.Cluster.Id).To(Equal("cl-2"))
+
+			got, err := clusterService.GetCluster(ctx, &v1.GetClusterRequest{Id: "cl-2"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got.Cluster.Name).To(Equal("second"))
+		})
+
+		It("should assign UUIDs by default", func() {
+			resp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = uuid.Parse(resp.Cluster.Id)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("EnsureCluster", func() {
+		It("should create the cluster with the given ID when missing", func() {
+			id := uuid.New().String()
+			cluster, err := clusterService.EnsureCluster(ctx, id, "unassigned")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cluster.Id).To(Equal(id))
+			Expect(cluster.Name).To(Equal("unassigned"))
+
+			exists, err := store.ClusterExists(ctx, id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue())
+		})
+
+		It("should keep an existing cluster unchanged", func() {
+			createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "existing"})
+			Expect(err).NotTo(HaveOccurred())
+
+			cluster, err := clusterService.EnsureCluster(ctx, createResp.Cluster.Id, "unassigned")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cluster.Name).To(Equal("existing"))
+		})
+
+		Context("with a default network config", func() {
+			template := &v1.NetworkConfig{Cidr: "10.0.0.0/24", Gateway: "10.0.0.1"}
+
+			It("should give the auto-created cluster the template", func() {
+				templated := service.NewClusterService(store, service.WithDefaultNetworkConfig(template, false))
+
+				cluster, err := templated.EnsureCluster(ctx, uuid.New().String(), "unassigned")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cluster.NetworkConfig.GetCidr()).To(Equal("10.0.0.0/24"))
+				Expect(cluster.NetworkConfig.GetGateway()).To(Equal("10.0.0.1"))
+				Expect(cluster.NetworkConfig).NotTo(BeIdenticalTo(template))
+			})
+
+			It("should reject an invalid template", func() {
+				templated := service.NewClusterService(store, service.WithDefaultNetworkConfig(
+					&v1.NetworkConfig{Cidr: "10.0.0.0/24", Gateway: "10.0.1.1"}, false))
+
+				_, err := templated.EnsureCluster(ctx, uuid.New().String(), "unassigned")
+				Expect(err).To(MatchError(ContainSubstring("invalid default network config")))
+			})
+
+			It("should apply the template to created clusters without a network config when enabled", func() {
+				templated := service.NewClusterService(store, service.WithDefaultNetworkConfig(template, true))
+
+				resp, err := templated.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "dev-cluster"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Cluster.NetworkConfig.GetCidr()).To(Equal("10.0.0.0/24"))
+
+				resp, err = templated.CreateCluster(ctx, &v1.CreateClusterRequest{
+					Name:          "explicit-cluster",
+					NetworkConfig: &v1.NetworkConfig{Cidr: "192.168.0.0/24"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Cluster.NetworkConfig.GetCidr()).To(Equal("192.168.0.0/24"))
+			})
+
+			It("should leave created clusters without a network config when not enabled", func() {
+				templated := service.NewClusterService(store, service.WithDefaultNetworkConfig(template, false))
+
+				resp, err := templated.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "prod-cluster"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Cluster.NetworkConfig).To(BeNil())
+			})
+		})
 	})
 
 	Describe("GetCluster", func() {
@@ -101,6 +331,86 @@ var _ = Describe("ClusterService", func() {
 			Expect(getResp.Cluster.Name).To(Equal("test-cluster"))
 		})
 
+		It("should return an etag that changes only when the cluster does", func() {
+			createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+			Expect(err).NotTo(HaveOccurred())
+			id := createResp.Cluster.Id
+
+			first, err := clusterService.GetCluster(ctx, &v1.GetClusterRequest{Id: id})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.Etag).NotTo(BeEmpty())
+
+			again, err := clusterService.GetCluster(ctx, &v1.GetClusterRequest{Id: id})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(again.Etag).To(Equal(first.Etag))
+
+			_, err = clusterService.UpdateCluster(ctx, &v1.UpdateClusterRequest{Id: id, Name: "renamed"})
+			Expect(err).NotTo(HaveOccurred())
+			updated, err := clusterService.GetCluster(ctx, &v1.GetClusterRequest{Id: id})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.Etag).NotTo(Equal(first.Etag))
+		})
+
+		Context("with agents included", func() {
+			var id string
+
+			BeforeEach(func() {
+				createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+				Expect(err).NotTo(HaveOccurred())
+				id = createResp.Cluster.Id
+
+				otherResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "other-cluster"})
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, agent := range []*v1.Agent{
+					{Id: "agent-active", ClusterId: id, Status: v1.AgentStatus_AGENT_STATUS_ACTIVE},
+					{Id: "agent-inactive", ClusterId: id, Status: v1.AgentStatus_AGENT_STATUS_INACTIVE},
+					{Id: "agent-other", ClusterId: otherResp.Cluster.Id, Status: v1.AgentStatus_AGENT_STATUS_ACTIVE},
+				} {
+					Expect(store.CreateAgent(ctx, agent)).To(Succeed())
+				}
+			})
+
+			agentIDs := func(agents []*v1.Agent) []string {
+				ids := make([]string, 0, len(agents))
+				for _, agent := range agents {
+					ids = append(ids, agent.Id)
+				}
+				return ids
+			}
+
+			It("should omit agents by default", func() {
+				resp, err := clusterService.GetCluster(ctx, &v1.GetClusterRequest{Id: id})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Agents).To(BeEmpty())
+				Expect(resp.Etag).NotTo(BeEmpty())
+			})
+
+			It("should embed the agents ListAgents returns for the cluster", func() {
+				resp, err := clusterService.GetCluster(ctx, &v1.GetClusterRequest{Id: id, IncludeAgents: true})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Cluster.Id).To(Equal(id))
+
+				listed, err := store.ListAgents(ctx, id)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agentIDs(resp.Agents)).To(ConsistOf(agentIDs(listed)))
+				Expect(agentIDs(resp.Agents)).To(ConsistOf("agent-active", "agent-inactive"))
+
+				// The ETag does not version the agents
+				Expect(resp.Etag).To(BeEmpty())
+			})
+
+			It("should only embed agents with the requested status", func() {
+				resp, err := clusterService.GetCluster(ctx, &v1.GetClusterRequest{
+					Id:            id,
+					IncludeAgents: true,
+					AgentStatus:   v1.AgentStatus_AGENT_STATUS_INACTIVE,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(agentIDs(resp.Agents)).To(Equal([]string{"agent-inactive"}))
+			})
+		})
+
 		It("should return error for non-existent cluster", func() {
 			req := &v1.GetClusterRequest{
 				Id: "non-existent-id",
@@ -148,9 +458,102 @@ var _ = Describe("ClusterService", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(listResp.Clusters).To(HaveLen(2))
 		})
+
+		It("should return only clusters whose name starts with the prefix", func() {
+			for _, name := range []string{"rack-a1", "rack-a2", "rack-b1", "lab-rack-a3"} {
+				_, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: name})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			listResp, err := clusterService.ListClusters(ctx, &v1.ListClustersRequest{NamePrefix: "rack-a"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listResp.Clusters).To(ConsistOf(
+				HaveField("Name", "rack-a1"),
+				HaveField("Name", "rack-a2"),
+			))
+
+			listResp, err = clusterService.ListClusters(ctx, &v1.ListClustersRequest{NamePrefix: "Rack"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listResp.Clusters).To(BeEmpty())
+		})
 	})
 
 	Describe("UpdateCluster", func() {
+		Context("when the CIDR changes under registered agents", func() {
+			var clusterID string
+
+			BeforeEach(func() {
+				createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{
+					Name:          "rack-a",
+					NetworkConfig: &v1.NetworkConfig{Cidr: "10.0.0.0/16", Gateway: "10.0.0.1"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				clusterID = createResp.Cluster.Id
+
+				for id, ip := range map[string]string{"agent-in": "10.0.0.10", "agent-out": "10.0.200.10", "agent-no-ip": ""} {
+					Expect(store.CreateAgent(ctx, &v1.Agent{Id: id, ClusterId: clusterID, IpAddress: ip})).To(Succeed())
+				}
+			})
+
+			narrow := &v1.NetworkConfig{Cidr: "10.0.0.0/24", Gateway: "10.0.0.1"}
+
+			It("should apply the change and flag agents left outside the new CIDR", func() {
+				resp, err := clusterService.UpdateCluster(ctx, &v1.UpdateClusterRequest{Id: clusterID, NetworkConfig: narrow})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.OutOfRangeAgentIds).To(Equal([]string{"agent-out"}))
+				Expect(resp.Cluster.NetworkConfig.Cidr).To(Equal("10.0.0.0/24"))
+			})
+
+			It("should reject the change under the reject policy", func() {
+				clusterService = service.NewClusterService(store, service.WithCIDRChangePolicy(service.CIDRChangeReject))
+
+				_, err := clusterService.UpdateCluster(ctx, &v1.UpdateClusterRequest{Id: clusterID, NetworkConfig: narrow})
+				Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+				Expect(err).To(MatchError(ContainSubstring("agent-out")))
+
+				cluster, err := store.GetCluster(ctx, clusterID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cluster.NetworkConfig.Cidr).To(Equal("10.0.0.0/16"))
+			})
+
+			It("should not flag agents when the CIDR is unchanged", func() {
+				clusterService = service.NewClusterService(store, service.WithCIDRChangePolicy(service.CIDRChangeReject))
+
+				resp, err := clusterService.UpdateCluster(ctx, &v1.UpdateClusterRequest{Id: clusterID, Description: "moved racks"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.OutOfRangeAgentIds).To(BeEmpty())
+			})
+		})
+
+		It("should replace the additional networks and validate each", func() {
+			createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{
+				Name: "multi-network-cluster",
+				AdditionalNetworks: []*v1.NetworkConfig{
+					{Name: "management", Cidr: "192.168.0.0/24"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = clusterService.UpdateCluster(ctx, &v1.UpdateClusterRequest{
+				Id: createResp.Cluster.Id,
+				AdditionalNetworks: []*v1.NetworkConfig{
+					{Name: "data", Cidr: "172.16.0.0/16", Gateway: "10.0.0.1"},
+				},
+			})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			updateResp, err := clusterService.UpdateCluster(ctx, &v1.UpdateClusterRequest{
+				Id: createResp.Cluster.Id,
+				AdditionalNetworks: []*v1.NetworkConfig{
+					{Name: "data", Cidr: "172.16.0.0/16", Gateway: "172.16.0.1"},
+					{Name: "backup", Cidr: "10.10.0.0/16"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updateResp.Cluster.AdditionalNetworks).To(HaveLen(2))
+			Expect(updateResp.Cluster.AdditionalNetworks[0].Name).To(Equal("data"))
+		})
+
 		It("should update cluster name and description", func() {
 			createReq := &v1.CreateClusterRequest{
 				Name:        "original-name",
@@ -234,4 +637,202 @@ var _ = Describe("ClusterService", func() {
 			Expect(st.Code()).To(Equal(codes.InvalidArgument))
 		})
 	})
+
+	Describe("DrainCluster", func() {
+		It("should cordon the cluster", func() {
+			createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+			Expect(err).NotTo(HaveOccurred())
+
+			drainResp, err := clusterService.DrainCluster(ctx, &v1.DrainClusterRequest{Id: createResp.Cluster.Id})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(drainResp.Cluster.Cordoned).To(BeTrue())
+			Expect(drainResp.AgentCount).To(Equal(int32(0)))
+
+			getResp, err := clusterService.GetCluster(ctx, &v1.GetClusterRequest{Id: createResp.Cluster.Id})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(getResp.Cluster.Cordoned).To(BeTrue())
+		})
+
+		It("should return error for non-existent cluster", func() {
+			_, err := clusterService.DrainCluster(ctx, &v1.DrainClusterRequest{Id: "non-existent-id"})
+			Expect(err).To(HaveOccurred())
+			st, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(st.Code()).To(Equal(codes.NotFound))
+		})
+
+		It("should return error when ID is empty", func() {
+			_, err := clusterService.DrainCluster(ctx, &v1.DrainClusterRequest{Id: ""})
+			Expect(err).To(HaveOccurred())
+			st, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(st.Code()).To(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("UncordonCluster", func() {
+		It("should lift the cordon", func() {
+			createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = clusterService.DrainCluster(ctx, &v1.DrainClusterRequest{Id: createResp.Cluster.Id})
+			Expect(err).NotTo(HaveOccurred())
+
+			uncordonResp, err := clusterService.UncordonCluster(ctx, &v1.UncordonClusterRequest{Id: createResp.Cluster.Id})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uncordonResp.Cluster.Cordoned).To(BeFalse())
+		})
+
+		It("should return error for non-existent cluster", func() {
+			_, err := clusterService.UncordonCluster(ctx, &v1.UncordonClusterRequest{Id: "non-existent-id"})
+			Expect(err).To(HaveOccurred())
+			st, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(st.Code()).To(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("MoveCluster", func() {
+		var clusterId string
+
+		BeforeEach(func() {
+			createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+			Expect(err).NotTo(HaveOccurred())
+			clusterId = createResp.Cluster.Id
+
+			agentService := service.NewAgentService(store)
+			for _, id := range []string{"agent-1", "agent-2"} {
+				_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: id, ClusterId: clusterId})
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should re-key the cluster and its agents", func() {
+			newId := uuid.New().String()
+
+			resp, err := clusterService.MoveCluster(ctx, &v1.MoveClusterRequest{Id: clusterId, NewId: newId})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Cluster.Id).To(Equal(newId))
+			Expect(resp.Cluster.Name).To(Equal("test-cluster"))
+			Expect(resp.AgentCount).To(Equal(int32(2)))
+
+			_, err = clusterService.GetCluster(ctx, &v1.GetClusterRequest{Id: clusterId})
+			Expect(err).To(HaveOccurred())
+
+			agents, err := store.ListAgents(ctx, newId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agents).To(HaveLen(2))
+			oldAgents, err := store.ListAgents(ctx, clusterId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(oldAgents).To(BeEmpty())
+		})
+
+		It("should return AlreadyExists when the new ID is taken", func() {
+			otherResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "other-cluster"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = clusterService.MoveCluster(ctx, &v1.MoveClusterRequest{Id: clusterId, NewId: otherResp.Cluster.Id})
+			Expect(err).To(HaveOccurred())
+			st, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(st.Code()).To(Equal(codes.AlreadyExists))
+
+			conflict := conflictingResource(err)
+			Expect(conflict.ResourceType).To(Equal(service.ResourceTypeCluster))
+			Expect(conflict.ResourceName).To(Equal(otherResp.Cluster.Id))
+
+			agents, err := store.ListAgents(ctx, clusterId)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(agents).To(HaveLen(2))
+		})
+
+		It("should return error for non-existent cluster", func() {
+			_, err := clusterService.MoveCluster(ctx, &v1.MoveClusterRequest{Id: "non-existent-id", NewId: uuid.New().String()})
+			Expect(err).To(HaveOccurred())
+			st, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(st.Code()).To(Equal(codes.NotFound))
+		})
+
+		It("should return error when the new ID is not a UUID", func() {
+			_, err := clusterService.MoveCluster(ctx, &v1.MoveClusterRequest{Id: clusterId, NewId: "not-a-uuid"})
+			Expect(err).To(HaveOccurred())
+			st, ok := status.FromError(err)
+			Expect(ok).To(BeTrue())
+			Expect(st.Code()).To(Equal(codes.InvalidArgument))
+		})
+
+		It("should accept a new ID following the configured scheme", func() {
+			clusterService = service.NewClusterService(store, service.WithIDGenerator(&sequenceGenerator{prefix: "cl-"}))
+
+			_, err := clusterService.MoveCluster(ctx, &v1.MoveClusterRequest{Id: clusterId, NewId: uuid.New().String()})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			resp, err := clusterService.MoveCluster(ctx, &v1.MoveClusterRequest{Id: clusterId, NewId: "cl-7"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Cluster.Id).To(Equal("cl-7"))
+		})
+	})
+
+	Describe("FindMatchingClusters", func() {
+		var managementId, dataId string
+
+		BeforeEach(func() {
+			for name, cidr := range map[string]string{"management": "10.0.0.0/24", "data": "10.1.0.0/16"} {
+				createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{
+					Name:          name,
+					NetworkConfig: &v1.NetworkConfig{Cidr: cidr},
+				})
+				Expect(err).NotTo(HaveOccurred())
+				if name == "management" {
+					managementId = createResp.Cluster.Id
+				} else {
+					dataId = createResp.Cluster.Id
+				}
+			}
+			_, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "unmanaged"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		// matchingIds returns the IDs of the clusters matching an IP address
+		matchingIds := func(ip string) []string {
+			resp, err := clusterService.FindMatchingClusters(ctx, &v1.FindMatchingClustersRequest{IpAddress: ip})
+			Expect(err).NotTo(HaveOccurred())
+
+			var ids []string
+			for _, cluster := range resp.Clusters {
+				ids = append(ids, cluster.Id)
+			}
+			return ids
+		}
+
+		It("should return the clusters whose CIDR contains the IP", func() {
+			Expect(matchingIds("10.0.0.42")).To(ConsistOf(managementId))
+			Expect(matchingIds("10.1.200.7")).To(ConsistOf(dataId))
+		})
+
+		It("should return every overlapping cluster", func() {
+			createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{
+				Name:          "wide",
+				NetworkConfig: &v1.NetworkConfig{Cidr: "10.0.0.0/8"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(matchingIds("10.0.0.42")).To(ConsistOf(managementId, createResp.Cluster.Id))
+		})
+
+		It("should return an empty list when no cluster matches", func() {
+			resp, err := clusterService.FindMatchingClusters(ctx, &v1.FindMatchingClustersRequest{IpAddress: "192.168.1.1"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Clusters).NotTo(BeNil())
+			Expect(resp.Clusters).To(BeEmpty())
+		})
+
+		It("should reject a missing or malformed IP address", func() {
+			_, err := clusterService.FindMatchingClusters(ctx, &v1.FindMatchingClustersRequest{})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			_, err = clusterService.FindMatchingClusters(ctx, &v1.FindMatchingClustersRequest{IpAddress: "10.0.0.0/24"})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+	})
 })
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/filanov/netctrl-server/internal/storage"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// ReconcileInterval is how often the reconciler compares desired and applied config
+const ReconcileInterval = 30 * time.Second
+
+// ConfigReconciler compares each cluster's desired network config with the
+// config its agents report as applied and flags drifted agents, which are then
+// sent APPLY_NETWORK_CONFIG instructions on their next poll
+type ConfigReconciler struct {
+	storage storage.Storage
+	stopCh  chan struct{}
+
+	mu          sync.RWMutex
+	driftCounts map[string]int
+}
+
+// NewConfigReconciler creates a new config reconciler
+func NewConfigReconciler(store storage.Storage) *ConfigReconciler {
+	return &ConfigReconciler{
+		storage:     store,
+		stopCh:      make(chan struct{}),
+		driftCounts: make(map[string]int),
+	}
+}
+
+// Start begins the reconcile loop
+func (r *ConfigReconciler) Start(ctx context.Context) {
+	log.Println("Starting config reconciler...")
+	ticker := time.NewTicker(ReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Config reconciler stopping due to context cancellation")
+			return
+		case <-r.stopCh:
+			log.Println("Config reconciler stopped")
+			return
+		case <-ticker.C:
+			r.reconcile(ctx)
+		}
+	}
+}
+
+// Stop stops the config reconciler
+func (r *ConfigReconciler) Stop() {
+	close(r.stopCh)
+}
+
+// ReconcileOnce performs a single reconcile pass (exposed for testing)
+func (r *ConfigReconciler) ReconcileOnce(ctx context.Context) {
+	r.reconcile(ctx)
+}
+
+// DriftCounts returns the number of drifted agents per cluster as of the last pass
+func (r *ConfigReconciler) DriftCounts() map[string]int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make(map[string]int, len(r.driftCounts))
+	for clusterID, count := range r.driftCounts {
+		counts[clusterID] = count
+	}
+	return counts
+}
+
+// reconcile flags active agents whose applied config differs from their
+// cluster's desired config and clears the flag on agents that converged
+func (r *ConfigReconciler) reconcile(ctx context.Context) {
+	clusters, err := r.storage.ListClusters(ctx, "")
+	if err != nil {
+		log.Printf("Failed to list clusters for reconciliation: %v", err)
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, cluster := range clusters {
+		agents, err := r.storage.ListAgents(ctx, cluster.Id)
+		if err != nil {
+			log.Printf("Failed to list agents of cluster %s for reconciliation: %v", cluster.Id, err)
+			continue
+		}
+
+		for _, agent := range agents {
+			if agent.Status != v1.AgentStatus_AGENT_STATUS_ACTIVE {
+				continue
+			}
+
+			drifted := cluster.NetworkConfig != nil && !sameAddressing(cluster.NetworkConfig, agent.AppliedNetworkConfig)
+			if drifted {
+				counts[cluster.Id]++
+			}
+			if drifted == agent.ConfigDrifted {
+				continue
+			}
+
+			// An agent written since the listing is re-evaluated next cycle
+			agent.ConfigDrifted = drifted
+			if err := r.storage.UpdateAgent(ctx, agent); err != nil && !errors.Is(err, storage.ErrConflict) {
+				log.Printf("Failed to update config drift of agent %s: %v", agent.Id, err)
+			}
+		}
+
+		if counts[cluster.Id] > 0 {
+			log.Printf("Cluster %s has %d agents with drifted network config", cluster.Id, counts[cluster.Id])
+		}
+	}
+
+	r.mu.Lock()
+	r.driftCounts = counts
+	r.mu.Unlock()
+}
+
+// sameAddressing reports whether two network configs have the same CIDR and
+// gateway. Names and purposes only label a network, so agents need not echo them.
+func sameAddressing(desired, applied *v1.NetworkConfig) bool {
+	return applied != nil && desired.GetCidr() == applied.Cidr && desired.GetGateway() == applied.Gateway
+}
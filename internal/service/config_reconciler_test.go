@@ -0,0 +1,169 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+var _ = Describe("ConfigReconciler", func() {
+	var (
+		reconciler     *service.ConfigReconciler
+		agentService   *service.AgentService
+		clusterService *service.ClusterService
+		store          *mock.Storage
+		ctx            context.Context
+		testClusterId  string
+		desiredConfig  *v1.NetworkConfig
+	)
+
+	// applyInstructions returns the APPLY_NETWORK_CONFIG instructions of a poll
+	applyInstructions := func(agentID string) []*v1.Instruction {
+		resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentID})
+		Expect(err).NotTo(HaveOccurred())
+
+		var instructions []*v1.Instruction
+		for _, instruction := range resp.Instructions {
+			if instruction.Type == v1.InstructionType_INSTRUCTION_TYPE_APPLY_NETWORK_CONFIG {
+				instructions = append(instructions, instruction)
+			}
+		}
+		return instructions
+	}
+
+	submitApplyResult := func(agentID string, result *v1.NetworkConfigResult) {
+		resp, err := agentService.SubmitInstructionResult(ctx, &v1.SubmitInstructionResultRequest{
+			AgentId:       agentID,
+			InstructionId: "instruction-apply",
+			Result: &v1.InstructionResult{
+				InstructionType: v1.InstructionType_INSTRUCTION_TYPE_APPLY_NETWORK_CONFIG,
+				Result:          &v1.InstructionResult_NetworkConfig{NetworkConfig: result},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Success).To(BeTrue())
+	}
+
+	BeforeEach(func() {
+		store = mock.New()
+		reconciler = service.NewConfigReconciler(store)
+		agentService = service.NewAgentService(store)
+		clusterService = service.NewClusterService(store)
+		ctx = context.Background()
+
+		desiredConfig = &v1.NetworkConfig{Cidr: "10.0.0.0/24", Gateway: "10.0.0.1"}
+		createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{
+			Name:          "test-cluster",
+			NetworkConfig: desiredConfig,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		testClusterId = createResp.Cluster.Id
+
+		_, err = agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+			Id:        "agent-1",
+			ClusterId: testClusterId,
+			Hostname:  "node1",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should not issue config instructions before reconciling", func() {
+		Expect(applyInstructions("agent-1")).To(BeEmpty())
+	})
+
+	It("should flag an agent without the desired config and issue the config instruction", func() {
+		reconciler.ReconcileOnce(ctx)
+
+		agent, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agent.ConfigDrifted).To(BeTrue())
+		Expect(reconciler.DriftCounts()).To(HaveKeyWithValue(testClusterId, 1))
+
+		instructions := applyInstructions("agent-1")
+		Expect(instructions).To(HaveLen(1))
+		var payload map[string]string
+		Expect(json.Unmarshal([]byte(instructions[0].Payload), &payload)).To(Succeed())
+		Expect(payload).To(HaveKeyWithValue("cidr", "10.0.0.0/24"))
+		Expect(payload).To(HaveKeyWithValue("gateway", "10.0.0.1"))
+	})
+
+	It("should re-issue the config instruction while the agent stays drifted", func() {
+		reconciler.ReconcileOnce(ctx)
+		Expect(applyInstructions("agent-1")).To(HaveLen(1))
+
+		submitApplyResult("agent-1", &v1.NetworkConfigResult{Success: false, ErrorMessage: "interface busy"})
+
+		reconciler.ReconcileOnce(ctx)
+		Expect(applyInstructions("agent-1")).To(HaveLen(1))
+		Expect(reconciler.DriftCounts()).To(HaveKeyWithValue(testClusterId, 1))
+	})
+
+	It("should clear drift once the agent reports the desired config", func() {
+		reconciler.ReconcileOnce(ctx)
+		submitApplyResult("agent-1", &v1.NetworkConfigResult{
+			Success:       true,
+			AppliedConfig: &v1.NetworkConfig{Cidr: "10.0.0.0/24", Gateway: "10.0.0.1"},
+		})
+
+		reconciler.ReconcileOnce(ctx)
+
+		agent, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agent.ConfigDrifted).To(BeFalse())
+		Expect(reconciler.DriftCounts()).NotTo(HaveKey(testClusterId))
+		Expect(applyInstructions("agent-1")).To(BeEmpty())
+	})
+
+	It("should ignore the network name and purpose when comparing configs", func() {
+		_, err := clusterService.UpdateCluster(ctx, &v1.UpdateClusterRequest{
+			Id:            testClusterId,
+			NetworkConfig: &v1.NetworkConfig{Cidr: "10.0.0.0/24", Gateway: "10.0.0.1", Name: "management", Purpose: "management"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		reconciler.ReconcileOnce(ctx)
+		submitApplyResult("agent-1", &v1.NetworkConfigResult{Success: true, AppliedConfig: desiredConfig})
+		reconciler.ReconcileOnce(ctx)
+
+		Expect(reconciler.DriftCounts()).NotTo(HaveKey(testClusterId))
+		Expect(applyInstructions("agent-1")).To(BeEmpty())
+	})
+
+	It("should flag agents again when the desired config changes", func() {
+		reconciler.ReconcileOnce(ctx)
+		submitApplyResult("agent-1", &v1.NetworkConfigResult{Success: true, AppliedConfig: desiredConfig})
+		reconciler.ReconcileOnce(ctx)
+		Expect(applyInstructions("agent-1")).To(BeEmpty())
+
+		_, err := clusterService.UpdateCluster(ctx, &v1.UpdateClusterRequest{
+			Id:            testClusterId,
+			Name:          "test-cluster",
+			NetworkConfig: &v1.NetworkConfig{Cidr: "10.0.1.0/24", Gateway: "10.0.1.1"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		reconciler.ReconcileOnce(ctx)
+		Expect(applyInstructions("agent-1")).To(HaveLen(1))
+	})
+
+	It("should ignore clusters without a desired config", func() {
+		createResp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "unmanaged"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{
+			Id:        "agent-2",
+			ClusterId: createResp.Cluster.Id,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		reconciler.ReconcileOnce(ctx)
+
+		Expect(reconciler.DriftCounts()).NotTo(HaveKey(createResp.Cluster.Id))
+		Expect(applyInstructions("agent-2")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ResourceTypeCluster identifies clusters in conflict error details
+	ResourceTypeCluster = "cluster"
+
+	// ResourceTypeAgent identifies agents in conflict error details
+	ResourceTypeAgent = "agent"
+)
+
+// conflictError returns an AlreadyExists error carrying a ResourceInfo detail
+// naming the conflicting resource, so clients can act on it without another
+// lookup
+func conflictError(resourceType, resourceID, msg string) error {
+	st := status.New(codes.AlreadyExists, msg)
+	detailed, err := st.WithDetails(&errdetails.ResourceInfo{
+		ResourceType: resourceType,
+		ResourceName: resourceID,
+		Description:  msg,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
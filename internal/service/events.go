@@ -0,0 +1,220 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+const (
+	// DefaultEventBufferSize is the number of lifecycle events queued for
+	// publishing; events emitted while the queue is full are dropped
+	DefaultEventBufferSize = 1024
+
+	// DefaultEventPublishTimeout bounds publishing a single lifecycle event
+	DefaultEventPublishTimeout = 10 * time.Second
+)
+
+// LifecycleEventType identifies what happened to an agent
+type LifecycleEventType string
+
+const (
+	// LifecycleAgentRegistered is emitted when an agent registers or re-registers
+	LifecycleAgentRegistered LifecycleEventType = "agent.registered"
+
+	// LifecycleAgentUnregistered is emitted when an agent is removed, whether
+	// unregistered, decommissioned or reaped as an orphan
+	LifecycleAgentUnregistered LifecycleEventType = "agent.unregistered"
+
+	// LifecycleAgentStatusChanged is emitted when a poll reactivates an agent
+	// or the monitor escalates a silent one
+	LifecycleAgentStatusChanged LifecycleEventType = "agent.status_changed"
+)
+
+// LifecycleEvent describes one agent lifecycle change for downstream systems
+type LifecycleEvent struct {
+	Type           LifecycleEventType
+	AgentID        string
+	ClusterID      string
+	PreviousStatus v1.AgentStatus
+	Status         v1.AgentStatus
+	Time           time.Time
+}
+
+// MarshalJSON encodes the event with statuses by name, as downstream
+// consumers see it
+func (e LifecycleEvent) MarshalJSON() ([]byte, error) {
+	encoded := struct {
+		Type           LifecycleEventType `json:"type"`
+		AgentID        string             `json:"agent_id"`
+		ClusterID      string             `json:"cluster_id,omitempty"`
+		PreviousStatus string             `json:"previous_status,omitempty"`
+		Status         string             `json:"status,omitempty"`
+		Time           time.Time          `json:"time"`
+	}{
+		Type:      e.Type,
+		AgentID:   e.AgentID,
+		ClusterID: e.ClusterID,
+		Time:      e.Time,
+	}
+	if e.PreviousStatus != v1.AgentStatus_AGENT_STATUS_UNSPECIFIED {
+		encoded.PreviousStatus = e.PreviousStatus.String()
+	}
+	if e.Status != v1.AgentStatus_AGENT_STATUS_UNSPECIFIED {
+		encoded.Status = e.Status.String()
+	}
+	return json.Marshal(encoded)
+}
+
+// EventPublisher delivers lifecycle events to a downstream system. Publish
+// is called from a single dispatcher goroutine, never from an RPC.
+type EventPublisher interface {
+	Publish(ctx context.Context, event LifecycleEvent) error
+}
+
+// LogPublisher writes each lifecycle event to the server log as JSON
+type LogPublisher struct{}
+
+// Publish logs the event
+func (LogPublisher) Publish(ctx context.Context, event LifecycleEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	log.Printf("Agent lifecycle event: %s", data)
+	return nil
+}
+
+// WebhookPublisher POSTs each lifecycle event as JSON to a URL
+type WebhookPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookPublisher creates a publisher posting events to url
+func NewWebhookPublisher(url string) *WebhookPublisher {
+	return &WebhookPublisher{url: url, client: &http.Client{}}
+}
+
+// Publish posts the event, failing on any non-2xx response
+func (p *WebhookPublisher) Publish(ctx context.Context, event LifecycleEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// EventDispatcher queues lifecycle events and publishes them from a
+// background goroutine, so a slow or unreachable publisher never delays the
+// RPC or monitor emitting them. A nil dispatcher discards events.
+type EventDispatcher struct {
+	publisher EventPublisher
+	timeout   time.Duration
+
+	// mu guards closing events against concurrent emits
+	mu     sync.RWMutex
+	closed bool
+	events chan LifecycleEvent
+	done   chan struct{}
+
+	dropped atomic.Uint64
+}
+
+// NewEventDispatcher starts a dispatcher publishing through publisher,
+// queueing up to bufferSize events and bounding each publish by timeout.
+// Non-positive values keep the defaults.
+func NewEventDispatcher(publisher EventPublisher, bufferSize int, timeout time.Duration) *EventDispatcher {
+	if bufferSize <= 0 {
+		bufferSize = DefaultEventBufferSize
+	}
+	if timeout <= 0 {
+		timeout = DefaultEventPublishTimeout
+	}
+	d := &EventDispatcher{
+		publisher: publisher,
+		timeout:   timeout,
+		events:    make(chan LifecycleEvent, bufferSize),
+		done:      make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// run publishes queued events until the queue is closed and empty
+func (d *EventDispatcher) run() {
+	defer close(d.done)
+	for event := range d.events {
+		ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+		if err := d.publisher.Publish(ctx, event); err != nil {
+			log.Printf("Warning: failed to publish %s event for agent %s: %v", event.Type, event.AgentID, err)
+		}
+		cancel()
+	}
+}
+
+// emit queues an event without blocking, dropping it if the queue is full
+// or the dispatcher is closed
+func (d *EventDispatcher) emit(event LifecycleEvent) {
+	if d == nil {
+		return
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return
+	}
+	select {
+	case d.events <- event:
+	default:
+		if d.dropped.Add(1) == 1 {
+			log.Printf("Warning: lifecycle event queue full, dropping events")
+		}
+	}
+}
+
+// Dropped returns the number of events dropped because the queue was full
+func (d *EventDispatcher) Dropped() uint64 {
+	return d.dropped.Load()
+}
+
+// Close stops accepting events and waits until the queued ones have been
+// published or ctx ends
+func (d *EventDispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.events)
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,187 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// capturingPublisher records published events, optionally blocking each
+// publish until release is closed
+type capturingPublisher struct {
+	mu      sync.Mutex
+	events  []service.LifecycleEvent
+	release chan struct{}
+}
+
+func (p *capturingPublisher) Publish(ctx context.Context, event service.LifecycleEvent) error {
+	if p.release != nil {
+		<-p.release
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *capturingPublisher) published() []service.LifecycleEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]service.LifecycleEvent(nil), p.events...)
+}
+
+var _ = Describe("Lifecycle events", func() {
+	var (
+		store        *mock.Storage
+		publisher    *capturingPublisher
+		dispatcher   *service.EventDispatcher
+		agentService *service.AgentService
+		monitor      *service.AgentMonitor
+		clock        *service.FakeClock
+		ctx          context.Context
+		clusterID    string
+	)
+
+	BeforeEach(func() {
+		store = mock.New()
+		ctx = context.Background()
+		clock = service.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		publisher = &capturingPublisher{}
+		dispatcher = service.NewEventDispatcher(publisher, 0, 0)
+		agentService = service.NewAgentService(store, service.WithClock(clock), service.WithEventDispatcher(dispatcher))
+		monitor = service.NewAgentMonitor(store,
+			service.WithEscalationThresholds(3*time.Minute, time.Hour, 24*time.Hour),
+			service.WithMonitorClock(clock),
+			service.WithMonitorEventDispatcher(dispatcher),
+		)
+
+		cluster, err := service.NewClusterService(store).CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+		Expect(err).NotTo(HaveOccurred())
+		clusterID = cluster.Cluster.Id
+	})
+
+	It("should publish register, status change and unregister events in order", func() {
+		_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: clusterID})
+		Expect(err).NotTo(HaveOccurred())
+
+		clock.Advance(10 * time.Minute)
+		monitor.CheckAgentStatesOnce(ctx)
+		_, err = agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-1"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = agentService.UnregisterAgent(ctx, &v1.UnregisterAgentRequest{Id: "agent-1"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dispatcher.Close(ctx)).To(Succeed())
+		events := publisher.published()
+		Expect(events).To(HaveLen(4))
+
+		Expect(events[0].Type).To(Equal(service.LifecycleAgentRegistered))
+		Expect(events[0].ClusterID).To(Equal(clusterID))
+		Expect(events[0].Status).To(Equal(v1.AgentStatus_AGENT_STATUS_ACTIVE))
+
+		Expect(events[1].Type).To(Equal(service.LifecycleAgentStatusChanged))
+		Expect(events[1].PreviousStatus).To(Equal(v1.AgentStatus_AGENT_STATUS_ACTIVE))
+		Expect(events[1].Status).To(Equal(v1.AgentStatus_AGENT_STATUS_INACTIVE))
+		Expect(events[1].Time).To(Equal(clock.Now()))
+
+		Expect(events[2].Type).To(Equal(service.LifecycleAgentStatusChanged))
+		Expect(events[2].PreviousStatus).To(Equal(v1.AgentStatus_AGENT_STATUS_INACTIVE))
+		Expect(events[2].Status).To(Equal(v1.AgentStatus_AGENT_STATUS_ACTIVE))
+
+		Expect(events[3].Type).To(Equal(service.LifecycleAgentUnregistered))
+		Expect(events[3].AgentID).To(Equal("agent-1"))
+	})
+
+	It("should not publish a status change for a poll from an active agent", func() {
+		_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: clusterID})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: "agent-1"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(dispatcher.Close(ctx)).To(Succeed())
+		Expect(publisher.published()).To(HaveLen(1))
+	})
+
+	It("should not block the RPC path on a stuck publisher and drop what does not fit", func() {
+		stuck := &capturingPublisher{release: make(chan struct{})}
+		dispatcher = service.NewEventDispatcher(stuck, 1, 0)
+		agentService = service.NewAgentService(store, service.WithEventDispatcher(dispatcher))
+
+		for _, id := range []string{"agent-1", "agent-2", "agent-3", "agent-4"} {
+			_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: id, ClusterId: clusterID})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		// One event is being published, one is queued and the rest are dropped
+		Eventually(dispatcher.Dropped).Should(BeNumerically(">=", 2))
+
+		close(stuck.release)
+		Expect(dispatcher.Close(ctx)).To(Succeed())
+		Expect(len(stuck.published()) + int(dispatcher.Dropped())).To(Equal(4))
+	})
+
+	It("should stop waiting for a stuck publisher when the close context ends", func() {
+		stuck := &capturingPublisher{release: make(chan struct{})}
+		defer close(stuck.release)
+		dispatcher = service.NewEventDispatcher(stuck, 0, 0)
+		agentService = service.NewAgentService(store, service.WithEventDispatcher(dispatcher))
+		_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: clusterID})
+		Expect(err).NotTo(HaveOccurred())
+
+		closeCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		Expect(dispatcher.Close(closeCtx)).To(MatchError(context.DeadlineExceeded))
+	})
+
+	It("should post events to a webhook as JSON with statuses by name", func() {
+		received := make(chan map[string]string, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+			body, err := io.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			var event map[string]string
+			Expect(json.Unmarshal(body, &event)).To(Succeed())
+			received <- event
+		}))
+		defer server.Close()
+
+		err := service.NewWebhookPublisher(server.URL).Publish(ctx, service.LifecycleEvent{
+			Type:           service.LifecycleAgentStatusChanged,
+			AgentID:        "agent-1",
+			ClusterID:      clusterID,
+			PreviousStatus: v1.AgentStatus_AGENT_STATUS_ACTIVE,
+			Status:         v1.AgentStatus_AGENT_STATUS_STALE,
+			Time:           clock.Now(),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(<-received).To(Equal(map[string]string{
+			"type":            "agent.status_changed",
+			"agent_id":        "agent-1",
+			"cluster_id":      clusterID,
+			"previous_status": "AGENT_STATUS_ACTIVE",
+			"status":          "AGENT_STATUS_STALE",
+			"time":            "2026-01-01T00:00:00Z",
+		}))
+	})
+
+	It("should report a webhook rejecting an event", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		err := service.NewWebhookPublisher(server.URL).Publish(ctx, service.LifecycleEvent{Type: service.LifecycleAgentRegistered})
+		Expect(err).To(MatchError(ContainSubstring("503")))
+	})
+})
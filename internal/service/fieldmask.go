@@ -0,0 +1,68 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// maskTree is a field mask parsed into nested field names
+type maskTree map[string]maskTree
+
+// validateFieldMask checks that every path in the mask names a field of msg
+func validateFieldMask(mask *fieldmaskpb.FieldMask, msg proto.Message) error {
+	if mask == nil {
+		return nil
+	}
+	for _, path := range mask.GetPaths() {
+		if !(&fieldmaskpb.FieldMask{Paths: []string{path}}).IsValid(msg) {
+			return fmt.Errorf("invalid field mask path %q", path)
+		}
+	}
+	return nil
+}
+
+// applyFieldMask returns a copy of msg holding only the fields named by the
+// mask. An empty mask returns msg unchanged. The original message is never
+// modified, since storage may hand out shared instances.
+func applyFieldMask[T proto.Message](msg T, mask *fieldmaskpb.FieldMask) T {
+	if len(mask.GetPaths()) == 0 {
+		return msg
+	}
+
+	tree := maskTree{}
+	for _, path := range mask.GetPaths() {
+		node := tree
+		for _, name := range strings.Split(path, ".") {
+			if node[name] == nil {
+				node[name] = maskTree{}
+			}
+			node = node[name]
+		}
+	}
+
+	return filterMessage(msg.ProtoReflect(), tree).Interface().(T)
+}
+
+// filterMessage copies the fields of src selected by tree into a new message
+func filterMessage(src protoreflect.Message, tree maskTree) protoreflect.Message {
+	dst := src.New()
+	fields := src.Descriptor().Fields()
+
+	for name, sub := range tree {
+		fd := fields.ByName(protoreflect.Name(name))
+		if fd == nil || !src.Has(fd) {
+			continue
+		}
+		if len(sub) == 0 || fd.Message() == nil || fd.IsList() || fd.IsMap() {
+			dst.Set(fd, src.Get(fd))
+			continue
+		}
+		dst.Set(fd, protoreflect.ValueOfMessage(filterMessage(src.Get(fd).Message(), sub)))
+	}
+
+	return dst
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/filanov/netctrl-server/internal/storage"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// GetFleetHardwareSummary counts NICs by model and firmware across all clusters
+func (s *AgentService) GetFleetHardwareSummary(ctx context.Context, req *v1.GetFleetHardwareSummaryRequest) (*v1.GetFleetHardwareSummaryResponse, error) {
+	var counts []storage.NICModelCount
+	var err error
+	if s.hardwareSummarizer != nil {
+		counts, err = s.hardwareSummarizer.SummarizeNICs(ctx)
+	} else {
+		counts, err = s.summarizeNICs(ctx)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to summarize hardware: %v", err))
+	}
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		if counts[i].PartNumber != counts[j].PartNumber {
+			return counts[i].PartNumber < counts[j].PartNumber
+		}
+		return counts[i].FirmwareVersion < counts[j].FirmwareVersion
+	})
+
+	resp := &v1.GetFleetHardwareSummaryResponse{
+		Models: make([]*v1.NICModelCount, 0, len(counts)),
+	}
+	for _, count := range counts {
+		resp.Models = append(resp.Models, &v1.NICModelCount{
+			PartNumber:      count.PartNumber,
+			FirmwareVersion: count.FirmwareVersion,
+			Count:           int32(count.Count),
+		})
+		resp.TotalNics += int32(count.Count)
+	}
+	return resp, nil
+}
+
+// summarizeNICs counts NICs from the listed agents, for backends that cannot
+// aggregate themselves
+func (s *AgentService) summarizeNICs(ctx context.Context) ([]storage.NICModelCount, error) {
+	agents, err := s.storage.ListAgents(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	type modelKey struct{ partNumber, firmwareVersion string }
+	byModel := make(map[modelKey]int)
+	for _, agent := range agents {
+		for _, nic := range agent.NetworkInterfaces {
+			byModel[modelKey{nic.PartNumber, nic.FirmwareVersion}]++
+		}
+	}
+
+	counts := make([]storage.NICModelCount, 0, len(byModel))
+	for key, n := range byModel {
+		counts = append(counts, storage.NICModelCount{
+			PartNumber:      key.partNumber,
+			FirmwareVersion: key.firmwareVersion,
+			Count:           n,
+		})
+	}
+	return counts, nil
+}
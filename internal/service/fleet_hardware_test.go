@@ -0,0 +1,99 @@
+package service_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// fixedSummarizer is a backend that aggregates NIC counts itself
+type fixedSummarizer struct {
+	counts []storage.NICModelCount
+}
+
+func (f *fixedSummarizer) SummarizeNICs(ctx context.Context) ([]storage.NICModelCount, error) {
+	return f.counts, nil
+}
+
+var _ = Describe("GetFleetHardwareSummary", func() {
+	var (
+		agentService *service.AgentService
+		store        *mock.Storage
+		ctx          context.Context
+	)
+
+	// seedAgent registers an agent in clusterID reporting the given NICs
+	seedAgent := func(id, clusterID string, nics ...*v1.MellanoxNIC) {
+		_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: id, ClusterId: clusterID})
+		Expect(err).NotTo(HaveOccurred())
+
+		agent, err := store.GetAgent(ctx, id)
+		Expect(err).NotTo(HaveOccurred())
+		agent.NetworkInterfaces = nics
+		agent.HardwareCollected = true
+		Expect(store.UpdateAgent(ctx, agent)).To(Succeed())
+	}
+
+	nic := func(partNumber, firmwareVersion string) *v1.MellanoxNIC {
+		return &v1.MellanoxNIC{PartNumber: partNumber, FirmwareVersion: firmwareVersion}
+	}
+
+	BeforeEach(func() {
+		store = mock.New()
+		ctx = context.Background()
+		agentService = service.NewAgentService(store)
+	})
+
+	It("should return no models for an empty fleet", func() {
+		resp, err := agentService.GetFleetHardwareSummary(ctx, &v1.GetFleetHardwareSummaryRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Models).To(BeEmpty())
+		Expect(resp.TotalNics).To(BeZero())
+	})
+
+	It("should count NICs by model and firmware across all clusters", func() {
+		clusterService := service.NewClusterService(store)
+		var clusterIDs []string
+		for _, name := range []string{"cluster-a", "cluster-b"} {
+			resp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: name})
+			Expect(err).NotTo(HaveOccurred())
+			clusterIDs = append(clusterIDs, resp.Cluster.Id)
+		}
+
+		seedAgent("agent-1", clusterIDs[0], nic("MCX653106A", "20.31.1014"), nic("MCX653106A", "20.31.1014"))
+		seedAgent("agent-2", clusterIDs[0], nic("MCX653106A", "20.35.2000"))
+		seedAgent("agent-3", clusterIDs[1], nic("MCX653106A", "20.31.1014"), nic("MCX75310AAS", "28.39.1002"))
+		seedAgent("agent-4", clusterIDs[1])
+
+		resp, err := agentService.GetFleetHardwareSummary(ctx, &v1.GetFleetHardwareSummaryRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.TotalNics).To(Equal(int32(5)))
+		Expect(resp.Models).To(HaveLen(3))
+
+		// Largest count first, ties ordered by model then firmware
+		Expect(resp.Models[0]).To(Equal(&v1.NICModelCount{PartNumber: "MCX653106A", FirmwareVersion: "20.31.1014", Count: 3}))
+		Expect(resp.Models[1]).To(Equal(&v1.NICModelCount{PartNumber: "MCX653106A", FirmwareVersion: "20.35.2000", Count: 1}))
+		Expect(resp.Models[2]).To(Equal(&v1.NICModelCount{PartNumber: "MCX75310AAS", FirmwareVersion: "28.39.1002", Count: 1}))
+	})
+
+	It("should use the backend's aggregation when available", func() {
+		agentService = service.NewAgentService(store, service.WithHardwareSummarizer(&fixedSummarizer{
+			counts: []storage.NICModelCount{
+				{PartNumber: "MCX75310AAS", FirmwareVersion: "28.39.1002", Count: 2},
+				{PartNumber: "MCX653106A", FirmwareVersion: "20.31.1014", Count: 7},
+			},
+		}))
+
+		resp, err := agentService.GetFleetHardwareSummary(ctx, &v1.GetFleetHardwareSummaryRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.TotalNics).To(Equal(int32(9)))
+		Expect(resp.Models[0].PartNumber).To(Equal("MCX653106A"))
+		Expect(resp.Models[0].Count).To(Equal(int32(7)))
+	})
+})
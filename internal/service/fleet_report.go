@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// FleetReportSampleSize is the default maximum number of agent IDs listed
+// per report category
+const FleetReportSampleSize = 10
+
+// GetFleetReport groups the agents of a cluster, or of all clusters, by the
+// problems an operator should look at. Each category lists a bounded sample
+// of agent IDs; ListFleetReportAgents pages through the rest.
+func (s *AgentService) GetFleetReport(ctx context.Context, req *v1.GetFleetReportRequest) (*v1.GetFleetReportResponse, error) {
+	if req.SampleSize < 0 {
+		return nil, status.Error(codes.InvalidArgument, "sample size must not be negative")
+	}
+	sampleSize := int(req.SampleSize)
+	if sampleSize == 0 || sampleSize > s.fleetReportSampleSize {
+		sampleSize = s.fleetReportSampleSize
+	}
+
+	agents, err := s.fleetReportAgents(ctx, req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &v1.GetFleetReportResponse{
+		ClusterId:           req.ClusterId,
+		TotalAgents:         int32(len(agents)),
+		LatestVersion:       latestVersion(agents),
+		Inactive:            &v1.FleetReportCategory{},
+		ConfigDrifted:       &v1.FleetReportCategory{},
+		OutdatedVersion:     &v1.FleetReportCategory{},
+		MissingHardware:     &v1.FleetReportCategory{},
+		PollIntervalDrifted: &v1.FleetReportCategory{},
+	}
+	categories := map[v1.FleetReportCategoryType]*v1.FleetReportCategory{
+		v1.FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_INACTIVE:              resp.Inactive,
+		v1.FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_CONFIG_DRIFTED:        resp.ConfigDrifted,
+		v1.FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_OUTDATED_VERSION:      resp.OutdatedVersion,
+		v1.FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_MISSING_HARDWARE:      resp.MissingHardware,
+		v1.FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_POLL_INTERVAL_DRIFTED: resp.PollIntervalDrifted,
+	}
+
+	// Samples follow the drill-down order, so they are its first page
+	sortAgentsByKey(agents)
+	for _, agent := range agents {
+		for categoryType, category := range categories {
+			if inFleetReportCategory(agent, categoryType, resp.LatestVersion) {
+				category.Count++
+				if len(category.SampleAgentIds) < sampleSize {
+					category.SampleAgentIds = append(category.SampleAgentIds, agent.Id)
+				}
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// ListFleetReportAgents pages through the agents in one fleet report
+// category, in the order GetFleetReport samples them
+func (s *AgentService) ListFleetReportAgents(ctx context.Context, req *v1.ListFleetReportAgentsRequest) (*v1.ListFleetReportAgentsResponse, error) {
+	if _, ok := v1.FleetReportCategoryType_name[int32(req.Category)]; !ok ||
+		req.Category == v1.FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "a fleet report category is required")
+	}
+
+	agents, err := s.fleetReportAgents(ctx, req.ClusterId)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := latestVersion(agents)
+	inCategory := make([]*v1.Agent, 0)
+	for _, agent := range agents {
+		if inFleetReportCategory(agent, req.Category, latest) {
+			inCategory = append(inCategory, agent)
+		}
+	}
+
+	page, nextPageToken, err := paginateAgents(inCategory, req.PageSize, req.PageToken)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &v1.ListFleetReportAgentsResponse{
+		Agents:        page,
+		NextPageToken: nextPageToken,
+		TotalCount:    int32(len(inCategory)),
+	}, nil
+}
+
+// fleetReportAgents lists the agents a report covers: those of a cluster,
+// which must exist, or of all clusters
+func (s *AgentService) fleetReportAgents(ctx context.Context, clusterID string) ([]*v1.Agent, error) {
+	if clusterID != "" {
+		exists, err := s.storage.ClusterExists(ctx, clusterID)
+		if err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to check cluster: %v", err))
+		}
+		if !exists {
+			return nil, status.Error(codes.NotFound, fmt.Sprintf("cluster not found: %s", clusterID))
+		}
+	}
+
+	agents, err := s.storage.ListAgents(ctx, clusterID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to list agents: %v", err))
+	}
+	return agents, nil
+}
+
+// inFleetReportCategory reports whether an agent belongs in a report
+// category; latest is the newest version among the reported agents
+func inFleetReportCategory(agent *v1.Agent, category v1.FleetReportCategoryType, latest string) bool {
+	switch category {
+	case v1.FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_INACTIVE:
+		return agent.Status != v1.AgentStatus_AGENT_STATUS_ACTIVE
+	case v1.FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_CONFIG_DRIFTED:
+		return agent.ConfigDrifted
+	case v1.FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_OUTDATED_VERSION:
+		return agent.Version != "" && compareVersions(agent.Version, latest) < 0
+	case v1.FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_MISSING_HARDWARE:
+		return !agent.HardwareCollected
+	case v1.FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_POLL_INTERVAL_DRIFTED:
+		return agent.PollIntervalDrifted
+	default:
+		return false
+	}
+}
+
+// latestVersion returns the newest version among the agents
+func latestVersion(agents []*v1.Agent) string {
+	latest := ""
+	for _, agent := range agents {
+		if latest == "" || compareVersions(agent.Version, latest) > 0 {
+			latest = agent.Version
+		}
+	}
+	return latest
+}
+
+// compareVersions orders dotted agent versions ("1.10.2", "v2.0") component
+// by component, numerically where both components are numbers. It returns a
+// negative number, zero or a positive number as a is older than, equal to or
+// newer than b.
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		// Missing components count as zero, so "1.2" equals "1.2.0"
+		ac, bc := "0", "0"
+		if i < len(as) {
+			ac = as[i]
+		}
+		if i < len(bs) {
+			bc = bs[i]
+		}
+
+		an, aErr := strconv.Atoi(ac)
+		bn, bErr := strconv.Atoi(bc)
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return an - bn
+			}
+		case ac != bc:
+			return strings.Compare(ac, bc)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,169 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+var _ = Describe("GetFleetReport", func() {
+	var (
+		agentService *service.AgentService
+		store        *mock.Storage
+		ctx          context.Context
+		clusterId    string
+		otherId      string
+	)
+
+	// seedAgent registers a healthy agent and lets mutate introduce a problem
+	seedAgent := func(id, clusterID, version string, mutate func(*v1.Agent)) {
+		_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: id, ClusterId: clusterID, Version: version})
+		Expect(err).NotTo(HaveOccurred())
+
+		agent, err := store.GetAgent(ctx, id)
+		Expect(err).NotTo(HaveOccurred())
+		agent.HardwareCollected = true
+		if mutate != nil {
+			mutate(agent)
+		}
+		Expect(store.UpdateAgent(ctx, agent)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		store = mock.New()
+		ctx = context.Background()
+		agentService = service.NewAgentService(store)
+		clusterService := service.NewClusterService(store)
+
+		resp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+		Expect(err).NotTo(HaveOccurred())
+		clusterId = resp.Cluster.Id
+		resp, err = clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: "other-cluster"})
+		Expect(err).NotTo(HaveOccurred())
+		otherId = resp.Cluster.Id
+
+		seedAgent("healthy", clusterId, "1.10.0", nil)
+		seedAgent("inactive", clusterId, "1.10.0", func(a *v1.Agent) { a.Status = v1.AgentStatus_AGENT_STATUS_INACTIVE })
+		seedAgent("expired", clusterId, "1.10.0", func(a *v1.Agent) { a.Status = v1.AgentStatus_AGENT_STATUS_EXPIRED })
+		seedAgent("drifted", clusterId, "1.10.0", func(a *v1.Agent) { a.ConfigDrifted = true })
+		seedAgent("outdated", clusterId, "1.9.3", nil)
+		seedAgent("no-hardware", clusterId, "1.10.0", func(a *v1.Agent) { a.HardwareCollected = false })
+		seedAgent("off-cadence", clusterId, "1.10.0", func(a *v1.Agent) { a.PollIntervalDrifted = true })
+		seedAgent("elsewhere", otherId, "2.0.0", func(a *v1.Agent) { a.ConfigDrifted = true })
+	})
+
+	It("should count each problem category within a cluster", func() {
+		resp, err := agentService.GetFleetReport(ctx, &v1.GetFleetReportRequest{ClusterId: clusterId})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(resp.TotalAgents).To(BeEquivalentTo(7))
+		Expect(resp.LatestVersion).To(Equal("1.10.0"))
+		Expect(resp.Inactive.Count).To(BeEquivalentTo(2))
+		Expect(resp.Inactive.SampleAgentIds).To(ConsistOf("inactive", "expired"))
+		Expect(resp.ConfigDrifted.SampleAgentIds).To(ConsistOf("drifted"))
+		Expect(resp.OutdatedVersion.SampleAgentIds).To(ConsistOf("outdated"))
+		Expect(resp.MissingHardware.SampleAgentIds).To(ConsistOf("no-hardware"))
+		Expect(resp.PollIntervalDrifted.SampleAgentIds).To(ConsistOf("off-cadence"))
+	})
+
+	It("should report across all clusters without a cluster ID", func() {
+		resp, err := agentService.GetFleetReport(ctx, &v1.GetFleetReportRequest{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(resp.TotalAgents).To(BeEquivalentTo(8))
+		Expect(resp.LatestVersion).To(Equal("2.0.0"))
+		Expect(resp.ConfigDrifted.SampleAgentIds).To(ConsistOf("drifted", "elsewhere"))
+		Expect(resp.OutdatedVersion.Count).To(BeEquivalentTo(7))
+	})
+
+	It("should bound the sample agent IDs", func() {
+		for i := 0; i < service.FleetReportSampleSize+5; i++ {
+			seedAgent(fmt.Sprintf("bulk-%d", i), otherId, "2.0.0", func(a *v1.Agent) { a.HardwareCollected = false })
+		}
+
+		resp, err := agentService.GetFleetReport(ctx, &v1.GetFleetReportRequest{ClusterId: otherId})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.MissingHardware.Count).To(BeEquivalentTo(service.FleetReportSampleSize + 5))
+		Expect(resp.MissingHardware.SampleAgentIds).To(HaveLen(service.FleetReportSampleSize))
+	})
+
+	It("should return NotFound for an unknown cluster", func() {
+		_, err := agentService.GetFleetReport(ctx, &v1.GetFleetReportRequest{ClusterId: "missing"})
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+
+	Context("with a large category", func() {
+		const bulk = 25
+
+		BeforeEach(func() {
+			for i := 0; i < bulk; i++ {
+				seedAgent(fmt.Sprintf("bulk-%02d", i), otherId, "2.0.0", func(a *v1.Agent) { a.HardwareCollected = false })
+			}
+		})
+
+		It("should respect the requested sample size up to the configured cap", func() {
+			agentService = service.NewAgentService(store, service.WithFleetReportSampleSize(5))
+
+			resp, err := agentService.GetFleetReport(ctx, &v1.GetFleetReportRequest{ClusterId: otherId, SampleSize: 3})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.MissingHardware.Count).To(BeEquivalentTo(bulk))
+			Expect(resp.MissingHardware.SampleAgentIds).To(HaveLen(3))
+
+			resp, err = agentService.GetFleetReport(ctx, &v1.GetFleetReportRequest{ClusterId: otherId, SampleSize: 50})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.MissingHardware.SampleAgentIds).To(HaveLen(5))
+
+			_, err = agentService.GetFleetReport(ctx, &v1.GetFleetReportRequest{SampleSize: -1})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+
+		It("should page through every agent in a category, starting with the sample", func() {
+			report, err := agentService.GetFleetReport(ctx, &v1.GetFleetReportRequest{ClusterId: otherId})
+			Expect(err).NotTo(HaveOccurred())
+
+			var listed []string
+			pageToken := ""
+			for pages := 0; ; pages++ {
+				Expect(pages).To(BeNumerically("<", 10), "pagination did not terminate")
+				resp, err := agentService.ListFleetReportAgents(ctx, &v1.ListFleetReportAgentsRequest{
+					ClusterId: otherId,
+					Category:  v1.FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_MISSING_HARDWARE,
+					PageSize:  10,
+					PageToken: pageToken,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.TotalCount).To(BeEquivalentTo(bulk))
+				Expect(len(resp.Agents)).To(BeNumerically("<=", 10))
+				for _, agent := range resp.Agents {
+					listed = append(listed, agent.Id)
+				}
+				if resp.NextPageToken == "" {
+					break
+				}
+				pageToken = resp.NextPageToken
+			}
+
+			Expect(listed).To(HaveLen(bulk))
+			Expect(listed[:service.FleetReportSampleSize]).To(Equal(report.MissingHardware.SampleAgentIds))
+		})
+
+		It("should reject a missing category and an invalid page token", func() {
+			_, err := agentService.ListFleetReportAgents(ctx, &v1.ListFleetReportAgentsRequest{ClusterId: otherId})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			_, err = agentService.ListFleetReportAgents(ctx, &v1.ListFleetReportAgentsRequest{
+				Category:  v1.FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_INACTIVE,
+				PageToken: "not-a-token",
+			})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+	})
+})
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/filanov/netctrl-server/internal/storage"
+)
+
+const (
+	// GRPCHealthCheckInterval is how often storage reachability is checked
+	GRPCHealthCheckInterval = 10 * time.Second
+
+	// grpcHealthPingTimeout bounds a single storage reachability check
+	grpcHealthPingTimeout = 5 * time.Second
+)
+
+// GRPCHealthReporter serves the standard grpc.health.v1.Health service and
+// keeps its status in sync with storage reachability
+type GRPCHealthReporter struct {
+	storage storage.Storage
+	server  *health.Server
+}
+
+// NewGRPCHealthReporter creates a new gRPC health reporter
+func NewGRPCHealthReporter(store storage.Storage) *GRPCHealthReporter {
+	return &GRPCHealthReporter{
+		storage: store,
+		server:  health.NewServer(),
+	}
+}
+
+// Server returns the grpc.health.v1.Health implementation to register
+func (r *GRPCHealthReporter) Server() healthpb.HealthServer {
+	return r.server
+}
+
+// Start begins the storage reachability loop
+func (r *GRPCHealthReporter) Start(ctx context.Context) {
+	r.CheckOnce(ctx)
+
+	ticker := time.NewTicker(GRPCHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.CheckOnce(ctx)
+		}
+	}
+}
+
+// CheckOnce pings storage and updates the overall serving status
+func (r *GRPCHealthReporter) CheckOnce(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, grpcHealthPingTimeout)
+	defer cancel()
+
+	servingStatus := healthpb.HealthCheckResponse_SERVING
+	if err := r.storage.Ping(pingCtx); err != nil {
+		log.Printf("Storage unreachable, reporting NOT_SERVING: %v", err)
+		servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	// An empty service name reports the overall server health
+	r.server.SetServingStatus("", servingStatus)
+}
+
+// Shutdown reports NOT_SERVING for all services and ignores further updates
+// so load balancers stop routing traffic before the server closes
+func (r *GRPCHealthReporter) Shutdown() {
+	r.server.Shutdown()
+}
@@ -0,0 +1,83 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+)
+
+var _ = Describe("GRPCHealthReporter", func() {
+	var (
+		reporter     *service.GRPCHealthReporter
+		storage      *mock.Storage
+		grpcServer   *grpc.Server
+		conn         *grpc.ClientConn
+		healthClient healthpb.HealthClient
+		ctx          context.Context
+	)
+
+	BeforeEach(func() {
+		storage = mock.New()
+		reporter = service.NewGRPCHealthReporter(storage)
+		ctx = context.Background()
+
+		listener := bufconn.Listen(1024 * 1024)
+		grpcServer = grpc.NewServer()
+		healthpb.RegisterHealthServer(grpcServer, reporter.Server())
+		go func() {
+			_ = grpcServer.Serve(listener)
+		}()
+
+		var err error
+		conn, err = grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		Expect(err).NotTo(HaveOccurred())
+		healthClient = healthpb.NewHealthClient(conn)
+	})
+
+	AfterEach(func() {
+		conn.Close()
+		grpcServer.Stop()
+	})
+
+	It("should report SERVING when storage is reachable", func() {
+		reporter.CheckOnce(ctx)
+
+		resp, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Status).To(Equal(healthpb.HealthCheckResponse_SERVING))
+	})
+
+	It("should report NOT_SERVING when storage is unreachable", func() {
+		storage.SetPingError(errors.New("connection refused"))
+		reporter.CheckOnce(ctx)
+
+		resp, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Status).To(Equal(healthpb.HealthCheckResponse_NOT_SERVING))
+	})
+
+	It("should report NOT_SERVING after shutdown even if storage is reachable", func() {
+		reporter.CheckOnce(ctx)
+		reporter.Shutdown()
+		reporter.CheckOnce(ctx)
+
+		resp, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.Status).To(Equal(healthpb.HealthCheckResponse_NOT_SERVING))
+	})
+})
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"sync/atomic"
 
 	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
 )
@@ -9,6 +10,9 @@ import (
 // HealthService implements the health check service
 type HealthService struct {
 	v1.UnimplementedHealthServiceServer
+
+	// draining is set once shutdown begins
+	draining atomic.Bool
 }
 
 // NewHealthService creates a new health service instance
@@ -26,8 +30,20 @@ func (s *HealthService) Check(ctx context.Context, req *v1.HealthCheckRequest) (
 
 // Ready returns the readiness status of the service
 func (s *HealthService) Ready(ctx context.Context, req *v1.ReadinessCheckRequest) (*v1.ReadinessCheckResponse, error) {
+	if s.draining.Load() {
+		return &v1.ReadinessCheckResponse{
+			Status:  v1.ReadinessStatus_READINESS_STATUS_NOT_READY,
+			Message: "Service is shutting down",
+		}, nil
+	}
+
 	return &v1.ReadinessCheckResponse{
 		Status:  v1.ReadinessStatus_READINESS_STATUS_READY,
 		Message: "Service is ready",
 	}, nil
 }
+
+// Drain reports the service as not ready from now on, ahead of shutdown
+func (s *HealthService) Drain() {
+	s.draining.Store(true)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ID schemes a deployment may choose for new cluster IDs
+const (
+	IDSchemeUUID = "uuid"
+	IDSchemeULID = "ulid"
+)
+
+// IDGenerator produces identifiers for newly created entities
+type IDGenerator interface {
+	NewID() string
+	// Validate rejects a caller-supplied ID that does not follow the scheme
+	Validate(id string) error
+}
+
+// NewIDGenerator returns the generator of an ID scheme; an empty scheme is
+// UUID. Only ULIDs may carry a prefix.
+func NewIDGenerator(scheme, prefix string) (IDGenerator, error) {
+	switch scheme {
+	case "", IDSchemeUUID:
+		if prefix != "" {
+			return nil, fmt.Errorf("an ID prefix requires the %s scheme", IDSchemeULID)
+		}
+		return UUIDGenerator{}, nil
+	case IDSchemeULID:
+		if !idPrefixPattern.MatchString(prefix) {
+			return nil, fmt.Errorf("ID prefix %q may only hold up to 32 letters, digits, '-' and '_'", prefix)
+		}
+		return ULIDGenerator{Prefix: prefix}, nil
+	default:
+		return nil, fmt.Errorf("unknown ID scheme %q (expected %s or %s)", scheme, IDSchemeUUID, IDSchemeULID)
+	}
+}
+
+// idPrefixPattern bounds ULID prefixes to characters safe in URLs and logs
+var idPrefixPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{0,32}$`)
+
+// UUIDGenerator generates random (version 4) UUIDs
+type UUIDGenerator struct{}
+
+// NewID returns a new random UUID
+func (UUIDGenerator) NewID() string {
+	return uuid.New().String()
+}
+
+// Validate rejects IDs that are not UUIDs
+func (UUIDGenerator) Validate(id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return fmt.Errorf("%q is not a UUID", id)
+	}
+	return nil
+}
+
+// ULIDGenerator generates ULIDs behind an optional fixed prefix, e.g.
+// "cl-01JAF8YQ5E7ZK2C0V9N3M4P6RT". ULIDs sort by creation time.
+type ULIDGenerator struct {
+	Prefix string
+}
+
+// crockford is the Crockford base32 alphabet ULIDs are written in
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewID returns the prefix followed by a new ULID: a 48-bit millisecond
+// timestamp and 80 random bits
+func (g ULIDGenerator) NewID() string {
+	var id [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	binary.BigEndian.PutUint16(id[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(ms))
+	if _, err := rand.Read(id[6:]); err != nil {
+		panic(fmt.Sprintf("failed to read random bytes: %v", err))
+	}
+
+	// 128 bits in 26 five-bit characters, the first holding the top 3 bits
+	var out [26]byte
+	hi, lo := binary.BigEndian.Uint64(id[:8]), binary.BigEndian.Uint64(id[8:])
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return g.Prefix + string(out[:])
+}
+
+// Validate rejects IDs that are not the prefix followed by a ULID
+func (g ULIDGenerator) Validate(id string) error {
+	ulid, ok := strings.CutPrefix(id, g.Prefix)
+	if !ok {
+		return fmt.Errorf("%q does not start with %q", id, g.Prefix)
+	}
+	if len(ulid) != 26 || ulid[0] > '7' {
+		return fmt.Errorf("%q is not a prefixed ULID", id)
+	}
+	for i := 0; i < len(ulid); i++ {
+		if !strings.ContainsRune(crockford, rune(ulid[i])) {
+			return fmt.Errorf("%q is not a prefixed ULID", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package service_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/filanov/netctrl-server/internal/service"
+)
+
+var _ = Describe("ID generators", func() {
+	It("should generate ULIDs behind the configured prefix", func() {
+		gen, err := service.NewIDGenerator(service.IDSchemeULID, "cl-")
+		Expect(err).NotTo(HaveOccurred())
+
+		first, second := gen.NewID(), gen.NewID()
+		Expect(first).To(MatchRegexp(`^cl-[0-7][0-9A-HJKMNP-TV-Z]{25}$`))
+		Expect(second).NotTo(Equal(first))
+		Expect(gen.Validate(first)).To(Succeed())
+	})
+
+	It("should sort ULIDs by creation time", func() {
+		gen := service.ULIDGenerator{}
+		earlier := gen.NewID()
+		Eventually(gen.NewID).Should(WithTransform(func(id string) bool {
+			return id[:10] > earlier[:10]
+		}, BeTrue()))
+	})
+
+	DescribeTable("should reject IDs outside the ULID scheme",
+		func(id string) {
+			Expect(service.ULIDGenerator{Prefix: "cl-"}.Validate(id)).NotTo(Succeed())
+		},
+		Entry("missing prefix", "01JAF8YQ5E7ZK2C0V9N3M4P6RT"),
+		Entry("too short", "cl-01JAF8YQ5E"),
+		Entry("excluded letter", "cl-01JAF8YQ5E7ZK2C0V9N3M4P6RU"),
+		Entry("overflowing timestamp", "cl-81JAF8YQ5E7ZK2C0V9N3M4P6RT"),
+		Entry("UUID", "cl-"+strings.Repeat("0", 8)+"-0000-4000-8000-000000000000"),
+	)
+
+	It("should validate UUIDs with the default scheme", func() {
+		gen, err := service.NewIDGenerator("", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gen.Validate(gen.NewID())).To(Succeed())
+		Expect(gen.Validate("cl-1")).NotTo(Succeed())
+	})
+
+	DescribeTable("should reject invalid generator settings",
+		func(scheme, prefix string) {
+			_, err := service.NewIDGenerator(scheme, prefix)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("unknown scheme", "snowflake", ""),
+		Entry("prefixed UUIDs", service.IDSchemeUUID, "cl-"),
+		Entry("prefix with a slash", service.IDSchemeULID, "cl/"),
+	)
+})
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// agentSelector matches agents on the attributes an EnqueueInstructionBySelector
+// selector may name; unset terms match every agent
+type agentSelector struct {
+	role     v1.AgentRole
+	hasRole  bool
+	group    string
+	hasGroup bool
+}
+
+// parseAgentSelector parses comma-separated key=value terms such as
+// "role=spine,group=rack-a". Each key may appear once.
+func parseAgentSelector(selector string) (*agentSelector, error) {
+	parsed := &agentSelector{}
+	if strings.TrimSpace(selector) == "" {
+		return parsed, nil
+	}
+
+	for _, term := range strings.Split(selector, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(term), "=")
+		if !ok {
+			return nil, fmt.Errorf("selector term %q is not key=value", term)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch key {
+		case "role":
+			if parsed.hasRole {
+				return nil, fmt.Errorf("selector names role more than once")
+			}
+			role, ok := v1.AgentRole_value["AGENT_ROLE_"+strings.ToUpper(value)]
+			if !ok || role == int32(v1.AgentRole_AGENT_ROLE_UNSPECIFIED) {
+				return nil, fmt.Errorf("unknown role %q in selector (expected spine or leaf)", value)
+			}
+			parsed.role, parsed.hasRole = v1.AgentRole(role), true
+		case "group":
+			if parsed.hasGroup {
+				return nil, fmt.Errorf("selector names group more than once")
+			}
+			parsed.group, parsed.hasGroup = value, true
+		default:
+			return nil, fmt.Errorf("unknown selector key %q (expected role or group)", key)
+		}
+	}
+	return parsed, nil
+}
+
+// matches reports whether an agent satisfies every term of the selector
+func (sel *agentSelector) matches(agent *v1.Agent) bool {
+	if sel.hasRole && agent.Role != sel.role {
+		return false
+	}
+	if sel.hasGroup && agent.Group != sel.group {
+		return false
+	}
+	return true
+}
+
+// EnqueueInstructionBySelector requests a persistent instruction from every
+// agent of a cluster matching the selector. Requests are recorded on the
+// agents, so each receives the instruction on its next poll, however long it
+// has been offline. Decommissioning agents are skipped.
+func (s *AgentService) EnqueueInstructionBySelector(ctx context.Context, req *v1.EnqueueInstructionBySelectorRequest) (*v1.EnqueueInstructionBySelectorResponse, error) {
+	if req.ClusterId == "" {
+		return nil, status.Error(codes.InvalidArgument, "cluster ID is required")
+	}
+
+	var request func(context.Context, *v1.Agent) error
+	switch req.InstructionType {
+	case v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE:
+		request = s.requestHardwareCollection
+	case v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT:
+		request = s.requestRestart
+	default:
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf(
+			"instruction type %s cannot be enqueued (expected COLLECT_HARDWARE or RESTART_AGENT)", req.InstructionType))
+	}
+
+	selector, err := parseAgentSelector(req.LabelSelector)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	exists, err := s.storage.ClusterExists(ctx, req.ClusterId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to check cluster existence: %v", err))
+	}
+	if !exists {
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("cluster %s not found", req.ClusterId))
+	}
+
+	agents, err := s.storage.ListAgents(ctx, req.ClusterId)
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to list agents: %v", err))
+	}
+
+	targeted := make([]string, 0)
+	for _, agent := range agents {
+		if agent.Decommissioning || !selector.matches(agent) {
+			continue
+		}
+		if err := request(ctx, agent); err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to update agent %s: %v", agent.Id, err))
+		}
+		targeted = append(targeted, agent.Id)
+	}
+
+	log.Printf("Enqueued %s for %d agents of cluster %s matching %q",
+		req.InstructionType, len(targeted), req.ClusterId, req.LabelSelector)
+
+	return &v1.EnqueueInstructionBySelectorResponse{
+		TargetedCount: int32(len(targeted)),
+		AgentIds:      targeted,
+	}, nil
+}
@@ -0,0 +1,127 @@
+package service_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+var _ = Describe("EnqueueInstructionBySelector", func() {
+	var (
+		agentService *service.AgentService
+		store        *mock.Storage
+		ctx          context.Context
+		clusterID    string
+	)
+
+	// pollTypes returns the instruction types an agent receives on a poll
+	pollTypes := func(agentID string) []v1.InstructionType {
+		resp, err := agentService.GetInstructions(ctx, &v1.GetInstructionsRequest{AgentId: agentID})
+		Expect(err).NotTo(HaveOccurred())
+		types := make([]v1.InstructionType, 0, len(resp.Instructions))
+		for _, instruction := range resp.Instructions {
+			types = append(types, instruction.Type)
+		}
+		return types
+	}
+
+	BeforeEach(func() {
+		store = mock.New()
+		ctx = context.Background()
+		agentService = service.NewAgentService(store)
+
+		cluster, err := service.NewClusterService(store).CreateCluster(ctx, &v1.CreateClusterRequest{Name: "fabric"})
+		Expect(err).NotTo(HaveOccurred())
+		clusterID = cluster.Cluster.Id
+
+		for _, req := range []*v1.RegisterAgentRequest{
+			{Id: "spine-a", Role: v1.AgentRole_AGENT_ROLE_SPINE, Group: "rack-a"},
+			{Id: "spine-b", Role: v1.AgentRole_AGENT_ROLE_SPINE, Group: "rack-b"},
+			{Id: "leaf-a", Role: v1.AgentRole_AGENT_ROLE_LEAF, Group: "rack-a"},
+		} {
+			req.ClusterId = clusterID
+			_, err := agentService.RegisterAgent(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("should restart only the agents matching the selector", func() {
+		resp, err := agentService.EnqueueInstructionBySelector(ctx, &v1.EnqueueInstructionBySelectorRequest{
+			ClusterId:       clusterID,
+			LabelSelector:   "role=spine",
+			InstructionType: v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.TargetedCount).To(BeEquivalentTo(2))
+		Expect(resp.AgentIds).To(ConsistOf("spine-a", "spine-b"))
+
+		Expect(pollTypes("spine-a")).To(Equal([]v1.InstructionType{v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT}))
+		Expect(pollTypes("spine-b")).To(Equal([]v1.InstructionType{v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT}))
+		Expect(pollTypes("leaf-a")).NotTo(ContainElement(v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT))
+	})
+
+	It("should require every selector term to match", func() {
+		for _, id := range []string{"spine-a", "spine-b", "leaf-a"} {
+			agent, err := store.GetAgent(ctx, id)
+			Expect(err).NotTo(HaveOccurred())
+			agent.HardwareCollected = true
+			Expect(store.UpdateAgent(ctx, agent)).To(Succeed())
+		}
+
+		resp, err := agentService.EnqueueInstructionBySelector(ctx, &v1.EnqueueInstructionBySelectorRequest{
+			ClusterId:       clusterID,
+			LabelSelector:   "role=spine, group=rack-a",
+			InstructionType: v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.AgentIds).To(Equal([]string{"spine-a"}))
+
+		Expect(pollTypes("spine-a")).To(ContainElement(v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE))
+		Expect(pollTypes("spine-b")).NotTo(ContainElement(v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE))
+		Expect(pollTypes("leaf-a")).NotTo(ContainElement(v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE))
+	})
+
+	It("should skip decommissioning agents", func() {
+		_, err := agentService.DecommissionAgent(ctx, &v1.DecommissionAgentRequest{Id: "spine-b"})
+		Expect(err).NotTo(HaveOccurred())
+
+		resp, err := agentService.EnqueueInstructionBySelector(ctx, &v1.EnqueueInstructionBySelectorRequest{
+			ClusterId:       clusterID,
+			InstructionType: v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.AgentIds).To(ConsistOf("spine-a", "leaf-a"))
+	})
+
+	DescribeTable("should reject invalid requests",
+		func(selector string, instructionType v1.InstructionType, message string) {
+			_, err := agentService.EnqueueInstructionBySelector(ctx, &v1.EnqueueInstructionBySelectorRequest{
+				ClusterId:       clusterID,
+				LabelSelector:   selector,
+				InstructionType: instructionType,
+			})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("instruction without an operator request", "", v1.InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY, "cannot be enqueued"),
+		Entry("unknown key", "rack=a", v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT, "unknown selector key"),
+		Entry("unknown role", "role=core", v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT, "unknown role"),
+		Entry("term without a value", "role", v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT, "not key=value"),
+		Entry("repeated key", "group=a,group=b", v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT, "more than once"),
+	)
+
+	It("should return NotFound for an unknown cluster", func() {
+		_, err := agentService.EnqueueInstructionBySelector(ctx, &v1.EnqueueInstructionBySelectorRequest{
+			ClusterId:       "missing",
+			InstructionType: v1.InstructionType_INSTRUCTION_TYPE_RESTART_AGENT,
+		})
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+	})
+})
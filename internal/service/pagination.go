@@ -0,0 +1,108 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/filanov/netctrl-server/internal/storage"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+const (
+	// DefaultPageSize applies when a page token is given without a page size
+	DefaultPageSize = 100
+
+	// MaxPageSize caps the page size of paginated list requests
+	MaxPageSize = 1000
+)
+
+// agentCursor is the keyset position of the last agent returned in a page
+type agentCursor struct {
+	CreatedAt int64  `json:"created_at"`
+	ID        string `json:"id"`
+}
+
+// encodeAgentCursor returns the page token continuing after agent
+func encodeAgentCursor(agent *v1.Agent) string {
+	data, _ := json.Marshal(agentCursor{CreatedAt: agent.CreatedAt.AsTime().UnixNano(), ID: agent.Id})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeAgentCursor parses a page token produced by encodeAgentCursor
+func decodeAgentCursor(token string) (*agentCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid page token")
+	}
+
+	var cursor agentCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, fmt.Errorf("invalid page token")
+	}
+	return &cursor, nil
+}
+
+// sortAgentsByKey orders agents by (created_at, id), the pagination order
+func sortAgentsByKey(agents []*v1.Agent) {
+	sort.Slice(agents, func(i, j int) bool {
+		return storage.AgentKeyLess(agents[i].CreatedAt.AsTime(), agents[i].Id, agents[j])
+	})
+}
+
+// pageSize validates a requested page size, applying the default and cap
+func pageSize(requested int32) (int, error) {
+	if requested < 0 {
+		return 0, fmt.Errorf("page size must not be negative")
+	}
+	if requested == 0 {
+		return DefaultPageSize, nil
+	}
+	return int(min(requested, MaxPageSize)), nil
+}
+
+// agentPageQuery positions query after the agent a page token names
+func agentPageQuery(query *storage.AgentQuery, pageToken string) error {
+	if pageToken == "" {
+		return nil
+	}
+	cursor, err := decodeAgentCursor(pageToken)
+	if err != nil {
+		return err
+	}
+	query.AfterCreatedAt = time.Unix(0, cursor.CreatedAt).UTC()
+	query.AfterID = cursor.ID
+	return nil
+}
+
+// paginateAgents returns the page of already-loaded agents following the
+// token, ordered by (created_at, id). Positions are keys rather than offsets,
+// so agents created or deleted between requests never shift later pages.
+// Lists that can be filtered by the backend page through ListAgentsPage instead.
+func paginateAgents(agents []*v1.Agent, requestedSize int32, pageToken string) ([]*v1.Agent, string, error) {
+	size, err := pageSize(requestedSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sortAgentsByKey(agents)
+
+	var after storage.AgentQuery
+	if err := agentPageQuery(&after, pageToken); err != nil {
+		return nil, "", err
+	}
+	start := 0
+	if after.AfterID != "" {
+		start = sort.Search(len(agents), func(i int) bool {
+			return storage.AgentKeyLess(after.AfterCreatedAt, after.AfterID, agents[i])
+		})
+	}
+
+	end := start + size
+	if end >= len(agents) {
+		return agents[start:], "", nil
+	}
+	return agents[start:end], encodeAgentCursor(agents[end-1]), nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// PollStatsWindow is the number of recent poll intervals kept per agent
+const PollStatsWindow = 20
+
+// pollStats tracks the intervals between consecutive polls of each agent in
+// a bounded window, so memory stays proportional to the number of agents
+type pollStats struct {
+	mu     sync.Mutex
+	agents map[string]*agentPolls
+}
+
+// agentPolls holds the recent poll history of one agent
+type agentPolls struct {
+	clusterID string
+	lastPoll  time.Time
+	intervals []time.Duration
+}
+
+// pollSummary aggregates the poll intervals of a set of agents
+type pollSummary struct {
+	agents  int
+	samples int
+	min     time.Duration
+	max     time.Duration
+	total   time.Duration
+}
+
+func newPollStats() *pollStats {
+	return &pollStats{agents: make(map[string]*agentPolls)}
+}
+
+// record notes a poll by an agent at the given time
+func (p *pollStats) record(agentID, clusterID string, at time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	polls, ok := p.agents[agentID]
+	if !ok {
+		p.agents[agentID] = &agentPolls{clusterID: clusterID, lastPoll: at}
+		return
+	}
+
+	// Intervals observed in another cluster say nothing about this one
+	if polls.clusterID != clusterID {
+		*polls = agentPolls{clusterID: clusterID, lastPoll: at}
+		return
+	}
+
+	if at.After(polls.lastPoll) {
+		polls.intervals = append(polls.intervals, at.Sub(polls.lastPoll))
+		if len(polls.intervals) > PollStatsWindow {
+			polls.intervals = polls.intervals[len(polls.intervals)-PollStatsWindow:]
+		}
+	}
+	polls.lastPoll = at
+}
+
+// forget drops the poll history of an agent
+func (p *pollStats) forget(agentID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.agents, agentID)
+}
+
+// summarize aggregates the poll intervals of a cluster's agents
+func (p *pollStats) summarize(clusterID string) pollSummary {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var summary pollSummary
+	for _, polls := range p.agents {
+		if polls.clusterID != clusterID || len(polls.intervals) == 0 {
+			continue
+		}
+
+		summary.agents++
+		for _, interval := range polls.intervals {
+			if summary.samples == 0 || interval < summary.min {
+				summary.min = interval
+			}
+			if interval > summary.max {
+				summary.max = interval
+			}
+			summary.total += interval
+			summary.samples++
+		}
+	}
+	return summary
+}
+
+// avg returns the mean interval, or zero without samples
+func (s pollSummary) avg() time.Duration {
+	if s.samples == 0 {
+		return 0
+	}
+	return s.total / time.Duration(s.samples)
+}
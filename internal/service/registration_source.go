@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// gatewayUserAgentKey carries the HTTP client's user agent through the
+// gateway, whose own gRPC user agent would otherwise hide it
+const gatewayUserAgentKey = "grpcgateway-user-agent"
+
+// registrationSource describes where the registration in ctx came from
+func registrationSource(ctx context.Context, at *timestamppb.Timestamp) *v1.RegistrationSource {
+	source := &v1.RegistrationSource{RegisteredAt: at}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		source.PeerAddress = p.Addr.String()
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		source.ForwardedFor = strings.Join(md.Get("x-forwarded-for"), ", ")
+		if userAgent := md.Get(gatewayUserAgentKey); len(userAgent) > 0 {
+			source.UserAgent = userAgent[0]
+		} else if userAgent := md.Get("user-agent"); len(userAgent) > 0 {
+			source.UserAgent = userAgent[0]
+		}
+	}
+
+	return source
+}
+
+// sourceHost returns the host a registration came from, preferring the
+// client the gateway forwarded for over the gateway's own address
+func sourceHost(source *v1.RegistrationSource) string {
+	if forwarded := source.GetForwardedFor(); forwarded != "" {
+		return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	host, _, err := net.SplitHostPort(source.GetPeerAddress())
+	if err != nil {
+		return source.GetPeerAddress()
+	}
+	return host
+}
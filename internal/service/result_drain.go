@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+// resultTracker counts in-flight result submissions so shutdown can wait
+// for their writes instead of closing storage underneath them
+type resultTracker struct {
+	mu       sync.Mutex
+	draining bool
+	inflight sync.WaitGroup
+}
+
+// begin registers a submission; it returns false once draining has started
+func (t *resultTracker) begin() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.draining {
+		return false
+	}
+	t.inflight.Add(1)
+	return true
+}
+
+// end marks a submission registered by begin as finished
+func (t *resultTracker) end() {
+	t.inflight.Done()
+}
+
+// drain stops accepting submissions and waits for in-flight ones to finish
+// or ctx to end
+func (t *resultTracker) drain(ctx context.Context) error {
+	t.mu.Lock()
+	t.draining = true
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// DrainResults stops accepting instruction results, failing new submissions
+// with Unavailable so agents retry elsewhere, and waits until submissions in
+// progress have been written or ctx ends
+func (s *AgentService) DrainResults(ctx context.Context) error {
+	return s.results.drain(ctx)
+}
@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// blockingUpdateStorage holds agent updates until released
+type blockingUpdateStorage struct {
+	*mock.Storage
+
+	entered     chan struct{}
+	enteredOnce sync.Once
+	release     chan struct{}
+}
+
+func (s *blockingUpdateStorage) UpdateAgent(ctx context.Context, agent *v1.Agent) error {
+	s.enteredOnce.Do(func() { close(s.entered) })
+	<-s.release
+	return s.Storage.UpdateAgent(ctx, agent)
+}
+
+var _ = Describe("DrainResults", func() {
+	var (
+		agentService *service.AgentService
+		store        *blockingUpdateStorage
+		ctx          context.Context
+	)
+
+	healthResult := func(instructionID string) *v1.SubmitInstructionResultRequest {
+		return &v1.SubmitInstructionResultRequest{
+			AgentId:       "agent-1",
+			InstructionId: instructionID,
+			Result: &v1.InstructionResult{
+				InstructionType: v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK,
+				Result: &v1.InstructionResult_HealthCheck{
+					HealthCheck: &v1.HealthCheckResult{Healthy: true},
+				},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = &blockingUpdateStorage{
+			Storage: mock.New(),
+			entered: make(chan struct{}),
+			release: make(chan struct{}),
+		}
+		agentService = service.NewAgentService(store)
+
+		cluster, err := service.NewClusterService(store).CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: cluster.Cluster.Id})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should return immediately when no results are in progress", func() {
+		close(store.release)
+		Expect(agentService.DrainResults(ctx)).To(Succeed())
+	})
+
+	It("should wait for a submission in progress and reject new ones", func() {
+		submitted := make(chan error, 1)
+		go func() {
+			_, err := agentService.SubmitInstructionResult(ctx, healthResult("instruction-1"))
+			submitted <- err
+		}()
+		Eventually(store.entered).Should(BeClosed())
+
+		drained := make(chan error, 1)
+		go func() {
+			drained <- agentService.DrainResults(ctx)
+		}()
+		Consistently(drained, 100*time.Millisecond).ShouldNot(Receive())
+
+		_, err := agentService.SubmitInstructionResult(ctx, healthResult("instruction-2"))
+		Expect(status.Code(err)).To(Equal(codes.Unavailable))
+
+		close(store.release)
+		Eventually(submitted).Should(Receive(BeNil()))
+		Eventually(drained).Should(Receive(BeNil()))
+
+		agent, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agent.LastHealthyAt).NotTo(BeNil())
+	})
+
+	It("should give up when the context ends first", func() {
+		go func() {
+			_, _ = agentService.SubmitInstructionResult(ctx, healthResult("instruction-1"))
+		}()
+		Eventually(store.entered).Should(BeClosed())
+		DeferCleanup(func() { close(store.release) })
+
+		drainCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		Expect(agentService.DrainResults(drainCtx)).To(MatchError(context.DeadlineExceeded))
+	})
+})
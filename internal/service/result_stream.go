@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// MaxStreamedResultBytes bounds the reassembled size of a streamed instruction result
+const MaxStreamedResultBytes = 8 << 20
+
+// SubmitInstructionResultStream reassembles an instruction result sent in
+// chunks and processes it like SubmitInstructionResult
+func (s *AgentService) SubmitInstructionResultStream(stream grpc.ClientStreamingServer[v1.InstructionResultChunk, v1.SubmitInstructionResultResponse]) error {
+	var agentID, instructionID string
+	var data []byte
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if agentID == "" && instructionID == "" {
+			agentID, instructionID = chunk.AgentId, chunk.InstructionId
+		} else if (chunk.AgentId != "" && chunk.AgentId != agentID) || (chunk.InstructionId != "" && chunk.InstructionId != instructionID) {
+			return status.Error(codes.InvalidArgument, "chunks must all belong to the same agent and instruction")
+		}
+
+		if len(data)+len(chunk.Data) > MaxStreamedResultBytes {
+			return status.Error(codes.InvalidArgument, fmt.Sprintf("streamed result exceeds the limit of %d bytes", MaxStreamedResultBytes))
+		}
+		data = append(data, chunk.Data...)
+	}
+
+	if len(data) == 0 {
+		return status.Error(codes.InvalidArgument, "result is required")
+	}
+	result := &v1.InstructionResult{}
+	if err := proto.Unmarshal(data, result); err != nil {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("malformed streamed result: %v", err))
+	}
+
+	resp, err := s.SubmitInstructionResult(stream.Context(), &v1.SubmitInstructionResultRequest{
+		AgentId:       agentID,
+		InstructionId: instructionID,
+		Result:        result,
+	})
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(resp)
+}
@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// resultStream is a client stream that replays queued chunks and captures the response
+type resultStream struct {
+	grpc.ServerStream
+	chunks []*v1.InstructionResultChunk
+	resp   *v1.SubmitInstructionResultResponse
+}
+
+func (s *resultStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *resultStream) Recv() (*v1.InstructionResultChunk, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *resultStream) SendAndClose(resp *v1.SubmitInstructionResultResponse) error {
+	s.resp = resp
+	return nil
+}
+
+var _ = Describe("SubmitInstructionResultStream", func() {
+	var (
+		agentService *service.AgentService
+		store        *mock.Storage
+		ctx          context.Context
+	)
+
+	// chunked splits a serialized result into chunks of at most size bytes,
+	// identifying the result on the first chunk only
+	chunked := func(result *v1.InstructionResult, size int) []*v1.InstructionResultChunk {
+		data, err := proto.Marshal(result)
+		Expect(err).NotTo(HaveOccurred())
+
+		var chunks []*v1.InstructionResultChunk
+		for start := 0; start < len(data); start += size {
+			chunks = append(chunks, &v1.InstructionResultChunk{Data: data[start:min(start+size, len(data))]})
+		}
+		chunks[0].AgentId = "agent-1"
+		chunks[0].InstructionId = "instruction-1"
+		return chunks
+	}
+
+	BeforeEach(func() {
+		store = mock.New()
+		ctx = context.Background()
+		agentService = service.NewAgentService(store)
+
+		cluster, err := service.NewClusterService(store).CreateCluster(ctx, &v1.CreateClusterRequest{Name: "test-cluster"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: "agent-1", ClusterId: cluster.Cluster.Id})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reassemble and process a multi-chunk hardware result", func() {
+		var nics []*v1.MellanoxNIC
+		for i := 0; i < 20; i++ {
+			nics = append(nics, &v1.MellanoxNIC{DeviceName: fmt.Sprintf("mlx5_%d", i)})
+		}
+		stream := &resultStream{chunks: chunked(&v1.InstructionResult{
+			InstructionType: v1.InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE,
+			Result: &v1.InstructionResult_HardwareCollection{
+				HardwareCollection: &v1.HardwareCollectionResult{NetworkInterfaces: nics},
+			},
+		}, 64)}
+		Expect(len(stream.chunks)).To(BeNumerically(">", 1))
+
+		Expect(agentService.SubmitInstructionResultStream(stream)).To(Succeed())
+		Expect(stream.resp.Success).To(BeTrue())
+
+		agent, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agent.HardwareCollected).To(BeTrue())
+		Expect(agent.NetworkInterfaces).To(HaveLen(20))
+		Expect(agent.NetworkInterfaces[19].DeviceName).To(Equal("mlx5_19"))
+	})
+
+	It("should reject chunks of different instructions", func() {
+		chunks := chunked(&v1.InstructionResult{
+			InstructionType: v1.InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK,
+			Result:          &v1.InstructionResult_HealthCheck{HealthCheck: &v1.HealthCheckResult{Healthy: true}},
+		}, 2)
+		chunks[1].InstructionId = "instruction-2"
+
+		err := agentService.SubmitInstructionResultStream(&resultStream{chunks: chunks})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+
+	It("should reject an empty stream", func() {
+		err := agentService.SubmitInstructionResultStream(&resultStream{})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+
+	It("should reject a result that does not decode", func() {
+		err := agentService.SubmitInstructionResultStream(&resultStream{chunks: []*v1.InstructionResultChunk{
+			{AgentId: "agent-1", InstructionId: "instruction-1", Data: []byte{0xff, 0xff, 0xff}},
+		}})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+})
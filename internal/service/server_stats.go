@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// GetServerStats returns a snapshot of the server's runtime, the clusters and
+// agents it manages and, when the backend has one, its connection pool
+func (s *AgentService) GetServerStats(ctx context.Context, req *v1.GetServerStatsRequest) (*v1.GetServerStatsResponse, error) {
+	clusters, err := s.storage.ListClusters(ctx, "")
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to list clusters: %v", err))
+	}
+	agents, err := s.storage.ListAgents(ctx, "")
+	if err != nil {
+		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to list agents: %v", err))
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	resp := &v1.GetServerStatsResponse{
+		Goroutines: int32(runtime.NumGoroutine()),
+		Memory: &v1.MemoryStats{
+			HeapAllocBytes: mem.HeapAlloc,
+			HeapInuseBytes: mem.HeapInuse,
+			SysBytes:       mem.Sys,
+			NumGc:          mem.NumGC,
+		},
+		UptimeSeconds: int64(s.clock.Now().Sub(s.startedAt).Seconds()),
+		TotalClusters: int32(len(clusters)),
+		TotalAgents:   int32(len(agents)),
+	}
+
+	for _, agent := range agents {
+		switch agent.Status {
+		case v1.AgentStatus_AGENT_STATUS_ACTIVE:
+			resp.ActiveAgents++
+		case v1.AgentStatus_AGENT_STATUS_INACTIVE:
+			resp.InactiveAgents++
+		case v1.AgentStatus_AGENT_STATUS_STALE:
+			resp.StaleAgents++
+		case v1.AgentStatus_AGENT_STATUS_EXPIRED:
+			resp.ExpiredAgents++
+		}
+	}
+
+	if s.poolStats != nil {
+		pool := s.poolStats.PoolStats()
+		resp.DatabasePool = &v1.DatabasePoolStats{
+			TotalConns:    pool.TotalConns,
+			IdleConns:     pool.IdleConns,
+			AcquiredConns: pool.AcquiredConns,
+			MaxConns:      pool.MaxConns,
+			AcquireCount:  pool.AcquireCount,
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,94 @@
+package service_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// fakePool reports fixed connection pool statistics
+type fakePool struct {
+	stats storage.PoolStats
+}
+
+func (p fakePool) PoolStats() storage.PoolStats {
+	return p.stats
+}
+
+var _ = Describe("GetServerStats", func() {
+	var (
+		store *mock.Storage
+		ctx   context.Context
+		clock *service.FakeClock
+	)
+
+	// seedAgent registers an agent and forces its status
+	seedAgent := func(agentService *service.AgentService, id, clusterID string, agentStatus v1.AgentStatus) {
+		_, err := agentService.RegisterAgent(ctx, &v1.RegisterAgentRequest{Id: id, ClusterId: clusterID})
+		Expect(err).NotTo(HaveOccurred())
+
+		agent, err := store.GetAgent(ctx, id)
+		Expect(err).NotTo(HaveOccurred())
+		agent.Status = agentStatus
+		Expect(store.UpdateAgent(ctx, agent)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		store = mock.New()
+		ctx = context.Background()
+		clock = service.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	})
+
+	It("should report counts matching the seeded store", func() {
+		agentService := service.NewAgentService(store, service.WithClock(clock))
+		clusterService := service.NewClusterService(store)
+
+		var clusterIDs []string
+		for _, name := range []string{"cluster-a", "cluster-b"} {
+			resp, err := clusterService.CreateCluster(ctx, &v1.CreateClusterRequest{Name: name})
+			Expect(err).NotTo(HaveOccurred())
+			clusterIDs = append(clusterIDs, resp.Cluster.Id)
+		}
+		seedAgent(agentService, "agent-1", clusterIDs[0], v1.AgentStatus_AGENT_STATUS_ACTIVE)
+		seedAgent(agentService, "agent-2", clusterIDs[0], v1.AgentStatus_AGENT_STATUS_ACTIVE)
+		seedAgent(agentService, "agent-3", clusterIDs[1], v1.AgentStatus_AGENT_STATUS_INACTIVE)
+		seedAgent(agentService, "agent-4", clusterIDs[1], v1.AgentStatus_AGENT_STATUS_STALE)
+		seedAgent(agentService, "agent-5", clusterIDs[1], v1.AgentStatus_AGENT_STATUS_EXPIRED)
+
+		clock.Advance(90 * time.Second)
+		resp, err := agentService.GetServerStats(ctx, &v1.GetServerStatsRequest{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(resp.TotalClusters).To(BeEquivalentTo(2))
+		Expect(resp.TotalAgents).To(BeEquivalentTo(5))
+		Expect(resp.ActiveAgents).To(BeEquivalentTo(2))
+		Expect(resp.InactiveAgents).To(BeEquivalentTo(1))
+		Expect(resp.StaleAgents).To(BeEquivalentTo(1))
+		Expect(resp.ExpiredAgents).To(BeEquivalentTo(1))
+		Expect(resp.UptimeSeconds).To(BeEquivalentTo(90))
+		Expect(resp.Goroutines).To(BeNumerically(">", 0))
+		Expect(resp.Memory.SysBytes).To(BeNumerically(">", 0))
+		Expect(resp.DatabasePool).To(BeNil())
+	})
+
+	It("should include the connection pool when one is configured", func() {
+		pool := fakePool{stats: storage.PoolStats{TotalConns: 4, IdleConns: 3, AcquiredConns: 1, MaxConns: 10, AcquireCount: 42}}
+		agentService := service.NewAgentService(store, service.WithPoolStats(pool))
+
+		resp, err := agentService.GetServerStats(ctx, &v1.GetServerStatsRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.TotalAgents).To(BeZero())
+		Expect(resp.DatabasePool).NotTo(BeNil())
+		Expect(resp.DatabasePool.TotalConns).To(BeEquivalentTo(4))
+		Expect(resp.DatabasePool.AcquiredConns).To(BeEquivalentTo(1))
+		Expect(resp.DatabasePool.MaxConns).To(BeEquivalentTo(10))
+		Expect(resp.DatabasePool.AcquireCount).To(BeEquivalentTo(42))
+	})
+})
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/filanov/netctrl-server/internal/storage"
+)
+
+// storageWriteError converts a failed storage write into a gRPC status,
+// reporting payloads rejected by the backend's size limits as InvalidArgument
+// and writes that kept conflicting with concurrent ones as Aborted
+func storageWriteError(msg string, err error) error {
+	if errors.Is(err, storage.ErrPayloadTooLarge) {
+		return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
+	}
+	if errors.Is(err, storage.ErrConflict) {
+		return status.Errorf(codes.Aborted, "%s: %v", msg, err)
+	}
+	return status.Errorf(codes.Internal, "%s: %v", msg, err)
+}
@@ -5,9 +5,24 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
 )
 
 func TestServiceSuite(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Service Suite")
 }
+
+// conflictingResource decodes the ResourceInfo detail of an AlreadyExists error
+func conflictingResource(err error) *errdetails.ResourceInfo {
+	st, ok := status.FromError(err)
+	Expect(ok).To(BeTrue())
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ResourceInfo); ok {
+			return info
+		}
+	}
+	Fail("error carries no ResourceInfo detail: " + err.Error())
+	return nil
+}
@@ -0,0 +1,14 @@
+package service
+
+import "log"
+
+// enforce applies a check that validation strictness relaxes. In strict mode
+// the check's error rejects the request; otherwise it is logged as a warning
+// about subject and the request is accepted.
+func enforce(strict bool, subject string, err error) error {
+	if err == nil || strict {
+		return err
+	}
+	log.Printf("Warning: accepting %s despite failed validation (strict validation is off): %v", subject, err)
+	return nil
+}
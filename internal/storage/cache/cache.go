@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/filanov/netctrl-server/internal/storage"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// MaxEntries bounds the number of cached cluster existence results
+const MaxEntries = 10000
+
+// Clock tells the current time; entries expire by it
+type Clock interface {
+	Now() time.Time
+}
+
+// realClock reads the system clock
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// entry is a cached ClusterExists result
+type entry struct {
+	exists  bool
+	expires time.Time
+}
+
+// Storage caches ClusterExists results of a backend store for a TTL. Creating,
+// deleting or moving a cluster through this store invalidates the affected
+// entries; changes made by other replicas are picked up once entries expire.
+type Storage struct {
+	storage.Storage
+
+	ttl     time.Duration
+	clock   Clock
+	mu      sync.Mutex
+	entries map[string]entry
+
+	// generation counts invalidations, so a lookup racing with a cluster
+	// change does not cache the result it read before the change
+	generation uint64
+}
+
+// Option configures optional Storage behavior
+type Option func(*Storage)
+
+// WithClock replaces the system clock used to expire entries
+func WithClock(clock Clock) Option {
+	return func(s *Storage) {
+		s.clock = clock
+	}
+}
+
+// New wraps backend with a ClusterExists cache holding results for ttl
+func New(backend storage.Storage, ttl time.Duration, opts ...Option) *Storage {
+	s := &Storage{
+		Storage: backend,
+		ttl:     ttl,
+		clock:   realClock{},
+		entries: make(map[string]entry),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ClusterExists returns a cached result when one is fresh, otherwise it
+// queries the backend and caches the answer
+func (s *Storage) ClusterExists(ctx context.Context, id string) (bool, error) {
+	now := s.clock.Now()
+
+	s.mu.Lock()
+	cached, ok := s.entries[id]
+	generation := s.generation
+	s.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.exists, nil
+	}
+
+	exists, err := s.Storage.ClusterExists(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if generation != s.generation {
+		return exists, nil
+	}
+	if len(s.entries) >= MaxEntries {
+		s.evictExpired(now)
+	}
+	if len(s.entries) < MaxEntries {
+		s.entries[id] = entry{exists: exists, expires: now.Add(s.ttl)}
+	}
+	return exists, nil
+}
+
+// CreateCluster creates the cluster and invalidates its cached result
+func (s *Storage) CreateCluster(ctx context.Context, cluster *v1.Cluster) error {
+	defer s.invalidate(cluster.Id)
+	return s.Storage.CreateCluster(ctx, cluster)
+}
+
+// DeleteCluster deletes the cluster and invalidates its cached result
+func (s *Storage) DeleteCluster(ctx context.Context, id string) error {
+	defer s.invalidate(id)
+	return s.Storage.DeleteCluster(ctx, id)
+}
+
+// MoveCluster re-keys the cluster and invalidates both IDs' cached results
+func (s *Storage) MoveCluster(ctx context.Context, oldID, newID string) error {
+	defer s.invalidate(oldID, newID)
+	return s.Storage.MoveCluster(ctx, oldID, newID)
+}
+
+// invalidate drops the cached results of the given cluster IDs
+func (s *Storage) invalidate(ids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.generation++
+	for _, id := range ids {
+		delete(s.entries, id)
+	}
+}
+
+// evictExpired drops expired entries; callers must hold mu
+func (s *Storage) evictExpired(now time.Time) {
+	for id, cached := range s.entries {
+		if !now.Before(cached.expires) {
+			delete(s.entries, id)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package cache_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/filanov/netctrl-server/internal/service"
+	"github.com/filanov/netctrl-server/internal/storage/cache"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// countingStorage counts ClusterExists calls reaching the backend
+type countingStorage struct {
+	*mock.Storage
+	existsCalls int
+}
+
+func (c *countingStorage) ClusterExists(ctx context.Context, id string) (bool, error) {
+	c.existsCalls++
+	return c.Storage.ClusterExists(ctx, id)
+}
+
+var _ = Describe("Cache storage", func() {
+	var (
+		backend *countingStorage
+		clock   *service.FakeClock
+		store   *cache.Storage
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		backend = &countingStorage{Storage: mock.New()}
+		clock = service.NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		store = cache.New(backend, time.Minute, cache.WithClock(clock))
+		ctx = context.Background()
+		Expect(store.CreateCluster(ctx, &v1.Cluster{Id: "cluster-1", Name: "test"})).To(Succeed())
+	})
+
+	It("should answer repeated lookups from the cache", func() {
+		for i := 0; i < 3; i++ {
+			exists, err := store.ClusterExists(ctx, "cluster-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exists).To(BeTrue())
+		}
+		Expect(backend.existsCalls).To(Equal(1))
+	})
+
+	It("should invalidate the entry when the cluster is deleted", func() {
+		exists, err := store.ClusterExists(ctx, "cluster-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeTrue())
+
+		Expect(store.DeleteCluster(ctx, "cluster-1")).To(Succeed())
+
+		exists, err = store.ClusterExists(ctx, "cluster-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeFalse())
+		Expect(backend.existsCalls).To(Equal(2))
+	})
+
+	It("should invalidate a cached miss when the cluster is created", func() {
+		exists, err := store.ClusterExists(ctx, "cluster-2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeFalse())
+
+		Expect(store.CreateCluster(ctx, &v1.Cluster{Id: "cluster-2", Name: "second"})).To(Succeed())
+
+		exists, err = store.ClusterExists(ctx, "cluster-2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exists).To(BeTrue())
+	})
+
+	It("should invalidate both IDs when a cluster moves", func() {
+		_, err := store.ClusterExists(ctx, "cluster-1")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = store.ClusterExists(ctx, "cluster-3")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(store.MoveCluster(ctx, "cluster-1", "cluster-3")).To(Succeed())
+
+		Expect(store.ClusterExists(ctx, "cluster-1")).To(BeFalse())
+		Expect(store.ClusterExists(ctx, "cluster-3")).To(BeTrue())
+	})
+
+	It("should query the backend again once an entry expires", func() {
+		_, err := store.ClusterExists(ctx, "cluster-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		clock.Advance(time.Minute - time.Second)
+		_, err = store.ClusterExists(ctx, "cluster-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backend.existsCalls).To(Equal(1))
+
+		clock.Advance(time.Second)
+		_, err = store.ClusterExists(ctx, "cluster-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backend.existsCalls).To(Equal(2))
+	})
+})
@@ -0,0 +1,496 @@
+package hybrid
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/filanov/netctrl-server/internal/storage"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// DefaultSyncInterval is how often buffered writes are replayed to the backend
+const DefaultSyncInterval = 30 * time.Second
+
+// write is a mutation that can be replayed against the backend
+type write struct {
+	desc  string
+	apply func(ctx context.Context, store storage.Storage) error
+	// reload re-reads the rows the write touches from the backend into
+	// memory, undoing it there if the backend rejects it
+	reload func(ctx context.Context) error
+}
+
+// Storage serves reads from memory and writes through to a backend store.
+// Writes made while the backend is unreachable are buffered and replayed in
+// order once it recovers. Memory holds copies, so a change to a returned
+// agent or cluster reaches neither store until it is written back.
+type Storage struct {
+	memory  atomic.Pointer[mock.Storage]
+	backend storage.Storage
+
+	// syncMu orders writes so memory and the backend apply them in the same
+	// order; reads and Pending never wait on it
+	syncMu sync.Mutex
+	// loaded reports whether memory holds the backend's image; guarded by syncMu
+	loaded bool
+	// rejected holds reloads of buffered writes the backend rejected, run
+	// once the queue drains; guarded by syncMu
+	rejected []func(ctx context.Context) error
+
+	// mu guards pending and is never held during backend I/O
+	mu      sync.Mutex
+	pending []write
+}
+
+// New creates a hybrid store, loading the current backend state into memory.
+// If the backend is unreachable the store starts with an empty memory image
+// and loads it on the first flush after the backend recovers, once the writes
+// made meanwhile are replayed.
+func New(ctx context.Context, backend storage.Storage) *Storage {
+	s := &Storage{backend: backend}
+	s.memory.Store(mock.New())
+	if err := s.load(ctx); err != nil {
+		log.Printf("Hybrid storage starting with an empty memory image: %v", err)
+	}
+	return s
+}
+
+// load replaces memory with the backend's current state
+func (s *Storage) load(ctx context.Context) error {
+	memory := mock.New()
+
+	clusters, err := s.backend.ListClusters(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to load clusters: %w", err)
+	}
+	for _, cluster := range clusters {
+		if err := memory.CreateCluster(ctx, cluster); err != nil {
+			return fmt.Errorf("failed to load cluster %s: %w", cluster.Id, err)
+		}
+	}
+
+	agents, err := s.backend.ListAgents(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to load agents: %w", err)
+	}
+	for _, agent := range agents {
+		if err := memory.CreateAgent(ctx, agent); err != nil {
+			return fmt.Errorf("failed to load agent %s: %w", agent.Id, err)
+		}
+	}
+
+	s.memory.Store(memory)
+	s.loaded = true
+	s.rejected = nil
+	return nil
+}
+
+// Start periodically replays buffered writes until the context is cancelled
+func (s *Storage) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Flush(ctx); err != nil {
+				log.Printf("Hybrid storage sync pending: %v", err)
+			}
+		}
+	}
+}
+
+// Pending returns the number of writes waiting to be replayed
+func (s *Storage) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+// Flush replays buffered writes in order, stopping at the first write that
+// fails because the backend is still unreachable. Writes rejected by a
+// reachable backend are dropped so they cannot block the queue; once the
+// queue drains, the rows they touched are re-read from the backend so memory
+// does not keep the rejected change.
+func (s *Storage) Flush(ctx context.Context) error {
+	for {
+		drained, err := s.replayNext(ctx)
+		if err != nil || drained {
+			return err
+		}
+	}
+}
+
+// replayNext replays the oldest buffered write, or reconciles memory with the
+// backend if none is left. Writes may be queued between replays.
+func (s *Storage) replayNext(ctx context.Context) (bool, error) {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	s.mu.Lock()
+	if len(s.pending) == 0 {
+		s.mu.Unlock()
+		return true, s.reconcile(ctx)
+	}
+	w := s.pending[0]
+	s.mu.Unlock()
+
+	if err := w.apply(ctx, s.backend); err != nil {
+		if !s.backendAvailable(ctx) {
+			return false, fmt.Errorf("backend unavailable, %d writes buffered: %w", s.Pending(), err)
+		}
+		log.Printf("Dropping buffered write %s rejected by backend: %v", w.desc, err)
+		s.rejected = append(s.rejected, w.reload)
+	}
+
+	s.mu.Lock()
+	s.pending = s.pending[1:]
+	s.mu.Unlock()
+	return false, nil
+}
+
+// reconcile brings memory in line with the backend after the queue drained:
+// it loads the memory image if the store started without one, and otherwise
+// re-reads the rows of rejected writes. Later writes to those rows may have
+// been replayed since, so they are only re-read once nothing is buffered.
+func (s *Storage) reconcile(ctx context.Context) error {
+	if !s.loaded {
+		return s.load(ctx)
+	}
+	for len(s.rejected) > 0 {
+		if err := s.rejected[0](ctx); err != nil {
+			return fmt.Errorf("failed to re-read rows of a rejected write: %w", err)
+		}
+		s.rejected = s.rejected[1:]
+	}
+	return nil
+}
+
+// mutate applies a write to the backend and then memory. When the backend is
+// unreachable, or earlier writes are still buffered, the write is applied to
+// memory only and queued for replay. When a reachable backend rejects the
+// write, the rows it touches are re-read before the error is returned.
+func (s *Storage) mutate(ctx context.Context, w write, applyMemory func(memory *mock.Storage) error) error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	if s.Pending() == 0 {
+		err := w.apply(ctx, s.backend)
+		if err == nil {
+			if err := applyMemory(s.memory.Load()); err != nil {
+				// Memory was behind the backend, e.g. while the store
+				// has not loaded its image yet
+				return w.reload(ctx)
+			}
+			return nil
+		}
+		if s.backendAvailable(ctx) {
+			// The backend rejected the write, e.g. a conflict or a missing
+			// row, so memory may be behind it; re-read what the write
+			// touches so the caller's retry sees the backend's rows
+			if reloadErr := w.reload(ctx); reloadErr != nil {
+				log.Printf("Failed to re-read rows of rejected write %s: %v", w.desc, reloadErr)
+			}
+			return err
+		}
+		log.Printf("Backend unavailable, buffering write %s: %v", w.desc, err)
+	}
+
+	if err := applyMemory(s.memory.Load()); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.pending = append(s.pending, w)
+	s.mu.Unlock()
+	return nil
+}
+
+// backendAvailable reports whether the backend answers a ping
+func (s *Storage) backendAvailable(ctx context.Context) bool {
+	return s.backend.Ping(ctx) == nil
+}
+
+// reloadCluster re-reads a cluster and its agents from the backend into
+// memory, dropping them from memory if the backend has no such cluster
+func (s *Storage) reloadCluster(ctx context.Context, id string) error {
+	memory := s.memory.Load()
+
+	cluster, err := s.backend.GetCluster(ctx, id)
+	if err != nil {
+		if !s.backendAvailable(ctx) {
+			return err
+		}
+		// The backend deletes a cluster's agents along with it
+		_ = memory.DeleteCluster(ctx, id)
+		return nil
+	}
+	agents, err := s.backend.ListAgents(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	held, err := memory.ListAgents(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, agent := range held {
+		_ = memory.DeleteAgent(ctx, agent.Id)
+	}
+	// Creating replaces what memory holds under the same ID
+	if err := memory.CreateCluster(ctx, cluster); err != nil {
+		return err
+	}
+	for _, agent := range agents {
+		if err := memory.CreateAgent(ctx, agent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reloadAgent re-reads an agent from the backend into memory, dropping it
+// from memory if the backend has no such agent
+func (s *Storage) reloadAgent(ctx context.Context, id string) error {
+	memory := s.memory.Load()
+
+	agent, err := s.backend.GetAgent(ctx, id)
+	if err != nil {
+		if !s.backendAvailable(ctx) {
+			return err
+		}
+		_ = memory.DeleteAgent(ctx, id)
+		return nil
+	}
+	// Creating replaces what memory holds under the same ID
+	return memory.CreateAgent(ctx, agent)
+}
+
+// Ping always succeeds since reads and writes are served from memory
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.memory.Load().Ping(ctx)
+}
+
+// Cluster operations
+
+func (s *Storage) CreateCluster(ctx context.Context, cluster *v1.Cluster) error {
+	snapshot := proto.Clone(cluster).(*v1.Cluster)
+	return s.mutate(ctx, write{
+		desc: "create cluster " + cluster.Id,
+		apply: func(ctx context.Context, store storage.Storage) error {
+			return store.CreateCluster(ctx, snapshot)
+		},
+		reload: func(ctx context.Context) error {
+			return s.reloadCluster(ctx, cluster.Id)
+		},
+	}, func(memory *mock.Storage) error {
+		return memory.CreateCluster(ctx, cluster)
+	})
+}
+
+func (s *Storage) GetCluster(ctx context.Context, id string) (*v1.Cluster, error) {
+	return s.memory.Load().GetCluster(ctx, id)
+}
+
+func (s *Storage) ListClusters(ctx context.Context, namePrefix string) ([]*v1.Cluster, error) {
+	return s.memory.Load().ListClusters(ctx, namePrefix)
+}
+
+func (s *Storage) UpdateCluster(ctx context.Context, cluster *v1.Cluster) error {
+	snapshot := proto.Clone(cluster).(*v1.Cluster)
+	return s.mutate(ctx, write{
+		desc: "update cluster " + cluster.Id,
+		apply: func(ctx context.Context, store storage.Storage) error {
+			return store.UpdateCluster(ctx, snapshot)
+		},
+		reload: func(ctx context.Context) error {
+			return s.reloadCluster(ctx, cluster.Id)
+		},
+	}, func(memory *mock.Storage) error {
+		return memory.UpdateCluster(ctx, cluster)
+	})
+}
+
+func (s *Storage) DeleteCluster(ctx context.Context, id string) error {
+	return s.mutate(ctx, write{
+		desc: "delete cluster " + id,
+		apply: func(ctx context.Context, store storage.Storage) error {
+			return store.DeleteCluster(ctx, id)
+		},
+		reload: func(ctx context.Context) error {
+			return s.reloadCluster(ctx, id)
+		},
+	}, func(memory *mock.Storage) error {
+		return memory.DeleteCluster(ctx, id)
+	})
+}
+
+func (s *Storage) ClusterExists(ctx context.Context, id string) (bool, error) {
+	return s.memory.Load().ClusterExists(ctx, id)
+}
+
+func (s *Storage) MoveCluster(ctx context.Context, oldID, newID string) error {
+	return s.mutate(ctx, write{
+		desc: "move cluster " + oldID + " to " + newID,
+		apply: func(ctx context.Context, store storage.Storage) error {
+			return store.MoveCluster(ctx, oldID, newID)
+		},
+		reload: func(ctx context.Context) error {
+			if err := s.reloadCluster(ctx, oldID); err != nil {
+				return err
+			}
+			return s.reloadCluster(ctx, newID)
+		},
+	}, func(memory *mock.Storage) error {
+		return memory.MoveCluster(ctx, oldID, newID)
+	})
+}
+
+// Agent operations
+
+func (s *Storage) CreateAgent(ctx context.Context, agent *v1.Agent) error {
+	snapshot := proto.Clone(agent).(*v1.Agent)
+	return s.mutate(ctx, write{
+		desc: "create agent " + agent.Id,
+		apply: func(ctx context.Context, store storage.Storage) error {
+			return store.CreateAgent(ctx, snapshot)
+		},
+		reload: func(ctx context.Context) error {
+			return s.reloadAgent(ctx, agent.Id)
+		},
+	}, func(memory *mock.Storage) error {
+		return memory.CreateAgent(ctx, agent)
+	})
+}
+
+func (s *Storage) GetAgent(ctx context.Context, id string) (*v1.Agent, error) {
+	return s.memory.Load().GetAgent(ctx, id)
+}
+
+func (s *Storage) ListAgents(ctx context.Context, clusterID string) ([]*v1.Agent, error) {
+	return s.memory.Load().ListAgents(ctx, clusterID)
+}
+
+func (s *Storage) ListAgentsByIP(ctx context.Context, clusterID, ipAddress string) ([]*v1.Agent, error) {
+	return s.memory.Load().ListAgentsByIP(ctx, clusterID, ipAddress)
+}
+
+func (s *Storage) ListAgentsPage(ctx context.Context, query storage.AgentQuery) ([]*v1.Agent, error) {
+	return s.memory.Load().ListAgentsPage(ctx, query)
+}
+
+func (s *Storage) ListOrphanedAgents(ctx context.Context) ([]*v1.Agent, error) {
+	return s.memory.Load().ListOrphanedAgents(ctx)
+}
+
+func (s *Storage) CountAgents(ctx context.Context) (int, error) {
+	return s.memory.Load().CountAgents(ctx)
+}
+
+func (s *Storage) GetAgentStatuses(ctx context.Context, ids []string) (map[string]v1.AgentStatus, error) {
+	return s.memory.Load().GetAgentStatuses(ctx, ids)
+}
+
+func (s *Storage) UpdateAgent(ctx context.Context, agent *v1.Agent) error {
+	snapshot := proto.Clone(agent).(*v1.Agent)
+	return s.mutate(ctx, write{
+		desc: "update agent " + agent.Id,
+		apply: func(ctx context.Context, store storage.Storage) error {
+			return store.UpdateAgent(ctx, snapshot)
+		},
+		reload: func(ctx context.Context) error {
+			return s.reloadAgent(ctx, agent.Id)
+		},
+	}, func(memory *mock.Storage) error {
+		return memory.UpdateAgent(ctx, agent)
+	})
+}
+
+func (s *Storage) DeleteAgent(ctx context.Context, id string) error {
+	return s.mutate(ctx, write{
+		desc: "delete agent " + id,
+		apply: func(ctx context.Context, store storage.Storage) error {
+			return store.DeleteAgent(ctx, id)
+		},
+		reload: func(ctx context.Context) error {
+			return s.reloadAgent(ctx, id)
+		},
+	}, func(memory *mock.Storage) error {
+		return memory.DeleteAgent(ctx, id)
+	})
+}
+
+// Backend capabilities
+
+// TryLock takes the backend's lock so replicas sharing the database still
+// elect one holder. A backend without locks serves a single process, which
+// always holds the lock.
+func (s *Storage) TryLock(ctx context.Context, key int64) (storage.Lock, error) {
+	if locker, ok := s.backend.(storage.Locker); ok {
+		return locker.TryLock(ctx, key)
+	}
+	return heldLock{}, nil
+}
+
+// heldLock is the lock of a backend that serves a single process
+type heldLock struct{}
+
+func (heldLock) Check(ctx context.Context) error  { return nil }
+func (heldLock) Unlock(ctx context.Context) error { return nil }
+
+// PoolStats returns the backend's connection pool stats, or zero stats for a
+// backend without a pool
+func (s *Storage) PoolStats() storage.PoolStats {
+	if stater, ok := s.backend.(storage.PoolStater); ok {
+		return stater.PoolStats()
+	}
+	return storage.PoolStats{}
+}
+
+// SummarizeNICs aggregates in the backend while it holds every write. With
+// writes buffered, the backend unreachable or unable to aggregate, it counts
+// the NICs held in memory instead.
+func (s *Storage) SummarizeNICs(ctx context.Context) ([]storage.NICModelCount, error) {
+	if summarizer, ok := s.backend.(storage.HardwareSummarizer); ok && s.Pending() == 0 {
+		if counts, err := summarizer.SummarizeNICs(ctx); err == nil {
+			return counts, nil
+		}
+	}
+
+	agents, err := s.memory.Load().ListAgents(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	type modelKey struct{ partNumber, firmwareVersion string }
+	byModel := make(map[modelKey]int)
+	for _, agent := range agents {
+		for _, nic := range agent.NetworkInterfaces {
+			byModel[modelKey{nic.PartNumber, nic.FirmwareVersion}]++
+		}
+	}
+
+	counts := make([]storage.NICModelCount, 0, len(byModel))
+	for key, n := range byModel {
+		counts = append(counts, storage.NICModelCount{
+			PartNumber:      key.partNumber,
+			FirmwareVersion: key.firmwareVersion,
+			Count:           n,
+		})
+	}
+	return counts, nil
+}
+
+// Ensure Storage implements storage.Storage interface and forwards the
+// optional backend capabilities
+var _ storage.Storage = (*Storage)(nil)
+var _ storage.Locker = (*Storage)(nil)
+var _ storage.PoolStater = (*Storage)(nil)
+var _ storage.HardwareSummarizer = (*Storage)(nil)
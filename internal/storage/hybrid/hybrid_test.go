@@ -0,0 +1,376 @@
+package hybrid_test
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/filanov/netctrl-server/internal/storage"
+	"github.com/filanov/netctrl-server/internal/storage/hybrid"
+	"github.com/filanov/netctrl-server/internal/storage/mock"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+var (
+	errUnavailable = errors.New("connection refused")
+	errRejected    = errors.New("constraint violation")
+)
+
+// flakyStorage simulates a backend that can go down, reject agent updates
+// or stall on them
+type flakyStorage struct {
+	*mock.Storage
+	down bool
+	// reject, when set, is returned by agent updates
+	reject error
+	// stall, when set, holds agent updates until it is closed; stalled is
+	// signalled once an update is held
+	stall   chan struct{}
+	stalled chan struct{}
+}
+
+func (f *flakyStorage) Ping(ctx context.Context) error {
+	if f.down {
+		return errUnavailable
+	}
+	return nil
+}
+
+func (f *flakyStorage) CreateCluster(ctx context.Context, cluster *v1.Cluster) error {
+	if f.down {
+		return errUnavailable
+	}
+	return f.Storage.CreateCluster(ctx, cluster)
+}
+
+func (f *flakyStorage) ListClusters(ctx context.Context, namePrefix string) ([]*v1.Cluster, error) {
+	if f.down {
+		return nil, errUnavailable
+	}
+	return f.Storage.ListClusters(ctx, namePrefix)
+}
+
+func (f *flakyStorage) CreateAgent(ctx context.Context, agent *v1.Agent) error {
+	if f.down {
+		return errUnavailable
+	}
+	return f.Storage.CreateAgent(ctx, agent)
+}
+
+func (f *flakyStorage) UpdateAgent(ctx context.Context, agent *v1.Agent) error {
+	if f.stall != nil {
+		f.stalled <- struct{}{}
+		<-f.stall
+	}
+	if f.down {
+		return errUnavailable
+	}
+	if f.reject != nil {
+		return f.reject
+	}
+	return f.Storage.UpdateAgent(ctx, agent)
+}
+
+// capableStorage is a backend that can lock, report its pool and aggregate
+// NICs itself
+type capableStorage struct {
+	*flakyStorage
+	lock storage.Lock
+}
+
+func (c *capableStorage) TryLock(ctx context.Context, key int64) (storage.Lock, error) {
+	return c.lock, nil
+}
+
+func (c *capableStorage) PoolStats() storage.PoolStats {
+	return storage.PoolStats{MaxConns: 10}
+}
+
+func (c *capableStorage) SummarizeNICs(ctx context.Context) ([]storage.NICModelCount, error) {
+	if c.down {
+		return nil, errUnavailable
+	}
+	return []storage.NICModelCount{{PartNumber: "from-backend", Count: 1}}, nil
+}
+
+// fakeLock is a lock handed out by capableStorage
+type fakeLock struct{}
+
+func (fakeLock) Check(ctx context.Context) error  { return nil }
+func (fakeLock) Unlock(ctx context.Context) error { return nil }
+
+var _ = Describe("Hybrid Storage", func() {
+	var (
+		backend *flakyStorage
+		store   *hybrid.Storage
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		backend = &flakyStorage{Storage: mock.New()}
+		Expect(backend.CreateCluster(ctx, &v1.Cluster{Id: "cluster-1", Name: "existing"})).To(Succeed())
+
+		store = hybrid.New(ctx, backend)
+	})
+
+	It("should load existing backend data into memory", func() {
+		cluster, err := store.GetCluster(ctx, "cluster-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cluster.Name).To(Equal("existing"))
+	})
+
+	It("should write through when the backend is available", func() {
+		Expect(store.CreateAgent(ctx, &v1.Agent{Id: "agent-1", ClusterId: "cluster-1"})).To(Succeed())
+		Expect(store.Pending()).To(Equal(0))
+
+		_, err := backend.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should buffer writes while the backend is down and flush them on recovery", func() {
+		backend.down = true
+
+		Expect(store.CreateCluster(ctx, &v1.Cluster{Id: "cluster-2", Name: "offline"})).To(Succeed())
+		Expect(store.CreateAgent(ctx, &v1.Agent{Id: "agent-1", ClusterId: "cluster-2"})).To(Succeed())
+		Expect(store.Pending()).To(Equal(2))
+
+		// Reads are served from memory
+		agent, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agent.ClusterId).To(Equal("cluster-2"))
+
+		// Flushing while still down keeps the writes buffered
+		Expect(store.Flush(ctx)).NotTo(Succeed())
+		Expect(store.Pending()).To(Equal(2))
+		_, err = backend.Storage.GetCluster(ctx, "cluster-2")
+		Expect(err).To(HaveOccurred())
+
+		backend.down = false
+		Expect(store.Flush(ctx)).To(Succeed())
+		Expect(store.Pending()).To(Equal(0))
+
+		cluster, err := backend.GetCluster(ctx, "cluster-2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cluster.Name).To(Equal("offline"))
+		agent, err = backend.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agent.ClusterId).To(Equal("cluster-2"))
+	})
+
+	It("should keep write order once writes are buffered", func() {
+		Expect(store.CreateAgent(ctx, &v1.Agent{Id: "agent-1", ClusterId: "cluster-1", Hostname: "v1"})).To(Succeed())
+
+		agent, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		backend.down = true
+		agent.Hostname = "v2"
+		Expect(store.UpdateAgent(ctx, agent)).To(Succeed())
+		backend.down = false
+
+		// The backend recovered but the buffered update must land first
+		agent.Hostname = "v3"
+		Expect(store.UpdateAgent(ctx, agent)).To(Succeed())
+		Expect(store.Pending()).To(Equal(2))
+
+		Expect(store.Flush(ctx)).To(Succeed())
+		agent, err = backend.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agent.Hostname).To(Equal("v3"))
+	})
+
+	It("should return backend errors when the backend is reachable", func() {
+		err := store.UpdateAgent(ctx, &v1.Agent{Id: "missing"})
+		Expect(err).To(HaveOccurred())
+		Expect(store.Pending()).To(Equal(0))
+	})
+
+	It("should not keep changes to a returned agent that were not written", func() {
+		Expect(store.CreateAgent(ctx, &v1.Agent{Id: "agent-1", ClusterId: "cluster-1", Hostname: "v1"})).To(Succeed())
+
+		agent, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		agent.Hostname = "v2"
+
+		agent, err = store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agent.Hostname).To(Equal("v1"))
+	})
+
+	It("should leave memory unchanged when the backend rejects a write", func() {
+		Expect(store.CreateAgent(ctx, &v1.Agent{Id: "agent-1", ClusterId: "cluster-1", Hostname: "v1"})).To(Succeed())
+		agent, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		backend.reject = errRejected
+		agent.Hostname = "v2"
+		Expect(store.UpdateAgent(ctx, agent)).To(MatchError(errRejected))
+
+		agent, err = store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agent.Hostname).To(Equal("v1"))
+	})
+
+	It("should re-read rows changed behind its back when the backend rejects a write", func() {
+		Expect(store.CreateAgent(ctx, &v1.Agent{Id: "agent-1", ClusterId: "cluster-1", Hostname: "v1"})).To(Succeed())
+		stale, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		// Another server updates the agent in the shared backend
+		other, err := backend.Storage.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		other.Hostname = "other"
+		Expect(backend.Storage.UpdateAgent(ctx, other)).To(Succeed())
+
+		stale.Hostname = "v2"
+		Expect(store.UpdateAgent(ctx, stale)).To(MatchError(storage.ErrConflict))
+
+		// Memory caught up with the backend, so a retry on a fresh read lands
+		fresh, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fresh.Hostname).To(Equal("other"))
+		fresh.Hostname = "v2"
+		Expect(store.UpdateAgent(ctx, fresh)).To(Succeed())
+
+		agent, err := backend.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agent.Hostname).To(Equal("v2"))
+	})
+
+	It("should drop rows deleted behind its back when the backend rejects a write", func() {
+		Expect(store.CreateAgent(ctx, &v1.Agent{Id: "agent-1", ClusterId: "cluster-1"})).To(Succeed())
+		agent, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(backend.Storage.DeleteAgent(ctx, "agent-1")).To(Succeed())
+		Expect(store.UpdateAgent(ctx, agent)).To(HaveOccurred())
+
+		_, err = store.GetAgent(ctx, "agent-1")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should re-read rows whose buffered writes the backend rejects", func() {
+		Expect(store.CreateAgent(ctx, &v1.Agent{Id: "agent-1", ClusterId: "cluster-1", Hostname: "v1"})).To(Succeed())
+		agent, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		backend.down = true
+		agent.Hostname = "v2"
+		Expect(store.UpdateAgent(ctx, agent)).To(Succeed())
+		Expect(store.Pending()).To(Equal(1))
+
+		backend.down = false
+		backend.reject = errRejected
+		Expect(store.Flush(ctx)).To(Succeed())
+		Expect(store.Pending()).To(Equal(0))
+
+		agent, err = store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agent.Hostname).To(Equal("v1"))
+		Expect(agent.Revision).To(BeZero())
+	})
+
+	It("should start without the backend and load its state once it recovers", func() {
+		backend.down = true
+		store = hybrid.New(ctx, backend)
+
+		_, err := store.GetCluster(ctx, "cluster-1")
+		Expect(err).To(HaveOccurred())
+		Expect(store.CreateCluster(ctx, &v1.Cluster{Id: "cluster-2", Name: "offline"})).To(Succeed())
+
+		// Still down: nothing replays and the image stays unloaded
+		Expect(store.Flush(ctx)).NotTo(Succeed())
+		_, err = store.GetCluster(ctx, "cluster-1")
+		Expect(err).To(HaveOccurred())
+
+		backend.down = false
+		Expect(store.Flush(ctx)).To(Succeed())
+		Expect(store.Pending()).To(Equal(0))
+
+		clusters, err := store.ListClusters(ctx, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clusters).To(ConsistOf(HaveField("Id", "cluster-1"), HaveField("Id", "cluster-2")))
+		_, err = backend.GetCluster(ctx, "cluster-2")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should serve reads and the queue length while a backend write is in flight", func() {
+		Expect(store.CreateAgent(ctx, &v1.Agent{Id: "agent-1", ClusterId: "cluster-1", Hostname: "v1"})).To(Succeed())
+		agent, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		backend.stall = make(chan struct{})
+		backend.stalled = make(chan struct{}, 1)
+		done := make(chan error, 1)
+		agent.Hostname = "v2"
+		go func() {
+			done <- store.UpdateAgent(ctx, agent)
+		}()
+		Eventually(backend.stalled).Should(Receive())
+
+		Expect(store.Pending()).To(Equal(0))
+		read, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(read.Hostname).To(Equal("v1"))
+
+		close(backend.stall)
+		Eventually(done).Should(Receive(BeNil()))
+		read, err = store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(read.Hostname).To(Equal("v2"))
+	})
+
+	Context("backend capabilities", func() {
+		nics := []*v1.MellanoxNIC{{PartNumber: "MCX623106AN", FirmwareVersion: "22.36"}}
+
+		It("should forward them to a backend that has them", func() {
+			capable := &capableStorage{flakyStorage: backend, lock: fakeLock{}}
+			store = hybrid.New(ctx, capable)
+
+			lock, err := store.TryLock(ctx, 42)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lock).To(Equal(fakeLock{}))
+			Expect(store.PoolStats().MaxConns).To(Equal(int32(10)))
+			counts, err := store.SummarizeNICs(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(ConsistOf(HaveField("PartNumber", "from-backend")))
+		})
+
+		It("should not elect a leader when the backend lock is taken", func() {
+			store = hybrid.New(ctx, &capableStorage{flakyStorage: backend})
+
+			lock, err := store.TryLock(ctx, 42)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lock).To(BeNil())
+		})
+
+		It("should count NICs in memory while writes are buffered", func() {
+			capable := &capableStorage{flakyStorage: backend, lock: fakeLock{}}
+			store = hybrid.New(ctx, capable)
+
+			backend.down = true
+			Expect(store.CreateAgent(ctx, &v1.Agent{Id: "agent-1", ClusterId: "cluster-1", NetworkInterfaces: nics})).To(Succeed())
+			Expect(store.Pending()).To(Equal(1))
+
+			counts, err := store.SummarizeNICs(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(ConsistOf(storage.NICModelCount{PartNumber: "MCX623106AN", FirmwareVersion: "22.36", Count: 1}))
+		})
+
+		It("should serve a single process when the backend has none of them", func() {
+			Expect(store.CreateAgent(ctx, &v1.Agent{Id: "agent-1", ClusterId: "cluster-1", NetworkInterfaces: nics})).To(Succeed())
+
+			lock, err := store.TryLock(ctx, 42)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lock).NotTo(BeNil())
+			Expect(lock.Check(ctx)).To(Succeed())
+			Expect(store.PoolStats()).To(BeZero())
+			counts, err := store.SummarizeNICs(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(ConsistOf(storage.NICModelCount{PartNumber: "MCX623106AN", FirmwareVersion: "22.36", Count: 1}))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package hybrid_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestHybridSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Hybrid Storage Suite")
+}
@@ -2,24 +2,128 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
 )
 
+// ErrPayloadTooLarge is returned when a field exceeds the size the backend accepts
+var ErrPayloadTooLarge = errors.New("payload too large")
+
+// ErrConflict is returned when an update is based on a stale read: the stored
+// revision changed since the record was read
+var ErrConflict = errors.New("concurrent update conflict")
+
 // Storage defines the interface for cluster and agent data persistence
 type Storage interface {
+	// Ping checks that the storage backend is reachable
+	Ping(ctx context.Context) error
+
 	// Cluster operations
 	CreateCluster(ctx context.Context, cluster *v1.Cluster) error
 	GetCluster(ctx context.Context, id string) (*v1.Cluster, error)
-	ListClusters(ctx context.Context) ([]*v1.Cluster, error)
+	// ListClusters lists clusters whose name starts with namePrefix; empty lists all
+	ListClusters(ctx context.Context, namePrefix string) ([]*v1.Cluster, error)
 	UpdateCluster(ctx context.Context, cluster *v1.Cluster) error
 	DeleteCluster(ctx context.Context, id string) error
 	ClusterExists(ctx context.Context, id string) (bool, error)
+	// MoveCluster atomically changes a cluster's ID and its agents' cluster references
+	MoveCluster(ctx context.Context, oldID, newID string) error
 
 	// Agent operations
 	CreateAgent(ctx context.Context, agent *v1.Agent) error
 	GetAgent(ctx context.Context, id string) (*v1.Agent, error)
 	ListAgents(ctx context.Context, clusterID string) ([]*v1.Agent, error)
+	ListAgentsByIP(ctx context.Context, clusterID, ipAddress string) ([]*v1.Agent, error)
+	// ListAgentsPage lists the agents matching query in (created_at, id)
+	// order, a keyset page at a time
+	ListAgentsPage(ctx context.Context, query AgentQuery) ([]*v1.Agent, error)
+	ListOrphanedAgents(ctx context.Context) ([]*v1.Agent, error)
+	// CountAgents returns the number of agents across all clusters
+	CountAgents(ctx context.Context) (int, error)
+	// GetAgentStatuses returns the status of each listed agent that exists
+	GetAgentStatuses(ctx context.Context, ids []string) (map[string]v1.AgentStatus, error)
+	// UpdateAgent writes an agent read at agent.Revision and advances the
+	// revision; it returns ErrConflict if the agent was written since
 	UpdateAgent(ctx context.Context, agent *v1.Agent) error
 	DeleteAgent(ctx context.Context, id string) error
 }
+
+// AgentQuery selects agents for ListAgentsPage. Zero values match every agent.
+type AgentQuery struct {
+	// ClusterID and Group restrict agents to a cluster and a group
+	ClusterID string
+	Group     string
+
+	// DownPortsOnly keeps agents with at least one NIC port whose link is down
+	DownPortsOnly bool
+
+	// UnhealthyBefore, when set, keeps active agents last healthy before it,
+	// counting agents never healthy from their creation
+	UnhealthyBefore time.Time
+
+	// AfterCreatedAt and AfterID start the page after the agent with this
+	// key; an empty AfterID starts at the first agent
+	AfterCreatedAt time.Time
+	AfterID        string
+
+	// Limit caps the number of agents returned; 0 returns all
+	Limit int
+}
+
+// AgentKeyLess reports whether the agent keyed (createdAt, id) sorts before
+// other in ListAgentsPage order
+func AgentKeyLess(createdAt time.Time, id string, other *v1.Agent) bool {
+	otherCreatedAt := other.CreatedAt.AsTime()
+	if !createdAt.Equal(otherCreatedAt) {
+		return createdAt.Before(otherCreatedAt)
+	}
+	return id < other.Id
+}
+
+// Locker provides mutual exclusion across server replicas sharing a backend.
+// Single-process backends do not implement it.
+type Locker interface {
+	// TryLock attempts to take the lock identified by key without blocking.
+	// It returns nil if another holder owns the lock.
+	TryLock(ctx context.Context, key int64) (Lock, error)
+}
+
+// Lock is a held Locker lock
+type Lock interface {
+	// Check verifies the lock is still held
+	Check(ctx context.Context) error
+	// Unlock releases the lock
+	Unlock(ctx context.Context) error
+}
+
+// PoolStater reports connection pool statistics. Backends without a
+// connection pool do not implement it.
+type PoolStater interface {
+	PoolStats() PoolStats
+}
+
+// PoolStats is a snapshot of a connection pool
+type PoolStats struct {
+	TotalConns    int32
+	IdleConns     int32
+	AcquiredConns int32
+	MaxConns      int32
+	AcquireCount  int64
+}
+
+// HardwareSummarizer aggregates agent NIC inventory inside the backend,
+// without loading every agent. Backends that do not implement it are
+// summarized from ListAgents.
+type HardwareSummarizer interface {
+	// SummarizeNICs counts the NICs of all agents by part number and firmware version
+	SummarizeNICs(ctx context.Context) ([]NICModelCount, error)
+}
+
+// NICModelCount is the number of NICs sharing a part number and firmware version
+type NICModelCount struct {
+	PartNumber      string
+	FirmwareVersion string
+	Count           int
+}
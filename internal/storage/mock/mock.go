@@ -3,15 +3,24 @@ package mock
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
+	"google.golang.org/protobuf/proto"
+
+	"github.com/filanov/netctrl-server/internal/storage"
 	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
 )
 
-// Storage is an in-memory storage implementation for testing
+// Storage is an in-memory storage implementation used for testing and as the
+// memory layer of the hybrid store. Like a database it stores and returns
+// copies, so changes to a returned agent or cluster only take effect once
+// written back.
 type Storage struct {
 	clusters map[string]*v1.Cluster
 	agents   map[string]*v1.Agent
+	pingErr  error
 	mu       sync.RWMutex
 }
 
@@ -23,12 +32,25 @@ func New() *Storage {
 	}
 }
 
+// SetPingError makes Ping return err, simulating an unreachable backend
+func (s *Storage) SetPingError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pingErr = err
+}
+
+func (s *Storage) Ping(ctx context.Context) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.pingErr
+}
+
 // Cluster operations
 
 func (s *Storage) CreateCluster(ctx context.Context, cluster *v1.Cluster) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.clusters[cluster.Id] = cluster
+	s.clusters[cluster.Id] = proto.Clone(cluster).(*v1.Cluster)
 	return nil
 }
 
@@ -39,15 +61,17 @@ func (s *Storage) GetCluster(ctx context.Context, id string) (*v1.Cluster, error
 	if !ok {
 		return nil, fmt.Errorf("cluster not found")
 	}
-	return cluster, nil
+	return proto.Clone(cluster).(*v1.Cluster), nil
 }
 
-func (s *Storage) ListClusters(ctx context.Context) ([]*v1.Cluster, error) {
+func (s *Storage) ListClusters(ctx context.Context, namePrefix string) ([]*v1.Cluster, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	clusters := make([]*v1.Cluster, 0, len(s.clusters))
 	for _, cluster := range s.clusters {
-		clusters = append(clusters, cluster)
+		if strings.HasPrefix(cluster.Name, namePrefix) {
+			clusters = append(clusters, proto.Clone(cluster).(*v1.Cluster))
+		}
 	}
 	return clusters, nil
 }
@@ -58,7 +82,7 @@ func (s *Storage) UpdateCluster(ctx context.Context, cluster *v1.Cluster) error
 	if _, ok := s.clusters[cluster.Id]; !ok {
 		return fmt.Errorf("cluster not found")
 	}
-	s.clusters[cluster.Id] = cluster
+	s.clusters[cluster.Id] = proto.Clone(cluster).(*v1.Cluster)
 	return nil
 }
 
@@ -85,12 +109,33 @@ func (s *Storage) ClusterExists(ctx context.Context, id string) (bool, error) {
 	return ok, nil
 }
 
+func (s *Storage) MoveCluster(ctx context.Context, oldID, newID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cluster, ok := s.clusters[oldID]
+	if !ok {
+		return fmt.Errorf("cluster not found")
+	}
+	if _, ok := s.clusters[newID]; ok {
+		return fmt.Errorf("cluster %s already exists", newID)
+	}
+	delete(s.clusters, oldID)
+	cluster.Id = newID
+	s.clusters[newID] = cluster
+	for _, agent := range s.agents {
+		if agent.ClusterId == oldID {
+			agent.ClusterId = newID
+		}
+	}
+	return nil
+}
+
 // Agent operations
 
 func (s *Storage) CreateAgent(ctx context.Context, agent *v1.Agent) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.agents[agent.Id] = agent
+	s.agents[agent.Id] = proto.Clone(agent).(*v1.Agent)
 	return nil
 }
 
@@ -101,7 +146,7 @@ func (s *Storage) GetAgent(ctx context.Context, id string) (*v1.Agent, error) {
 	if !ok {
 		return nil, fmt.Errorf("agent not found")
 	}
-	return agent, nil
+	return proto.Clone(agent).(*v1.Agent), nil
 }
 
 func (s *Storage) ListAgents(ctx context.Context, clusterID string) ([]*v1.Agent, error) {
@@ -110,19 +155,122 @@ func (s *Storage) ListAgents(ctx context.Context, clusterID string) ([]*v1.Agent
 	agents := make([]*v1.Agent, 0)
 	for _, agent := range s.agents {
 		if clusterID == "" || agent.ClusterId == clusterID {
-			agents = append(agents, agent)
+			agents = append(agents, proto.Clone(agent).(*v1.Agent))
 		}
 	}
 	return agents, nil
 }
 
+func (s *Storage) ListAgentsByIP(ctx context.Context, clusterID, ipAddress string) ([]*v1.Agent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	agents := make([]*v1.Agent, 0)
+	for _, agent := range s.agents {
+		if agent.ClusterId == clusterID && agent.IpAddress == ipAddress {
+			agents = append(agents, proto.Clone(agent).(*v1.Agent))
+		}
+	}
+	return agents, nil
+}
+
+func (s *Storage) ListAgentsPage(ctx context.Context, query storage.AgentQuery) ([]*v1.Agent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	agents := make([]*v1.Agent, 0)
+	for _, agent := range s.agents {
+		if matchesAgentQuery(agent, query) {
+			agents = append(agents, proto.Clone(agent).(*v1.Agent))
+		}
+	}
+	sort.Slice(agents, func(i, j int) bool {
+		return storage.AgentKeyLess(agents[i].CreatedAt.AsTime(), agents[i].Id, agents[j])
+	})
+	if query.Limit > 0 && len(agents) > query.Limit {
+		agents = agents[:query.Limit]
+	}
+	return agents, nil
+}
+
+// matchesAgentQuery reports whether an agent passes the filters of query
+// and sorts after its page start
+func matchesAgentQuery(agent *v1.Agent, query storage.AgentQuery) bool {
+	if query.ClusterID != "" && agent.ClusterId != query.ClusterID {
+		return false
+	}
+	if query.Group != "" && agent.Group != query.Group {
+		return false
+	}
+	if query.DownPortsOnly && !hasDownPort(agent) {
+		return false
+	}
+	if !query.UnhealthyBefore.IsZero() {
+		lastHealthy := agent.LastHealthyAt
+		if lastHealthy == nil {
+			lastHealthy = agent.CreatedAt
+		}
+		if agent.Status != v1.AgentStatus_AGENT_STATUS_ACTIVE || !lastHealthy.AsTime().Before(query.UnhealthyBefore) {
+			return false
+		}
+	}
+	if query.AfterID != "" && !storage.AgentKeyLess(query.AfterCreatedAt, query.AfterID, agent) {
+		return false
+	}
+	return true
+}
+
+func (s *Storage) ListOrphanedAgents(ctx context.Context) ([]*v1.Agent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	agents := make([]*v1.Agent, 0)
+	for _, agent := range s.agents {
+		if _, ok := s.clusters[agent.ClusterId]; !ok {
+			agents = append(agents, proto.Clone(agent).(*v1.Agent))
+		}
+	}
+	return agents, nil
+}
+
+func (s *Storage) CountAgents(ctx context.Context) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.agents), nil
+}
+
+func (s *Storage) GetAgentStatuses(ctx context.Context, ids []string) (map[string]v1.AgentStatus, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	statuses := make(map[string]v1.AgentStatus, len(ids))
+	for _, id := range ids {
+		if agent, ok := s.agents[id]; ok {
+			statuses[id] = agent.Status
+		}
+	}
+	return statuses, nil
+}
+
+func hasDownPort(agent *v1.Agent) bool {
+	for _, nic := range agent.NetworkInterfaces {
+		for _, port := range nic.Ports {
+			if port.State == v1.PortState_PORT_STATE_DOWN {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (s *Storage) UpdateAgent(ctx context.Context, agent *v1.Agent) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if _, ok := s.agents[agent.Id]; !ok {
+	stored, ok := s.agents[agent.Id]
+	if !ok {
 		return fmt.Errorf("agent not found")
 	}
-	s.agents[agent.Id] = agent
+	if stored.Revision != agent.Revision {
+		return storage.ErrConflict
+	}
+	agent.Revision++
+	s.agents[agent.Id] = proto.Clone(agent).(*v1.Agent)
 	return nil
 }
 
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/filanov/netctrl-server/internal/storage"
 	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
 )
 
@@ -19,13 +21,66 @@ func (s *Storage) CreateAgent(ctx context.Context, agent *v1.Agent) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal network interfaces: %w", err)
 	}
+	if err := s.limits.checkNetworkInterfaces(networkInterfaces); err != nil {
+		return err
+	}
+
+	lastGatewayProbe, err := marshalGatewayProbe(agent.LastGatewayProbe)
+	if err != nil {
+		return err
+	}
+
+	appliedNetworkConfig, err := marshalNetworkConfig(agent.AppliedNetworkConfig)
+	if err != nil {
+		return err
+	}
+
+	metrics, err := marshalMetrics(agent.Metrics)
+	if err != nil {
+		return err
+	}
+
+	lastOutcomes, err := marshalOutcomes(agent.LastOutcomes)
+	if err != nil {
+		return err
+	}
+
+	lastRegistration, err := marshalRegistrationSource(agent.LastRegistration)
+	if err != nil {
+		return err
+	}
+
+	unrecognizedResults, err := marshalRawResults(agent.UnrecognizedResults)
+	if err != nil {
+		return err
+	}
+
+	installMetadata, err := marshalInstallMetadata(agent.InstallMetadata)
+	if err != nil {
+		return err
+	}
+
+	failedNICs, err := marshalFailedNICs(agent.FailedNics)
+	if err != nil {
+		return err
+	}
+
+	configOverrides, err := marshalConfigOverrides(agent.ConfigOverrides)
+	if err != nil {
+		return err
+	}
 
 	query := `
 		INSERT INTO agents (
 			id, cluster_id, hostname, ip_address, version, status,
-			last_seen, created_at, updated_at, hardware_collected, network_interfaces
+			last_seen, created_at, updated_at, hardware_collected, network_interfaces, role,
+			last_gateway_probe, last_gateway_probe_at, applied_network_config, config_drifted,
+			metrics, metrics_reported_at, agent_group, last_outcomes, decommissioning, last_registration,
+			instructed_poll_interval_seconds, reported_poll_interval_seconds, poll_interval_drifted,
+			unrecognized_results, last_healthy_at, install_metadata, failed_nics,
+			restart_requested, last_restart_at, config_overrides, poll_interval_source, revision
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34)
 	`
 
 	_, err = s.pool.Exec(ctx, query,
@@ -40,6 +95,29 @@ func (s *Storage) CreateAgent(ctx context.Context, agent *v1.Agent) error {
 		agent.UpdatedAt.AsTime(),
 		agent.HardwareCollected,
 		networkInterfaces,
+		agent.Role.String(),
+		lastGatewayProbe,
+		optionalTime(agent.LastGatewayProbeAt),
+		appliedNetworkConfig,
+		agent.ConfigDrifted,
+		metrics,
+		optionalTime(agent.MetricsReportedAt),
+		agent.Group,
+		lastOutcomes,
+		agent.Decommissioning,
+		lastRegistration,
+		agent.InstructedPollIntervalSeconds,
+		agent.ReportedPollIntervalSeconds,
+		agent.PollIntervalDrifted,
+		unrecognizedResults,
+		optionalTime(agent.LastHealthyAt),
+		installMetadata,
+		failedNICs,
+		agent.RestartRequested,
+		optionalTime(agent.LastRestartAt),
+		configOverrides,
+		agent.PollIntervalSource.String(),
+		agent.Revision,
 	)
 
 	if err != nil {
@@ -51,32 +129,9 @@ func (s *Storage) CreateAgent(ctx context.Context, agent *v1.Agent) error {
 
 // GetAgent retrieves an agent by ID
 func (s *Storage) GetAgent(ctx context.Context, id string) (*v1.Agent, error) {
-	query := `
-		SELECT id, cluster_id, hostname, ip_address, version, status,
-		       last_seen, created_at, updated_at, hardware_collected, network_interfaces
-		FROM agents
-		WHERE id = $1
-	`
-
-	var agent v1.Agent
-	var statusStr string
-	var lastSeen, createdAt, updatedAt time.Time
-	var networkInterfacesJSON []byte
-
-	err := s.pool.QueryRow(ctx, query, id).Scan(
-		&agent.Id,
-		&agent.ClusterId,
-		&agent.Hostname,
-		&agent.IpAddress,
-		&agent.Version,
-		&statusStr,
-		&lastSeen,
-		&createdAt,
-		&updatedAt,
-		&agent.HardwareCollected,
-		&networkInterfacesJSON,
-	)
+	query := `SELECT ` + agentColumns + ` FROM agents WHERE id = $1`
 
+	agent, err := scanAgent(s.pool.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("agent not found")
@@ -84,22 +139,7 @@ func (s *Storage) GetAgent(ctx context.Context, id string) (*v1.Agent, error) {
 		return nil, fmt.Errorf("failed to get agent: %w", err)
 	}
 
-	// Parse status
-	agent.Status = parseAgentStatus(statusStr)
-
-	// Parse timestamps
-	agent.LastSeen = timestamppb.New(lastSeen)
-	agent.CreatedAt = timestamppb.New(createdAt)
-	agent.UpdatedAt = timestamppb.New(updatedAt)
-
-	// Parse network interfaces
-	if len(networkInterfacesJSON) > 0 && string(networkInterfacesJSON) != "[]" {
-		if err := json.Unmarshal(networkInterfacesJSON, &agent.NetworkInterfaces); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal network interfaces: %w", err)
-		}
-	}
-
-	return &agent, nil
+	return agent, nil
 }
 
 // ListAgents lists agents, optionally filtered by cluster
@@ -108,73 +148,110 @@ func (s *Storage) ListAgents(ctx context.Context, clusterID string) ([]*v1.Agent
 	var args []interface{}
 
 	if clusterID != "" {
-		query = `
-			SELECT id, cluster_id, hostname, ip_address, version, status,
-			       last_seen, created_at, updated_at, hardware_collected, network_interfaces
-			FROM agents
-			WHERE cluster_id = $1
-			ORDER BY created_at DESC
-		`
+		query = `SELECT ` + agentColumns + ` FROM agents WHERE cluster_id = $1 ORDER BY created_at DESC`
 		args = append(args, clusterID)
 	} else {
-		query = `
-			SELECT id, cluster_id, hostname, ip_address, version, status,
-			       last_seen, created_at, updated_at, hardware_collected, network_interfaces
-			FROM agents
-			ORDER BY created_at DESC
-		`
+		query = `SELECT ` + agentColumns + ` FROM agents ORDER BY created_at DESC`
 	}
 
-	rows, err := s.pool.Query(ctx, query, args...)
+	return s.queryAgents(ctx, query, args...)
+}
+
+// ListAgentsByIP lists agents in a cluster that report the given IP address
+func (s *Storage) ListAgentsByIP(ctx context.Context, clusterID, ipAddress string) ([]*v1.Agent, error) {
+	query := `SELECT ` + agentColumns + ` FROM agents WHERE cluster_id = $1 AND ip_address = $2 ORDER BY created_at DESC`
+
+	return s.queryAgents(ctx, query, clusterID, ipAddress)
+}
+
+// downPortFilter matches agents with any NIC port in the down state. Network
+// interfaces are stored as JSON-encoded protobuf structs, so enum values are
+// numeric; containment lets the query use the GIN index on network_interfaces.
+var downPortFilter = fmt.Sprintf(`network_interfaces @> '[{"ports":[{"state":%d}]}]'`, v1.PortState_PORT_STATE_DOWN)
+
+// ListAgentsPage lists the agents matching query in (created_at, id) order.
+// Filters, the page start and the limit are all applied by the database, so
+// each page reads only its own rows through the (created_at, id) indexes.
+func (s *Storage) ListAgentsPage(ctx context.Context, query storage.AgentQuery) ([]*v1.Agent, error) {
+	var conditions []string
+	var args []interface{}
+	arg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if query.ClusterID != "" {
+		conditions = append(conditions, "cluster_id = "+arg(query.ClusterID))
+	}
+	if query.Group != "" {
+		conditions = append(conditions, "agent_group = "+arg(query.Group))
+	}
+	if query.DownPortsOnly {
+		conditions = append(conditions, downPortFilter)
+	}
+	if !query.UnhealthyBefore.IsZero() {
+		conditions = append(conditions, fmt.Sprintf("status = '%s'", v1.AgentStatus_AGENT_STATUS_ACTIVE),
+			"COALESCE(last_healthy_at, created_at) < "+arg(query.UnhealthyBefore))
+	}
+	if query.AfterID != "" {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) > (%s, %s)", arg(query.AfterCreatedAt), arg(query.AfterID)))
+	}
+
+	sql := `SELECT ` + agentColumns + ` FROM agents`
+	if len(conditions) > 0 {
+		sql += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	sql += ` ORDER BY created_at, id`
+	if query.Limit > 0 {
+		sql += ` LIMIT ` + arg(query.Limit)
+	}
+
+	return s.queryAgents(ctx, sql, args...)
+}
+
+// ListOrphanedAgents lists agents whose cluster_id has no matching cluster
+func (s *Storage) ListOrphanedAgents(ctx context.Context) ([]*v1.Agent, error) {
+	query := `SELECT ` + agentColumns + ` FROM agents WHERE id IN (
+		SELECT a.id FROM agents a
+		LEFT JOIN clusters c ON c.id = a.cluster_id
+		WHERE c.id IS NULL
+	) ORDER BY created_at DESC`
+
+	return s.queryAgents(ctx, query)
+}
+
+// CountAgents returns the number of agents across all clusters
+func (s *Storage) CountAgents(ctx context.Context) (int, error) {
+	var count int
+	if err := s.pool.QueryRow(ctx, `SELECT COUNT(*) FROM agents`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count agents: %w", err)
+	}
+	return count, nil
+}
+
+// GetAgentStatuses returns the status of each listed agent that exists,
+// reading only the id and status columns
+func (s *Storage) GetAgentStatuses(ctx context.Context, ids []string) (map[string]v1.AgentStatus, error) {
+	rows, err := s.pool.Query(ctx, `SELECT id, status FROM agents WHERE id = ANY($1)`, ids)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list agents: %w", err)
+		return nil, fmt.Errorf("failed to get agent statuses: %w", err)
 	}
 	defer rows.Close()
 
-	var agents []*v1.Agent
+	statuses := make(map[string]v1.AgentStatus, len(ids))
 	for rows.Next() {
-		var agent v1.Agent
-		var statusStr string
-		var lastSeen, createdAt, updatedAt time.Time
-		var networkInterfacesJSON []byte
-
-		err := rows.Scan(
-			&agent.Id,
-			&agent.ClusterId,
-			&agent.Hostname,
-			&agent.IpAddress,
-			&agent.Version,
-			&statusStr,
-			&lastSeen,
-			&createdAt,
-			&updatedAt,
-			&agent.HardwareCollected,
-			&networkInterfacesJSON,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan agent: %w", err)
+		var id, statusStr string
+		if err := rows.Scan(&id, &statusStr); err != nil {
+			return nil, fmt.Errorf("failed to scan agent status: %w", err)
 		}
-
-		agent.Status = parseAgentStatus(statusStr)
-		agent.LastSeen = timestamppb.New(lastSeen)
-		agent.CreatedAt = timestamppb.New(createdAt)
-		agent.UpdatedAt = timestamppb.New(updatedAt)
-
-		// Parse network interfaces
-		if len(networkInterfacesJSON) > 0 && string(networkInterfacesJSON) != "[]" {
-			if err := json.Unmarshal(networkInterfacesJSON, &agent.NetworkInterfaces); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal network interfaces: %w", err)
-			}
-		}
-
-		agents = append(agents, &agent)
+		statuses[id] = parseAgentStatus(statusStr)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating agents: %w", err)
+		return nil, fmt.Errorf("error iterating agent statuses: %w", err)
 	}
 
-	return agents, nil
+	return statuses, nil
 }
 
 // UpdateAgent updates an existing agent
@@ -183,13 +260,70 @@ func (s *Storage) UpdateAgent(ctx context.Context, agent *v1.Agent) error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal network interfaces: %w", err)
 	}
+	if err := s.limits.checkNetworkInterfaces(networkInterfaces); err != nil {
+		return err
+	}
+
+	lastGatewayProbe, err := marshalGatewayProbe(agent.LastGatewayProbe)
+	if err != nil {
+		return err
+	}
+
+	appliedNetworkConfig, err := marshalNetworkConfig(agent.AppliedNetworkConfig)
+	if err != nil {
+		return err
+	}
+
+	metrics, err := marshalMetrics(agent.Metrics)
+	if err != nil {
+		return err
+	}
+
+	lastOutcomes, err := marshalOutcomes(agent.LastOutcomes)
+	if err != nil {
+		return err
+	}
+
+	lastRegistration, err := marshalRegistrationSource(agent.LastRegistration)
+	if err != nil {
+		return err
+	}
+
+	unrecognizedResults, err := marshalRawResults(agent.UnrecognizedResults)
+	if err != nil {
+		return err
+	}
+
+	installMetadata, err := marshalInstallMetadata(agent.InstallMetadata)
+	if err != nil {
+		return err
+	}
+
+	failedNICs, err := marshalFailedNICs(agent.FailedNics)
+	if err != nil {
+		return err
+	}
+
+	configOverrides, err := marshalConfigOverrides(agent.ConfigOverrides)
+	if err != nil {
+		return err
+	}
 
 	query := `
 		UPDATE agents
 		SET cluster_id = $2, hostname = $3, ip_address = $4, version = $5,
 		    status = $6, last_seen = $7, updated_at = $8,
-		    hardware_collected = $9, network_interfaces = $10
-		WHERE id = $1
+		    hardware_collected = $9, network_interfaces = $10, role = $11,
+		    last_gateway_probe = $12, last_gateway_probe_at = $13,
+		    applied_network_config = $14, config_drifted = $15,
+		    metrics = $16, metrics_reported_at = $17, agent_group = $18,
+		    last_outcomes = $19, decommissioning = $20, last_registration = $21,
+		    instructed_poll_interval_seconds = $22, reported_poll_interval_seconds = $23,
+		    poll_interval_drifted = $24, unrecognized_results = $25,
+		    last_healthy_at = $26, install_metadata = $27, failed_nics = $28,
+		    restart_requested = $29, last_restart_at = $30, config_overrides = $31,
+		    poll_interval_source = $32, revision = revision + 1
+		WHERE id = $1 AND revision = $33
 	`
 
 	result, err := s.pool.Exec(ctx, query,
@@ -203,6 +337,29 @@ func (s *Storage) UpdateAgent(ctx context.Context, agent *v1.Agent) error {
 		agent.UpdatedAt.AsTime(),
 		agent.HardwareCollected,
 		networkInterfaces,
+		agent.Role.String(),
+		lastGatewayProbe,
+		optionalTime(agent.LastGatewayProbeAt),
+		appliedNetworkConfig,
+		agent.ConfigDrifted,
+		metrics,
+		optionalTime(agent.MetricsReportedAt),
+		agent.Group,
+		lastOutcomes,
+		agent.Decommissioning,
+		lastRegistration,
+		agent.InstructedPollIntervalSeconds,
+		agent.ReportedPollIntervalSeconds,
+		agent.PollIntervalDrifted,
+		unrecognizedResults,
+		optionalTime(agent.LastHealthyAt),
+		installMetadata,
+		failedNICs,
+		agent.RestartRequested,
+		optionalTime(agent.LastRestartAt),
+		configOverrides,
+		agent.PollIntervalSource.String(),
+		agent.Revision,
 	)
 
 	if err != nil {
@@ -210,9 +367,18 @@ func (s *Storage) UpdateAgent(ctx context.Context, agent *v1.Agent) error {
 	}
 
 	if result.RowsAffected() == 0 {
+		// Tell a missing agent apart from one written since it was read
+		var exists bool
+		if err := s.pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM agents WHERE id = $1)`, agent.Id).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to update agent: %w", err)
+		}
+		if exists {
+			return storage.ErrConflict
+		}
 		return fmt.Errorf("agent not found")
 	}
 
+	agent.Revision++
 	return nil
 }
 
@@ -232,6 +398,311 @@ func (s *Storage) DeleteAgent(ctx context.Context, id string) error {
 	return nil
 }
 
+// SummarizeNICs counts NICs across all agents by part number and firmware
+// version, aggregating in the database rather than loading agents
+func (s *Storage) SummarizeNICs(ctx context.Context) ([]storage.NICModelCount, error) {
+	query := `
+		SELECT COALESCE(nic->>'part_number', ''), COALESCE(nic->>'firmware_version', ''), COUNT(*)
+		FROM agents, jsonb_array_elements(COALESCE(network_interfaces, '[]'::jsonb)) AS nic
+		GROUP BY 1, 2
+	`
+
+	rows, err := s.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize NICs: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make([]storage.NICModelCount, 0)
+	for rows.Next() {
+		var count storage.NICModelCount
+		var n int64
+		if err := rows.Scan(&count.PartNumber, &count.FirmwareVersion, &n); err != nil {
+			return nil, fmt.Errorf("failed to scan NIC count: %w", err)
+		}
+		count.Count = int(n)
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating NIC counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// agentColumns lists the agent columns in the order expected by scanAgent
+const agentColumns = `id, cluster_id, hostname, ip_address, version, status,
+	last_seen, created_at, updated_at, hardware_collected, network_interfaces, role,
+	last_gateway_probe, last_gateway_probe_at, applied_network_config, config_drifted,
+	metrics, metrics_reported_at, agent_group, last_outcomes, decommissioning, last_registration,
+	instructed_poll_interval_seconds, reported_poll_interval_seconds, poll_interval_drifted,
+	unrecognized_results, last_healthy_at, install_metadata, failed_nics,
+	restart_requested, last_restart_at, config_overrides, poll_interval_source, revision`
+
+// queryAgents runs a query selecting agentColumns and scans all resulting rows
+func (s *Storage) queryAgents(ctx context.Context, query string, args ...interface{}) ([]*v1.Agent, error) {
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	defer rows.Close()
+
+	agents := make([]*v1.Agent, 0)
+	for rows.Next() {
+		// Stop scanning as soon as the caller gives up; the deferred Close
+		// releases the connection without draining the remaining rows
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		agent, err := scanAgent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan agent: %w", err)
+		}
+		agents = append(agents, agent)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating agents: %w", err)
+	}
+
+	return agents, nil
+}
+
+// scanAgent scans a single row selected with agentColumns into an agent
+func scanAgent(row pgx.Row) (*v1.Agent, error) {
+	var agent v1.Agent
+	var statusStr, roleStr, pollIntervalSourceStr string
+	var lastSeen, createdAt, updatedAt time.Time
+	var networkInterfacesJSON, lastGatewayProbeJSON, appliedNetworkConfigJSON, metricsJSON, lastOutcomesJSON []byte
+	var lastRegistrationJSON, unrecognizedResultsJSON, installMetadataJSON, failedNICsJSON, configOverridesJSON []byte
+	var lastGatewayProbeAt, metricsReportedAt, lastHealthyAt, lastRestartAt *time.Time
+
+	err := row.Scan(
+		&agent.Id,
+		&agent.ClusterId,
+		&agent.Hostname,
+		&agent.IpAddress,
+		&agent.Version,
+		&statusStr,
+		&lastSeen,
+		&createdAt,
+		&updatedAt,
+		&agent.HardwareCollected,
+		&networkInterfacesJSON,
+		&roleStr,
+		&lastGatewayProbeJSON,
+		&lastGatewayProbeAt,
+		&appliedNetworkConfigJSON,
+		&agent.ConfigDrifted,
+		&metricsJSON,
+		&metricsReportedAt,
+		&agent.Group,
+		&lastOutcomesJSON,
+		&agent.Decommissioning,
+		&lastRegistrationJSON,
+		&agent.InstructedPollIntervalSeconds,
+		&agent.ReportedPollIntervalSeconds,
+		&agent.PollIntervalDrifted,
+		&unrecognizedResultsJSON,
+		&lastHealthyAt,
+		&installMetadataJSON,
+		&failedNICsJSON,
+		&agent.RestartRequested,
+		&lastRestartAt,
+		&configOverridesJSON,
+		&pollIntervalSourceStr,
+		&agent.Revision,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse enums
+	agent.Status = parseAgentStatus(statusStr)
+	agent.Role = parseAgentRole(roleStr)
+	agent.PollIntervalSource = v1.PollIntervalSource(v1.PollIntervalSource_value[pollIntervalSourceStr])
+
+	// Parse timestamps
+	agent.LastSeen = timestamppb.New(lastSeen)
+	agent.CreatedAt = timestamppb.New(createdAt)
+	agent.UpdatedAt = timestamppb.New(updatedAt)
+
+	// Parse network interfaces
+	if len(networkInterfacesJSON) > 0 && string(networkInterfacesJSON) != "[]" {
+		if err := json.Unmarshal(networkInterfacesJSON, &agent.NetworkInterfaces); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal network interfaces: %w", err)
+		}
+	}
+
+	// Parse last gateway probe
+	if len(lastGatewayProbeJSON) > 0 {
+		var probe v1.GatewayProbeResult
+		if err := json.Unmarshal(lastGatewayProbeJSON, &probe); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal gateway probe: %w", err)
+		}
+		agent.LastGatewayProbe = &probe
+	}
+	if lastGatewayProbeAt != nil {
+		agent.LastGatewayProbeAt = timestamppb.New(*lastGatewayProbeAt)
+	}
+
+	// Parse applied network config
+	if len(appliedNetworkConfigJSON) > 0 {
+		var networkConfig v1.NetworkConfig
+		if err := json.Unmarshal(appliedNetworkConfigJSON, &networkConfig); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal applied network config: %w", err)
+		}
+		agent.AppliedNetworkConfig = &networkConfig
+	}
+
+	// Parse metrics snapshot
+	if len(metricsJSON) > 0 {
+		if err := json.Unmarshal(metricsJSON, &agent.Metrics); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metrics: %w", err)
+		}
+	}
+	if metricsReportedAt != nil {
+		agent.MetricsReportedAt = timestamppb.New(*metricsReportedAt)
+	}
+
+	// Parse last instruction outcomes
+	if len(lastOutcomesJSON) > 0 {
+		if err := json.Unmarshal(lastOutcomesJSON, &agent.LastOutcomes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal last outcomes: %w", err)
+		}
+	}
+
+	// Parse last registration source
+	if len(lastRegistrationJSON) > 0 {
+		var source v1.RegistrationSource
+		if err := json.Unmarshal(lastRegistrationJSON, &source); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal last registration: %w", err)
+		}
+		agent.LastRegistration = &source
+	}
+
+	if lastHealthyAt != nil {
+		agent.LastHealthyAt = timestamppb.New(*lastHealthyAt)
+	}
+	if lastRestartAt != nil {
+		agent.LastRestartAt = timestamppb.New(*lastRestartAt)
+	}
+
+	// Parse raw results of unrecognized instruction types
+	if len(unrecognizedResultsJSON) > 0 {
+		if err := json.Unmarshal(unrecognizedResultsJSON, &agent.UnrecognizedResults); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal unrecognized results: %w", err)
+		}
+	}
+
+	// Parse install metadata
+	if len(installMetadataJSON) > 0 {
+		var metadata v1.InstallMetadata
+		if err := json.Unmarshal(installMetadataJSON, &metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal install metadata: %w", err)
+		}
+		agent.InstallMetadata = &metadata
+	}
+
+	// Parse NICs the last hardware collection failed on
+	if len(failedNICsJSON) > 0 {
+		if err := json.Unmarshal(failedNICsJSON, &agent.FailedNics); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal failed NICs: %w", err)
+		}
+	}
+
+	// Parse per-agent config overrides
+	if len(configOverridesJSON) > 0 {
+		var overrides v1.AgentConfigOverrides
+		if err := json.Unmarshal(configOverridesJSON, &overrides); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal config overrides: %w", err)
+		}
+		agent.ConfigOverrides = &overrides
+	}
+
+	return &agent, nil
+}
+
+// marshalGatewayProbe converts a gateway probe result to JSON, keeping nil as NULL
+func marshalGatewayProbe(probe *v1.GatewayProbeResult) ([]byte, error) {
+	if probe == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(probe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal gateway probe: %w", err)
+	}
+
+	return data, nil
+}
+
+// marshalMetrics converts a metrics snapshot to JSON, keeping an empty one as NULL
+func marshalMetrics(metrics map[string]float64) ([]byte, error) {
+	if len(metrics) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(metrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal metrics: %w", err)
+	}
+
+	return data, nil
+}
+
+// marshalOutcomes converts instruction outcomes to JSON, keeping none as NULL
+func marshalOutcomes(outcomes []*v1.InstructionOutcome) ([]byte, error) {
+	if len(outcomes) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(outcomes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal last outcomes: %w", err)
+	}
+
+	return data, nil
+}
+
+// marshalRawResults converts raw instruction results to JSON, keeping an empty list as NULL
+func marshalRawResults(results []*v1.RawInstructionResult) ([]byte, error) {
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal unrecognized results: %w", err)
+	}
+
+	return data, nil
+}
+
+// marshalFailedNICs converts NIC collection failures to JSON, keeping an empty list as NULL
+func marshalFailedNICs(failures []*v1.NICCollectionFailure) ([]byte, error) {
+	if len(failures) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(failures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal failed NICs: %w", err)
+	}
+
+	return data, nil
+}
+
+// optionalTime converts an optional timestamp to a nullable time value
+func optionalTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
 // parseAgentStatus converts string status to enum
 func parseAgentStatus(status string) v1.AgentStatus {
 	switch status {
@@ -239,7 +710,66 @@ func parseAgentStatus(status string) v1.AgentStatus {
 		return v1.AgentStatus_AGENT_STATUS_ACTIVE
 	case "AGENT_STATUS_INACTIVE":
 		return v1.AgentStatus_AGENT_STATUS_INACTIVE
+	case "AGENT_STATUS_STALE":
+		return v1.AgentStatus_AGENT_STATUS_STALE
+	case "AGENT_STATUS_EXPIRED":
+		return v1.AgentStatus_AGENT_STATUS_EXPIRED
 	default:
 		return v1.AgentStatus_AGENT_STATUS_UNSPECIFIED
 	}
 }
+
+// parseAgentRole converts string role to enum
+func parseAgentRole(role string) v1.AgentRole {
+	switch role {
+	case "AGENT_ROLE_SPINE":
+		return v1.AgentRole_AGENT_ROLE_SPINE
+	case "AGENT_ROLE_LEAF":
+		return v1.AgentRole_AGENT_ROLE_LEAF
+	default:
+		return v1.AgentRole_AGENT_ROLE_UNSPECIFIED
+	}
+}
+
+// marshalRegistrationSource converts a registration source to JSON, keeping nil as NULL
+func marshalRegistrationSource(source *v1.RegistrationSource) ([]byte, error) {
+	if source == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal last registration: %w", err)
+	}
+
+	return data, nil
+}
+
+// marshalInstallMetadata converts install metadata to JSON, keeping nil as NULL
+func marshalInstallMetadata(metadata *v1.InstallMetadata) ([]byte, error) {
+	if metadata == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal install metadata: %w", err)
+	}
+
+	return data, nil
+}
+
+// marshalConfigOverrides converts per-agent config overrides to JSON, keeping
+// nil as NULL
+func marshalConfigOverrides(overrides *v1.AgentConfigOverrides) ([]byte, error) {
+	if overrides == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config overrides: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+var _ = Describe("GetAgentStatuses", func() {
+	It("should return only the statuses of existing agents", func() {
+		ctx := context.Background()
+		store := newMigratedStorage()
+
+		now := timestamppb.Now()
+		Expect(store.CreateCluster(ctx, &v1.Cluster{Id: "cluster-1", Name: "test", CreatedAt: now, UpdatedAt: now})).To(Succeed())
+		for id, agentStatus := range map[string]v1.AgentStatus{
+			"agent-active": v1.AgentStatus_AGENT_STATUS_ACTIVE,
+			"agent-stale":  v1.AgentStatus_AGENT_STATUS_STALE,
+		} {
+			Expect(store.CreateAgent(ctx, &v1.Agent{
+				Id: id, ClusterId: "cluster-1", Status: agentStatus,
+				LastSeen: now, CreatedAt: now, UpdatedAt: now,
+			})).To(Succeed())
+		}
+
+		statuses, err := store.GetAgentStatuses(ctx, []string{"agent-active", "agent-missing", "agent-stale"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(statuses).To(Equal(map[string]v1.AgentStatus{
+			"agent-active": v1.AgentStatus_AGENT_STATUS_ACTIVE,
+			"agent-stale":  v1.AgentStatus_AGENT_STATUS_STALE,
+		}))
+	})
+})
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+// cancelAfterContext reports cancellation from Err once it has been checked
+// more than allowed times. Its Done channel never closes, so the query
+// itself runs to completion and only the row loop observes the cancellation.
+type cancelAfterContext struct {
+	context.Context
+	allowed int
+	checks  int
+}
+
+func (c *cancelAfterContext) Err() error {
+	c.checks++
+	if c.checks > c.allowed {
+		return context.Canceled
+	}
+	return nil
+}
+
+var _ = Describe("Cancelled listings", func() {
+	const rowCount = 50
+
+	var (
+		ctx   context.Context
+		store *Storage
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = newMigratedStorage()
+
+		now := timestamppb.Now()
+		for i := 0; i < rowCount; i++ {
+			id := fmt.Sprintf("cluster-%d", i)
+			Expect(store.CreateCluster(ctx, &v1.Cluster{Id: id, Name: id, CreatedAt: now, UpdatedAt: now})).To(Succeed())
+		}
+		for i := 0; i < rowCount; i++ {
+			Expect(store.CreateAgent(ctx, &v1.Agent{
+				Id: fmt.Sprintf("agent-%d", i), ClusterId: "cluster-0",
+				LastSeen: now, CreatedAt: now, UpdatedAt: now,
+			})).To(Succeed())
+		}
+	})
+
+	It("should stop listing agents once the context is cancelled", func() {
+		cancelling := &cancelAfterContext{Context: ctx, allowed: 5}
+		agents, err := store.ListAgents(cancelling, "")
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(agents).To(BeNil())
+		Expect(cancelling.checks).To(BeNumerically("<", rowCount))
+	})
+
+	It("should stop listing clusters once the context is cancelled", func() {
+		cancelling := &cancelAfterContext{Context: ctx, allowed: 5}
+		clusters, err := store.ListClusters(cancelling, "")
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(clusters).To(BeNil())
+		Expect(cancelling.checks).To(BeNumerically("<", rowCount))
+	})
+})
@@ -2,8 +2,10 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -14,17 +16,34 @@ import (
 
 // CreateCluster creates a new cluster
 func (s *Storage) CreateCluster(ctx context.Context, cluster *v1.Cluster) error {
+	if err := s.limits.checkDescription(cluster.Description); err != nil {
+		return err
+	}
+
+	networkConfig, err := marshalNetworkConfig(cluster.NetworkConfig)
+	if err != nil {
+		return err
+	}
+
+	additionalNetworks, err := marshalAdditionalNetworks(cluster.AdditionalNetworks)
+	if err != nil {
+		return err
+	}
+
 	query := `
-		INSERT INTO clusters (id, name, description, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO clusters (id, name, description, created_at, updated_at, cordoned, network_config, additional_networks)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
 
-	_, err := s.pool.Exec(ctx, query,
+	_, err = s.pool.Exec(ctx, query,
 		cluster.Id,
 		cluster.Name,
 		cluster.Description,
 		cluster.CreatedAt.AsTime(),
 		cluster.UpdatedAt.AsTime(),
+		cluster.Cordoned,
+		networkConfig,
+		additionalNetworks,
 	)
 
 	if err != nil {
@@ -36,23 +55,9 @@ func (s *Storage) CreateCluster(ctx context.Context, cluster *v1.Cluster) error
 
 // GetCluster retrieves a cluster by ID
 func (s *Storage) GetCluster(ctx context.Context, id string) (*v1.Cluster, error) {
-	query := `
-		SELECT id, name, description, created_at, updated_at
-		FROM clusters
-		WHERE id = $1
-	`
-
-	var cluster v1.Cluster
-	var createdAt, updatedAt time.Time
-
-	err := s.pool.QueryRow(ctx, query, id).Scan(
-		&cluster.Id,
-		&cluster.Name,
-		&cluster.Description,
-		&createdAt,
-		&updatedAt,
-	)
+	query := `SELECT ` + clusterColumns + ` FROM clusters WHERE id = $1`
 
+	cluster, err := scanCluster(s.pool.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("cluster not found")
@@ -60,46 +65,38 @@ func (s *Storage) GetCluster(ctx context.Context, id string) (*v1.Cluster, error
 		return nil, fmt.Errorf("failed to get cluster: %w", err)
 	}
 
-	cluster.CreatedAt = timestamppb.New(createdAt)
-	cluster.UpdatedAt = timestamppb.New(updatedAt)
-
-	return &cluster, nil
+	return cluster, nil
 }
 
-// ListClusters lists all clusters
-func (s *Storage) ListClusters(ctx context.Context) ([]*v1.Cluster, error) {
-	query := `
-		SELECT id, name, description, created_at, updated_at
-		FROM clusters
-		ORDER BY created_at DESC
-	`
+// ListClusters lists clusters whose name starts with namePrefix, or all
+// clusters when it is empty. The prefix match uses the name pattern index.
+func (s *Storage) ListClusters(ctx context.Context, namePrefix string) ([]*v1.Cluster, error) {
+	query := `SELECT ` + clusterColumns + ` FROM clusters`
+	var args []interface{}
+	if namePrefix != "" {
+		query += ` WHERE name LIKE $1 || '%'`
+		args = append(args, escapeLike(namePrefix))
+	}
+	query += ` ORDER BY created_at DESC`
 
-	rows, err := s.pool.Query(ctx, query)
+	rows, err := s.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list clusters: %w", err)
 	}
 	defer rows.Close()
 
-	var clusters []*v1.Cluster
+	clusters := make([]*v1.Cluster, 0)
 	for rows.Next() {
-		var cluster v1.Cluster
-		var createdAt, updatedAt time.Time
-
-		err := rows.Scan(
-			&cluster.Id,
-			&cluster.Name,
-			&cluster.Description,
-			&createdAt,
-			&updatedAt,
-		)
+		// Stop scanning as soon as the caller gives up; the deferred Close
+		// releases the connection without draining the remaining rows
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		cluster, err := scanCluster(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan cluster: %w", err)
 		}
-
-		cluster.CreatedAt = timestamppb.New(createdAt)
-		cluster.UpdatedAt = timestamppb.New(updatedAt)
-
-		clusters = append(clusters, &cluster)
+		clusters = append(clusters, cluster)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -111,9 +108,24 @@ func (s *Storage) ListClusters(ctx context.Context) ([]*v1.Cluster, error) {
 
 // UpdateCluster updates an existing cluster
 func (s *Storage) UpdateCluster(ctx context.Context, cluster *v1.Cluster) error {
+	if err := s.limits.checkDescription(cluster.Description); err != nil {
+		return err
+	}
+
+	networkConfig, err := marshalNetworkConfig(cluster.NetworkConfig)
+	if err != nil {
+		return err
+	}
+
+	additionalNetworks, err := marshalAdditionalNetworks(cluster.AdditionalNetworks)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		UPDATE clusters
-		SET name = $2, description = $3, updated_at = $4
+		SET name = $2, description = $3, updated_at = $4, cordoned = $5, network_config = $6,
+		    additional_networks = $7
 		WHERE id = $1
 	`
 
@@ -122,6 +134,9 @@ func (s *Storage) UpdateCluster(ctx context.Context, cluster *v1.Cluster) error
 		cluster.Name,
 		cluster.Description,
 		cluster.UpdatedAt.AsTime(),
+		cluster.Cordoned,
+		networkConfig,
+		additionalNetworks,
 	)
 
 	if err != nil {
@@ -163,3 +178,133 @@ func (s *Storage) ClusterExists(ctx context.Context, id string) (bool, error) {
 
 	return exists, nil
 }
+
+// MoveCluster atomically changes a cluster's ID and its agents' cluster references.
+// The new cluster row is inserted before agents are repointed so the foreign key
+// holds throughout the transaction.
+func (s *Storage) MoveCluster(ctx context.Context, oldID, newID string) error {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	var exists bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM clusters WHERE id = $1)`, newID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check cluster existence: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("cluster %s already exists", newID)
+	}
+
+	insert := `
+		INSERT INTO clusters (` + clusterColumns + `)
+		SELECT $2, name, description, created_at, updated_at, cordoned, network_config, additional_networks
+		FROM clusters
+		WHERE id = $1
+	`
+	result, err := tx.Exec(ctx, insert, oldID, newID)
+	if err != nil {
+		return fmt.Errorf("failed to copy cluster: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("cluster not found")
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE agents SET cluster_id = $2 WHERE cluster_id = $1`, oldID, newID); err != nil {
+		return fmt.Errorf("failed to move agents: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM clusters WHERE id = $1`, oldID); err != nil {
+		return fmt.Errorf("failed to delete old cluster: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit cluster move: %w", err)
+	}
+
+	return nil
+}
+
+// clusterColumns lists the cluster columns in the order expected by scanCluster
+const clusterColumns = `id, name, description, created_at, updated_at, cordoned, network_config, additional_networks`
+
+// scanCluster scans a single row selected with clusterColumns into a cluster
+func scanCluster(row pgx.Row) (*v1.Cluster, error) {
+	var cluster v1.Cluster
+	var createdAt, updatedAt time.Time
+	var networkConfigJSON, additionalNetworksJSON []byte
+
+	err := row.Scan(
+		&cluster.Id,
+		&cluster.Name,
+		&cluster.Description,
+		&createdAt,
+		&updatedAt,
+		&cluster.Cordoned,
+		&networkConfigJSON,
+		&additionalNetworksJSON,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster.CreatedAt = timestamppb.New(createdAt)
+	cluster.UpdatedAt = timestamppb.New(updatedAt)
+
+	// Parse network config
+	if len(networkConfigJSON) > 0 {
+		var networkConfig v1.NetworkConfig
+		if err := json.Unmarshal(networkConfigJSON, &networkConfig); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal network config: %w", err)
+		}
+		cluster.NetworkConfig = &networkConfig
+	}
+
+	if len(additionalNetworksJSON) > 0 {
+		if err := json.Unmarshal(additionalNetworksJSON, &cluster.AdditionalNetworks); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal additional networks: %w", err)
+		}
+	}
+
+	return &cluster, nil
+}
+
+// marshalNetworkConfig converts a network config to JSON, keeping nil as NULL
+func marshalNetworkConfig(networkConfig *v1.NetworkConfig) ([]byte, error) {
+	if networkConfig == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(networkConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal network config: %w", err)
+	}
+
+	return data, nil
+}
+
+// marshalAdditionalNetworks converts additional networks to JSON, keeping an
+// empty list as NULL
+func marshalAdditionalNetworks(networks []*v1.NetworkConfig) ([]byte, error) {
+	if len(networks) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(networks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal additional networks: %w", err)
+	}
+
+	return data, nil
+}
+
+// likeEscaper escapes LIKE wildcards so a prefix matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike escapes the LIKE wildcards in s, using the default \ escape
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/filanov/netctrl-server/internal/storage"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+var _ = Describe("SummarizeNICs", func() {
+	var (
+		ctx   context.Context
+		store *Storage
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = newMigratedStorage()
+	})
+
+	It("should count NICs by part number and firmware across clusters", func() {
+		now := timestamppb.Now()
+		for _, clusterID := range []string{"cluster-a", "cluster-b"} {
+			Expect(store.CreateCluster(ctx, &v1.Cluster{Id: clusterID, Name: clusterID, CreatedAt: now, UpdatedAt: now})).To(Succeed())
+		}
+
+		agents := map[string][]*v1.MellanoxNIC{
+			"cluster-a": {
+				{PartNumber: "MCX653106A", FirmwareVersion: "20.31.1014"},
+				{PartNumber: "MCX75310AAS", FirmwareVersion: "28.39.1002"},
+			},
+			"cluster-b": {
+				{PartNumber: "MCX653106A", FirmwareVersion: "20.31.1014"},
+			},
+		}
+		for clusterID, nics := range agents {
+			Expect(store.CreateAgent(ctx, &v1.Agent{
+				Id:                "agent-" + clusterID,
+				ClusterId:         clusterID,
+				LastSeen:          now,
+				CreatedAt:         now,
+				UpdatedAt:         now,
+				NetworkInterfaces: nics,
+			})).To(Succeed())
+		}
+		Expect(store.CreateAgent(ctx, &v1.Agent{Id: "agent-no-nics", ClusterId: "cluster-a", LastSeen: now, CreatedAt: now, UpdatedAt: now})).To(Succeed())
+
+		counts, err := store.SummarizeNICs(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(counts).To(ConsistOf(
+			storage.NICModelCount{PartNumber: "MCX653106A", FirmwareVersion: "20.31.1014", Count: 2},
+			storage.NICModelCount{PartNumber: "MCX75310AAS", FirmwareVersion: "28.39.1002", Count: 1},
+		))
+	})
+})
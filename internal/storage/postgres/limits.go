@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/filanov/netctrl-server/internal/storage"
+)
+
+const (
+	// DefaultMaxDescriptionBytes caps cluster descriptions
+	DefaultMaxDescriptionBytes = 4 << 10
+
+	// DefaultMaxNetworkInterfacesBytes caps an agent's serialized NIC data
+	DefaultMaxNetworkInterfacesBytes = 1 << 20
+)
+
+// sizeLimits bounds the size of free-form data written to the database, so
+// every write path is protected regardless of service-level validation
+type sizeLimits struct {
+	maxDescriptionBytes       int
+	maxNetworkInterfacesBytes int
+}
+
+// newSizeLimits applies defaults to the configured limits
+func newSizeLimits(cfg Config) sizeLimits {
+	limits := sizeLimits{
+		maxDescriptionBytes:       cfg.MaxDescriptionBytes,
+		maxNetworkInterfacesBytes: cfg.MaxNetworkInterfacesBytes,
+	}
+	if limits.maxDescriptionBytes <= 0 {
+		limits.maxDescriptionBytes = DefaultMaxDescriptionBytes
+	}
+	if limits.maxNetworkInterfacesBytes <= 0 {
+		limits.maxNetworkInterfacesBytes = DefaultMaxNetworkInterfacesBytes
+	}
+	return limits
+}
+
+// checkDescription rejects a cluster description over the limit
+func (l sizeLimits) checkDescription(description string) error {
+	if len(description) > l.maxDescriptionBytes {
+		return fmt.Errorf("%w: cluster description is %d bytes, exceeding the limit of %d",
+			storage.ErrPayloadTooLarge, len(description), l.maxDescriptionBytes)
+	}
+	return nil
+}
+
+// checkNetworkInterfaces rejects serialized NIC data over the limit
+func (l sizeLimits) checkNetworkInterfaces(data []byte) error {
+	if len(data) > l.maxNetworkInterfacesBytes {
+		return fmt.Errorf("%w: network interfaces are %d bytes, exceeding the limit of %d",
+			storage.ErrPayloadTooLarge, len(data), l.maxNetworkInterfacesBytes)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/filanov/netctrl-server/internal/storage"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+var _ = Describe("Size limits", func() {
+	var (
+		ctx   context.Context
+		store *Storage
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		// No pool: oversized writes must be rejected before reaching the database
+		store = &Storage{limits: newSizeLimits(Config{
+			MaxDescriptionBytes:       8,
+			MaxNetworkInterfacesBytes: 64,
+		})}
+	})
+
+	It("should apply defaults when limits are unset", func() {
+		limits := newSizeLimits(Config{})
+		Expect(limits.maxDescriptionBytes).To(Equal(DefaultMaxDescriptionBytes))
+		Expect(limits.maxNetworkInterfacesBytes).To(Equal(DefaultMaxNetworkInterfacesBytes))
+	})
+
+	It("should reject an oversized description on create and update", func() {
+		cluster := &v1.Cluster{Id: "cluster-1", Name: "test", Description: strings.Repeat("x", 9)}
+
+		err := store.CreateCluster(ctx, cluster)
+		Expect(err).To(MatchError(storage.ErrPayloadTooLarge))
+
+		err = store.UpdateCluster(ctx, cluster)
+		Expect(err).To(MatchError(storage.ErrPayloadTooLarge))
+	})
+
+	It("should reject oversized network interfaces on create and update", func() {
+		agent := &v1.Agent{
+			Id:        "agent-1",
+			ClusterId: "cluster-1",
+			NetworkInterfaces: []*v1.MellanoxNIC{
+				{DeviceName: strings.Repeat("mlx5_0", 16)},
+			},
+		}
+
+		err := store.CreateAgent(ctx, agent)
+		Expect(err).To(MatchError(storage.ErrPayloadTooLarge))
+
+		err = store.UpdateAgent(ctx, agent)
+		Expect(err).To(MatchError(storage.ErrPayloadTooLarge))
+	})
+})
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/filanov/netctrl-server/internal/storage"
+	v1 "github.com/filanov/netctrl-server/pkg/api/v1"
+)
+
+var _ = Describe("Empty lists", func() {
+	var (
+		ctx   context.Context
+		store *Storage
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = newMigratedStorage()
+	})
+
+	// Callers and the gateway rely on empty results being non-nil, matching
+	// the in-memory backend
+	It("should return non-nil empty slices when nothing matches", func() {
+		clusters, err := store.ListClusters(ctx, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clusters).NotTo(BeNil())
+		Expect(clusters).To(BeEmpty())
+
+		agents, err := store.ListAgents(ctx, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agents).NotTo(BeNil())
+		Expect(agents).To(BeEmpty())
+
+		agents, err = store.ListOrphanedAgents(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agents).NotTo(BeNil())
+		Expect(agents).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Cluster name prefix", func() {
+	It("should match the prefix literally, not as a LIKE pattern", func() {
+		ctx := context.Background()
+		store := newMigratedStorage()
+
+		now := timestamppb.Now()
+		for i, name := range []string{"rack_a1", "rack_a2", "rackXa3", "rack%b", "lab-rack_a4"} {
+			Expect(store.CreateCluster(ctx, &v1.Cluster{
+				Id: fmt.Sprintf("cluster-%d", i), Name: name, CreatedAt: now, UpdatedAt: now,
+			})).To(Succeed())
+		}
+
+		clusters, err := store.ListClusters(ctx, "rack_a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clusters).To(ConsistOf(HaveField("Name", "rack_a1"), HaveField("Name", "rack_a2")))
+
+		clusters, err = store.ListClusters(ctx, "rack%")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clusters).To(ConsistOf(HaveField("Name", "rack%b")))
+	})
+})
+
+var _ = Describe("Agent pages", func() {
+	// Agent IDs are UUIDs; the last digit names the agent and orders ties
+	const (
+		agentA = "00000000-0000-0000-0000-00000000000a"
+		agentB = "00000000-0000-0000-0000-00000000000b"
+		agentC = "00000000-0000-0000-0000-00000000000c"
+		agentD = "00000000-0000-0000-0000-00000000000d"
+	)
+
+	var (
+		ctx   context.Context
+		store *Storage
+		base  time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = newMigratedStorage()
+		base = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+		created := timestamppb.New(base)
+		Expect(store.CreateCluster(ctx, &v1.Cluster{Id: "cluster-1", Name: "test", CreatedAt: created, UpdatedAt: created})).To(Succeed())
+
+		// agentA and agentD share a creation time, so the ID breaks the tie
+		for i, id := range []string{agentC, agentB, agentA, agentD} {
+			createdAt := timestamppb.New(base.Add(time.Duration(min(i, 2)) * time.Minute))
+			group := "rack-a"
+			if id == agentD {
+				group = "rack-b"
+			}
+			Expect(store.CreateAgent(ctx, &v1.Agent{
+				Id: id, ClusterId: "cluster-1", Group: group, Status: v1.AgentStatus_AGENT_STATUS_ACTIVE,
+				LastSeen: createdAt, CreatedAt: createdAt, UpdatedAt: createdAt,
+			})).To(Succeed())
+		}
+	})
+
+	ids := func(agents []*v1.Agent) []string {
+		result := make([]string, 0, len(agents))
+		for _, agent := range agents {
+			result = append(result, agent.Id)
+		}
+		return result
+	}
+
+	It("should order agents by creation time, then ID", func() {
+		agents, err := store.ListAgentsPage(ctx, storage.AgentQuery{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids(agents)).To(Equal([]string{agentC, agentB, agentA, agentD}))
+	})
+
+	It("should start after the given key and stop at the limit", func() {
+		agents, err := store.ListAgentsPage(ctx, storage.AgentQuery{
+			AfterCreatedAt: base.Add(time.Minute),
+			AfterID:        agentB,
+			Limit:          1,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids(agents)).To(Equal([]string{agentA}))
+	})
+
+	It("should apply the filters in the query", func() {
+		agents, err := store.ListAgentsPage(ctx, storage.AgentQuery{ClusterID: "cluster-1", Group: "rack-b"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids(agents)).To(Equal([]string{agentD}))
+
+		agents, err = store.ListAgentsPage(ctx, storage.AgentQuery{UnhealthyBefore: base.Add(90 * time.Second)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids(agents)).To(Equal([]string{agentC, agentB}))
+	})
+
+	It("should keep agents with a down port when asked", func() {
+		agent, err := store.GetAgent(ctx, agentB)
+		Expect(err).NotTo(HaveOccurred())
+		agent.NetworkInterfaces = []*v1.MellanoxNIC{{
+			DeviceName: "mlx5_0",
+			Ports: []*v1.MellanoxPort{
+				{Number: 1, State: v1.PortState_PORT_STATE_UP},
+				{Number: 2, State: v1.PortState_PORT_STATE_DOWN},
+			},
+		}}
+		Expect(store.UpdateAgent(ctx, agent)).To(Succeed())
+		agent, err = store.GetAgent(ctx, agentD)
+		Expect(err).NotTo(HaveOccurred())
+		agent.NetworkInterfaces = []*v1.MellanoxNIC{{
+			DeviceName: "mlx5_0",
+			Ports:      []*v1.MellanoxPort{{Number: 1, State: v1.PortState_PORT_STATE_UP}},
+		}}
+		Expect(store.UpdateAgent(ctx, agent)).To(Succeed())
+
+		agents, err := store.ListAgentsPage(ctx, storage.AgentQuery{DownPortsOnly: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids(agents)).To(Equal([]string{agentB}))
+
+		agents, err = store.ListAgentsPage(ctx, storage.AgentQuery{ClusterID: "cluster-1", Group: "rack-b", DownPortsOnly: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agents).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Agent revisions", func() {
+	It("should reject an update based on a stale read", func() {
+		ctx := context.Background()
+		store := newMigratedStorage()
+
+		now := timestamppb.Now()
+		Expect(store.CreateCluster(ctx, &v1.Cluster{Id: "cluster-1", Name: "test", CreatedAt: now, UpdatedAt: now})).To(Succeed())
+		Expect(store.CreateAgent(ctx, &v1.Agent{
+			Id: "agent-1", ClusterId: "cluster-1", CreatedAt: now, UpdatedAt: now, LastSeen: now,
+		})).To(Succeed())
+
+		poll, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		operator, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+
+		operator.RestartRequested = true
+		Expect(store.UpdateAgent(ctx, operator)).To(Succeed())
+		Expect(operator.Revision).To(Equal(int64(1)))
+
+		poll.LastSeen = timestamppb.Now()
+		Expect(store.UpdateAgent(ctx, poll)).To(MatchError(storage.ErrConflict))
+
+		stored, err := store.GetAgent(ctx, "agent-1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.RestartRequested).To(BeTrue())
+		Expect(stored.Revision).To(Equal(int64(1)))
+
+		Expect(store.UpdateAgent(ctx, &v1.Agent{Id: "missing"})).To(MatchError("agent not found"))
+	})
+})
+
+var _ = Describe("Cluster IDs", func() {
+	It("should store IDs that are not UUIDs", func() {
+		ctx := context.Background()
+		store := newMigratedStorage()
+
+		// Agent IDs are still UUID columns; only cluster IDs follow the scheme
+		agentID := uuid.NewString()
+		now := timestamppb.Now()
+		Expect(store.CreateCluster(ctx, &v1.Cluster{Id: "cl-01JAF8YQ5E7ZK2C0V9N3M4P6RT", Name: "prefixed", CreatedAt: now, UpdatedAt: now})).To(Succeed())
+		Expect(store.CreateAgent(ctx, &v1.Agent{
+			Id: agentID, ClusterId: "cl-01JAF8YQ5E7ZK2C0V9N3M4P6RT", CreatedAt: now, UpdatedAt: now, LastSeen: now,
+		})).To(Succeed())
+
+		Expect(store.MoveCluster(ctx, "cl-01JAF8YQ5E7ZK2C0V9N3M4P6RT", "cl-01JAF8YQ5E7ZK2C0V9N3M4P6RV")).To(Succeed())
+		agents, err := store.ListAgents(ctx, "cl-01JAF8YQ5E7ZK2C0V9N3M4P6RV")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(agents).To(ConsistOf(HaveField("Id", agentID)))
+
+		// Deleting the cluster still removes its agents
+		Expect(store.DeleteCluster(ctx, "cl-01JAF8YQ5E7ZK2C0V9N3M4P6RV")).To(Succeed())
+		_, err = store.GetAgent(ctx, agentID)
+		Expect(err).To(HaveOccurred())
+	})
+})
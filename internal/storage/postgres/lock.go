@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/filanov/netctrl-server/internal/storage"
+)
+
+// advisoryLock is a session-level advisory lock pinned to a pool connection
+type advisoryLock struct {
+	conn *pgxpool.Conn
+	key  int64
+}
+
+// TryLock takes a session-level advisory lock (pg_try_advisory_lock). The
+// lock lives as long as the dedicated connection it was taken on, so it is
+// also released if this replica dies.
+func (s *Storage) TryLock(ctx context.Context, key int64) (storage.Lock, error) {
+	conn, err := s.pool.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock connection: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&acquired); err != nil {
+		conn.Release()
+		return nil, fmt.Errorf("failed to take advisory lock: %w", err)
+	}
+	if !acquired {
+		conn.Release()
+		return nil, nil
+	}
+
+	return &advisoryLock{conn: conn, key: key}, nil
+}
+
+// Check verifies the connection holding the lock is still alive
+func (l *advisoryLock) Check(ctx context.Context) error {
+	if err := l.conn.Ping(ctx); err != nil {
+		return fmt.Errorf("advisory lock connection lost: %w", err)
+	}
+	return nil
+}
+
+// Unlock releases the advisory lock and returns its connection to the pool
+func (l *advisoryLock) Unlock(ctx context.Context) error {
+	defer l.conn.Release()
+
+	var released bool
+	if err := l.conn.QueryRow(ctx, `SELECT pg_advisory_unlock($1)`, l.key).Scan(&released); err != nil {
+		// Drop the connection so the session, and with it the lock, ends
+		l.conn.Conn().Close(ctx)
+		return fmt.Errorf("failed to release advisory lock: %w", err)
+	}
+	if !released {
+		return fmt.Errorf("advisory lock %d was not held", l.key)
+	}
+	return nil
+}
+
+// Ensure Storage implements storage.Locker interface
+var _ storage.Locker = (*Storage)(nil)
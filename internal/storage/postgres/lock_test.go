@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Advisory lock", func() {
+	const key int64 = 42
+
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should not be acquired by a second holder while the first holds it", func() {
+		first := newEmbeddedStorage()
+		second := newEmbeddedStorage()
+
+		lock, err := first.TryLock(ctx, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lock).NotTo(BeNil())
+		Expect(lock.Check(ctx)).To(Succeed())
+
+		contender, err := second.TryLock(ctx, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(contender).To(BeNil())
+
+		Expect(lock.Unlock(ctx)).To(Succeed())
+
+		contender, err = second.TryLock(ctx, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(contender).NotTo(BeNil())
+		Expect(contender.Unlock(ctx)).To(Succeed())
+	})
+
+	It("should be released when the holder disconnects", func() {
+		first := newEmbeddedStorage()
+		second := newEmbeddedStorage()
+
+		lock, err := first.TryLock(ctx, key)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lock).NotTo(BeNil())
+
+		first.Close()
+
+		Eventually(func() bool {
+			contender, err := second.TryLock(ctx, key)
+			if err != nil || contender == nil {
+				return false
+			}
+			Expect(contender.Unlock(ctx)).To(Succeed())
+			return true
+		}).Should(BeTrue())
+	})
+})
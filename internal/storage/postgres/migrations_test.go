@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// migrationsDir is the repository's migrations directory, relative to this package
+const migrationsDir = "../../../migrations"
+
+// migrationFiles returns the migration files with the given suffix in version order
+func migrationFiles(suffix string) []string {
+	files, err := filepath.Glob(filepath.Join(migrationsDir, "*"+suffix))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(files).NotTo(BeEmpty())
+	sort.Strings(files)
+	return files
+}
+
+// applyMigrations executes each migration file in order on conn
+func applyMigrations(ctx context.Context, conn *pgx.Conn, files []string) {
+	for _, file := range files {
+		sql, err := os.ReadFile(file)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = conn.Exec(ctx, string(sql))
+		Expect(err).NotTo(HaveOccurred(), "applying %s", filepath.Base(file))
+	}
+}
+
+var _ = Describe("Migrations", func() {
+	var (
+		ctx  context.Context
+		conn *pgx.Conn
+	)
+
+	// indexes returns the names of the indexes on table
+	indexes := func(table string) []string {
+		rows, err := conn.Query(ctx, `SELECT indexname FROM pg_indexes WHERE schemaname = 'public' AND tablename = $1`, table)
+		Expect(err).NotTo(HaveOccurred())
+		names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		Expect(err).NotTo(HaveOccurred())
+		return names
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		admin := newEmbeddedStorage()
+
+		// Each spec migrates its own database so schemas never leak between specs
+		dbName := "migrations_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+		_, err := admin.pool.Exec(ctx, "CREATE DATABASE "+dbName)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(func() {
+			_, err := admin.pool.Exec(context.Background(), "DROP DATABASE IF EXISTS "+dbName+" WITH (FORCE)")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		conn, err = pgx.Connect(ctx, strings.Replace(embeddedURL, "/postgres?", fmt.Sprintf("/%s?", dbName), 1))
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(conn.Close, context.Background())
+
+		applyMigrations(ctx, conn, migrationFiles(".up.sql"))
+	})
+
+	It("should index the columns the list filters query", func() {
+		Expect(indexes("agents")).To(ContainElements(
+			"idx_agents_cluster",
+			"idx_agents_status",
+			"idx_agents_last_seen",
+			"idx_agents_cluster_ip",
+			"idx_agents_network_interfaces",
+			"idx_agents_cluster_created",
+			"idx_agents_created",
+			"idx_agents_created_id",
+			"idx_agents_cluster_created_id",
+		))
+		Expect(indexes("clusters")).To(ContainElements(
+			"idx_clusters_name",
+			"idx_clusters_created",
+		))
+	})
+
+	It("should drop the list query indexes when rolled back", func() {
+		applyMigrations(ctx, conn, []string{filepath.Join(migrationsDir, "008_list_query_indexes.down.sql")})
+
+		Expect(indexes("agents")).NotTo(ContainElement("idx_agents_cluster_created"))
+		Expect(indexes("clusters")).NotTo(ContainElement("idx_clusters_created"))
+	})
+})
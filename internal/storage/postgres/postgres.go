@@ -3,6 +3,8 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
@@ -11,20 +13,87 @@ import (
 
 // Storage implements the storage.Storage interface using PostgreSQL
 type Storage struct {
-	pool *pgxpool.Pool
+	pool   *pgxpool.Pool
+	limits sizeLimits
 }
 
 // Config holds PostgreSQL configuration
 type Config struct {
 	URL             string
 	MaxConnIdleTime string
+	MaxConnLifetime string
 	ConnectTimeout  string
 	MaxConnections  int32
 	MinConnections  int32
+
+	// WarmUp opens MinConnections connections before New returns instead of
+	// letting the pool create them lazily under the first burst of load
+	WarmUp bool
+
+	// AllowUnavailable lets New return while the database is unreachable;
+	// the pool connects once it comes up and calls fail until then
+	AllowUnavailable bool
+
+	// Size limits enforced on every write; zero uses the defaults
+	MaxDescriptionBytes       int
+	MaxNetworkInterfacesBytes int
 }
 
 // New creates a new PostgreSQL storage instance
 func New(ctx context.Context, cfg Config) (*Storage, error) {
+	config, err := buildPoolConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create connection pool
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create connection pool: %w", err)
+	}
+
+	// Test connection
+	if err := pool.Ping(ctx); err != nil {
+		if !cfg.AllowUnavailable {
+			pool.Close()
+			return nil, fmt.Errorf("unable to connect to database: %w", err)
+		}
+		log.Printf("Database unreachable, connecting once it is up: %v", err)
+		return &Storage{pool: pool, limits: newSizeLimits(cfg)}, nil
+	}
+
+	if cfg.WarmUp {
+		if err := warmUp(ctx, pool, config.MinConns); err != nil {
+			pool.Close()
+			return nil, err
+		}
+	}
+
+	return &Storage{pool: pool, limits: newSizeLimits(cfg)}, nil
+}
+
+// warmUp opens n connections by holding n acquisitions at once, then returns
+// them to the pool as idle connections
+func warmUp(ctx context.Context, pool *pgxpool.Pool, n int32) error {
+	conns := make([]*pgxpool.Conn, 0, n)
+	defer func() {
+		for _, conn := range conns {
+			conn.Release()
+		}
+	}()
+
+	for i := int32(0); i < n; i++ {
+		conn, err := pool.Acquire(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to warm up connection pool (%d of %d connections opened): %w", i, n, err)
+		}
+		conns = append(conns, conn)
+	}
+	return nil
+}
+
+// buildPoolConfig parses the connection string and applies pool settings
+func buildPoolConfig(cfg Config) (*pgxpool.Config, error) {
 	// Parse connection string
 	config, err := pgxpool.ParseConfig(cfg.URL)
 	if err != nil {
@@ -39,18 +108,53 @@ func New(ctx context.Context, cfg Config) (*Storage, error) {
 		config.MinConns = cfg.MinConnections
 	}
 
-	// Create connection pool
-	pool, err := pgxpool.NewWithConfig(ctx, config)
+	maxConnIdleTime, err := parseDuration("max_conn_idle_time", cfg.MaxConnIdleTime)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create connection pool: %w", err)
+		return nil, err
+	}
+	if maxConnIdleTime > 0 {
+		config.MaxConnIdleTime = maxConnIdleTime
 	}
 
-	// Test connection
-	if err := pool.Ping(ctx); err != nil {
-		return nil, fmt.Errorf("unable to connect to database: %w", err)
+	maxConnLifetime, err := parseDuration("max_conn_lifetime", cfg.MaxConnLifetime)
+	if err != nil {
+		return nil, err
+	}
+	if maxConnLifetime > 0 {
+		config.MaxConnLifetime = maxConnLifetime
+	}
+
+	connectTimeout, err := parseDuration("connect_timeout", cfg.ConnectTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if connectTimeout > 0 {
+		config.ConnConfig.ConnectTimeout = connectTimeout
+	}
+
+	return config, nil
+}
+
+// parseDuration parses an optional duration setting; empty means unset
+func parseDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid database %s %q: %w", name, value, err)
 	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid database %s %q: must not be negative", name, value)
+	}
+
+	return d, nil
+}
 
-	return &Storage{pool: pool}, nil
+// Ping checks that the database is reachable
+func (s *Storage) Ping(ctx context.Context) error {
+	return s.pool.Ping(ctx)
 }
 
 // Close closes the database connection pool
@@ -58,5 +162,19 @@ func (s *Storage) Close() {
 	s.pool.Close()
 }
 
+// PoolStats reports the state of the database connection pool
+func (s *Storage) PoolStats() storage.PoolStats {
+	stat := s.pool.Stat()
+	return storage.PoolStats{
+		TotalConns:    stat.TotalConns(),
+		IdleConns:     stat.IdleConns(),
+		AcquiredConns: stat.AcquiredConns(),
+		MaxConns:      stat.MaxConns(),
+		AcquireCount:  stat.AcquireCount(),
+	}
+}
+
 // Ensure Storage implements storage.Storage interface
 var _ storage.Storage = (*Storage)(nil)
+var _ storage.PoolStater = (*Storage)(nil)
+var _ storage.HardwareSummarizer = (*Storage)(nil)
@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPostgresSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Postgres Suite")
+}
+
+var (
+	// embeddedDB is the embedded PostgreSQL server shared by the suite
+	embeddedDB *embeddedpostgres.EmbeddedPostgres
+	// embeddedURL is the connection URL of embeddedDB
+	embeddedURL string
+	// embeddedErr records why the embedded server could not start
+	embeddedErr error
+)
+
+var _ = BeforeSuite(func() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	port := uint32(listener.Addr().(*net.TCPAddr).Port)
+	Expect(listener.Close()).To(Succeed())
+
+	cfg := embeddedpostgres.DefaultConfig().
+		Port(port).
+		RuntimePath(GinkgoT().TempDir()).
+		Logger(io.Discard)
+	embeddedDB = embeddedpostgres.NewDatabase(cfg)
+	if embeddedErr = embeddedDB.Start(); embeddedErr != nil {
+		embeddedDB = nil
+		return
+	}
+	embeddedURL = cfg.GetConnectionURL() + "?sslmode=disable"
+})
+
+var _ = AfterSuite(func() {
+	if embeddedDB != nil {
+		Expect(embeddedDB.Stop()).To(Succeed())
+	}
+})
+
+// newEmbeddedStorage connects a new Storage to the embedded server, skipping
+// the spec when the server is unavailable (e.g. binaries cannot be fetched)
+func newEmbeddedStorage() *Storage {
+	if embeddedDB == nil {
+		Skip("embedded postgres unavailable: " + embeddedErr.Error())
+	}
+
+	store, err := New(context.Background(), Config{URL: embeddedURL, MaxConnections: 4})
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(store.Close)
+	return store
+}
+
+// newMigratedStorage connects a new Storage to a fresh database on the
+// embedded server with all up migrations applied
+func newMigratedStorage() *Storage {
+	ctx := context.Background()
+	admin := newEmbeddedStorage()
+
+	dbName := "storage_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	_, err := admin.pool.Exec(ctx, "CREATE DATABASE "+dbName)
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(func() {
+		_, err := admin.pool.Exec(context.Background(), "DROP DATABASE IF EXISTS "+dbName+" WITH (FORCE)")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	url := strings.Replace(embeddedURL, "/postgres?", fmt.Sprintf("/%s?", dbName), 1)
+	conn, err := pgx.Connect(ctx, url)
+	Expect(err).NotTo(HaveOccurred())
+	applyMigrations(ctx, conn, migrationFiles(".up.sql"))
+	Expect(conn.Close(ctx)).To(Succeed())
+
+	store, err := New(ctx, Config{URL: url, MaxConnections: 4})
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(store.Close)
+	return store
+}
@@ -0,0 +1,2 @@
+-- Cordon flag suppresses instruction generation for a cluster's agents
+ALTER TABLE clusters ADD COLUMN cordoned BOOLEAN NOT NULL DEFAULT false;
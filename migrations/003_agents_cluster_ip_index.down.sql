@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_agents_cluster_ip;
@@ -0,0 +1,2 @@
+-- Supports IP uniqueness lookups within a cluster
+CREATE INDEX IF NOT EXISTS idx_agents_cluster_ip ON agents(cluster_id, ip_address);
@@ -0,0 +1,2 @@
+-- Network role of the node running the agent
+ALTER TABLE agents ADD COLUMN role TEXT NOT NULL DEFAULT 'AGENT_ROLE_UNSPECIFIED';
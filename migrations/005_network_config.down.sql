@@ -0,0 +1,3 @@
+ALTER TABLE agents DROP COLUMN IF EXISTS last_gateway_probe_at;
+ALTER TABLE agents DROP COLUMN IF EXISTS last_gateway_probe;
+ALTER TABLE clusters DROP COLUMN IF EXISTS network_config;
@@ -0,0 +1,6 @@
+-- Cluster network configuration (CIDR and gateway)
+ALTER TABLE clusters ADD COLUMN network_config JSONB;
+
+-- Latest gateway probe outcome reported by each agent
+ALTER TABLE agents ADD COLUMN last_gateway_probe JSONB;
+ALTER TABLE agents ADD COLUMN last_gateway_probe_at TIMESTAMPTZ;
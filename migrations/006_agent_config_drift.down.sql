@@ -0,0 +1,2 @@
+ALTER TABLE agents DROP COLUMN IF EXISTS config_drifted;
+ALTER TABLE agents DROP COLUMN IF EXISTS applied_network_config;
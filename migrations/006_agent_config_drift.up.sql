@@ -0,0 +1,4 @@
+-- Network config each agent reports as applied, and whether it has drifted
+-- from its cluster's desired config
+ALTER TABLE agents ADD COLUMN applied_network_config JSONB;
+ALTER TABLE agents ADD COLUMN config_drifted BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+ALTER TABLE agents DROP COLUMN IF EXISTS metrics_reported_at;
+ALTER TABLE agents DROP COLUMN IF EXISTS metrics;
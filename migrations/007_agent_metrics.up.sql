@@ -0,0 +1,3 @@
+-- Latest runtime metrics snapshot reported by each agent on poll
+ALTER TABLE agents ADD COLUMN metrics JSONB;
+ALTER TABLE agents ADD COLUMN metrics_reported_at TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_clusters_created;
+DROP INDEX IF EXISTS idx_agents_created;
+DROP INDEX IF EXISTS idx_agents_cluster_created;
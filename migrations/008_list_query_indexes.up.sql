@@ -0,0 +1,6 @@
+-- The cluster_id, status, last_seen and name indexes come with the initial
+-- schema; these cover the ordering used by the list queries so that a
+-- filtered, newest-first page does not sort the whole table
+CREATE INDEX IF NOT EXISTS idx_agents_cluster_created ON agents(cluster_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_agents_created ON agents(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_clusters_created ON clusters(created_at DESC);
@@ -0,0 +1,2 @@
+-- Operator-defined subset of a cluster (e.g. rack or zone); "group" is reserved
+ALTER TABLE agents ADD COLUMN agent_group TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- Outcome of each agent's most recent result per instruction type
+ALTER TABLE agents ADD COLUMN last_outcomes JSONB;
@@ -0,0 +1,2 @@
+-- Agents marked for decommission are unregistered once cleanup is confirmed
+ALTER TABLE agents ADD COLUMN decommissioning BOOLEAN NOT NULL DEFAULT false;
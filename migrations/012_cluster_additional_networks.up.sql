@@ -0,0 +1,2 @@
+-- Named networks of a cluster beyond its primary network_config
+ALTER TABLE clusters ADD COLUMN additional_networks JSONB;
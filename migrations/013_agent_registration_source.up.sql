@@ -0,0 +1,2 @@
+-- Origin of each agent's most recent registration, for forensics
+ALTER TABLE agents ADD COLUMN last_registration JSONB;
@@ -0,0 +1,3 @@
+ALTER TABLE agents DROP COLUMN IF EXISTS poll_interval_drifted;
+ALTER TABLE agents DROP COLUMN IF EXISTS reported_poll_interval_seconds;
+ALTER TABLE agents DROP COLUMN IF EXISTS instructed_poll_interval_seconds;
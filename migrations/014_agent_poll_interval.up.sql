@@ -0,0 +1,4 @@
+-- Instructed and agent-reported poll intervals, for detecting agents polling off cadence
+ALTER TABLE agents ADD COLUMN instructed_poll_interval_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE agents ADD COLUMN reported_poll_interval_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE agents ADD COLUMN poll_interval_drifted BOOLEAN NOT NULL DEFAULT false;
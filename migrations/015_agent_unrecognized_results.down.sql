@@ -0,0 +1 @@
+ALTER TABLE agents DROP COLUMN IF EXISTS unrecognized_results;
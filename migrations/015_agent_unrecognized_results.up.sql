@@ -0,0 +1,2 @@
+-- Raw results of instruction types the server did not recognize
+ALTER TABLE agents ADD COLUMN unrecognized_results JSONB;
@@ -0,0 +1,2 @@
+-- Last healthy health check, for finding reachable-but-unhealthy agents
+ALTER TABLE agents ADD COLUMN last_healthy_at TIMESTAMPTZ;
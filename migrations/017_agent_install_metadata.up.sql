@@ -0,0 +1,2 @@
+-- How the agent was installed, recorded at first registration
+ALTER TABLE agents ADD COLUMN install_metadata JSONB;
@@ -0,0 +1,2 @@
+-- NICs the most recent hardware collection failed to collect
+ALTER TABLE agents ADD COLUMN failed_nics JSONB;
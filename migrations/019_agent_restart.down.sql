@@ -0,0 +1,2 @@
+ALTER TABLE agents DROP COLUMN IF EXISTS last_restart_at;
+ALTER TABLE agents DROP COLUMN IF EXISTS restart_requested;
@@ -0,0 +1,4 @@
+-- Restart requested by an operator and not yet confirmed by the agent
+ALTER TABLE agents ADD COLUMN restart_requested BOOLEAN NOT NULL DEFAULT false;
+-- When the agent last confirmed initiating a requested restart
+ALTER TABLE agents ADD COLUMN last_restart_at TIMESTAMPTZ;
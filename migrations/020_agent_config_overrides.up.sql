@@ -0,0 +1,2 @@
+-- Per-agent settings taking precedence over the fleet defaults
+ALTER TABLE agents ADD COLUMN config_overrides JSONB;
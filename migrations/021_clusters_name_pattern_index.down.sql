@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_clusters_name_pattern;
@@ -0,0 +1,4 @@
+-- idx_clusters_name only serves prefix (LIKE 'x%') matches under the C
+-- collation; text_pattern_ops lets the ListClusters name prefix filter use
+-- an index whatever the database collation
+CREATE INDEX IF NOT EXISTS idx_clusters_name_pattern ON clusters(name text_pattern_ops);
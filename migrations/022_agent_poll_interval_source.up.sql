@@ -0,0 +1,2 @@
+-- Factor that determined the instructed poll interval on the most recent poll
+ALTER TABLE agents ADD COLUMN poll_interval_source TEXT NOT NULL DEFAULT 'POLL_INTERVAL_SOURCE_UNSPECIFIED';
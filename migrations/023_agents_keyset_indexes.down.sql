@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_agents_cluster_created_id;
+DROP INDEX IF EXISTS idx_agents_created_id;
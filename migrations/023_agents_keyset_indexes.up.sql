@@ -0,0 +1,4 @@
+-- Keyset pagination reads agents in (created_at, id) order, within a cluster
+-- or across the fleet, starting after the previous page's last key
+CREATE INDEX IF NOT EXISTS idx_agents_created_id ON agents(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_agents_cluster_created_id ON agents(cluster_id, created_at, id);
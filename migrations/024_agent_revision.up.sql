@@ -0,0 +1,2 @@
+-- Incremented on every agent write; updates apply only at the revision they read
+ALTER TABLE agents ADD COLUMN revision BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- Fails if any cluster ID is not a UUID
+ALTER TABLE agents DROP CONSTRAINT IF EXISTS agents_cluster_id_fkey;
+ALTER TABLE agents ALTER COLUMN cluster_id TYPE UUID USING cluster_id::uuid;
+ALTER TABLE clusters ALTER COLUMN id TYPE UUID USING id::uuid;
+ALTER TABLE agents ADD CONSTRAINT agents_cluster_id_fkey
+    FOREIGN KEY (cluster_id) REFERENCES clusters(id) ON DELETE CASCADE;
@@ -0,0 +1,7 @@
+-- Cluster IDs follow the configured ID scheme (UUID or prefixed ULID), so
+-- they are stored as text; the foreign key is recreated for the new type
+ALTER TABLE agents DROP CONSTRAINT IF EXISTS agents_cluster_id_fkey;
+ALTER TABLE clusters ALTER COLUMN id TYPE TEXT;
+ALTER TABLE agents ALTER COLUMN cluster_id TYPE TEXT;
+ALTER TABLE agents ADD CONSTRAINT agents_cluster_id_fkey
+    FOREIGN KEY (cluster_id) REFERENCES clusters(id) ON DELETE CASCADE;
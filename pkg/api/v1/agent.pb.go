@@ -10,6 +10,7 @@ import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -29,7 +30,12 @@ type AgentStatus int32
 const (
 	AgentStatus_AGENT_STATUS_UNSPECIFIED AgentStatus = 0
 	AgentStatus_AGENT_STATUS_ACTIVE      AgentStatus = 1
-	AgentStatus_AGENT_STATUS_INACTIVE    AgentStatus = 2
+	// INACTIVE agents missed a few polls
+	AgentStatus_AGENT_STATUS_INACTIVE AgentStatus = 2
+	// STALE agents have been silent long enough that their state is unknown
+	AgentStatus_AGENT_STATUS_STALE AgentStatus = 3
+	// EXPIRED agents have been silent long enough to be eligible for cleanup
+	AgentStatus_AGENT_STATUS_EXPIRED AgentStatus = 4
 )
 
 // Enum value maps for AgentStatus.
@@ -38,11 +44,15 @@ var (
 		0: "AGENT_STATUS_UNSPECIFIED",
 		1: "AGENT_STATUS_ACTIVE",
 		2: "AGENT_STATUS_INACTIVE",
+		3: "AGENT_STATUS_STALE",
+		4: "AGENT_STATUS_EXPIRED",
 	}
 	AgentStatus_value = map[string]int32{
 		"AGENT_STATUS_UNSPECIFIED": 0,
 		"AGENT_STATUS_ACTIVE":      1,
 		"AGENT_STATUS_INACTIVE":    2,
+		"AGENT_STATUS_STALE":       3,
+		"AGENT_STATUS_EXPIRED":     4,
 	}
 )
 
@@ -73,6 +83,120 @@ func (AgentStatus) EnumDescriptor() ([]byte, []int) {
 	return file_v1_agent_proto_rawDescGZIP(), []int{0}
 }
 
+// AgentRole represents the network role of the node running an agent
+type AgentRole int32
+
+const (
+	AgentRole_AGENT_ROLE_UNSPECIFIED AgentRole = 0
+	AgentRole_AGENT_ROLE_SPINE       AgentRole = 1
+	AgentRole_AGENT_ROLE_LEAF        AgentRole = 2
+)
+
+// Enum value maps for AgentRole.
+var (
+	AgentRole_name = map[int32]string{
+		0: "AGENT_ROLE_UNSPECIFIED",
+		1: "AGENT_ROLE_SPINE",
+		2: "AGENT_ROLE_LEAF",
+	}
+	AgentRole_value = map[string]int32{
+		"AGENT_ROLE_UNSPECIFIED": 0,
+		"AGENT_ROLE_SPINE":       1,
+		"AGENT_ROLE_LEAF":        2,
+	}
+)
+
+func (x AgentRole) Enum() *AgentRole {
+	p := new(AgentRole)
+	*p = x
+	return p
+}
+
+func (x AgentRole) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AgentRole) Descriptor() protoreflect.EnumDescriptor {
+	return file_v1_agent_proto_enumTypes[1].Descriptor()
+}
+
+func (AgentRole) Type() protoreflect.EnumType {
+	return &file_v1_agent_proto_enumTypes[1]
+}
+
+func (x AgentRole) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AgentRole.Descriptor instead.
+func (AgentRole) EnumDescriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{1}
+}
+
+// PollIntervalSource identifies the factor that determined an agent's
+// effective poll interval. The first that applies wins, in the order
+// throttle, backoff, agent override, default.
+type PollIntervalSource int32
+
+const (
+	// The agent has not polled since the source was tracked
+	PollIntervalSource_POLL_INTERVAL_SOURCE_UNSPECIFIED PollIntervalSource = 0
+	// The server's default poll interval
+	PollIntervalSource_POLL_INTERVAL_SOURCE_DEFAULT PollIntervalSource = 1
+	// The agent's poll interval override, longer than the default
+	PollIntervalSource_POLL_INTERVAL_SOURCE_AGENT_OVERRIDE PollIntervalSource = 2
+	// Fleet-wide throttle mode
+	PollIntervalSource_POLL_INTERVAL_SOURCE_THROTTLE PollIntervalSource = 3
+	// A failed instruction's retry backoff; the agent polls again when the
+	// earliest retry is due, within bounds
+	PollIntervalSource_POLL_INTERVAL_SOURCE_BACKOFF PollIntervalSource = 4
+)
+
+// Enum value maps for PollIntervalSource.
+var (
+	PollIntervalSource_name = map[int32]string{
+		0: "POLL_INTERVAL_SOURCE_UNSPECIFIED",
+		1: "POLL_INTERVAL_SOURCE_DEFAULT",
+		2: "POLL_INTERVAL_SOURCE_AGENT_OVERRIDE",
+		3: "POLL_INTERVAL_SOURCE_THROTTLE",
+		4: "POLL_INTERVAL_SOURCE_BACKOFF",
+	}
+	PollIntervalSource_value = map[string]int32{
+		"POLL_INTERVAL_SOURCE_UNSPECIFIED":    0,
+		"POLL_INTERVAL_SOURCE_DEFAULT":        1,
+		"POLL_INTERVAL_SOURCE_AGENT_OVERRIDE": 2,
+		"POLL_INTERVAL_SOURCE_THROTTLE":       3,
+		"POLL_INTERVAL_SOURCE_BACKOFF":        4,
+	}
+)
+
+func (x PollIntervalSource) Enum() *PollIntervalSource {
+	p := new(PollIntervalSource)
+	*p = x
+	return p
+}
+
+func (x PollIntervalSource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PollIntervalSource) Descriptor() protoreflect.EnumDescriptor {
+	return file_v1_agent_proto_enumTypes[2].Descriptor()
+}
+
+func (PollIntervalSource) Type() protoreflect.EnumType {
+	return &file_v1_agent_proto_enumTypes[2]
+}
+
+func (x PollIntervalSource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PollIntervalSource.Descriptor instead.
+func (PollIntervalSource) EnumDescriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{2}
+}
+
 // PortState represents the operational state of a NIC port
 type PortState int32
 
@@ -110,11 +234,11 @@ func (x PortState) String() string {
 }
 
 func (PortState) Descriptor() protoreflect.EnumDescriptor {
-	return file_v1_agent_proto_enumTypes[1].Descriptor()
+	return file_v1_agent_proto_enumTypes[3].Descriptor()
 }
 
 func (PortState) Type() protoreflect.EnumType {
-	return &file_v1_agent_proto_enumTypes[1]
+	return &file_v1_agent_proto_enumTypes[3]
 }
 
 func (x PortState) Number() protoreflect.EnumNumber {
@@ -123,7 +247,7 @@ func (x PortState) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PortState.Descriptor instead.
 func (PortState) EnumDescriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{1}
+	return file_v1_agent_proto_rawDescGZIP(), []int{3}
 }
 
 // PortSpeed represents the link speed of a port
@@ -178,11 +302,11 @@ func (x PortSpeed) String() string {
 }
 
 func (PortSpeed) Descriptor() protoreflect.EnumDescriptor {
-	return file_v1_agent_proto_enumTypes[2].Descriptor()
+	return file_v1_agent_proto_enumTypes[4].Descriptor()
 }
 
 func (PortSpeed) Type() protoreflect.EnumType {
-	return &file_v1_agent_proto_enumTypes[2]
+	return &file_v1_agent_proto_enumTypes[4]
 }
 
 func (x PortSpeed) Number() protoreflect.EnumNumber {
@@ -191,7 +315,124 @@ func (x PortSpeed) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PortSpeed.Descriptor instead.
 func (PortSpeed) EnumDescriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{2}
+	return file_v1_agent_proto_rawDescGZIP(), []int{4}
+}
+
+// FailureReason classifies why an agent failed to execute an instruction,
+// so the server can decide how soon to retry it
+type FailureReason int32
+
+const (
+	FailureReason_FAILURE_REASON_UNSPECIFIED FailureReason = 0
+	// TIMEOUT failures are transient and retried soon
+	FailureReason_FAILURE_REASON_TIMEOUT FailureReason = 1
+	// PERMISSION_DENIED failures need operator action and are retried rarely
+	FailureReason_FAILURE_REASON_PERMISSION_DENIED FailureReason = 2
+	// NOT_SUPPORTED instructions cannot run on the agent and are retried least often
+	FailureReason_FAILURE_REASON_NOT_SUPPORTED FailureReason = 3
+	// INTERNAL failures are agent-side errors, retried soon
+	FailureReason_FAILURE_REASON_INTERNAL FailureReason = 4
+)
+
+// Enum value maps for FailureReason.
+var (
+	FailureReason_name = map[int32]string{
+		0: "FAILURE_REASON_UNSPECIFIED",
+		1: "FAILURE_REASON_TIMEOUT",
+		2: "FAILURE_REASON_PERMISSION_DENIED",
+		3: "FAILURE_REASON_NOT_SUPPORTED",
+		4: "FAILURE_REASON_INTERNAL",
+	}
+	FailureReason_value = map[string]int32{
+		"FAILURE_REASON_UNSPECIFIED":       0,
+		"FAILURE_REASON_TIMEOUT":           1,
+		"FAILURE_REASON_PERMISSION_DENIED": 2,
+		"FAILURE_REASON_NOT_SUPPORTED":     3,
+		"FAILURE_REASON_INTERNAL":          4,
+	}
+)
+
+func (x FailureReason) Enum() *FailureReason {
+	p := new(FailureReason)
+	*p = x
+	return p
+}
+
+func (x FailureReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FailureReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_v1_agent_proto_enumTypes[5].Descriptor()
+}
+
+func (FailureReason) Type() protoreflect.EnumType {
+	return &file_v1_agent_proto_enumTypes[5]
+}
+
+func (x FailureReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FailureReason.Descriptor instead.
+func (FailureReason) EnumDescriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{5}
+}
+
+// ActivityEventType defines what an agent did
+type ActivityEventType int32
+
+const (
+	ActivityEventType_ACTIVITY_EVENT_TYPE_UNSPECIFIED ActivityEventType = 0
+	// POLL is emitted when the agent polls for instructions
+	ActivityEventType_ACTIVITY_EVENT_TYPE_POLL ActivityEventType = 1
+	// INSTRUCTIONS_ISSUED is emitted when a poll hands the agent instructions
+	ActivityEventType_ACTIVITY_EVENT_TYPE_INSTRUCTIONS_ISSUED ActivityEventType = 2
+	// RESULT_SUBMITTED is emitted when the agent submits an instruction result
+	ActivityEventType_ACTIVITY_EVENT_TYPE_RESULT_SUBMITTED ActivityEventType = 3
+)
+
+// Enum value maps for ActivityEventType.
+var (
+	ActivityEventType_name = map[int32]string{
+		0: "ACTIVITY_EVENT_TYPE_UNSPECIFIED",
+		1: "ACTIVITY_EVENT_TYPE_POLL",
+		2: "ACTIVITY_EVENT_TYPE_INSTRUCTIONS_ISSUED",
+		3: "ACTIVITY_EVENT_TYPE_RESULT_SUBMITTED",
+	}
+	ActivityEventType_value = map[string]int32{
+		"ACTIVITY_EVENT_TYPE_UNSPECIFIED":         0,
+		"ACTIVITY_EVENT_TYPE_POLL":                1,
+		"ACTIVITY_EVENT_TYPE_INSTRUCTIONS_ISSUED": 2,
+		"ACTIVITY_EVENT_TYPE_RESULT_SUBMITTED":    3,
+	}
+)
+
+func (x ActivityEventType) Enum() *ActivityEventType {
+	p := new(ActivityEventType)
+	*p = x
+	return p
+}
+
+func (x ActivityEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ActivityEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_v1_agent_proto_enumTypes[6].Descriptor()
+}
+
+func (ActivityEventType) Type() protoreflect.EnumType {
+	return &file_v1_agent_proto_enumTypes[6]
+}
+
+func (x ActivityEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ActivityEventType.Descriptor instead.
+func (ActivityEventType) EnumDescriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{6}
 }
 
 // InstructionType defines the type of instruction
@@ -205,6 +446,20 @@ const (
 	InstructionType_INSTRUCTION_TYPE_HEALTH_CHECK InstructionType = 2
 	// COLLECT_HARDWARE requests hardware inventory (Mellanox NICs)
 	InstructionType_INSTRUCTION_TYPE_COLLECT_HARDWARE InstructionType = 3
+	// DRAIN instructs the agent to stop work because its cluster is cordoned
+	InstructionType_INSTRUCTION_TYPE_DRAIN InstructionType = 4
+	// PROBE_GATEWAY requests a reachability check of the cluster gateway
+	InstructionType_INSTRUCTION_TYPE_PROBE_GATEWAY InstructionType = 5
+	// APPLY_NETWORK_CONFIG pushes the cluster's desired network config to a drifted agent
+	InstructionType_INSTRUCTION_TYPE_APPLY_NETWORK_CONFIG InstructionType = 6
+	// DECOMMISSION asks an agent marked for decommission to clean up before it is unregistered
+	InstructionType_INSTRUCTION_TYPE_DECOMMISSION InstructionType = 7
+	// RESTART_AGENT asks the agent to restart its own process
+	InstructionType_INSTRUCTION_TYPE_RESTART_AGENT InstructionType = 8
+	// CLUSTER_DELETED tells an agent its cluster no longer exists, so it should
+	// unregister itself and stop polling rather than retry. The payload names
+	// the deleted cluster; no result is expected.
+	InstructionType_INSTRUCTION_TYPE_CLUSTER_DELETED InstructionType = 9
 )
 
 // Enum value maps for InstructionType.
@@ -214,12 +469,24 @@ var (
 		1: "INSTRUCTION_TYPE_POLL_INTERVAL",
 		2: "INSTRUCTION_TYPE_HEALTH_CHECK",
 		3: "INSTRUCTION_TYPE_COLLECT_HARDWARE",
+		4: "INSTRUCTION_TYPE_DRAIN",
+		5: "INSTRUCTION_TYPE_PROBE_GATEWAY",
+		6: "INSTRUCTION_TYPE_APPLY_NETWORK_CONFIG",
+		7: "INSTRUCTION_TYPE_DECOMMISSION",
+		8: "INSTRUCTION_TYPE_RESTART_AGENT",
+		9: "INSTRUCTION_TYPE_CLUSTER_DELETED",
 	}
 	InstructionType_value = map[string]int32{
-		"INSTRUCTION_TYPE_UNSPECIFIED":      0,
-		"INSTRUCTION_TYPE_POLL_INTERVAL":    1,
-		"INSTRUCTION_TYPE_HEALTH_CHECK":     2,
-		"INSTRUCTION_TYPE_COLLECT_HARDWARE": 3,
+		"INSTRUCTION_TYPE_UNSPECIFIED":          0,
+		"INSTRUCTION_TYPE_POLL_INTERVAL":        1,
+		"INSTRUCTION_TYPE_HEALTH_CHECK":         2,
+		"INSTRUCTION_TYPE_COLLECT_HARDWARE":     3,
+		"INSTRUCTION_TYPE_DRAIN":                4,
+		"INSTRUCTION_TYPE_PROBE_GATEWAY":        5,
+		"INSTRUCTION_TYPE_APPLY_NETWORK_CONFIG": 6,
+		"INSTRUCTION_TYPE_DECOMMISSION":         7,
+		"INSTRUCTION_TYPE_RESTART_AGENT":        8,
+		"INSTRUCTION_TYPE_CLUSTER_DELETED":      9,
 	}
 )
 
@@ -234,11 +501,11 @@ func (x InstructionType) String() string {
 }
 
 func (InstructionType) Descriptor() protoreflect.EnumDescriptor {
-	return file_v1_agent_proto_enumTypes[3].Descriptor()
+	return file_v1_agent_proto_enumTypes[7].Descriptor()
 }
 
 func (InstructionType) Type() protoreflect.EnumType {
-	return &file_v1_agent_proto_enumTypes[3]
+	return &file_v1_agent_proto_enumTypes[7]
 }
 
 func (x InstructionType) Number() protoreflect.EnumNumber {
@@ -247,7 +514,66 @@ func (x InstructionType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use InstructionType.Descriptor instead.
 func (InstructionType) EnumDescriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{3}
+	return file_v1_agent_proto_rawDescGZIP(), []int{7}
+}
+
+// FleetReportCategoryType identifies a fleet report category
+type FleetReportCategoryType int32
+
+const (
+	FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_UNSPECIFIED           FleetReportCategoryType = 0
+	FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_INACTIVE              FleetReportCategoryType = 1
+	FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_CONFIG_DRIFTED        FleetReportCategoryType = 2
+	FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_OUTDATED_VERSION      FleetReportCategoryType = 3
+	FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_MISSING_HARDWARE      FleetReportCategoryType = 4
+	FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_POLL_INTERVAL_DRIFTED FleetReportCategoryType = 5
+)
+
+// Enum value maps for FleetReportCategoryType.
+var (
+	FleetReportCategoryType_name = map[int32]string{
+		0: "FLEET_REPORT_CATEGORY_TYPE_UNSPECIFIED",
+		1: "FLEET_REPORT_CATEGORY_TYPE_INACTIVE",
+		2: "FLEET_REPORT_CATEGORY_TYPE_CONFIG_DRIFTED",
+		3: "FLEET_REPORT_CATEGORY_TYPE_OUTDATED_VERSION",
+		4: "FLEET_REPORT_CATEGORY_TYPE_MISSING_HARDWARE",
+		5: "FLEET_REPORT_CATEGORY_TYPE_POLL_INTERVAL_DRIFTED",
+	}
+	FleetReportCategoryType_value = map[string]int32{
+		"FLEET_REPORT_CATEGORY_TYPE_UNSPECIFIED":           0,
+		"FLEET_REPORT_CATEGORY_TYPE_INACTIVE":              1,
+		"FLEET_REPORT_CATEGORY_TYPE_CONFIG_DRIFTED":        2,
+		"FLEET_REPORT_CATEGORY_TYPE_OUTDATED_VERSION":      3,
+		"FLEET_REPORT_CATEGORY_TYPE_MISSING_HARDWARE":      4,
+		"FLEET_REPORT_CATEGORY_TYPE_POLL_INTERVAL_DRIFTED": 5,
+	}
+)
+
+func (x FleetReportCategoryType) Enum() *FleetReportCategoryType {
+	p := new(FleetReportCategoryType)
+	*p = x
+	return p
+}
+
+func (x FleetReportCategoryType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FleetReportCategoryType) Descriptor() protoreflect.EnumDescriptor {
+	return file_v1_agent_proto_enumTypes[8].Descriptor()
+}
+
+func (FleetReportCategoryType) Type() protoreflect.EnumType {
+	return &file_v1_agent_proto_enumTypes[8]
+}
+
+func (x FleetReportCategoryType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FleetReportCategoryType.Descriptor instead.
+func (FleetReportCategoryType) EnumDescriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{8}
 }
 
 // MellanoxPort represents a single port on a Mellanox NIC
@@ -493,8 +819,65 @@ type Agent struct {
 	NetworkInterfaces []*MellanoxNIC `protobuf:"bytes,10,rep,name=network_interfaces,json=networkInterfaces,proto3" json:"network_interfaces,omitempty"`
 	// Whether hardware collection has been completed (true even if no NICs found)
 	HardwareCollected bool `protobuf:"varint,11,opt,name=hardware_collected,json=hardwareCollected,proto3" json:"hardware_collected,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// Network role of the node (affects generated instructions)
+	Role AgentRole `protobuf:"varint,12,opt,name=role,proto3,enum=netctrl.v1.AgentRole" json:"role,omitempty"`
+	// Outcome of the most recent cluster gateway probe
+	LastGatewayProbe *GatewayProbeResult `protobuf:"bytes,13,opt,name=last_gateway_probe,json=lastGatewayProbe,proto3" json:"last_gateway_probe,omitempty"`
+	// When the most recent gateway probe result was received
+	LastGatewayProbeAt *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=last_gateway_probe_at,json=lastGatewayProbeAt,proto3" json:"last_gateway_probe_at,omitempty"`
+	// Network config the agent last reported as applied
+	AppliedNetworkConfig *NetworkConfig `protobuf:"bytes,15,opt,name=applied_network_config,json=appliedNetworkConfig,proto3" json:"applied_network_config,omitempty"`
+	// Whether the applied network config differs from the cluster's desired config
+	ConfigDrifted bool `protobuf:"varint,16,opt,name=config_drifted,json=configDrifted,proto3" json:"config_drifted,omitempty"`
+	// Latest runtime metrics snapshot reported on poll (e.g. cpu, memory, load)
+	Metrics map[string]float64 `protobuf:"bytes,17,rep,name=metrics,proto3" json:"metrics,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	// When the metrics snapshot was reported
+	MetricsReportedAt *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=metrics_reported_at,json=metricsReportedAt,proto3" json:"metrics_reported_at,omitempty"`
+	// Operator-defined subset of the cluster, e.g. a rack or zone
+	Group string `protobuf:"bytes,19,opt,name=group,proto3" json:"group,omitempty"`
+	// Outcome of the most recent result per instruction type
+	LastOutcomes []*InstructionOutcome `protobuf:"bytes,20,rep,name=last_outcomes,json=lastOutcomes,proto3" json:"last_outcomes,omitempty"`
+	// Whether the agent is being decommissioned; it is unregistered once it
+	// confirms cleanup
+	Decommissioning bool `protobuf:"varint,21,opt,name=decommissioning,proto3" json:"decommissioning,omitempty"`
+	// Where the agent's most recent registration came from, for forensics
+	LastRegistration *RegistrationSource `protobuf:"bytes,22,opt,name=last_registration,json=lastRegistration,proto3" json:"last_registration,omitempty"`
+	// Effective poll interval the server handed the agent on its most recent
+	// poll, resolved from throttle mode, the agent's override and the default
+	InstructedPollIntervalSeconds int32 `protobuf:"varint,23,opt,name=instructed_poll_interval_seconds,json=instructedPollIntervalSeconds,proto3" json:"instructed_poll_interval_seconds,omitempty"`
+	// Poll interval the agent reports it is configured with; 0 if not reported
+	ReportedPollIntervalSeconds int32 `protobuf:"varint,24,opt,name=reported_poll_interval_seconds,json=reportedPollIntervalSeconds,proto3" json:"reported_poll_interval_seconds,omitempty"`
+	// Whether the reported poll interval is far off the instructed one, as
+	// flagged by the agent monitor
+	PollIntervalDrifted bool `protobuf:"varint,25,opt,name=poll_interval_drifted,json=pollIntervalDrifted,proto3" json:"poll_interval_drifted,omitempty"`
+	// Most recent results of instruction types the server does not recognize,
+	// kept when the unknown result policy is store_raw
+	UnrecognizedResults []*RawInstructionResult `protobuf:"bytes,26,rep,name=unrecognized_results,json=unrecognizedResults,proto3" json:"unrecognized_results,omitempty"`
+	// When the agent last reported a healthy health check; unset if it never has
+	LastHealthyAt *timestamppb.Timestamp `protobuf:"bytes,27,opt,name=last_healthy_at,json=lastHealthyAt,proto3" json:"last_healthy_at,omitempty"`
+	// How and by whom the agent was installed, recorded at first registration
+	// and never overwritten by re-registrations
+	InstallMetadata *InstallMetadata `protobuf:"bytes,28,opt,name=install_metadata,json=installMetadata,proto3" json:"install_metadata,omitempty"`
+	// NICs the most recent hardware collection failed to collect; while any
+	// are listed hardware_collected stays false
+	FailedNics []*NICCollectionFailure `protobuf:"bytes,29,rep,name=failed_nics,json=failedNics,proto3" json:"failed_nics,omitempty"`
+	// Whether an operator asked the agent to restart and it has not yet
+	// confirmed initiating the restart
+	RestartRequested bool `protobuf:"varint,30,opt,name=restart_requested,json=restartRequested,proto3" json:"restart_requested,omitempty"`
+	// When the agent last confirmed initiating a requested restart
+	LastRestartAt *timestamppb.Timestamp `protobuf:"bytes,31,opt,name=last_restart_at,json=lastRestartAt,proto3" json:"last_restart_at,omitempty"`
+	// Per-agent settings taking precedence over the fleet defaults; unset if
+	// the agent has none
+	ConfigOverrides *AgentConfigOverrides `protobuf:"bytes,32,opt,name=config_overrides,json=configOverrides,proto3" json:"config_overrides,omitempty"`
+	// Which factor determined instructed_poll_interval_seconds on the most
+	// recent poll
+	PollIntervalSource PollIntervalSource `protobuf:"varint,33,opt,name=poll_interval_source,json=pollIntervalSource,proto3,enum=netctrl.v1.PollIntervalSource" json:"poll_interval_source,omitempty"`
+	// Incremented on every write. An update only applies while it matches the
+	// stored revision, so a write based on a stale read is rejected instead of
+	// erasing changes made since.
+	Revision      int64 `protobuf:"varint,34,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Agent) Reset() {
@@ -604,157 +987,3304 @@ func (x *Agent) GetHardwareCollected() bool {
 	return false
 }
 
-// RegisterAgentRequest contains parameters for registering an agent
-type RegisterAgentRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Agent-generated UUID (required)
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// Cluster ID (required)
-	ClusterId string `protobuf:"bytes,2,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
-	// Node hostname (optional)
-	Hostname string `protobuf:"bytes,3,opt,name=hostname,proto3" json:"hostname,omitempty"`
-	// Node IP address (optional)
-	IpAddress string `protobuf:"bytes,4,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
-	// Agent version (optional)
-	Version       string `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Agent) GetRole() AgentRole {
+	if x != nil {
+		return x.Role
+	}
+	return AgentRole_AGENT_ROLE_UNSPECIFIED
 }
 
-func (x *RegisterAgentRequest) Reset() {
-	*x = RegisterAgentRequest{}
-	mi := &file_v1_agent_proto_msgTypes[3]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Agent) GetLastGatewayProbe() *GatewayProbeResult {
+	if x != nil {
+		return x.LastGatewayProbe
+	}
+	return nil
 }
 
-func (x *RegisterAgentRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Agent) GetLastGatewayProbeAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastGatewayProbeAt
+	}
+	return nil
 }
 
-func (*RegisterAgentRequest) ProtoMessage() {}
-
-func (x *RegisterAgentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[3]
+func (x *Agent) GetAppliedNetworkConfig() *NetworkConfig {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.AppliedNetworkConfig
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use RegisterAgentRequest.ProtoReflect.Descriptor instead.
-func (*RegisterAgentRequest) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{3}
+func (x *Agent) GetConfigDrifted() bool {
+	if x != nil {
+		return x.ConfigDrifted
+	}
+	return false
 }
 
-func (x *RegisterAgentRequest) GetId() string {
+func (x *Agent) GetMetrics() map[string]float64 {
 	if x != nil {
-		return x.Id
+		return x.Metrics
 	}
-	return ""
+	return nil
 }
 
-func (x *RegisterAgentRequest) GetClusterId() string {
+func (x *Agent) GetMetricsReportedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.ClusterId
+		return x.MetricsReportedAt
 	}
-	return ""
+	return nil
 }
 
-func (x *RegisterAgentRequest) GetHostname() string {
+func (x *Agent) GetGroup() string {
 	if x != nil {
-		return x.Hostname
+		return x.Group
 	}
 	return ""
 }
 
-func (x *RegisterAgentRequest) GetIpAddress() string {
+func (x *Agent) GetLastOutcomes() []*InstructionOutcome {
 	if x != nil {
-		return x.IpAddress
+		return x.LastOutcomes
 	}
-	return ""
+	return nil
 }
 
-func (x *RegisterAgentRequest) GetVersion() string {
+func (x *Agent) GetDecommissioning() bool {
 	if x != nil {
-		return x.Version
+		return x.Decommissioning
 	}
-	return ""
+	return false
 }
 
-// RegisterAgentResponse returns the registered agent
-type RegisterAgentResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Agent         *Agent                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Agent) GetLastRegistration() *RegistrationSource {
+	if x != nil {
+		return x.LastRegistration
+	}
+	return nil
 }
 
-func (x *RegisterAgentResponse) Reset() {
-	*x = RegisterAgentResponse{}
-	mi := &file_v1_agent_proto_msgTypes[4]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Agent) GetInstructedPollIntervalSeconds() int32 {
+	if x != nil {
+		return x.InstructedPollIntervalSeconds
+	}
+	return 0
 }
 
-func (x *RegisterAgentResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Agent) GetReportedPollIntervalSeconds() int32 {
+	if x != nil {
+		return x.ReportedPollIntervalSeconds
+	}
+	return 0
 }
 
-func (*RegisterAgentResponse) ProtoMessage() {}
-
-func (x *RegisterAgentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[4]
+func (x *Agent) GetPollIntervalDrifted() bool {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.PollIntervalDrifted
 	}
-	return mi.MessageOf(x)
+	return false
 }
 
-// Deprecated: Use RegisterAgentResponse.ProtoReflect.Descriptor instead.
-func (*RegisterAgentResponse) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{4}
+func (x *Agent) GetUnrecognizedResults() []*RawInstructionResult {
+	if x != nil {
+		return x.UnrecognizedResults
+	}
+	return nil
 }
 
-func (x *RegisterAgentResponse) GetAgent() *Agent {
+func (x *Agent) GetLastHealthyAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Agent
+		return x.LastHealthyAt
 	}
 	return nil
 }
 
-// GetAgentRequest contains parameters for retrieving an agent
-type GetAgentRequest struct {
+func (x *Agent) GetInstallMetadata() *InstallMetadata {
+	if x != nil {
+		return x.InstallMetadata
+	}
+	return nil
+}
+
+func (x *Agent) GetFailedNics() []*NICCollectionFailure {
+	if x != nil {
+		return x.FailedNics
+	}
+	return nil
+}
+
+func (x *Agent) GetRestartRequested() bool {
+	if x != nil {
+		return x.RestartRequested
+	}
+	return false
+}
+
+func (x *Agent) GetLastRestartAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastRestartAt
+	}
+	return nil
+}
+
+func (x *Agent) GetConfigOverrides() *AgentConfigOverrides {
+	if x != nil {
+		return x.ConfigOverrides
+	}
+	return nil
+}
+
+func (x *Agent) GetPollIntervalSource() PollIntervalSource {
+	if x != nil {
+		return x.PollIntervalSource
+	}
+	return PollIntervalSource_POLL_INTERVAL_SOURCE_UNSPECIFIED
+}
+
+func (x *Agent) GetRevision() int64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+// AgentConfigOverrides adjusts instruction delivery for a single agent
+type AgentConfigOverrides struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// ID of the agent to retrieve
-	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Poll interval handed to the agent; 0 keeps the default. It may only
+	// lengthen the interval; throttle mode and retry backoff take precedence.
+	// Agents polling less often than the inactive threshold are marked
+	// inactive between polls.
+	PollIntervalSeconds int32 `protobuf:"varint,1,opt,name=poll_interval_seconds,json=pollIntervalSeconds,proto3" json:"poll_interval_seconds,omitempty"`
+	// Instruction types never generated for the agent. Only the periodic
+	// types (COLLECT_HARDWARE, HEALTH_CHECK, PROBE_GATEWAY and
+	// APPLY_NETWORK_CONFIG) may be disabled.
+	DisabledInstructions []InstructionType `protobuf:"varint,2,rep,packed,name=disabled_instructions,json=disabledInstructions,proto3,enum=netctrl.v1.InstructionType" json:"disabled_instructions,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *AgentConfigOverrides) Reset() {
+	*x = AgentConfigOverrides{}
+	mi := &file_v1_agent_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentConfigOverrides) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentConfigOverrides) ProtoMessage() {}
+
+func (x *AgentConfigOverrides) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgentConfigOverrides.ProtoReflect.Descriptor instead.
+func (*AgentConfigOverrides) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AgentConfigOverrides) GetPollIntervalSeconds() int32 {
+	if x != nil {
+		return x.PollIntervalSeconds
+	}
+	return 0
+}
+
+func (x *AgentConfigOverrides) GetDisabledInstructions() []InstructionType {
+	if x != nil {
+		return x.DisabledInstructions
+	}
+	return nil
+}
+
+// InstallMetadata describes how an agent was installed
+type InstallMetadata struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Installation method, e.g. "ansible", "pxe" or "manual"
+	Method string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	// ID of the provisioning job that installed the agent
+	ProvisioningJobId string `protobuf:"bytes,2,opt,name=provisioning_job_id,json=provisioningJobId,proto3" json:"provisioning_job_id,omitempty"`
+	// Operator or automation identity that installed the agent
+	InstalledBy string `protobuf:"bytes,3,opt,name=installed_by,json=installedBy,proto3" json:"installed_by,omitempty"`
+	// When the server recorded the metadata
+	RecordedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=recorded_at,json=recordedAt,proto3" json:"recorded_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAgentRequest) Reset() {
-	*x = GetAgentRequest{}
+func (x *InstallMetadata) Reset() {
+	*x = InstallMetadata{}
+	mi := &file_v1_agent_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstallMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstallMetadata) ProtoMessage() {}
+
+func (x *InstallMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstallMetadata.ProtoReflect.Descriptor instead.
+func (*InstallMetadata) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *InstallMetadata) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *InstallMetadata) GetProvisioningJobId() string {
+	if x != nil {
+		return x.ProvisioningJobId
+	}
+	return ""
+}
+
+func (x *InstallMetadata) GetInstalledBy() string {
+	if x != nil {
+		return x.InstalledBy
+	}
+	return ""
+}
+
+func (x *InstallMetadata) GetRecordedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RecordedAt
+	}
+	return nil
+}
+
+// RawInstructionResult preserves an instruction result the server could not
+// interpret, e.g. one sent by a newer agent
+type RawInstructionResult struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	InstructionId   string                 `protobuf:"bytes,1,opt,name=instruction_id,json=instructionId,proto3" json:"instruction_id,omitempty"`
+	InstructionType InstructionType        `protobuf:"varint,2,opt,name=instruction_type,json=instructionType,proto3,enum=netctrl.v1.InstructionType" json:"instruction_type,omitempty"`
+	// Protobuf wire encoding of the submitted InstructionResult, including any
+	// fields unknown to the server
+	Payload []byte `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	// When the server received the result
+	ReceivedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=received_at,json=receivedAt,proto3" json:"received_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RawInstructionResult) Reset() {
+	*x = RawInstructionResult{}
 	mi := &file_v1_agent_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAgentRequest) String() string {
+func (x *RawInstructionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RawInstructionResult) ProtoMessage() {}
+
+func (x *RawInstructionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RawInstructionResult.ProtoReflect.Descriptor instead.
+func (*RawInstructionResult) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RawInstructionResult) GetInstructionId() string {
+	if x != nil {
+		return x.InstructionId
+	}
+	return ""
+}
+
+func (x *RawInstructionResult) GetInstructionType() InstructionType {
+	if x != nil {
+		return x.InstructionType
+	}
+	return InstructionType_INSTRUCTION_TYPE_UNSPECIFIED
+}
+
+func (x *RawInstructionResult) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *RawInstructionResult) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+// RegistrationSource records the origin of an agent registration. A change
+// of source between registrations may indicate a compromised agent identity.
+type RegistrationSource struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Address of the connection the registration arrived on
+	PeerAddress string `protobuf:"bytes,1,opt,name=peer_address,json=peerAddress,proto3" json:"peer_address,omitempty"`
+	// X-Forwarded-For chain when the registration came through the HTTP
+	// gateway or a proxy; supplied by the client, so not authoritative
+	ForwardedFor string `protobuf:"bytes,2,opt,name=forwarded_for,json=forwardedFor,proto3" json:"forwarded_for,omitempty"`
+	// User agent the client reported
+	UserAgent string `protobuf:"bytes,3,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	// When the server accepted the registration
+	RegisteredAt  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegistrationSource) Reset() {
+	*x = RegistrationSource{}
+	mi := &file_v1_agent_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegistrationSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegistrationSource) ProtoMessage() {}
+
+func (x *RegistrationSource) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegistrationSource.ProtoReflect.Descriptor instead.
+func (*RegistrationSource) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RegistrationSource) GetPeerAddress() string {
+	if x != nil {
+		return x.PeerAddress
+	}
+	return ""
+}
+
+func (x *RegistrationSource) GetForwardedFor() string {
+	if x != nil {
+		return x.ForwardedFor
+	}
+	return ""
+}
+
+func (x *RegistrationSource) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *RegistrationSource) GetRegisteredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RegisteredAt
+	}
+	return nil
+}
+
+// InstructionOutcome records whether an agent's latest result for an
+// instruction type succeeded
+type InstructionOutcome struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	InstructionType InstructionType        `protobuf:"varint,1,opt,name=instruction_type,json=instructionType,proto3,enum=netctrl.v1.InstructionType" json:"instruction_type,omitempty"`
+	Success         bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	// When the server received the result
+	ReceivedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=received_at,json=receivedAt,proto3" json:"received_at,omitempty"`
+	// Why the instruction failed, as reported by the agent
+	FailureReason FailureReason `protobuf:"varint,4,opt,name=failure_reason,json=failureReason,proto3,enum=netctrl.v1.FailureReason" json:"failure_reason,omitempty"`
+	// The instruction is not re-issued before this time
+	RetryAfter    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstructionOutcome) Reset() {
+	*x = InstructionOutcome{}
+	mi := &file_v1_agent_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstructionOutcome) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstructionOutcome) ProtoMessage() {}
+
+func (x *InstructionOutcome) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstructionOutcome.ProtoReflect.Descriptor instead.
+func (*InstructionOutcome) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *InstructionOutcome) GetInstructionType() InstructionType {
+	if x != nil {
+		return x.InstructionType
+	}
+	return InstructionType_INSTRUCTION_TYPE_UNSPECIFIED
+}
+
+func (x *InstructionOutcome) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *InstructionOutcome) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+func (x *InstructionOutcome) GetFailureReason() FailureReason {
+	if x != nil {
+		return x.FailureReason
+	}
+	return FailureReason_FAILURE_REASON_UNSPECIFIED
+}
+
+func (x *InstructionOutcome) GetRetryAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return nil
+}
+
+// RegisterAgentRequest contains parameters for registering an agent
+type RegisterAgentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Agent-generated UUID (required)
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Cluster ID (required unless the server configures a default cluster)
+	ClusterId string `protobuf:"bytes,2,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	// Node hostname (optional)
+	Hostname string `protobuf:"bytes,3,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	// Node IP address (optional)
+	IpAddress string `protobuf:"bytes,4,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	// Agent version (optional)
+	Version string `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
+	// Network role of the node (optional)
+	Role AgentRole `protobuf:"varint,6,opt,name=role,proto3,enum=netctrl.v1.AgentRole" json:"role,omitempty"`
+	// Group within the cluster, e.g. a rack or zone (optional)
+	Group string `protobuf:"bytes,7,opt,name=group,proto3" json:"group,omitempty"`
+	// How the agent was installed (optional); only kept from the first
+	// registration that supplies it, recorded_at is set by the server
+	InstallMetadata *InstallMetadata `protobuf:"bytes,8,opt,name=install_metadata,json=installMetadata,proto3" json:"install_metadata,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RegisterAgentRequest) Reset() {
+	*x = RegisterAgentRequest{}
+	mi := &file_v1_agent_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterAgentRequest) ProtoMessage() {}
+
+func (x *RegisterAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterAgentRequest.ProtoReflect.Descriptor instead.
+func (*RegisterAgentRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RegisterAgentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RegisterAgentRequest) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *RegisterAgentRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *RegisterAgentRequest) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *RegisterAgentRequest) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *RegisterAgentRequest) GetRole() AgentRole {
+	if x != nil {
+		return x.Role
+	}
+	return AgentRole_AGENT_ROLE_UNSPECIFIED
+}
+
+func (x *RegisterAgentRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *RegisterAgentRequest) GetInstallMetadata() *InstallMetadata {
+	if x != nil {
+		return x.InstallMetadata
+	}
+	return nil
+}
+
+// RegisterAgentResponse returns the registered agent
+type RegisterAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agent         *Agent                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterAgentResponse) Reset() {
+	*x = RegisterAgentResponse{}
+	mi := &file_v1_agent_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterAgentResponse) ProtoMessage() {}
+
+func (x *RegisterAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterAgentResponse.ProtoReflect.Descriptor instead.
+func (*RegisterAgentResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RegisterAgentResponse) GetAgent() *Agent {
+	if x != nil {
+		return x.Agent
+	}
+	return nil
+}
+
+// GetAgentRequest contains parameters for retrieving an agent
+type GetAgentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the agent to retrieve
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Optional fields to return; the full agent is returned when omitted
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,2,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAgentRequest) Reset() {
+	*x = GetAgentRequest{}
+	mi := &file_v1_agent_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAgentRequest) ProtoMessage() {}
+
+func (x *GetAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAgentRequest.ProtoReflect.Descriptor instead.
+func (*GetAgentRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetAgentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetAgentRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+// GetAgentResponse returns the requested agent
+type GetAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agent         *Agent                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAgentResponse) Reset() {
+	*x = GetAgentResponse{}
+	mi := &file_v1_agent_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAgentResponse) ProtoMessage() {}
+
+func (x *GetAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAgentResponse.ProtoReflect.Descriptor instead.
+func (*GetAgentResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetAgentResponse) GetAgent() *Agent {
+	if x != nil {
+		return x.Agent
+	}
+	return nil
+}
+
+// ListAgentsRequest contains parameters for listing agents
+type ListAgentsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional cluster ID filter
+	ClusterId string `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	// Only return agents with at least one NIC port whose link is down
+	DownPortsOnly bool `protobuf:"varint,2,opt,name=down_ports_only,json=downPortsOnly,proto3" json:"down_ports_only,omitempty"`
+	// Optional fields to return for each agent; full agents are returned when omitted
+	ReadMask *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	// Optional group filter
+	Group string `protobuf:"bytes,4,opt,name=group,proto3" json:"group,omitempty"`
+	// Maximum number of agents to return; when set, agents are returned a page
+	// at a time. Agents are always ordered by creation time, then ID.
+	PageSize int32 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Token from a previous response's next_page_token to continue listing
+	PageToken string `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// When set, only return active agents without a healthy health check in
+	// this many seconds (counted from registration for agents never healthy),
+	// separating reachable-but-unhealthy agents from unreachable ones
+	UnhealthyForSeconds int32 `protobuf:"varint,7,opt,name=unhealthy_for_seconds,json=unhealthyForSeconds,proto3" json:"unhealthy_for_seconds,omitempty"`
+	// Fail with NOT_FOUND when cluster_id names a cluster that does not exist,
+	// instead of returning an empty list
+	RequireCluster bool `protobuf:"varint,8,opt,name=require_cluster,json=requireCluster,proto3" json:"require_cluster,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListAgentsRequest) Reset() {
+	*x = ListAgentsRequest{}
+	mi := &file_v1_agent_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsRequest) ProtoMessage() {}
+
+func (x *ListAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsRequest.ProtoReflect.Descriptor instead.
+func (*ListAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListAgentsRequest) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *ListAgentsRequest) GetDownPortsOnly() bool {
+	if x != nil {
+		return x.DownPortsOnly
+	}
+	return false
+}
+
+func (x *ListAgentsRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+func (x *ListAgentsRequest) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ListAgentsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListAgentsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListAgentsRequest) GetUnhealthyForSeconds() int32 {
+	if x != nil {
+		return x.UnhealthyForSeconds
+	}
+	return 0
+}
+
+func (x *ListAgentsRequest) GetRequireCluster() bool {
+	if x != nil {
+		return x.RequireCluster
+	}
+	return false
+}
+
+// ListAgentsResponse returns a list of agents
+type ListAgentsResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Agents []*Agent               `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	// Token for the next page; empty on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAgentsResponse) Reset() {
+	*x = ListAgentsResponse{}
+	mi := &file_v1_agent_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAgentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAgentsResponse) ProtoMessage() {}
+
+func (x *ListAgentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAgentsResponse.ProtoReflect.Descriptor instead.
+func (*ListAgentsResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListAgentsResponse) GetAgents() []*Agent {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+func (x *ListAgentsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// GetAgentStatusesRequest lists the agents to return statuses for
+type GetAgentStatusesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Agent IDs; at most 1000
+	Ids           []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAgentStatusesRequest) Reset() {
+	*x = GetAgentStatusesRequest{}
+	mi := &file_v1_agent_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAgentStatusesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAgentStatusesRequest) ProtoMessage() {}
+
+func (x *GetAgentStatusesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAgentStatusesRequest.ProtoReflect.Descriptor instead.
+func (*GetAgentStatusesRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetAgentStatusesRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+// GetAgentStatusesResponse maps each existing requested agent to its status;
+// IDs of agents that do not exist are omitted
+type GetAgentStatusesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      map[string]AgentStatus `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value,enum=netctrl.v1.AgentStatus"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAgentStatusesResponse) Reset() {
+	*x = GetAgentStatusesResponse{}
+	mi := &file_v1_agent_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAgentStatusesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAgentStatusesResponse) ProtoMessage() {}
+
+func (x *GetAgentStatusesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAgentStatusesResponse.ProtoReflect.Descriptor instead.
+func (*GetAgentStatusesResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetAgentStatusesResponse) GetStatuses() map[string]AgentStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+// UnregisterAgentRequest contains parameters for unregistering an agent
+type UnregisterAgentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the agent to unregister
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// When true, unregistering an agent that no longer exists succeeds
+	// instead of returning NotFound, so a retried request is safe
+	Idempotent    bool `protobuf:"varint,2,opt,name=idempotent,proto3" json:"idempotent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterAgentRequest) Reset() {
+	*x = UnregisterAgentRequest{}
+	mi := &file_v1_agent_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterAgentRequest) ProtoMessage() {}
+
+func (x *UnregisterAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterAgentRequest.ProtoReflect.Descriptor instead.
+func (*UnregisterAgentRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UnregisterAgentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UnregisterAgentRequest) GetIdempotent() bool {
+	if x != nil {
+		return x.Idempotent
+	}
+	return false
+}
+
+// UnregisterAgentResponse confirms unregistration
+type UnregisterAgentResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether the unregistration was successful
+	Success       bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnregisterAgentResponse) Reset() {
+	*x = UnregisterAgentResponse{}
+	mi := &file_v1_agent_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnregisterAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnregisterAgentResponse) ProtoMessage() {}
+
+func (x *UnregisterAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnregisterAgentResponse.ProtoReflect.Descriptor instead.
+func (*UnregisterAgentResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UnregisterAgentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// DecommissionAgentRequest contains parameters for decommissioning an agent
+type DecommissionAgentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the agent to decommission
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecommissionAgentRequest) Reset() {
+	*x = DecommissionAgentRequest{}
+	mi := &file_v1_agent_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecommissionAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecommissionAgentRequest) ProtoMessage() {}
+
+func (x *DecommissionAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecommissionAgentRequest.ProtoReflect.Descriptor instead.
+func (*DecommissionAgentRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DecommissionAgentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// DecommissionAgentResponse returns the agent marked for decommission
+type DecommissionAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agent         *Agent                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecommissionAgentResponse) Reset() {
+	*x = DecommissionAgentResponse{}
+	mi := &file_v1_agent_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecommissionAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecommissionAgentResponse) ProtoMessage() {}
+
+func (x *DecommissionAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecommissionAgentResponse.ProtoReflect.Descriptor instead.
+func (*DecommissionAgentResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DecommissionAgentResponse) GetAgent() *Agent {
+	if x != nil {
+		return x.Agent
+	}
+	return nil
+}
+
+// UpdateAgentConfigRequest sets an agent's config overrides
+type UpdateAgentConfigRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the agent
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Overrides replacing the agent's current ones; empty clears them
+	Overrides     *AgentConfigOverrides `protobuf:"bytes,2,opt,name=overrides,proto3" json:"overrides,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAgentConfigRequest) Reset() {
+	*x = UpdateAgentConfigRequest{}
+	mi := &file_v1_agent_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAgentConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAgentConfigRequest) ProtoMessage() {}
+
+func (x *UpdateAgentConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAgentConfigRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAgentConfigRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UpdateAgentConfigRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateAgentConfigRequest) GetOverrides() *AgentConfigOverrides {
+	if x != nil {
+		return x.Overrides
+	}
+	return nil
+}
+
+// UpdateAgentConfigResponse returns the agent with its new overrides
+type UpdateAgentConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agent         *Agent                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAgentConfigResponse) Reset() {
+	*x = UpdateAgentConfigResponse{}
+	mi := &file_v1_agent_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAgentConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAgentConfigResponse) ProtoMessage() {}
+
+func (x *UpdateAgentConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAgentConfigResponse.ProtoReflect.Descriptor instead.
+func (*UpdateAgentConfigResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UpdateAgentConfigResponse) GetAgent() *Agent {
+	if x != nil {
+		return x.Agent
+	}
+	return nil
+}
+
+// RestartAgentRequest contains parameters for restarting an agent
+type RestartAgentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the agent to restart
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestartAgentRequest) Reset() {
+	*x = RestartAgentRequest{}
+	mi := &file_v1_agent_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestartAgentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestartAgentRequest) ProtoMessage() {}
+
+func (x *RestartAgentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestartAgentRequest.ProtoReflect.Descriptor instead.
+func (*RestartAgentRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RestartAgentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// RestartAgentResponse returns the agent with its restart requested
+type RestartAgentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agent         *Agent                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestartAgentResponse) Reset() {
+	*x = RestartAgentResponse{}
+	mi := &file_v1_agent_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestartAgentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestartAgentResponse) ProtoMessage() {}
+
+func (x *RestartAgentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestartAgentResponse.ProtoReflect.Descriptor instead.
+func (*RestartAgentResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *RestartAgentResponse) GetAgent() *Agent {
+	if x != nil {
+		return x.Agent
+	}
+	return nil
+}
+
+// TriggerHardwareCollectionRequest selects agents by cluster or by ID;
+// exactly one of the two must be set
+type TriggerHardwareCollectionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Re-collect hardware from every agent in this cluster
+	ClusterId string `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	// Re-collect hardware from these agents
+	AgentIds      []string `protobuf:"bytes,2,rep,name=agent_ids,json=agentIds,proto3" json:"agent_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerHardwareCollectionRequest) Reset() {
+	*x = TriggerHardwareCollectionRequest{}
+	mi := &file_v1_agent_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerHardwareCollectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerHardwareCollectionRequest) ProtoMessage() {}
+
+func (x *TriggerHardwareCollectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerHardwareCollectionRequest.ProtoReflect.Descriptor instead.
+func (*TriggerHardwareCollectionRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *TriggerHardwareCollectionRequest) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *TriggerHardwareCollectionRequest) GetAgentIds() []string {
+	if x != nil {
+		return x.AgentIds
+	}
+	return nil
+}
+
+// TriggerHardwareCollectionResponse lists the agents that will re-collect
+type TriggerHardwareCollectionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentIds      []string               `protobuf:"bytes,1,rep,name=agent_ids,json=agentIds,proto3" json:"agent_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerHardwareCollectionResponse) Reset() {
+	*x = TriggerHardwareCollectionResponse{}
+	mi := &file_v1_agent_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerHardwareCollectionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerHardwareCollectionResponse) ProtoMessage() {}
+
+func (x *TriggerHardwareCollectionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerHardwareCollectionResponse.ProtoReflect.Descriptor instead.
+func (*TriggerHardwareCollectionResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *TriggerHardwareCollectionResponse) GetAgentIds() []string {
+	if x != nil {
+		return x.AgentIds
+	}
+	return nil
+}
+
+// EnqueueInstructionBySelectorRequest selects agents of a cluster and the
+// instruction to request from them
+type EnqueueInstructionBySelectorRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ClusterId string                 `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	// Comma-separated key=value terms an agent must all match, e.g.
+	// "role=spine,group=rack-a". Keys are role (spine or leaf) and group; an
+	// empty selector matches every agent of the cluster.
+	LabelSelector string `protobuf:"bytes,2,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	// Instruction to request: INSTRUCTION_TYPE_COLLECT_HARDWARE or
+	// INSTRUCTION_TYPE_RESTART_AGENT. Both are persistent, so agents that are
+	// offline receive it on their next poll.
+	InstructionType InstructionType `protobuf:"varint,3,opt,name=instruction_type,json=instructionType,proto3,enum=netctrl.v1.InstructionType" json:"instruction_type,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *EnqueueInstructionBySelectorRequest) Reset() {
+	*x = EnqueueInstructionBySelectorRequest{}
+	mi := &file_v1_agent_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnqueueInstructionBySelectorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnqueueInstructionBySelectorRequest) ProtoMessage() {}
+
+func (x *EnqueueInstructionBySelectorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnqueueInstructionBySelectorRequest.ProtoReflect.Descriptor instead.
+func (*EnqueueInstructionBySelectorRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *EnqueueInstructionBySelectorRequest) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *EnqueueInstructionBySelectorRequest) GetLabelSelector() string {
+	if x != nil {
+		return x.LabelSelector
+	}
+	return ""
+}
+
+func (x *EnqueueInstructionBySelectorRequest) GetInstructionType() InstructionType {
+	if x != nil {
+		return x.InstructionType
+	}
+	return InstructionType_INSTRUCTION_TYPE_UNSPECIFIED
+}
+
+// EnqueueInstructionBySelectorResponse lists the agents the instruction was
+// requested from; decommissioning agents are never targeted
+type EnqueueInstructionBySelectorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TargetedCount int32                  `protobuf:"varint,1,opt,name=targeted_count,json=targetedCount,proto3" json:"targeted_count,omitempty"`
+	AgentIds      []string               `protobuf:"bytes,2,rep,name=agent_ids,json=agentIds,proto3" json:"agent_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnqueueInstructionBySelectorResponse) Reset() {
+	*x = EnqueueInstructionBySelectorResponse{}
+	mi := &file_v1_agent_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnqueueInstructionBySelectorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnqueueInstructionBySelectorResponse) ProtoMessage() {}
+
+func (x *EnqueueInstructionBySelectorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnqueueInstructionBySelectorResponse.ProtoReflect.Descriptor instead.
+func (*EnqueueInstructionBySelectorResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *EnqueueInstructionBySelectorResponse) GetTargetedCount() int32 {
+	if x != nil {
+		return x.TargetedCount
+	}
+	return 0
+}
+
+func (x *EnqueueInstructionBySelectorResponse) GetAgentIds() []string {
+	if x != nil {
+		return x.AgentIds
+	}
+	return nil
+}
+
+// FindOrphanedAgentsRequest contains parameters for finding orphaned agents
+type FindOrphanedAgentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindOrphanedAgentsRequest) Reset() {
+	*x = FindOrphanedAgentsRequest{}
+	mi := &file_v1_agent_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindOrphanedAgentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindOrphanedAgentsRequest) ProtoMessage() {}
+
+func (x *FindOrphanedAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindOrphanedAgentsRequest.ProtoReflect.Descriptor instead.
+func (*FindOrphanedAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{28}
+}
+
+// FindOrphanedAgentsResponse returns agents referencing missing clusters
+type FindOrphanedAgentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Agents        []*Agent               `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindOrphanedAgentsResponse) Reset() {
+	*x = FindOrphanedAgentsResponse{}
+	mi := &file_v1_agent_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindOrphanedAgentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindOrphanedAgentsResponse) ProtoMessage() {}
+
+func (x *FindOrphanedAgentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindOrphanedAgentsResponse.ProtoReflect.Descriptor instead.
+func (*FindOrphanedAgentsResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *FindOrphanedAgentsResponse) GetAgents() []*Agent {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+// ReapOrphanedAgentsRequest contains parameters for deleting orphaned agents
+type ReapOrphanedAgentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReapOrphanedAgentsRequest) Reset() {
+	*x = ReapOrphanedAgentsRequest{}
+	mi := &file_v1_agent_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReapOrphanedAgentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReapOrphanedAgentsRequest) ProtoMessage() {}
+
+func (x *ReapOrphanedAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReapOrphanedAgentsRequest.ProtoReflect.Descriptor instead.
+func (*ReapOrphanedAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{30}
+}
+
+// ReapOrphanedAgentsResponse reports which orphaned agents were deleted
+type ReapOrphanedAgentsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// IDs of the deleted agents
+	AgentIds      []string `protobuf:"bytes,1,rep,name=agent_ids,json=agentIds,proto3" json:"agent_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReapOrphanedAgentsResponse) Reset() {
+	*x = ReapOrphanedAgentsResponse{}
+	mi := &file_v1_agent_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReapOrphanedAgentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReapOrphanedAgentsResponse) ProtoMessage() {}
+
+func (x *ReapOrphanedAgentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReapOrphanedAgentsResponse.ProtoReflect.Descriptor instead.
+func (*ReapOrphanedAgentsResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ReapOrphanedAgentsResponse) GetAgentIds() []string {
+	if x != nil {
+		return x.AgentIds
+	}
+	return nil
+}
+
+// SetThrottleModeRequest contains parameters for fleet-wide throttling
+type SetThrottleModeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether throttle mode is on
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Poll interval returned to agents while throttled (optional, uses the server default)
+	PollIntervalSeconds int32 `protobuf:"varint,2,opt,name=poll_interval_seconds,json=pollIntervalSeconds,proto3" json:"poll_interval_seconds,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *SetThrottleModeRequest) Reset() {
+	*x = SetThrottleModeRequest{}
+	mi := &file_v1_agent_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetThrottleModeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetThrottleModeRequest) ProtoMessage() {}
+
+func (x *SetThrottleModeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetThrottleModeRequest.ProtoReflect.Descriptor instead.
+func (*SetThrottleModeRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *SetThrottleModeRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *SetThrottleModeRequest) GetPollIntervalSeconds() int32 {
+	if x != nil {
+		return x.PollIntervalSeconds
+	}
+	return 0
+}
+
+// SetThrottleModeResponse returns the throttle mode now in effect
+type SetThrottleModeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether throttle mode is on
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Poll interval returned to agents while throttled
+	PollIntervalSeconds int32 `protobuf:"varint,2,opt,name=poll_interval_seconds,json=pollIntervalSeconds,proto3" json:"poll_interval_seconds,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *SetThrottleModeResponse) Reset() {
+	*x = SetThrottleModeResponse{}
+	mi := &file_v1_agent_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetThrottleModeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetThrottleModeResponse) ProtoMessage() {}
+
+func (x *SetThrottleModeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetThrottleModeResponse.ProtoReflect.Descriptor instead.
+func (*SetThrottleModeResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SetThrottleModeResponse) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *SetThrottleModeResponse) GetPollIntervalSeconds() int32 {
+	if x != nil {
+		return x.PollIntervalSeconds
+	}
+	return 0
+}
+
+// GetClusterPollStatsRequest identifies the cluster to report on
+type GetClusterPollStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClusterId     string                 `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClusterPollStatsRequest) Reset() {
+	*x = GetClusterPollStatsRequest{}
+	mi := &file_v1_agent_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClusterPollStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClusterPollStatsRequest) ProtoMessage() {}
+
+func (x *GetClusterPollStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClusterPollStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetClusterPollStatsRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetClusterPollStatsRequest) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+// GetClusterPollStatsResponse aggregates the intervals between consecutive
+// polls of a cluster's agents over a bounded window of recent polls
+type GetClusterPollStatsResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ClusterId string                 `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	// Number of agents with at least one observed interval
+	AgentCount int32 `protobuf:"varint,2,opt,name=agent_count,json=agentCount,proto3" json:"agent_count,omitempty"`
+	// Number of intervals the statistics are computed over
+	SampleCount        int32   `protobuf:"varint,3,opt,name=sample_count,json=sampleCount,proto3" json:"sample_count,omitempty"`
+	MinIntervalSeconds float64 `protobuf:"fixed64,4,opt,name=min_interval_seconds,json=minIntervalSeconds,proto3" json:"min_interval_seconds,omitempty"`
+	AvgIntervalSeconds float64 `protobuf:"fixed64,5,opt,name=avg_interval_seconds,json=avgIntervalSeconds,proto3" json:"avg_interval_seconds,omitempty"`
+	MaxIntervalSeconds float64 `protobuf:"fixed64,6,opt,name=max_interval_seconds,json=maxIntervalSeconds,proto3" json:"max_interval_seconds,omitempty"`
+	// Poll interval currently handed to agents
+	ConfiguredIntervalSeconds int32 `protobuf:"varint,7,opt,name=configured_interval_seconds,json=configuredIntervalSeconds,proto3" json:"configured_interval_seconds,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *GetClusterPollStatsResponse) Reset() {
+	*x = GetClusterPollStatsResponse{}
+	mi := &file_v1_agent_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClusterPollStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClusterPollStatsResponse) ProtoMessage() {}
+
+func (x *GetClusterPollStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClusterPollStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetClusterPollStatsResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetClusterPollStatsResponse) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *GetClusterPollStatsResponse) GetAgentCount() int32 {
+	if x != nil {
+		return x.AgentCount
+	}
+	return 0
+}
+
+func (x *GetClusterPollStatsResponse) GetSampleCount() int32 {
+	if x != nil {
+		return x.SampleCount
+	}
+	return 0
+}
+
+func (x *GetClusterPollStatsResponse) GetMinIntervalSeconds() float64 {
+	if x != nil {
+		return x.MinIntervalSeconds
+	}
+	return 0
+}
+
+func (x *GetClusterPollStatsResponse) GetAvgIntervalSeconds() float64 {
+	if x != nil {
+		return x.AvgIntervalSeconds
+	}
+	return 0
+}
+
+func (x *GetClusterPollStatsResponse) GetMaxIntervalSeconds() float64 {
+	if x != nil {
+		return x.MaxIntervalSeconds
+	}
+	return 0
+}
+
+func (x *GetClusterPollStatsResponse) GetConfiguredIntervalSeconds() int32 {
+	if x != nil {
+		return x.ConfiguredIntervalSeconds
+	}
+	return 0
+}
+
+// GetClusterInstructionSummaryRequest selects the cluster and instruction type to summarize
+type GetClusterInstructionSummaryRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ClusterId string                 `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	// Instruction type to summarize (required)
+	InstructionType InstructionType `protobuf:"varint,2,opt,name=instruction_type,json=instructionType,proto3,enum=netctrl.v1.InstructionType" json:"instruction_type,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetClusterInstructionSummaryRequest) Reset() {
+	*x = GetClusterInstructionSummaryRequest{}
+	mi := &file_v1_agent_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClusterInstructionSummaryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClusterInstructionSummaryRequest) ProtoMessage() {}
+
+func (x *GetClusterInstructionSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClusterInstructionSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetClusterInstructionSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetClusterInstructionSummaryRequest) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *GetClusterInstructionSummaryRequest) GetInstructionType() InstructionType {
+	if x != nil {
+		return x.InstructionType
+	}
+	return InstructionType_INSTRUCTION_TYPE_UNSPECIFIED
+}
+
+// GetClusterInstructionSummaryResponse aggregates the most recent result per agent
+type GetClusterInstructionSummaryResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ClusterId       string                 `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	InstructionType InstructionType        `protobuf:"varint,2,opt,name=instruction_type,json=instructionType,proto3,enum=netctrl.v1.InstructionType" json:"instruction_type,omitempty"`
+	// Agents whose most recent result of the type succeeded
+	Succeeded int32 `protobuf:"varint,3,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	// Agents whose most recent result of the type failed
+	Failed int32 `protobuf:"varint,4,opt,name=failed,proto3" json:"failed,omitempty"`
+	// Agents that have not reported a result of the type
+	NoResult      int32 `protobuf:"varint,5,opt,name=no_result,json=noResult,proto3" json:"no_result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClusterInstructionSummaryResponse) Reset() {
+	*x = GetClusterInstructionSummaryResponse{}
+	mi := &file_v1_agent_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClusterInstructionSummaryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClusterInstructionSummaryResponse) ProtoMessage() {}
+
+func (x *GetClusterInstructionSummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClusterInstructionSummaryResponse.ProtoReflect.Descriptor instead.
+func (*GetClusterInstructionSummaryResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetClusterInstructionSummaryResponse) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *GetClusterInstructionSummaryResponse) GetInstructionType() InstructionType {
+	if x != nil {
+		return x.InstructionType
+	}
+	return InstructionType_INSTRUCTION_TYPE_UNSPECIFIED
+}
+
+func (x *GetClusterInstructionSummaryResponse) GetSucceeded() int32 {
+	if x != nil {
+		return x.Succeeded
+	}
+	return 0
+}
+
+func (x *GetClusterInstructionSummaryResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *GetClusterInstructionSummaryResponse) GetNoResult() int32 {
+	if x != nil {
+		return x.NoResult
+	}
+	return 0
+}
+
+// TailAgentActivityRequest identifies the agent to tail
+type TailAgentActivityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TailAgentActivityRequest) Reset() {
+	*x = TailAgentActivityRequest{}
+	mi := &file_v1_agent_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TailAgentActivityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TailAgentActivityRequest) ProtoMessage() {}
+
+func (x *TailAgentActivityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TailAgentActivityRequest.ProtoReflect.Descriptor instead.
+func (*TailAgentActivityRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *TailAgentActivityRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// ActivityEvent describes one step of an agent's instruction activity
+type ActivityEvent struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AgentId   string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Type      ActivityEventType      `protobuf:"varint,2,opt,name=type,proto3,enum=netctrl.v1.ActivityEventType" json:"type,omitempty"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Instructions issued by the poll (INSTRUCTIONS_ISSUED only)
+	InstructionTypes []InstructionType `protobuf:"varint,4,rep,packed,name=instruction_types,json=instructionTypes,proto3,enum=netctrl.v1.InstructionType" json:"instruction_types,omitempty"`
+	// Instruction the result belongs to (RESULT_SUBMITTED only)
+	InstructionId   string          `protobuf:"bytes,5,opt,name=instruction_id,json=instructionId,proto3" json:"instruction_id,omitempty"`
+	InstructionType InstructionType `protobuf:"varint,6,opt,name=instruction_type,json=instructionType,proto3,enum=netctrl.v1.InstructionType" json:"instruction_type,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ActivityEvent) Reset() {
+	*x = ActivityEvent{}
+	mi := &file_v1_agent_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivityEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivityEvent) ProtoMessage() {}
+
+func (x *ActivityEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivityEvent.ProtoReflect.Descriptor instead.
+func (*ActivityEvent) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ActivityEvent) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *ActivityEvent) GetType() ActivityEventType {
+	if x != nil {
+		return x.Type
+	}
+	return ActivityEventType_ACTIVITY_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *ActivityEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ActivityEvent) GetInstructionTypes() []InstructionType {
+	if x != nil {
+		return x.InstructionTypes
+	}
+	return nil
+}
+
+func (x *ActivityEvent) GetInstructionId() string {
+	if x != nil {
+		return x.InstructionId
+	}
+	return ""
+}
+
+func (x *ActivityEvent) GetInstructionType() InstructionType {
+	if x != nil {
+		return x.InstructionType
+	}
+	return InstructionType_INSTRUCTION_TYPE_UNSPECIFIED
+}
+
+// Instruction represents a command or directive from the service to an agent
+type Instruction struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique instruction ID
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Type of instruction
+	Type InstructionType `protobuf:"varint,2,opt,name=type,proto3,enum=netctrl.v1.InstructionType" json:"type,omitempty"`
+	// Instruction payload (type-specific data as JSON)
+	Payload string `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	// When the instruction was created
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Whether the instruction is redelivered on every poll until the agent
+	// reports its result, so it survives agent downtime and restarts. Other
+	// instructions are periodic: polls missed while the agent is offline are
+	// not made up for.
+	Persistent    bool `protobuf:"varint,5,opt,name=persistent,proto3" json:"persistent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Instruction) Reset() {
+	*x = Instruction{}
+	mi := &file_v1_agent_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Instruction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Instruction) ProtoMessage() {}
+
+func (x *Instruction) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Instruction.ProtoReflect.Descriptor instead.
+func (*Instruction) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *Instruction) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Instruction) GetType() InstructionType {
+	if x != nil {
+		return x.Type
+	}
+	return InstructionType_INSTRUCTION_TYPE_UNSPECIFIED
+}
+
+func (x *Instruction) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *Instruction) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Instruction) GetPersistent() bool {
+	if x != nil {
+		return x.Persistent
+	}
+	return false
+}
+
+// HardwareCollectionResult contains the result of hardware collection
+type HardwareCollectionResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Collected Mellanox NICs
+	NetworkInterfaces []*MellanoxNIC `protobuf:"bytes,1,rep,name=network_interfaces,json=networkInterfaces,proto3" json:"network_interfaces,omitempty"`
+	// Whether some NICs could not be collected; network_interfaces then holds
+	// only the NICs that were
+	Partial bool `protobuf:"varint,2,opt,name=partial,proto3" json:"partial,omitempty"`
+	// NICs the agent found but failed to collect
+	FailedNics    []*NICCollectionFailure `protobuf:"bytes,3,rep,name=failed_nics,json=failedNics,proto3" json:"failed_nics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HardwareCollectionResult) Reset() {
+	*x = HardwareCollectionResult{}
+	mi := &file_v1_agent_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HardwareCollectionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HardwareCollectionResult) ProtoMessage() {}
+
+func (x *HardwareCollectionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HardwareCollectionResult.ProtoReflect.Descriptor instead.
+func (*HardwareCollectionResult) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *HardwareCollectionResult) GetNetworkInterfaces() []*MellanoxNIC {
+	if x != nil {
+		return x.NetworkInterfaces
+	}
+	return nil
+}
+
+func (x *HardwareCollectionResult) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
+func (x *HardwareCollectionResult) GetFailedNics() []*NICCollectionFailure {
+	if x != nil {
+		return x.FailedNics
+	}
+	return nil
+}
+
+// NICCollectionFailure identifies a NIC whose details could not be collected
+type NICCollectionFailure struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Device name (e.g., "mlx5_0")
+	DeviceName string `protobuf:"bytes,1,opt,name=device_name,json=deviceName,proto3" json:"device_name,omitempty"`
+	// PCI address (e.g., "0000:03:00.0")
+	PciAddress string `protobuf:"bytes,2,opt,name=pci_address,json=pciAddress,proto3" json:"pci_address,omitempty"`
+	// Why collection failed, e.g. permission denied on the device
+	Error         string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NICCollectionFailure) Reset() {
+	*x = NICCollectionFailure{}
+	mi := &file_v1_agent_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NICCollectionFailure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NICCollectionFailure) ProtoMessage() {}
+
+func (x *NICCollectionFailure) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NICCollectionFailure.ProtoReflect.Descriptor instead.
+func (*NICCollectionFailure) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *NICCollectionFailure) GetDeviceName() string {
+	if x != nil {
+		return x.DeviceName
+	}
+	return ""
+}
+
+func (x *NICCollectionFailure) GetPciAddress() string {
+	if x != nil {
+		return x.PciAddress
+	}
+	return ""
+}
+
+func (x *NICCollectionFailure) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// HealthCheckResult contains the result of a health check
+type HealthCheckResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Health status
+	Healthy bool `protobuf:"varint,1,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	// Optional error message if unhealthy
+	ErrorMessage  string `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthCheckResult) Reset() {
+	*x = HealthCheckResult{}
+	mi := &file_v1_agent_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthCheckResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthCheckResult) ProtoMessage() {}
+
+func (x *HealthCheckResult) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthCheckResult.ProtoReflect.Descriptor instead.
+func (*HealthCheckResult) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *HealthCheckResult) GetHealthy() bool {
+	if x != nil {
+		return x.Healthy
+	}
+	return false
+}
+
+func (x *HealthCheckResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+// GatewayProbeResult contains the result of a cluster gateway probe
+type GatewayProbeResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Gateway IP address that was probed
+	Gateway string `protobuf:"bytes,1,opt,name=gateway,proto3" json:"gateway,omitempty"`
+	// Whether the gateway was reachable
+	Reachable bool `protobuf:"varint,2,opt,name=reachable,proto3" json:"reachable,omitempty"`
+	// Round-trip latency in milliseconds (when reachable)
+	LatencyMs float64 `protobuf:"fixed64,3,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	// Optional error message if unreachable
+	ErrorMessage  string `protobuf:"bytes,4,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GatewayProbeResult) Reset() {
+	*x = GatewayProbeResult{}
+	mi := &file_v1_agent_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GatewayProbeResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GatewayProbeResult) ProtoMessage() {}
+
+func (x *GatewayProbeResult) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GatewayProbeResult.ProtoReflect.Descriptor instead.
+func (*GatewayProbeResult) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *GatewayProbeResult) GetGateway() string {
+	if x != nil {
+		return x.Gateway
+	}
+	return ""
+}
+
+func (x *GatewayProbeResult) GetReachable() bool {
+	if x != nil {
+		return x.Reachable
+	}
+	return false
+}
+
+func (x *GatewayProbeResult) GetLatencyMs() float64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *GatewayProbeResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+// NetworkConfigResult contains the result of applying a network config
+type NetworkConfigResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether the config was applied successfully
+	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// Network config now in effect on the agent
+	AppliedConfig *NetworkConfig `protobuf:"bytes,2,opt,name=applied_config,json=appliedConfig,proto3" json:"applied_config,omitempty"`
+	// Optional error message if the config could not be applied
+	ErrorMessage  string `protobuf:"bytes,3,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkConfigResult) Reset() {
+	*x = NetworkConfigResult{}
+	mi := &file_v1_agent_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkConfigResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkConfigResult) ProtoMessage() {}
+
+func (x *NetworkConfigResult) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkConfigResult.ProtoReflect.Descriptor instead.
+func (*NetworkConfigResult) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *NetworkConfigResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *NetworkConfigResult) GetAppliedConfig() *NetworkConfig {
+	if x != nil {
+		return x.AppliedConfig
+	}
+	return nil
+}
+
+func (x *NetworkConfigResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+// DecommissionResult contains the result of decommission cleanup
+type DecommissionResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether cleanup completed; the agent is unregistered on success
+	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// Optional error message if cleanup failed
+	ErrorMessage  string `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecommissionResult) Reset() {
+	*x = DecommissionResult{}
+	mi := &file_v1_agent_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecommissionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecommissionResult) ProtoMessage() {}
+
+func (x *DecommissionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecommissionResult.ProtoReflect.Descriptor instead.
+func (*DecommissionResult) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *DecommissionResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DecommissionResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+// RestartAgentResult confirms that the agent is restarting; it is sent
+// before the process exits
+type RestartAgentResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether the restart was initiated
+	Initiated bool `protobuf:"varint,1,opt,name=initiated,proto3" json:"initiated,omitempty"`
+	// Optional error message if the restart could not be initiated
+	ErrorMessage  string `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestartAgentResult) Reset() {
+	*x = RestartAgentResult{}
+	mi := &file_v1_agent_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestartAgentResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestartAgentResult) ProtoMessage() {}
+
+func (x *RestartAgentResult) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestartAgentResult.ProtoReflect.Descriptor instead.
+func (*RestartAgentResult) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *RestartAgentResult) GetInitiated() bool {
+	if x != nil {
+		return x.Initiated
+	}
+	return false
+}
+
+func (x *RestartAgentResult) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+// InstructionResult represents the result of executing an instruction
+type InstructionResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Type of instruction that was executed
+	InstructionType InstructionType `protobuf:"varint,1,opt,name=instruction_type,json=instructionType,proto3,enum=netctrl.v1.InstructionType" json:"instruction_type,omitempty"`
+	// Type-specific result data
+	//
+	// Types that are valid to be assigned to Result:
+	//
+	//	*InstructionResult_HardwareCollection
+	//	*InstructionResult_HealthCheck
+	//	*InstructionResult_GatewayProbe
+	//	*InstructionResult_NetworkConfig
+	//	*InstructionResult_Decommission
+	//	*InstructionResult_RestartAgent
+	Result isInstructionResult_Result `protobuf_oneof:"result"`
+	// When the agent finished executing the instruction (agent clock, optional).
+	// Rejected if it differs from server time by more than the allowed skew.
+	CompletedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	// Set by agents when the instruction failed; the result is then treated
+	// as a failure and the instruction is retried after a reason-specific backoff
+	FailureReason FailureReason `protobuf:"varint,8,opt,name=failure_reason,json=failureReason,proto3,enum=netctrl.v1.FailureReason" json:"failure_reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstructionResult) Reset() {
+	*x = InstructionResult{}
+	mi := &file_v1_agent_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstructionResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstructionResult) ProtoMessage() {}
+
+func (x *InstructionResult) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstructionResult.ProtoReflect.Descriptor instead.
+func (*InstructionResult) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *InstructionResult) GetInstructionType() InstructionType {
+	if x != nil {
+		return x.InstructionType
+	}
+	return InstructionType_INSTRUCTION_TYPE_UNSPECIFIED
+}
+
+func (x *InstructionResult) GetResult() isInstructionResult_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *InstructionResult) GetHardwareCollection() *HardwareCollectionResult {
+	if x != nil {
+		if x, ok := x.Result.(*InstructionResult_HardwareCollection); ok {
+			return x.HardwareCollection
+		}
+	}
+	return nil
+}
+
+func (x *InstructionResult) GetHealthCheck() *HealthCheckResult {
+	if x != nil {
+		if x, ok := x.Result.(*InstructionResult_HealthCheck); ok {
+			return x.HealthCheck
+		}
+	}
+	return nil
+}
+
+func (x *InstructionResult) GetGatewayProbe() *GatewayProbeResult {
+	if x != nil {
+		if x, ok := x.Result.(*InstructionResult_GatewayProbe); ok {
+			return x.GatewayProbe
+		}
+	}
+	return nil
+}
+
+func (x *InstructionResult) GetNetworkConfig() *NetworkConfigResult {
+	if x != nil {
+		if x, ok := x.Result.(*InstructionResult_NetworkConfig); ok {
+			return x.NetworkConfig
+		}
+	}
+	return nil
+}
+
+func (x *InstructionResult) GetDecommission() *DecommissionResult {
+	if x != nil {
+		if x, ok := x.Result.(*InstructionResult_Decommission); ok {
+			return x.Decommission
+		}
+	}
+	return nil
+}
+
+func (x *InstructionResult) GetRestartAgent() *RestartAgentResult {
+	if x != nil {
+		if x, ok := x.Result.(*InstructionResult_RestartAgent); ok {
+			return x.RestartAgent
+		}
+	}
+	return nil
+}
+
+func (x *InstructionResult) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+func (x *InstructionResult) GetFailureReason() FailureReason {
+	if x != nil {
+		return x.FailureReason
+	}
+	return FailureReason_FAILURE_REASON_UNSPECIFIED
+}
+
+type isInstructionResult_Result interface {
+	isInstructionResult_Result()
+}
+
+type InstructionResult_HardwareCollection struct {
+	HardwareCollection *HardwareCollectionResult `protobuf:"bytes,2,opt,name=hardware_collection,json=hardwareCollection,proto3,oneof"`
+}
+
+type InstructionResult_HealthCheck struct {
+	HealthCheck *HealthCheckResult `protobuf:"bytes,3,opt,name=health_check,json=healthCheck,proto3,oneof"`
+}
+
+type InstructionResult_GatewayProbe struct {
+	GatewayProbe *GatewayProbeResult `protobuf:"bytes,4,opt,name=gateway_probe,json=gatewayProbe,proto3,oneof"`
+}
+
+type InstructionResult_NetworkConfig struct {
+	NetworkConfig *NetworkConfigResult `protobuf:"bytes,5,opt,name=network_config,json=networkConfig,proto3,oneof"`
+}
+
+type InstructionResult_Decommission struct {
+	Decommission *DecommissionResult `protobuf:"bytes,7,opt,name=decommission,proto3,oneof"`
+}
+
+type InstructionResult_RestartAgent struct {
+	RestartAgent *RestartAgentResult `protobuf:"bytes,9,opt,name=restart_agent,json=restartAgent,proto3,oneof"` // Future result types can be added here
+}
+
+func (*InstructionResult_HardwareCollection) isInstructionResult_Result() {}
+
+func (*InstructionResult_HealthCheck) isInstructionResult_Result() {}
+
+func (*InstructionResult_GatewayProbe) isInstructionResult_Result() {}
+
+func (*InstructionResult_NetworkConfig) isInstructionResult_Result() {}
+
+func (*InstructionResult_Decommission) isInstructionResult_Result() {}
+
+func (*InstructionResult_RestartAgent) isInstructionResult_Result() {}
+
+// GetInstructionsRequest requests pending instructions for an agent
+type GetInstructionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the agent requesting instructions
+	AgentId string `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// Optional runtime metrics snapshot; replaces the previously reported one
+	Metrics map[string]float64 `protobuf:"bytes,2,rep,name=metrics,proto3" json:"metrics,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	// Poll interval the agent is actually configured with (optional), compared
+	// against the instructed interval to detect agents polling off cadence
+	ReportedPollIntervalSeconds int32 `protobuf:"varint,3,opt,name=reported_poll_interval_seconds,json=reportedPollIntervalSeconds,proto3" json:"reported_poll_interval_seconds,omitempty"`
+	unknownFields               protoimpl.UnknownFields
+	sizeCache                   protoimpl.SizeCache
+}
+
+func (x *GetInstructionsRequest) Reset() {
+	*x = GetInstructionsRequest{}
+	mi := &file_v1_agent_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInstructionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInstructionsRequest) ProtoMessage() {}
+
+func (x *GetInstructionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInstructionsRequest.ProtoReflect.Descriptor instead.
+func (*GetInstructionsRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *GetInstructionsRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *GetInstructionsRequest) GetMetrics() map[string]float64 {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *GetInstructionsRequest) GetReportedPollIntervalSeconds() int32 {
+	if x != nil {
+		return x.ReportedPollIntervalSeconds
+	}
+	return 0
+}
+
+// PreviewInstructionsRequest selects the agent to preview instructions for
+type PreviewInstructionsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the agent
+	AgentId       string `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PreviewInstructionsRequest) Reset() {
+	*x = PreviewInstructionsRequest{}
+	mi := &file_v1_agent_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PreviewInstructionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PreviewInstructionsRequest) ProtoMessage() {}
+
+func (x *PreviewInstructionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PreviewInstructionsRequest.ProtoReflect.Descriptor instead.
+func (*PreviewInstructionsRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *PreviewInstructionsRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+// GetInstructionsResponse returns instructions and polling configuration
+type GetInstructionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// List of pending instructions to execute
+	Instructions []*Instruction `protobuf:"bytes,1,rep,name=instructions,proto3" json:"instructions,omitempty"`
+	// Seconds to wait before the next poll
+	// Default behavior: agent should poll after this interval
+	PollIntervalSeconds int32 `protobuf:"varint,2,opt,name=poll_interval_seconds,json=pollIntervalSeconds,proto3" json:"poll_interval_seconds,omitempty"`
+	// Server timestamp when response was generated
+	ServerTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`
+	// Seconds agents should wait before retrying failed requests; non-zero
+	// only while the server is throttling the fleet
+	BackoffSeconds int32 `protobuf:"varint,4,opt,name=backoff_seconds,json=backoffSeconds,proto3" json:"backoff_seconds,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetInstructionsResponse) Reset() {
+	*x = GetInstructionsResponse{}
+	mi := &file_v1_agent_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInstructionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInstructionsResponse) ProtoMessage() {}
+
+func (x *GetInstructionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInstructionsResponse.ProtoReflect.Descriptor instead.
+func (*GetInstructionsResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *GetInstructionsResponse) GetInstructions() []*Instruction {
+	if x != nil {
+		return x.Instructions
+	}
+	return nil
+}
+
+func (x *GetInstructionsResponse) GetPollIntervalSeconds() int32 {
+	if x != nil {
+		return x.PollIntervalSeconds
+	}
+	return 0
+}
+
+func (x *GetInstructionsResponse) GetServerTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ServerTime
+	}
+	return nil
+}
+
+func (x *GetInstructionsResponse) GetBackoffSeconds() int32 {
+	if x != nil {
+		return x.BackoffSeconds
+	}
+	return 0
+}
+
+// SubmitInstructionResultRequest submits the result of an instruction
+type SubmitInstructionResultRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the agent submitting the result
+	AgentId string `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	// ID of the instruction that was executed
+	InstructionId string `protobuf:"bytes,2,opt,name=instruction_id,json=instructionId,proto3" json:"instruction_id,omitempty"`
+	// Result of the instruction execution
+	Result        *InstructionResult `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitInstructionResultRequest) Reset() {
+	*x = SubmitInstructionResultRequest{}
+	mi := &file_v1_agent_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitInstructionResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitInstructionResultRequest) ProtoMessage() {}
+
+func (x *SubmitInstructionResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitInstructionResultRequest.ProtoReflect.Descriptor instead.
+func (*SubmitInstructionResultRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *SubmitInstructionResultRequest) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *SubmitInstructionResultRequest) GetInstructionId() string {
+	if x != nil {
+		return x.InstructionId
+	}
+	return ""
+}
+
+func (x *SubmitInstructionResultRequest) GetResult() *InstructionResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+// SubmitInstructionResultResponse confirms receipt of the instruction result
+type SubmitInstructionResultResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether the result was successfully processed
+	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// Optional message (error details or acknowledgment)
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitInstructionResultResponse) Reset() {
+	*x = SubmitInstructionResultResponse{}
+	mi := &file_v1_agent_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitInstructionResultResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAgentRequest) ProtoMessage() {}
+func (*SubmitInstructionResultResponse) ProtoMessage() {}
 
-func (x *GetAgentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[5]
+func (x *SubmitInstructionResultResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -765,41 +4295,53 @@ func (x *GetAgentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAgentRequest.ProtoReflect.Descriptor instead.
-func (*GetAgentRequest) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use SubmitInstructionResultResponse.ProtoReflect.Descriptor instead.
+func (*SubmitInstructionResultResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{53}
 }
 
-func (x *GetAgentRequest) GetId() string {
+func (x *SubmitInstructionResultResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Id
+		return x.Success
+	}
+	return false
+}
+
+func (x *SubmitInstructionResultResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
 	}
 	return ""
 }
 
-// GetAgentResponse returns the requested agent
-type GetAgentResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Agent         *Agent                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+// InstructionResultChunk carries part of a streamed instruction result
+type InstructionResultChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Identify the result; required on the first chunk and, when set on later
+	// chunks, must match it
+	AgentId       string `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	InstructionId string `protobuf:"bytes,2,opt,name=instruction_id,json=instructionId,proto3" json:"instruction_id,omitempty"`
+	// Next slice of the InstructionResult in protobuf wire format
+	Data          []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAgentResponse) Reset() {
-	*x = GetAgentResponse{}
-	mi := &file_v1_agent_proto_msgTypes[6]
+func (x *InstructionResultChunk) Reset() {
+	*x = InstructionResultChunk{}
+	mi := &file_v1_agent_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAgentResponse) String() string {
+func (x *InstructionResultChunk) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAgentResponse) ProtoMessage() {}
+func (*InstructionResultChunk) ProtoMessage() {}
 
-func (x *GetAgentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[6]
+func (x *InstructionResultChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -810,42 +4352,54 @@ func (x *GetAgentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAgentResponse.ProtoReflect.Descriptor instead.
-func (*GetAgentResponse) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use InstructionResultChunk.ProtoReflect.Descriptor instead.
+func (*InstructionResultChunk) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{54}
 }
 
-func (x *GetAgentResponse) GetAgent() *Agent {
+func (x *InstructionResultChunk) GetAgentId() string {
 	if x != nil {
-		return x.Agent
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *InstructionResultChunk) GetInstructionId() string {
+	if x != nil {
+		return x.InstructionId
+	}
+	return ""
+}
+
+func (x *InstructionResultChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
 	}
 	return nil
 }
 
-// ListAgentsRequest contains parameters for listing agents
-type ListAgentsRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Optional cluster ID filter
-	ClusterId     string `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+// GetServerStatsRequest is the request for a server stats snapshot
+type GetServerStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAgentsRequest) Reset() {
-	*x = ListAgentsRequest{}
-	mi := &file_v1_agent_proto_msgTypes[7]
+func (x *GetServerStatsRequest) Reset() {
+	*x = GetServerStatsRequest{}
+	mi := &file_v1_agent_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAgentsRequest) String() string {
+func (x *GetServerStatsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAgentsRequest) ProtoMessage() {}
+func (*GetServerStatsRequest) ProtoMessage() {}
 
-func (x *ListAgentsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[7]
+func (x *GetServerStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -856,41 +4410,48 @@ func (x *ListAgentsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAgentsRequest.ProtoReflect.Descriptor instead.
-func (*ListAgentsRequest) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{7}
-}
-
-func (x *ListAgentsRequest) GetClusterId() string {
-	if x != nil {
-		return x.ClusterId
-	}
-	return ""
+// Deprecated: Use GetServerStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetServerStatsRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{55}
 }
 
-// ListAgentsResponse returns a list of agents
-type ListAgentsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Agents        []*Agent               `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+// GetServerStatsResponse is a point-in-time snapshot of the server process
+// and the data it manages
+type GetServerStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of running goroutines
+	Goroutines int32        `protobuf:"varint,1,opt,name=goroutines,proto3" json:"goroutines,omitempty"`
+	Memory     *MemoryStats `protobuf:"bytes,2,opt,name=memory,proto3" json:"memory,omitempty"`
+	// Time since the server started
+	UptimeSeconds int64 `protobuf:"varint,3,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
+	TotalClusters int32 `protobuf:"varint,4,opt,name=total_clusters,json=totalClusters,proto3" json:"total_clusters,omitempty"`
+	TotalAgents   int32 `protobuf:"varint,5,opt,name=total_agents,json=totalAgents,proto3" json:"total_agents,omitempty"`
+	// Agents per status
+	ActiveAgents   int32 `protobuf:"varint,6,opt,name=active_agents,json=activeAgents,proto3" json:"active_agents,omitempty"`
+	InactiveAgents int32 `protobuf:"varint,7,opt,name=inactive_agents,json=inactiveAgents,proto3" json:"inactive_agents,omitempty"`
+	StaleAgents    int32 `protobuf:"varint,8,opt,name=stale_agents,json=staleAgents,proto3" json:"stale_agents,omitempty"`
+	ExpiredAgents  int32 `protobuf:"varint,9,opt,name=expired_agents,json=expiredAgents,proto3" json:"expired_agents,omitempty"`
+	// Database connection pool; unset when the backend has no pool
+	DatabasePool  *DatabasePoolStats `protobuf:"bytes,10,opt,name=database_pool,json=databasePool,proto3" json:"database_pool,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAgentsResponse) Reset() {
-	*x = ListAgentsResponse{}
-	mi := &file_v1_agent_proto_msgTypes[8]
+func (x *GetServerStatsResponse) Reset() {
+	*x = GetServerStatsResponse{}
+	mi := &file_v1_agent_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAgentsResponse) String() string {
+func (x *GetServerStatsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAgentsResponse) ProtoMessage() {}
+func (*GetServerStatsResponse) ProtoMessage() {}
 
-func (x *ListAgentsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[8]
+func (x *GetServerStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -901,88 +4462,111 @@ func (x *ListAgentsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAgentsResponse.ProtoReflect.Descriptor instead.
-func (*ListAgentsResponse) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use GetServerStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetServerStatsResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *ListAgentsResponse) GetAgents() []*Agent {
+func (x *GetServerStatsResponse) GetGoroutines() int32 {
 	if x != nil {
-		return x.Agents
+		return x.Goroutines
+	}
+	return 0
+}
+
+func (x *GetServerStatsResponse) GetMemory() *MemoryStats {
+	if x != nil {
+		return x.Memory
 	}
 	return nil
 }
 
-// UnregisterAgentRequest contains parameters for unregistering an agent
-type UnregisterAgentRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// ID of the agent to unregister
-	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *GetServerStatsResponse) GetUptimeSeconds() int64 {
+	if x != nil {
+		return x.UptimeSeconds
+	}
+	return 0
 }
 
-func (x *UnregisterAgentRequest) Reset() {
-	*x = UnregisterAgentRequest{}
-	mi := &file_v1_agent_proto_msgTypes[9]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *GetServerStatsResponse) GetTotalClusters() int32 {
+	if x != nil {
+		return x.TotalClusters
+	}
+	return 0
 }
 
-func (x *UnregisterAgentRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *GetServerStatsResponse) GetTotalAgents() int32 {
+	if x != nil {
+		return x.TotalAgents
+	}
+	return 0
 }
 
-func (*UnregisterAgentRequest) ProtoMessage() {}
+func (x *GetServerStatsResponse) GetActiveAgents() int32 {
+	if x != nil {
+		return x.ActiveAgents
+	}
+	return 0
+}
 
-func (x *UnregisterAgentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[9]
+func (x *GetServerStatsResponse) GetInactiveAgents() int32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.InactiveAgents
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use UnregisterAgentRequest.ProtoReflect.Descriptor instead.
-func (*UnregisterAgentRequest) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{9}
+func (x *GetServerStatsResponse) GetStaleAgents() int32 {
+	if x != nil {
+		return x.StaleAgents
+	}
+	return 0
 }
 
-func (x *UnregisterAgentRequest) GetId() string {
+func (x *GetServerStatsResponse) GetExpiredAgents() int32 {
 	if x != nil {
-		return x.Id
+		return x.ExpiredAgents
 	}
-	return ""
+	return 0
 }
 
-// UnregisterAgentResponse confirms unregistration
-type UnregisterAgentResponse struct {
+func (x *GetServerStatsResponse) GetDatabasePool() *DatabasePoolStats {
+	if x != nil {
+		return x.DatabasePool
+	}
+	return nil
+}
+
+// MemoryStats summarizes the Go runtime memory statistics
+type MemoryStats struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Whether the unregistration was successful
-	Success       bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// Bytes of allocated heap objects
+	HeapAllocBytes uint64 `protobuf:"varint,1,opt,name=heap_alloc_bytes,json=heapAllocBytes,proto3" json:"heap_alloc_bytes,omitempty"`
+	// Bytes in in-use heap spans
+	HeapInuseBytes uint64 `protobuf:"varint,2,opt,name=heap_inuse_bytes,json=heapInuseBytes,proto3" json:"heap_inuse_bytes,omitempty"`
+	// Total bytes obtained from the OS
+	SysBytes uint64 `protobuf:"varint,3,opt,name=sys_bytes,json=sysBytes,proto3" json:"sys_bytes,omitempty"`
+	// Completed garbage collection cycles
+	NumGc         uint32 `protobuf:"varint,4,opt,name=num_gc,json=numGc,proto3" json:"num_gc,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UnregisterAgentResponse) Reset() {
-	*x = UnregisterAgentResponse{}
-	mi := &file_v1_agent_proto_msgTypes[10]
+func (x *MemoryStats) Reset() {
+	*x = MemoryStats{}
+	mi := &file_v1_agent_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UnregisterAgentResponse) String() string {
+func (x *MemoryStats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UnregisterAgentResponse) ProtoMessage() {}
+func (*MemoryStats) ProtoMessage() {}
 
-func (x *UnregisterAgentResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[10]
+func (x *MemoryStats) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -993,48 +4577,67 @@ func (x *UnregisterAgentResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UnregisterAgentResponse.ProtoReflect.Descriptor instead.
-func (*UnregisterAgentResponse) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use MemoryStats.ProtoReflect.Descriptor instead.
+func (*MemoryStats) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *UnregisterAgentResponse) GetSuccess() bool {
+func (x *MemoryStats) GetHeapAllocBytes() uint64 {
 	if x != nil {
-		return x.Success
+		return x.HeapAllocBytes
 	}
-	return false
+	return 0
 }
 
-// Instruction represents a command or directive from the service to an agent
-type Instruction struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Unique instruction ID
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// Type of instruction
-	Type InstructionType `protobuf:"varint,2,opt,name=type,proto3,enum=netctrl.v1.InstructionType" json:"type,omitempty"`
-	// Instruction payload (type-specific data as JSON)
-	Payload string `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
-	// When the instruction was created
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+func (x *MemoryStats) GetHeapInuseBytes() uint64 {
+	if x != nil {
+		return x.HeapInuseBytes
+	}
+	return 0
+}
+
+func (x *MemoryStats) GetSysBytes() uint64 {
+	if x != nil {
+		return x.SysBytes
+	}
+	return 0
+}
+
+func (x *MemoryStats) GetNumGc() uint32 {
+	if x != nil {
+		return x.NumGc
+	}
+	return 0
+}
+
+// DatabasePoolStats reports the state of the database connection pool
+type DatabasePoolStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TotalConns    int32                  `protobuf:"varint,1,opt,name=total_conns,json=totalConns,proto3" json:"total_conns,omitempty"`
+	IdleConns     int32                  `protobuf:"varint,2,opt,name=idle_conns,json=idleConns,proto3" json:"idle_conns,omitempty"`
+	AcquiredConns int32                  `protobuf:"varint,3,opt,name=acquired_conns,json=acquiredConns,proto3" json:"acquired_conns,omitempty"`
+	MaxConns      int32                  `protobuf:"varint,4,opt,name=max_conns,json=maxConns,proto3" json:"max_conns,omitempty"`
+	// Cumulative successful connection acquisitions
+	AcquireCount  int64 `protobuf:"varint,5,opt,name=acquire_count,json=acquireCount,proto3" json:"acquire_count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Instruction) Reset() {
-	*x = Instruction{}
-	mi := &file_v1_agent_proto_msgTypes[11]
+func (x *DatabasePoolStats) Reset() {
+	*x = DatabasePoolStats{}
+	mi := &file_v1_agent_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Instruction) String() string {
+func (x *DatabasePoolStats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Instruction) ProtoMessage() {}
+func (*DatabasePoolStats) ProtoMessage() {}
 
-func (x *Instruction) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[11]
+func (x *DatabasePoolStats) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1045,63 +4648,73 @@ func (x *Instruction) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Instruction.ProtoReflect.Descriptor instead.
-func (*Instruction) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use DatabasePoolStats.ProtoReflect.Descriptor instead.
+func (*DatabasePoolStats) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{58}
 }
 
-func (x *Instruction) GetId() string {
+func (x *DatabasePoolStats) GetTotalConns() int32 {
 	if x != nil {
-		return x.Id
+		return x.TotalConns
 	}
-	return ""
+	return 0
 }
 
-func (x *Instruction) GetType() InstructionType {
+func (x *DatabasePoolStats) GetIdleConns() int32 {
 	if x != nil {
-		return x.Type
+		return x.IdleConns
 	}
-	return InstructionType_INSTRUCTION_TYPE_UNSPECIFIED
+	return 0
 }
 
-func (x *Instruction) GetPayload() string {
+func (x *DatabasePoolStats) GetAcquiredConns() int32 {
 	if x != nil {
-		return x.Payload
+		return x.AcquiredConns
 	}
-	return ""
+	return 0
 }
 
-func (x *Instruction) GetCreatedAt() *timestamppb.Timestamp {
+func (x *DatabasePoolStats) GetMaxConns() int32 {
 	if x != nil {
-		return x.CreatedAt
+		return x.MaxConns
 	}
-	return nil
+	return 0
 }
 
-// HardwareCollectionResult contains the result of hardware collection
-type HardwareCollectionResult struct {
+func (x *DatabasePoolStats) GetAcquireCount() int64 {
+	if x != nil {
+		return x.AcquireCount
+	}
+	return 0
+}
+
+// GetFleetReportRequest selects the agents to report on
+type GetFleetReportRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Collected Mellanox NICs
-	NetworkInterfaces []*MellanoxNIC `protobuf:"bytes,1,rep,name=network_interfaces,json=networkInterfaces,proto3" json:"network_interfaces,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// Limit the report to one cluster; empty reports on all agents
+	ClusterId string `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	// Maximum number of sample agent IDs per category; 0 uses the server's
+	// configured sample size, which also caps larger values
+	SampleSize    int32 `protobuf:"varint,2,opt,name=sample_size,json=sampleSize,proto3" json:"sample_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HardwareCollectionResult) Reset() {
-	*x = HardwareCollectionResult{}
-	mi := &file_v1_agent_proto_msgTypes[12]
+func (x *GetFleetReportRequest) Reset() {
+	*x = GetFleetReportRequest{}
+	mi := &file_v1_agent_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HardwareCollectionResult) String() string {
+func (x *GetFleetReportRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HardwareCollectionResult) ProtoMessage() {}
+func (*GetFleetReportRequest) ProtoMessage() {}
 
-func (x *HardwareCollectionResult) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[12]
+func (x *GetFleetReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1112,44 +4725,51 @@ func (x *HardwareCollectionResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HardwareCollectionResult.ProtoReflect.Descriptor instead.
-func (*HardwareCollectionResult) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use GetFleetReportRequest.ProtoReflect.Descriptor instead.
+func (*GetFleetReportRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *HardwareCollectionResult) GetNetworkInterfaces() []*MellanoxNIC {
+func (x *GetFleetReportRequest) GetClusterId() string {
 	if x != nil {
-		return x.NetworkInterfaces
+		return x.ClusterId
 	}
-	return nil
+	return ""
 }
 
-// HealthCheckResult contains the result of a health check
-type HealthCheckResult struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Health status
-	Healthy bool `protobuf:"varint,1,opt,name=healthy,proto3" json:"healthy,omitempty"`
-	// Optional error message if unhealthy
-	ErrorMessage  string `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *GetFleetReportRequest) GetSampleSize() int32 {
+	if x != nil {
+		return x.SampleSize
+	}
+	return 0
 }
 
-func (x *HealthCheckResult) Reset() {
-	*x = HealthCheckResult{}
-	mi := &file_v1_agent_proto_msgTypes[13]
+// FleetReportCategory counts the agents in one problem category
+type FleetReportCategory struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Count int32                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	// IDs of the first agents in the category by creation time, as a starting
+	// point for investigation; ListFleetReportAgents lists them all
+	SampleAgentIds []string `protobuf:"bytes,2,rep,name=sample_agent_ids,json=sampleAgentIds,proto3" json:"sample_agent_ids,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *FleetReportCategory) Reset() {
+	*x = FleetReportCategory{}
+	mi := &file_v1_agent_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HealthCheckResult) String() string {
+func (x *FleetReportCategory) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthCheckResult) ProtoMessage() {}
+func (*FleetReportCategory) ProtoMessage() {}
 
-func (x *HealthCheckResult) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[13]
+func (x *FleetReportCategory) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1160,56 +4780,56 @@ func (x *HealthCheckResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthCheckResult.ProtoReflect.Descriptor instead.
-func (*HealthCheckResult) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use FleetReportCategory.ProtoReflect.Descriptor instead.
+func (*FleetReportCategory) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *HealthCheckResult) GetHealthy() bool {
+func (x *FleetReportCategory) GetCount() int32 {
 	if x != nil {
-		return x.Healthy
+		return x.Count
 	}
-	return false
+	return 0
 }
 
-func (x *HealthCheckResult) GetErrorMessage() string {
+func (x *FleetReportCategory) GetSampleAgentIds() []string {
 	if x != nil {
-		return x.ErrorMessage
+		return x.SampleAgentIds
 	}
-	return ""
+	return nil
 }
 
-// InstructionResult represents the result of executing an instruction
-type InstructionResult struct {
+// ListFleetReportAgentsRequest selects a fleet report category to list
+type ListFleetReportAgentsRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Type of instruction that was executed
-	InstructionType InstructionType `protobuf:"varint,1,opt,name=instruction_type,json=instructionType,proto3,enum=netctrl.v1.InstructionType" json:"instruction_type,omitempty"`
-	// Type-specific result data
-	//
-	// Types that are valid to be assigned to Result:
-	//
-	//	*InstructionResult_HardwareCollection
-	//	*InstructionResult_HealthCheck
-	Result        isInstructionResult_Result `protobuf_oneof:"result"`
+	// Limit the listing to one cluster; empty lists across all agents
+	ClusterId string `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	// Category to list agents of
+	Category FleetReportCategoryType `protobuf:"varint,2,opt,name=category,proto3,enum=netctrl.v1.FleetReportCategoryType" json:"category,omitempty"`
+	// Maximum number of agents to return; agents are ordered by creation time
+	// and ID, matching the order of the report's samples
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Token from a previous response's next_page_token to continue listing
+	PageToken     string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *InstructionResult) Reset() {
-	*x = InstructionResult{}
-	mi := &file_v1_agent_proto_msgTypes[14]
+func (x *ListFleetReportAgentsRequest) Reset() {
+	*x = ListFleetReportAgentsRequest{}
+	mi := &file_v1_agent_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *InstructionResult) String() string {
+func (x *ListFleetReportAgentsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*InstructionResult) ProtoMessage() {}
+func (*ListFleetReportAgentsRequest) ProtoMessage() {}
 
-func (x *InstructionResult) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[14]
+func (x *ListFleetReportAgentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1220,83 +4840,66 @@ func (x *InstructionResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use InstructionResult.ProtoReflect.Descriptor instead.
-func (*InstructionResult) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ListFleetReportAgentsRequest.ProtoReflect.Descriptor instead.
+func (*ListFleetReportAgentsRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *InstructionResult) GetInstructionType() InstructionType {
+func (x *ListFleetReportAgentsRequest) GetClusterId() string {
 	if x != nil {
-		return x.InstructionType
+		return x.ClusterId
 	}
-	return InstructionType_INSTRUCTION_TYPE_UNSPECIFIED
+	return ""
 }
 
-func (x *InstructionResult) GetResult() isInstructionResult_Result {
+func (x *ListFleetReportAgentsRequest) GetCategory() FleetReportCategoryType {
 	if x != nil {
-		return x.Result
+		return x.Category
 	}
-	return nil
+	return FleetReportCategoryType_FLEET_REPORT_CATEGORY_TYPE_UNSPECIFIED
 }
 
-func (x *InstructionResult) GetHardwareCollection() *HardwareCollectionResult {
+func (x *ListFleetReportAgentsRequest) GetPageSize() int32 {
 	if x != nil {
-		if x, ok := x.Result.(*InstructionResult_HardwareCollection); ok {
-			return x.HardwareCollection
-		}
+		return x.PageSize
 	}
-	return nil
+	return 0
 }
 
-func (x *InstructionResult) GetHealthCheck() *HealthCheckResult {
+func (x *ListFleetReportAgentsRequest) GetPageToken() string {
 	if x != nil {
-		if x, ok := x.Result.(*InstructionResult_HealthCheck); ok {
-			return x.HealthCheck
-		}
+		return x.PageToken
 	}
-	return nil
-}
-
-type isInstructionResult_Result interface {
-	isInstructionResult_Result()
-}
-
-type InstructionResult_HardwareCollection struct {
-	HardwareCollection *HardwareCollectionResult `protobuf:"bytes,2,opt,name=hardware_collection,json=hardwareCollection,proto3,oneof"`
-}
-
-type InstructionResult_HealthCheck struct {
-	HealthCheck *HealthCheckResult `protobuf:"bytes,3,opt,name=health_check,json=healthCheck,proto3,oneof"` // Future result types can be added here
+	return ""
 }
 
-func (*InstructionResult_HardwareCollection) isInstructionResult_Result() {}
-
-func (*InstructionResult_HealthCheck) isInstructionResult_Result() {}
-
-// GetInstructionsRequest requests pending instructions for an agent
-type GetInstructionsRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// ID of the agent requesting instructions
-	AgentId       string `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+// ListFleetReportAgentsResponse returns a page of the agents in a category
+type ListFleetReportAgentsResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Agents []*Agent               `protobuf:"bytes,1,rep,name=agents,proto3" json:"agents,omitempty"`
+	// Token for the next page; empty on the last page
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Number of agents in the category across all pages
+	TotalCount    int32 `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetInstructionsRequest) Reset() {
-	*x = GetInstructionsRequest{}
-	mi := &file_v1_agent_proto_msgTypes[15]
+func (x *ListFleetReportAgentsResponse) Reset() {
+	*x = ListFleetReportAgentsResponse{}
+	mi := &file_v1_agent_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetInstructionsRequest) String() string {
+func (x *ListFleetReportAgentsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetInstructionsRequest) ProtoMessage() {}
+func (*ListFleetReportAgentsResponse) ProtoMessage() {}
 
-func (x *GetInstructionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[15]
+func (x *ListFleetReportAgentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1307,47 +4910,70 @@ func (x *GetInstructionsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetInstructionsRequest.ProtoReflect.Descriptor instead.
-func (*GetInstructionsRequest) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use ListFleetReportAgentsResponse.ProtoReflect.Descriptor instead.
+func (*ListFleetReportAgentsResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *GetInstructionsRequest) GetAgentId() string {
+func (x *ListFleetReportAgentsResponse) GetAgents() []*Agent {
 	if x != nil {
-		return x.AgentId
+		return x.Agents
+	}
+	return nil
+}
+
+func (x *ListFleetReportAgentsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
 	}
 	return ""
 }
 
-// GetInstructionsResponse returns instructions and polling configuration
-type GetInstructionsResponse struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// List of pending instructions to execute
-	Instructions []*Instruction `protobuf:"bytes,1,rep,name=instructions,proto3" json:"instructions,omitempty"`
-	// Seconds to wait before the next poll
-	// Default behavior: agent should poll after this interval
-	PollIntervalSeconds int32 `protobuf:"varint,2,opt,name=poll_interval_seconds,json=pollIntervalSeconds,proto3" json:"poll_interval_seconds,omitempty"`
-	// Server timestamp when response was generated
-	ServerTime    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`
+func (x *ListFleetReportAgentsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+// GetFleetReportResponse groups the reported agents by problem category; an
+// agent may appear in several categories
+type GetFleetReportResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ClusterId string                 `protobuf:"bytes,1,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	// Number of agents the report covers
+	TotalAgents int32 `protobuf:"varint,2,opt,name=total_agents,json=totalAgents,proto3" json:"total_agents,omitempty"`
+	// Agents not active: inactive, stale or expired
+	Inactive *FleetReportCategory `protobuf:"bytes,3,opt,name=inactive,proto3" json:"inactive,omitempty"`
+	// Agents whose applied network config differs from their cluster's
+	ConfigDrifted *FleetReportCategory `protobuf:"bytes,4,opt,name=config_drifted,json=configDrifted,proto3" json:"config_drifted,omitempty"`
+	// Agents running a version older than latest_version
+	OutdatedVersion *FleetReportCategory `protobuf:"bytes,5,opt,name=outdated_version,json=outdatedVersion,proto3" json:"outdated_version,omitempty"`
+	// Agents that have not completed hardware collection
+	MissingHardware *FleetReportCategory `protobuf:"bytes,6,opt,name=missing_hardware,json=missingHardware,proto3" json:"missing_hardware,omitempty"`
+	// Agents polling far off their instructed interval
+	PollIntervalDrifted *FleetReportCategory `protobuf:"bytes,7,opt,name=poll_interval_drifted,json=pollIntervalDrifted,proto3" json:"poll_interval_drifted,omitempty"`
+	// Newest agent version among the reported agents
+	LatestVersion string `protobuf:"bytes,8,opt,name=latest_version,json=latestVersion,proto3" json:"latest_version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetInstructionsResponse) Reset() {
-	*x = GetInstructionsResponse{}
-	mi := &file_v1_agent_proto_msgTypes[16]
+func (x *GetFleetReportResponse) Reset() {
+	*x = GetFleetReportResponse{}
+	mi := &file_v1_agent_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetInstructionsResponse) String() string {
+func (x *GetFleetReportResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetInstructionsResponse) ProtoMessage() {}
+func (*GetFleetReportResponse) ProtoMessage() {}
 
-func (x *GetInstructionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[16]
+func (x *GetFleetReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1358,60 +4984,89 @@ func (x *GetInstructionsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetInstructionsResponse.ProtoReflect.Descriptor instead.
-func (*GetInstructionsResponse) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use GetFleetReportResponse.ProtoReflect.Descriptor instead.
+func (*GetFleetReportResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *GetInstructionsResponse) GetInstructions() []*Instruction {
+func (x *GetFleetReportResponse) GetClusterId() string {
 	if x != nil {
-		return x.Instructions
+		return x.ClusterId
 	}
-	return nil
+	return ""
 }
 
-func (x *GetInstructionsResponse) GetPollIntervalSeconds() int32 {
+func (x *GetFleetReportResponse) GetTotalAgents() int32 {
 	if x != nil {
-		return x.PollIntervalSeconds
+		return x.TotalAgents
 	}
 	return 0
 }
 
-func (x *GetInstructionsResponse) GetServerTime() *timestamppb.Timestamp {
+func (x *GetFleetReportResponse) GetInactive() *FleetReportCategory {
 	if x != nil {
-		return x.ServerTime
+		return x.Inactive
 	}
 	return nil
 }
 
-// SubmitInstructionResultRequest submits the result of an instruction
-type SubmitInstructionResultRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// ID of the agent submitting the result
-	AgentId string `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	// ID of the instruction that was executed
-	InstructionId string `protobuf:"bytes,2,opt,name=instruction_id,json=instructionId,proto3" json:"instruction_id,omitempty"`
-	// Result of the instruction execution
-	Result        *InstructionResult `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`
+func (x *GetFleetReportResponse) GetConfigDrifted() *FleetReportCategory {
+	if x != nil {
+		return x.ConfigDrifted
+	}
+	return nil
+}
+
+func (x *GetFleetReportResponse) GetOutdatedVersion() *FleetReportCategory {
+	if x != nil {
+		return x.OutdatedVersion
+	}
+	return nil
+}
+
+func (x *GetFleetReportResponse) GetMissingHardware() *FleetReportCategory {
+	if x != nil {
+		return x.MissingHardware
+	}
+	return nil
+}
+
+func (x *GetFleetReportResponse) GetPollIntervalDrifted() *FleetReportCategory {
+	if x != nil {
+		return x.PollIntervalDrifted
+	}
+	return nil
+}
+
+func (x *GetFleetReportResponse) GetLatestVersion() string {
+	if x != nil {
+		return x.LatestVersion
+	}
+	return ""
+}
+
+// GetFleetHardwareSummaryRequest requests the fleet-wide NIC counts
+type GetFleetHardwareSummaryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SubmitInstructionResultRequest) Reset() {
-	*x = SubmitInstructionResultRequest{}
-	mi := &file_v1_agent_proto_msgTypes[17]
+func (x *GetFleetHardwareSummaryRequest) Reset() {
+	*x = GetFleetHardwareSummaryRequest{}
+	mi := &file_v1_agent_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SubmitInstructionResultRequest) String() string {
+func (x *GetFleetHardwareSummaryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubmitInstructionResultRequest) ProtoMessage() {}
+func (*GetFleetHardwareSummaryRequest) ProtoMessage() {}
 
-func (x *SubmitInstructionResultRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[17]
+func (x *GetFleetHardwareSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1422,58 +5077,98 @@ func (x *SubmitInstructionResultRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubmitInstructionResultRequest.ProtoReflect.Descriptor instead.
-func (*SubmitInstructionResultRequest) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use GetFleetHardwareSummaryRequest.ProtoReflect.Descriptor instead.
+func (*GetFleetHardwareSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{64}
 }
 
-func (x *SubmitInstructionResultRequest) GetAgentId() string {
+// NICModelCount is the number of NICs of one model running one firmware version
+type NICModelCount struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PartNumber      string                 `protobuf:"bytes,1,opt,name=part_number,json=partNumber,proto3" json:"part_number,omitempty"`
+	FirmwareVersion string                 `protobuf:"bytes,2,opt,name=firmware_version,json=firmwareVersion,proto3" json:"firmware_version,omitempty"`
+	Count           int32                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *NICModelCount) Reset() {
+	*x = NICModelCount{}
+	mi := &file_v1_agent_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NICModelCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NICModelCount) ProtoMessage() {}
+
+func (x *NICModelCount) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[65]
 	if x != nil {
-		return x.AgentId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NICModelCount.ProtoReflect.Descriptor instead.
+func (*NICModelCount) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *NICModelCount) GetPartNumber() string {
+	if x != nil {
+		return x.PartNumber
 	}
 	return ""
 }
 
-func (x *SubmitInstructionResultRequest) GetInstructionId() string {
+func (x *NICModelCount) GetFirmwareVersion() string {
 	if x != nil {
-		return x.InstructionId
+		return x.FirmwareVersion
 	}
 	return ""
 }
 
-func (x *SubmitInstructionResultRequest) GetResult() *InstructionResult {
+func (x *NICModelCount) GetCount() int32 {
 	if x != nil {
-		return x.Result
+		return x.Count
 	}
-	return nil
+	return 0
 }
 
-// SubmitInstructionResultResponse confirms receipt of the instruction result
-type SubmitInstructionResultResponse struct {
+// GetFleetHardwareSummaryResponse returns NIC counts across all agents
+type GetFleetHardwareSummaryResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Whether the result was successfully processed
-	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	// Optional message (error details or acknowledgment)
-	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Counts per model and firmware, largest first
+	Models []*NICModelCount `protobuf:"bytes,1,rep,name=models,proto3" json:"models,omitempty"`
+	// Total NICs reported by all agents
+	TotalNics     int32 `protobuf:"varint,2,opt,name=total_nics,json=totalNics,proto3" json:"total_nics,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SubmitInstructionResultResponse) Reset() {
-	*x = SubmitInstructionResultResponse{}
-	mi := &file_v1_agent_proto_msgTypes[18]
+func (x *GetFleetHardwareSummaryResponse) Reset() {
+	*x = GetFleetHardwareSummaryResponse{}
+	mi := &file_v1_agent_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SubmitInstructionResultResponse) String() string {
+func (x *GetFleetHardwareSummaryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubmitInstructionResultResponse) ProtoMessage() {}
+func (*GetFleetHardwareSummaryResponse) ProtoMessage() {}
 
-func (x *SubmitInstructionResultResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_agent_proto_msgTypes[18]
+func (x *GetFleetHardwareSummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_agent_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1484,23 +5179,23 @@ func (x *SubmitInstructionResultResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubmitInstructionResultResponse.ProtoReflect.Descriptor instead.
-func (*SubmitInstructionResultResponse) Descriptor() ([]byte, []int) {
-	return file_v1_agent_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use GetFleetHardwareSummaryResponse.ProtoReflect.Descriptor instead.
+func (*GetFleetHardwareSummaryResponse) Descriptor() ([]byte, []int) {
+	return file_v1_agent_proto_rawDescGZIP(), []int{66}
 }
 
-func (x *SubmitInstructionResultResponse) GetSuccess() bool {
+func (x *GetFleetHardwareSummaryResponse) GetModels() []*NICModelCount {
 	if x != nil {
-		return x.Success
+		return x.Models
 	}
-	return false
+	return nil
 }
 
-func (x *SubmitInstructionResultResponse) GetMessage() string {
+func (x *GetFleetHardwareSummaryResponse) GetTotalNics() int32 {
 	if x != nil {
-		return x.Message
+		return x.TotalNics
 	}
-	return ""
+	return 0
 }
 
 var File_v1_agent_proto protoreflect.FileDescriptor
@@ -1508,7 +5203,7 @@ var File_v1_agent_proto protoreflect.FileDescriptor
 const file_v1_agent_proto_rawDesc = "" +
 	"\n" +
 	"\x0ev1/agent.proto\x12\n" +
-	"netctrl.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8f\x02\n" +
+	"netctrl.v1\x1a\x1cgoogle/api/annotations.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x10v1/network.proto\"\x8f\x02\n" +
 	"\fMellanoxPort\x12\x16\n" +
 	"\x06number\x18\x01 \x01(\x05R\x06number\x12+\n" +
 	"\x05state\x18\x02 \x01(\x0e2\x15.netctrl.v1.PortStateR\x05state\x12+\n" +
@@ -1532,7 +5227,7 @@ const file_v1_agent_proto_rawDesc = "" +
 	"\n" +
 	"port_count\x18\x06 \x01(\x05R\tportCount\x12.\n" +
 	"\x05ports\x18\a \x03(\v2\x18.netctrl.v1.MellanoxPortR\x05ports\x12\x12\n" +
-	"\x04psid\x18\b \x01(\tR\x04psid\"\xe2\x03\n" +
+	"\x04psid\x18\b \x01(\tR\x04psid\"\xc8\x0f\n" +
 	"\x05Agent\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
@@ -1549,7 +5244,63 @@ const file_v1_agent_proto_rawDesc = "" +
 	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12F\n" +
 	"\x12network_interfaces\x18\n" +
 	" \x03(\v2\x17.netctrl.v1.MellanoxNICR\x11networkInterfaces\x12-\n" +
-	"\x12hardware_collected\x18\v \x01(\bR\x11hardwareCollected\"\x9a\x01\n" +
+	"\x12hardware_collected\x18\v \x01(\bR\x11hardwareCollected\x12)\n" +
+	"\x04role\x18\f \x01(\x0e2\x15.netctrl.v1.AgentRoleR\x04role\x12L\n" +
+	"\x12last_gateway_probe\x18\r \x01(\v2\x1e.netctrl.v1.GatewayProbeResultR\x10lastGatewayProbe\x12M\n" +
+	"\x15last_gateway_probe_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\x12lastGatewayProbeAt\x12O\n" +
+	"\x16applied_network_config\x18\x0f \x01(\v2\x19.netctrl.v1.NetworkConfigR\x14appliedNetworkConfig\x12%\n" +
+	"\x0econfig_drifted\x18\x10 \x01(\bR\rconfigDrifted\x128\n" +
+	"\ametrics\x18\x11 \x03(\v2\x1e.netctrl.v1.Agent.MetricsEntryR\ametrics\x12J\n" +
+	"\x13metrics_reported_at\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\x11metricsReportedAt\x12\x14\n" +
+	"\x05group\x18\x13 \x01(\tR\x05group\x12C\n" +
+	"\rlast_outcomes\x18\x14 \x03(\v2\x1e.netctrl.v1.InstructionOutcomeR\flastOutcomes\x12(\n" +
+	"\x0fdecommissioning\x18\x15 \x01(\bR\x0fdecommissioning\x12K\n" +
+	"\x11last_registration\x18\x16 \x01(\v2\x1e.netctrl.v1.RegistrationSourceR\x10lastRegistration\x12G\n" +
+	" instructed_poll_interval_seconds\x18\x17 \x01(\x05R\x1dinstructedPollIntervalSeconds\x12C\n" +
+	"\x1ereported_poll_interval_seconds\x18\x18 \x01(\x05R\x1breportedPollIntervalSeconds\x122\n" +
+	"\x15poll_interval_drifted\x18\x19 \x01(\bR\x13pollIntervalDrifted\x12S\n" +
+	"\x14unrecognized_results\x18\x1a \x03(\v2 .netctrl.v1.RawInstructionResultR\x13unrecognizedResults\x12B\n" +
+	"\x0flast_healthy_at\x18\x1b \x01(\v2\x1a.google.protobuf.TimestampR\rlastHealthyAt\x12F\n" +
+	"\x10install_metadata\x18\x1c \x01(\v2\x1b.netctrl.v1.InstallMetadataR\x0finstallMetadata\x12A\n" +
+	"\vfailed_nics\x18\x1d \x03(\v2 .netctrl.v1.NICCollectionFailureR\n" +
+	"failedNics\x12+\n" +
+	"\x11restart_requested\x18\x1e \x01(\bR\x10restartRequested\x12B\n" +
+	"\x0flast_restart_at\x18\x1f \x01(\v2\x1a.google.protobuf.TimestampR\rlastRestartAt\x12K\n" +
+	"\x10config_overrides\x18  \x01(\v2 .netctrl.v1.AgentConfigOverridesR\x0fconfigOverrides\x12P\n" +
+	"\x14poll_interval_source\x18! \x01(\x0e2\x1e.netctrl.v1.PollIntervalSourceR\x12pollIntervalSource\x12\x1a\n" +
+	"\brevision\x18\" \x01(\x03R\brevision\x1a:\n" +
+	"\fMetricsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"\x9c\x01\n" +
+	"\x14AgentConfigOverrides\x122\n" +
+	"\x15poll_interval_seconds\x18\x01 \x01(\x05R\x13pollIntervalSeconds\x12P\n" +
+	"\x15disabled_instructions\x18\x02 \x03(\x0e2\x1b.netctrl.v1.InstructionTypeR\x14disabledInstructions\"\xb9\x01\n" +
+	"\x0fInstallMetadata\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12.\n" +
+	"\x13provisioning_job_id\x18\x02 \x01(\tR\x11provisioningJobId\x12!\n" +
+	"\finstalled_by\x18\x03 \x01(\tR\vinstalledBy\x12;\n" +
+	"\vrecorded_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"recordedAt\"\xdc\x01\n" +
+	"\x14RawInstructionResult\x12%\n" +
+	"\x0einstruction_id\x18\x01 \x01(\tR\rinstructionId\x12F\n" +
+	"\x10instruction_type\x18\x02 \x01(\x0e2\x1b.netctrl.v1.InstructionTypeR\x0finstructionType\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\fR\apayload\x12;\n" +
+	"\vreceived_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"receivedAt\"\xbc\x01\n" +
+	"\x12RegistrationSource\x12!\n" +
+	"\fpeer_address\x18\x01 \x01(\tR\vpeerAddress\x12#\n" +
+	"\rforwarded_for\x18\x02 \x01(\tR\fforwardedFor\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x03 \x01(\tR\tuserAgent\x12?\n" +
+	"\rregistered_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fregisteredAt\"\xb2\x02\n" +
+	"\x12InstructionOutcome\x12F\n" +
+	"\x10instruction_type\x18\x01 \x01(\x0e2\x1b.netctrl.v1.InstructionTypeR\x0finstructionType\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12;\n" +
+	"\vreceived_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"receivedAt\x12@\n" +
+	"\x0efailure_reason\x18\x04 \x01(\x0e2\x19.netctrl.v1.FailureReasonR\rfailureReason\x12;\n" +
+	"\vretry_after\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"retryAfter\"\xa3\x02\n" +
 	"\x14RegisterAgentRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
@@ -1557,56 +5308,277 @@ const file_v1_agent_proto_rawDesc = "" +
 	"\bhostname\x18\x03 \x01(\tR\bhostname\x12\x1d\n" +
 	"\n" +
 	"ip_address\x18\x04 \x01(\tR\tipAddress\x12\x18\n" +
-	"\aversion\x18\x05 \x01(\tR\aversion\"@\n" +
+	"\aversion\x18\x05 \x01(\tR\aversion\x12)\n" +
+	"\x04role\x18\x06 \x01(\x0e2\x15.netctrl.v1.AgentRoleR\x04role\x12\x14\n" +
+	"\x05group\x18\a \x01(\tR\x05group\x12F\n" +
+	"\x10install_metadata\x18\b \x01(\v2\x1b.netctrl.v1.InstallMetadataR\x0finstallMetadata\"@\n" +
 	"\x15RegisterAgentResponse\x12'\n" +
-	"\x05agent\x18\x01 \x01(\v2\x11.netctrl.v1.AgentR\x05agent\"!\n" +
+	"\x05agent\x18\x01 \x01(\v2\x11.netctrl.v1.AgentR\x05agent\"Z\n" +
 	"\x0fGetAgentRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\";\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x127\n" +
+	"\tread_mask\x18\x02 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\";\n" +
 	"\x10GetAgentResponse\x12'\n" +
-	"\x05agent\x18\x01 \x01(\v2\x11.netctrl.v1.AgentR\x05agent\"2\n" +
+	"\x05agent\x18\x01 \x01(\v2\x11.netctrl.v1.AgentR\x05agent\"\xc2\x02\n" +
 	"\x11ListAgentsRequest\x12\x1d\n" +
 	"\n" +
-	"cluster_id\x18\x01 \x01(\tR\tclusterId\"?\n" +
+	"cluster_id\x18\x01 \x01(\tR\tclusterId\x12&\n" +
+	"\x0fdown_ports_only\x18\x02 \x01(\bR\rdownPortsOnly\x127\n" +
+	"\tread_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\x12\x14\n" +
+	"\x05group\x18\x04 \x01(\tR\x05group\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\x122\n" +
+	"\x15unhealthy_for_seconds\x18\a \x01(\x05R\x13unhealthyForSeconds\x12'\n" +
+	"\x0frequire_cluster\x18\b \x01(\bR\x0erequireCluster\"g\n" +
 	"\x12ListAgentsResponse\x12)\n" +
-	"\x06agents\x18\x01 \x03(\v2\x11.netctrl.v1.AgentR\x06agents\"(\n" +
+	"\x06agents\x18\x01 \x03(\v2\x11.netctrl.v1.AgentR\x06agents\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"+\n" +
+	"\x17GetAgentStatusesRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"\xc0\x01\n" +
+	"\x18GetAgentStatusesResponse\x12N\n" +
+	"\bstatuses\x18\x01 \x03(\v22.netctrl.v1.GetAgentStatusesResponse.StatusesEntryR\bstatuses\x1aT\n" +
+	"\rStatusesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12-\n" +
+	"\x05value\x18\x02 \x01(\x0e2\x17.netctrl.v1.AgentStatusR\x05value:\x028\x01\"H\n" +
 	"\x16UnregisterAgentRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"3\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1e\n" +
+	"\n" +
+	"idempotent\x18\x02 \x01(\bR\n" +
+	"idempotent\"3\n" +
 	"\x17UnregisterAgentResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xa3\x01\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"*\n" +
+	"\x18DecommissionAgentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"D\n" +
+	"\x19DecommissionAgentResponse\x12'\n" +
+	"\x05agent\x18\x01 \x01(\v2\x11.netctrl.v1.AgentR\x05agent\"j\n" +
+	"\x18UpdateAgentConfigRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12>\n" +
+	"\toverrides\x18\x02 \x01(\v2 .netctrl.v1.AgentConfigOverridesR\toverrides\"D\n" +
+	"\x19UpdateAgentConfigResponse\x12'\n" +
+	"\x05agent\x18\x01 \x01(\v2\x11.netctrl.v1.AgentR\x05agent\"%\n" +
+	"\x13RestartAgentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"?\n" +
+	"\x14RestartAgentResponse\x12'\n" +
+	"\x05agent\x18\x01 \x01(\v2\x11.netctrl.v1.AgentR\x05agent\"^\n" +
+	" TriggerHardwareCollectionRequest\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x01 \x01(\tR\tclusterId\x12\x1b\n" +
+	"\tagent_ids\x18\x02 \x03(\tR\bagentIds\"@\n" +
+	"!TriggerHardwareCollectionResponse\x12\x1b\n" +
+	"\tagent_ids\x18\x01 \x03(\tR\bagentIds\"\xb3\x01\n" +
+	"#EnqueueInstructionBySelectorRequest\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x01 \x01(\tR\tclusterId\x12%\n" +
+	"\x0elabel_selector\x18\x02 \x01(\tR\rlabelSelector\x12F\n" +
+	"\x10instruction_type\x18\x03 \x01(\x0e2\x1b.netctrl.v1.InstructionTypeR\x0finstructionType\"j\n" +
+	"$EnqueueInstructionBySelectorResponse\x12%\n" +
+	"\x0etargeted_count\x18\x01 \x01(\x05R\rtargetedCount\x12\x1b\n" +
+	"\tagent_ids\x18\x02 \x03(\tR\bagentIds\"\x1b\n" +
+	"\x19FindOrphanedAgentsRequest\"G\n" +
+	"\x1aFindOrphanedAgentsResponse\x12)\n" +
+	"\x06agents\x18\x01 \x03(\v2\x11.netctrl.v1.AgentR\x06agents\"\x1b\n" +
+	"\x19ReapOrphanedAgentsRequest\"9\n" +
+	"\x1aReapOrphanedAgentsResponse\x12\x1b\n" +
+	"\tagent_ids\x18\x01 \x03(\tR\bagentIds\"f\n" +
+	"\x16SetThrottleModeRequest\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x122\n" +
+	"\x15poll_interval_seconds\x18\x02 \x01(\x05R\x13pollIntervalSeconds\"g\n" +
+	"\x17SetThrottleModeResponse\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x122\n" +
+	"\x15poll_interval_seconds\x18\x02 \x01(\x05R\x13pollIntervalSeconds\";\n" +
+	"\x1aGetClusterPollStatsRequest\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x01 \x01(\tR\tclusterId\"\xd6\x02\n" +
+	"\x1bGetClusterPollStatsResponse\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x01 \x01(\tR\tclusterId\x12\x1f\n" +
+	"\vagent_count\x18\x02 \x01(\x05R\n" +
+	"agentCount\x12!\n" +
+	"\fsample_count\x18\x03 \x01(\x05R\vsampleCount\x120\n" +
+	"\x14min_interval_seconds\x18\x04 \x01(\x01R\x12minIntervalSeconds\x120\n" +
+	"\x14avg_interval_seconds\x18\x05 \x01(\x01R\x12avgIntervalSeconds\x120\n" +
+	"\x14max_interval_seconds\x18\x06 \x01(\x01R\x12maxIntervalSeconds\x12>\n" +
+	"\x1bconfigured_interval_seconds\x18\a \x01(\x05R\x19configuredIntervalSeconds\"\x8c\x01\n" +
+	"#GetClusterInstructionSummaryRequest\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x01 \x01(\tR\tclusterId\x12F\n" +
+	"\x10instruction_type\x18\x02 \x01(\x0e2\x1b.netctrl.v1.InstructionTypeR\x0finstructionType\"\xe0\x01\n" +
+	"$GetClusterInstructionSummaryResponse\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x01 \x01(\tR\tclusterId\x12F\n" +
+	"\x10instruction_type\x18\x02 \x01(\x0e2\x1b.netctrl.v1.InstructionTypeR\x0finstructionType\x12\x1c\n" +
+	"\tsucceeded\x18\x03 \x01(\x05R\tsucceeded\x12\x16\n" +
+	"\x06failed\x18\x04 \x01(\x05R\x06failed\x12\x1b\n" +
+	"\tno_result\x18\x05 \x01(\x05R\bnoResult\"5\n" +
+	"\x18TailAgentActivityRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"\xd0\x02\n" +
+	"\rActivityEvent\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x121\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x1d.netctrl.v1.ActivityEventTypeR\x04type\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12H\n" +
+	"\x11instruction_types\x18\x04 \x03(\x0e2\x1b.netctrl.v1.InstructionTypeR\x10instructionTypes\x12%\n" +
+	"\x0einstruction_id\x18\x05 \x01(\tR\rinstructionId\x12F\n" +
+	"\x10instruction_type\x18\x06 \x01(\x0e2\x1b.netctrl.v1.InstructionTypeR\x0finstructionType\"\xc3\x01\n" +
 	"\vInstruction\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12/\n" +
 	"\x04type\x18\x02 \x01(\x0e2\x1b.netctrl.v1.InstructionTypeR\x04type\x12\x18\n" +
 	"\apayload\x18\x03 \x01(\tR\apayload\x129\n" +
 	"\n" +
-	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"b\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1e\n" +
+	"\n" +
+	"persistent\x18\x05 \x01(\bR\n" +
+	"persistent\"\xbf\x01\n" +
 	"\x18HardwareCollectionResult\x12F\n" +
-	"\x12network_interfaces\x18\x01 \x03(\v2\x17.netctrl.v1.MellanoxNICR\x11networkInterfaces\"R\n" +
+	"\x12network_interfaces\x18\x01 \x03(\v2\x17.netctrl.v1.MellanoxNICR\x11networkInterfaces\x12\x18\n" +
+	"\apartial\x18\x02 \x01(\bR\apartial\x12A\n" +
+	"\vfailed_nics\x18\x03 \x03(\v2 .netctrl.v1.NICCollectionFailureR\n" +
+	"failedNics\"n\n" +
+	"\x14NICCollectionFailure\x12\x1f\n" +
+	"\vdevice_name\x18\x01 \x01(\tR\n" +
+	"deviceName\x12\x1f\n" +
+	"\vpci_address\x18\x02 \x01(\tR\n" +
+	"pciAddress\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"R\n" +
 	"\x11HealthCheckResult\x12\x18\n" +
 	"\ahealthy\x18\x01 \x01(\bR\ahealthy\x12#\n" +
-	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"\x82\x02\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"\x90\x01\n" +
+	"\x12GatewayProbeResult\x12\x18\n" +
+	"\agateway\x18\x01 \x01(\tR\agateway\x12\x1c\n" +
+	"\treachable\x18\x02 \x01(\bR\treachable\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x03 \x01(\x01R\tlatencyMs\x12#\n" +
+	"\rerror_message\x18\x04 \x01(\tR\ferrorMessage\"\x96\x01\n" +
+	"\x13NetworkConfigResult\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12@\n" +
+	"\x0eapplied_config\x18\x02 \x01(\v2\x19.netctrl.v1.NetworkConfigR\rappliedConfig\x12#\n" +
+	"\rerror_message\x18\x03 \x01(\tR\ferrorMessage\"S\n" +
+	"\x12DecommissionResult\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12#\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"W\n" +
+	"\x12RestartAgentResult\x12\x1c\n" +
+	"\tinitiated\x18\x01 \x01(\bR\tinitiated\x12#\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"\xa1\x05\n" +
 	"\x11InstructionResult\x12F\n" +
 	"\x10instruction_type\x18\x01 \x01(\x0e2\x1b.netctrl.v1.InstructionTypeR\x0finstructionType\x12W\n" +
 	"\x13hardware_collection\x18\x02 \x01(\v2$.netctrl.v1.HardwareCollectionResultH\x00R\x12hardwareCollection\x12B\n" +
-	"\fhealth_check\x18\x03 \x01(\v2\x1d.netctrl.v1.HealthCheckResultH\x00R\vhealthCheckB\b\n" +
-	"\x06result\"3\n" +
+	"\fhealth_check\x18\x03 \x01(\v2\x1d.netctrl.v1.HealthCheckResultH\x00R\vhealthCheck\x12E\n" +
+	"\rgateway_probe\x18\x04 \x01(\v2\x1e.netctrl.v1.GatewayProbeResultH\x00R\fgatewayProbe\x12H\n" +
+	"\x0enetwork_config\x18\x05 \x01(\v2\x1f.netctrl.v1.NetworkConfigResultH\x00R\rnetworkConfig\x12D\n" +
+	"\fdecommission\x18\a \x01(\v2\x1e.netctrl.v1.DecommissionResultH\x00R\fdecommission\x12E\n" +
+	"\rrestart_agent\x18\t \x01(\v2\x1e.netctrl.v1.RestartAgentResultH\x00R\frestartAgent\x12=\n" +
+	"\fcompleted_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\x12@\n" +
+	"\x0efailure_reason\x18\b \x01(\x0e2\x19.netctrl.v1.FailureReasonR\rfailureReasonB\b\n" +
+	"\x06result\"\xff\x01\n" +
 	"\x16GetInstructionsRequest\x12\x19\n" +
-	"\bagent_id\x18\x01 \x01(\tR\aagentId\"\xc7\x01\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12I\n" +
+	"\ametrics\x18\x02 \x03(\v2/.netctrl.v1.GetInstructionsRequest.MetricsEntryR\ametrics\x12C\n" +
+	"\x1ereported_poll_interval_seconds\x18\x03 \x01(\x05R\x1breportedPollIntervalSeconds\x1a:\n" +
+	"\fMetricsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"7\n" +
+	"\x1aPreviewInstructionsRequest\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\"\xf0\x01\n" +
 	"\x17GetInstructionsResponse\x12;\n" +
 	"\finstructions\x18\x01 \x03(\v2\x17.netctrl.v1.InstructionR\finstructions\x122\n" +
 	"\x15poll_interval_seconds\x18\x02 \x01(\x05R\x13pollIntervalSeconds\x12;\n" +
 	"\vserver_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
-	"serverTime\"\x99\x01\n" +
+	"serverTime\x12'\n" +
+	"\x0fbackoff_seconds\x18\x04 \x01(\x05R\x0ebackoffSeconds\"\x99\x01\n" +
 	"\x1eSubmitInstructionResultRequest\x12\x19\n" +
 	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12%\n" +
 	"\x0einstruction_id\x18\x02 \x01(\tR\rinstructionId\x125\n" +
 	"\x06result\x18\x03 \x01(\v2\x1d.netctrl.v1.InstructionResultR\x06result\"U\n" +
 	"\x1fSubmitInstructionResultResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage*_\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"n\n" +
+	"\x16InstructionResultChunk\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12%\n" +
+	"\x0einstruction_id\x18\x02 \x01(\tR\rinstructionId\x12\x12\n" +
+	"\x04data\x18\x03 \x01(\fR\x04data\"\x17\n" +
+	"\x15GetServerStatsRequest\"\xb6\x03\n" +
+	"\x16GetServerStatsResponse\x12\x1e\n" +
+	"\n" +
+	"goroutines\x18\x01 \x01(\x05R\n" +
+	"goroutines\x12/\n" +
+	"\x06memory\x18\x02 \x01(\v2\x17.netctrl.v1.MemoryStatsR\x06memory\x12%\n" +
+	"\x0euptime_seconds\x18\x03 \x01(\x03R\ruptimeSeconds\x12%\n" +
+	"\x0etotal_clusters\x18\x04 \x01(\x05R\rtotalClusters\x12!\n" +
+	"\ftotal_agents\x18\x05 \x01(\x05R\vtotalAgents\x12#\n" +
+	"\ractive_agents\x18\x06 \x01(\x05R\factiveAgents\x12'\n" +
+	"\x0finactive_agents\x18\a \x01(\x05R\x0einactiveAgents\x12!\n" +
+	"\fstale_agents\x18\b \x01(\x05R\vstaleAgents\x12%\n" +
+	"\x0eexpired_agents\x18\t \x01(\x05R\rexpiredAgents\x12B\n" +
+	"\rdatabase_pool\x18\n" +
+	" \x01(\v2\x1d.netctrl.v1.DatabasePoolStatsR\fdatabasePool\"\x95\x01\n" +
+	"\vMemoryStats\x12(\n" +
+	"\x10heap_alloc_bytes\x18\x01 \x01(\x04R\x0eheapAllocBytes\x12(\n" +
+	"\x10heap_inuse_bytes\x18\x02 \x01(\x04R\x0eheapInuseBytes\x12\x1b\n" +
+	"\tsys_bytes\x18\x03 \x01(\x04R\bsysBytes\x12\x15\n" +
+	"\x06num_gc\x18\x04 \x01(\rR\x05numGc\"\xbc\x01\n" +
+	"\x11DatabasePoolStats\x12\x1f\n" +
+	"\vtotal_conns\x18\x01 \x01(\x05R\n" +
+	"totalConns\x12\x1d\n" +
+	"\n" +
+	"idle_conns\x18\x02 \x01(\x05R\tidleConns\x12%\n" +
+	"\x0eacquired_conns\x18\x03 \x01(\x05R\racquiredConns\x12\x1b\n" +
+	"\tmax_conns\x18\x04 \x01(\x05R\bmaxConns\x12#\n" +
+	"\racquire_count\x18\x05 \x01(\x03R\facquireCount\"W\n" +
+	"\x15GetFleetReportRequest\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x01 \x01(\tR\tclusterId\x12\x1f\n" +
+	"\vsample_size\x18\x02 \x01(\x05R\n" +
+	"sampleSize\"U\n" +
+	"\x13FleetReportCategory\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x05R\x05count\x12(\n" +
+	"\x10sample_agent_ids\x18\x02 \x03(\tR\x0esampleAgentIds\"\xba\x01\n" +
+	"\x1cListFleetReportAgentsRequest\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x01 \x01(\tR\tclusterId\x12?\n" +
+	"\bcategory\x18\x02 \x01(\x0e2#.netctrl.v1.FleetReportCategoryTypeR\bcategory\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\"\x93\x01\n" +
+	"\x1dListFleetReportAgentsResponse\x12)\n" +
+	"\x06agents\x18\x01 \x03(\v2\x11.netctrl.v1.AgentR\x06agents\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1f\n" +
+	"\vtotal_count\x18\x03 \x01(\x05R\n" +
+	"totalCount\"\xf3\x03\n" +
+	"\x16GetFleetReportResponse\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x01 \x01(\tR\tclusterId\x12!\n" +
+	"\ftotal_agents\x18\x02 \x01(\x05R\vtotalAgents\x12;\n" +
+	"\binactive\x18\x03 \x01(\v2\x1f.netctrl.v1.FleetReportCategoryR\binactive\x12F\n" +
+	"\x0econfig_drifted\x18\x04 \x01(\v2\x1f.netctrl.v1.FleetReportCategoryR\rconfigDrifted\x12J\n" +
+	"\x10outdated_version\x18\x05 \x01(\v2\x1f.netctrl.v1.FleetReportCategoryR\x0foutdatedVersion\x12J\n" +
+	"\x10missing_hardware\x18\x06 \x01(\v2\x1f.netctrl.v1.FleetReportCategoryR\x0fmissingHardware\x12S\n" +
+	"\x15poll_interval_drifted\x18\a \x01(\v2\x1f.netctrl.v1.FleetReportCategoryR\x13pollIntervalDrifted\x12%\n" +
+	"\x0elatest_version\x18\b \x01(\tR\rlatestVersion\" \n" +
+	"\x1eGetFleetHardwareSummaryRequest\"q\n" +
+	"\rNICModelCount\x12\x1f\n" +
+	"\vpart_number\x18\x01 \x01(\tR\n" +
+	"partNumber\x12)\n" +
+	"\x10firmware_version\x18\x02 \x01(\tR\x0ffirmwareVersion\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x05R\x05count\"s\n" +
+	"\x1fGetFleetHardwareSummaryResponse\x121\n" +
+	"\x06models\x18\x01 \x03(\v2\x19.netctrl.v1.NICModelCountR\x06models\x12\x1d\n" +
+	"\n" +
+	"total_nics\x18\x02 \x01(\x05R\ttotalNics*\x91\x01\n" +
 	"\vAgentStatus\x12\x1c\n" +
 	"\x18AGENT_STATUS_UNSPECIFIED\x10\x00\x12\x17\n" +
 	"\x13AGENT_STATUS_ACTIVE\x10\x01\x12\x19\n" +
-	"\x15AGENT_STATUS_INACTIVE\x10\x02*g\n" +
+	"\x15AGENT_STATUS_INACTIVE\x10\x02\x12\x16\n" +
+	"\x12AGENT_STATUS_STALE\x10\x03\x12\x18\n" +
+	"\x14AGENT_STATUS_EXPIRED\x10\x04*R\n" +
+	"\tAgentRole\x12\x1a\n" +
+	"\x16AGENT_ROLE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10AGENT_ROLE_SPINE\x10\x01\x12\x13\n" +
+	"\x0fAGENT_ROLE_LEAF\x10\x02*\xca\x01\n" +
+	"\x12PollIntervalSource\x12$\n" +
+	" POLL_INTERVAL_SOURCE_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cPOLL_INTERVAL_SOURCE_DEFAULT\x10\x01\x12'\n" +
+	"#POLL_INTERVAL_SOURCE_AGENT_OVERRIDE\x10\x02\x12!\n" +
+	"\x1dPOLL_INTERVAL_SOURCE_THROTTLE\x10\x03\x12 \n" +
+	"\x1cPOLL_INTERVAL_SOURCE_BACKOFF\x10\x04*g\n" +
 	"\tPortState\x12\x1a\n" +
 	"\x16PORT_STATE_UNSPECIFIED\x10\x00\x12\x13\n" +
 	"\x0fPORT_STATE_DOWN\x10\x01\x12\x11\n" +
@@ -1622,20 +5594,62 @@ const file_v1_agent_proto_rawDesc = "" +
 	"\x0ePORT_SPEED_50G\x102\x12\x13\n" +
 	"\x0fPORT_SPEED_100G\x10d\x12\x14\n" +
 	"\x0fPORT_SPEED_200G\x10\xc8\x01\x12\x14\n" +
-	"\x0fPORT_SPEED_400G\x10\x90\x03*\xa1\x01\n" +
+	"\x0fPORT_SPEED_400G\x10\x90\x03*\xb0\x01\n" +
+	"\rFailureReason\x12\x1e\n" +
+	"\x1aFAILURE_REASON_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16FAILURE_REASON_TIMEOUT\x10\x01\x12$\n" +
+	" FAILURE_REASON_PERMISSION_DENIED\x10\x02\x12 \n" +
+	"\x1cFAILURE_REASON_NOT_SUPPORTED\x10\x03\x12\x1b\n" +
+	"\x17FAILURE_REASON_INTERNAL\x10\x04*\xad\x01\n" +
+	"\x11ActivityEventType\x12#\n" +
+	"\x1fACTIVITY_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18ACTIVITY_EVENT_TYPE_POLL\x10\x01\x12+\n" +
+	"'ACTIVITY_EVENT_TYPE_INSTRUCTIONS_ISSUED\x10\x02\x12(\n" +
+	"$ACTIVITY_EVENT_TYPE_RESULT_SUBMITTED\x10\x03*\xf9\x02\n" +
 	"\x0fInstructionType\x12 \n" +
 	"\x1cINSTRUCTION_TYPE_UNSPECIFIED\x10\x00\x12\"\n" +
 	"\x1eINSTRUCTION_TYPE_POLL_INTERVAL\x10\x01\x12!\n" +
 	"\x1dINSTRUCTION_TYPE_HEALTH_CHECK\x10\x02\x12%\n" +
-	"!INSTRUCTION_TYPE_COLLECT_HARDWARE\x10\x032\x9c\x06\n" +
+	"!INSTRUCTION_TYPE_COLLECT_HARDWARE\x10\x03\x12\x1a\n" +
+	"\x16INSTRUCTION_TYPE_DRAIN\x10\x04\x12\"\n" +
+	"\x1eINSTRUCTION_TYPE_PROBE_GATEWAY\x10\x05\x12)\n" +
+	"%INSTRUCTION_TYPE_APPLY_NETWORK_CONFIG\x10\x06\x12!\n" +
+	"\x1dINSTRUCTION_TYPE_DECOMMISSION\x10\a\x12\"\n" +
+	"\x1eINSTRUCTION_TYPE_RESTART_AGENT\x10\b\x12$\n" +
+	" INSTRUCTION_TYPE_CLUSTER_DELETED\x10\t*\xb5\x02\n" +
+	"\x17FleetReportCategoryType\x12*\n" +
+	"&FLEET_REPORT_CATEGORY_TYPE_UNSPECIFIED\x10\x00\x12'\n" +
+	"#FLEET_REPORT_CATEGORY_TYPE_INACTIVE\x10\x01\x12-\n" +
+	")FLEET_REPORT_CATEGORY_TYPE_CONFIG_DRIFTED\x10\x02\x12/\n" +
+	"+FLEET_REPORT_CATEGORY_TYPE_OUTDATED_VERSION\x10\x03\x12/\n" +
+	"+FLEET_REPORT_CATEGORY_TYPE_MISSING_HARDWARE\x10\x04\x124\n" +
+	"0FLEET_REPORT_CATEGORY_TYPE_POLL_INTERVAL_DRIFTED\x10\x052\xdc\x1a\n" +
 	"\fAgentService\x12x\n" +
 	"\rRegisterAgent\x12 .netctrl.v1.RegisterAgentRequest\x1a!.netctrl.v1.RegisterAgentResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/api/v1/agents/register\x12b\n" +
 	"\bGetAgent\x12\x1b.netctrl.v1.GetAgentRequest\x1a\x1c.netctrl.v1.GetAgentResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/api/v1/agents/{id}\x12c\n" +
 	"\n" +
-	"ListAgents\x12\x1d.netctrl.v1.ListAgentsRequest\x1a\x1e.netctrl.v1.ListAgentsResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/api/v1/agents\x12w\n" +
+	"ListAgents\x12\x1d.netctrl.v1.ListAgentsRequest\x1a\x1e.netctrl.v1.ListAgentsResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/api/v1/agents\x12\x81\x01\n" +
+	"\x10GetAgentStatuses\x12#.netctrl.v1.GetAgentStatusesRequest\x1a$.netctrl.v1.GetAgentStatusesResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/api/v1/agents:statuses\x12w\n" +
 	"\x0fUnregisterAgent\x12\".netctrl.v1.UnregisterAgentRequest\x1a#.netctrl.v1.UnregisterAgentResponse\"\x1b\x82\xd3\xe4\x93\x02\x15*\x13/api/v1/agents/{id}\x12\x8a\x01\n" +
-	"\x0fGetInstructions\x12\".netctrl.v1.GetInstructionsRequest\x1a#.netctrl.v1.GetInstructionsResponse\".\x82\xd3\xe4\x93\x02(\x12&/api/v1/agents/{agent_id}/instructions\x12\xc2\x01\n" +
-	"\x17SubmitInstructionResult\x12*.netctrl.v1.SubmitInstructionResultRequest\x1a+.netctrl.v1.SubmitInstructionResultResponse\"N\x82\xd3\xe4\x93\x02H:\x06result\">/api/v1/agents/{agent_id}/instructions/{instruction_id}/resultB\x9d\x01\n" +
+	"\x11DecommissionAgent\x12$.netctrl.v1.DecommissionAgentRequest\x1a%.netctrl.v1.DecommissionAgentResponse\"(\x82\xd3\xe4\x93\x02\"\" /api/v1/agents/{id}/decommission\x12v\n" +
+	"\fRestartAgent\x12\x1f.netctrl.v1.RestartAgentRequest\x1a .netctrl.v1.RestartAgentResponse\"#\x82\xd3\xe4\x93\x02\x1d\"\x1b/api/v1/agents/{id}/restart\x12\x8f\x01\n" +
+	"\x11UpdateAgentConfig\x12$.netctrl.v1.UpdateAgentConfigRequest\x1a%.netctrl.v1.UpdateAgentConfigResponse\"-\x82\xd3\xe4\x93\x02':\toverrides\"\x1a/api/v1/agents/{id}/config\x12\xc0\x01\n" +
+	"\x1cEnqueueInstructionBySelector\x12/.netctrl.v1.EnqueueInstructionBySelectorRequest\x1a0.netctrl.v1.EnqueueInstructionBySelectorResponse\"=\x82\xd3\xe4\x93\x027:\x01*\"2/api/v1/clusters/{cluster_id}/instructions:enqueue\x12\xa6\x01\n" +
+	"\x19TriggerHardwareCollection\x12,.netctrl.v1.TriggerHardwareCollectionRequest\x1a-.netctrl.v1.TriggerHardwareCollectionResponse\",\x82\xd3\xe4\x93\x02&:\x01*\"!/api/v1/admin/hardware-collection\x12\x8a\x01\n" +
+	"\x0fGetInstructions\x12\".netctrl.v1.GetInstructionsRequest\x1a#.netctrl.v1.GetInstructionsResponse\".\x82\xd3\xe4\x93\x02(\x12&/api/v1/agents/{agent_id}/instructions\x12\x9a\x01\n" +
+	"\x13PreviewInstructions\x12&.netctrl.v1.PreviewInstructionsRequest\x1a#.netctrl.v1.GetInstructionsResponse\"6\x82\xd3\xe4\x93\x020\x12./api/v1/agents/{agent_id}/instructions:preview\x12\xc2\x01\n" +
+	"\x17SubmitInstructionResult\x12*.netctrl.v1.SubmitInstructionResultRequest\x1a+.netctrl.v1.SubmitInstructionResultResponse\"N\x82\xd3\xe4\x93\x02H:\x06result\">/api/v1/agents/{agent_id}/instructions/{instruction_id}/result\x12r\n" +
+	"\x1dSubmitInstructionResultStream\x12\".netctrl.v1.InstructionResultChunk\x1a+.netctrl.v1.SubmitInstructionResultResponse(\x01\x12\x8a\x01\n" +
+	"\x12FindOrphanedAgents\x12%.netctrl.v1.FindOrphanedAgentsRequest\x1a&.netctrl.v1.FindOrphanedAgentsResponse\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/api/v1/admin/orphaned-agents\x12\x8a\x01\n" +
+	"\x12ReapOrphanedAgents\x12%.netctrl.v1.ReapOrphanedAgentsRequest\x1a&.netctrl.v1.ReapOrphanedAgentsResponse\"%\x82\xd3\xe4\x93\x02\x1f*\x1d/api/v1/admin/orphaned-agents\x12}\n" +
+	"\x0fSetThrottleMode\x12\".netctrl.v1.SetThrottleModeRequest\x1a#.netctrl.v1.SetThrottleModeResponse\"!\x82\xd3\xe4\x93\x02\x1b:\x01*\x1a\x16/api/v1/admin/throttle\x12\x98\x01\n" +
+	"\x13GetClusterPollStats\x12&.netctrl.v1.GetClusterPollStatsRequest\x1a'.netctrl.v1.GetClusterPollStatsResponse\"0\x82\xd3\xe4\x93\x02*\x12(/api/v1/clusters/{cluster_id}/poll-stats\x12\xbc\x01\n" +
+	"\x1cGetClusterInstructionSummary\x12/.netctrl.v1.GetClusterInstructionSummaryRequest\x1a0.netctrl.v1.GetClusterInstructionSummaryResponse\"9\x82\xd3\xe4\x93\x023\x121/api/v1/clusters/{cluster_id}/instruction-summary\x12\x82\x01\n" +
+	"\x11TailAgentActivity\x12$.netctrl.v1.TailAgentActivityRequest\x1a\x19.netctrl.v1.ActivityEvent\"*\x82\xd3\xe4\x93\x02$\x12\"/api/v1/agents/{agent_id}/activity0\x01\x12t\n" +
+	"\x0eGetServerStats\x12!.netctrl.v1.GetServerStatsRequest\x1a\".netctrl.v1.GetServerStatsResponse\"\x1b\x82\xd3\xe4\x93\x02\x15\x12\x13/api/v1/admin/stats\x12{\n" +
+	"\x0eGetFleetReport\x12!.netctrl.v1.GetFleetReportRequest\x1a\".netctrl.v1.GetFleetReportResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/api/v1/admin/fleet-report\x12\x97\x01\n" +
+	"\x15ListFleetReportAgents\x12(.netctrl.v1.ListFleetReportAgentsRequest\x1a).netctrl.v1.ListFleetReportAgentsResponse\")\x82\xd3\xe4\x93\x02#\x12!/api/v1/admin/fleet-report/agents\x12\xa0\x01\n" +
+	"\x17GetFleetHardwareSummary\x12*.netctrl.v1.GetFleetHardwareSummaryRequest\x1a+.netctrl.v1.GetFleetHardwareSummaryResponse\",\x82\xd3\xe4\x93\x02&\x12$/api/v1/admin/fleet-hardware-summaryB\x9d\x01\n" +
 	"\x0ecom.netctrl.v1B\n" +
 	"AgentProtoP\x01Z6github.com/filanov/netctrl-server/pkg/api/v1;netctrlv1\xa2\x02\x03NXX\xaa\x02\n" +
 	"Netctrl.V1\xca\x02\n" +
@@ -1653,72 +5667,227 @@ func file_v1_agent_proto_rawDescGZIP() []byte {
 	return file_v1_agent_proto_rawDescData
 }
 
-var file_v1_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_v1_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_v1_agent_proto_enumTypes = make([]protoimpl.EnumInfo, 9)
+var file_v1_agent_proto_msgTypes = make([]protoimpl.MessageInfo, 70)
 var file_v1_agent_proto_goTypes = []any{
-	(AgentStatus)(0),                        // 0: netctrl.v1.AgentStatus
-	(PortState)(0),                          // 1: netctrl.v1.PortState
-	(PortSpeed)(0),                          // 2: netctrl.v1.PortSpeed
-	(InstructionType)(0),                    // 3: netctrl.v1.InstructionType
-	(*MellanoxPort)(nil),                    // 4: netctrl.v1.MellanoxPort
-	(*MellanoxNIC)(nil),                     // 5: netctrl.v1.MellanoxNIC
-	(*Agent)(nil),                           // 6: netctrl.v1.Agent
-	(*RegisterAgentRequest)(nil),            // 7: netctrl.v1.RegisterAgentRequest
-	(*RegisterAgentResponse)(nil),           // 8: netctrl.v1.RegisterAgentResponse
-	(*GetAgentRequest)(nil),                 // 9: netctrl.v1.GetAgentRequest
-	(*GetAgentResponse)(nil),                // 10: netctrl.v1.GetAgentResponse
-	(*ListAgentsRequest)(nil),               // 11: netctrl.v1.ListAgentsRequest
-	(*ListAgentsResponse)(nil),              // 12: netctrl.v1.ListAgentsResponse
-	(*UnregisterAgentRequest)(nil),          // 13: netctrl.v1.UnregisterAgentRequest
-	(*UnregisterAgentResponse)(nil),         // 14: netctrl.v1.UnregisterAgentResponse
-	(*Instruction)(nil),                     // 15: netctrl.v1.Instruction
-	(*HardwareCollectionResult)(nil),        // 16: netctrl.v1.HardwareCollectionResult
-	(*HealthCheckResult)(nil),               // 17: netctrl.v1.HealthCheckResult
-	(*InstructionResult)(nil),               // 18: netctrl.v1.InstructionResult
-	(*GetInstructionsRequest)(nil),          // 19: netctrl.v1.GetInstructionsRequest
-	(*GetInstructionsResponse)(nil),         // 20: netctrl.v1.GetInstructionsResponse
-	(*SubmitInstructionResultRequest)(nil),  // 21: netctrl.v1.SubmitInstructionResultRequest
-	(*SubmitInstructionResultResponse)(nil), // 22: netctrl.v1.SubmitInstructionResultResponse
-	(*timestamppb.Timestamp)(nil),           // 23: google.protobuf.Timestamp
+	(AgentStatus)(0),                             // 0: netctrl.v1.AgentStatus
+	(AgentRole)(0),                               // 1: netctrl.v1.AgentRole
+	(PollIntervalSource)(0),                      // 2: netctrl.v1.PollIntervalSource
+	(PortState)(0),                               // 3: netctrl.v1.PortState
+	(PortSpeed)(0),                               // 4: netctrl.v1.PortSpeed
+	(FailureReason)(0),                           // 5: netctrl.v1.FailureReason
+	(ActivityEventType)(0),                       // 6: netctrl.v1.ActivityEventType
+	(InstructionType)(0),                         // 7: netctrl.v1.InstructionType
+	(FleetReportCategoryType)(0),                 // 8: netctrl.v1.FleetReportCategoryType
+	(*MellanoxPort)(nil),                         // 9: netctrl.v1.MellanoxPort
+	(*MellanoxNIC)(nil),                          // 10: netctrl.v1.MellanoxNIC
+	(*Agent)(nil),                                // 11: netctrl.v1.Agent
+	(*AgentConfigOverrides)(nil),                 // 12: netctrl.v1.AgentConfigOverrides
+	(*InstallMetadata)(nil),                      // 13: netctrl.v1.InstallMetadata
+	(*RawInstructionResult)(nil),                 // 14: netctrl.v1.RawInstructionResult
+	(*RegistrationSource)(nil),                   // 15: netctrl.v1.RegistrationSource
+	(*InstructionOutcome)(nil),                   // 16: netctrl.v1.InstructionOutcome
+	(*RegisterAgentRequest)(nil),                 // 17: netctrl.v1.RegisterAgentRequest
+	(*RegisterAgentResponse)(nil),                // 18: netctrl.v1.RegisterAgentResponse
+	(*GetAgentRequest)(nil),                      // 19: netctrl.v1.GetAgentRequest
+	(*GetAgentResponse)(nil),                     // 20: netctrl.v1.GetAgentResponse
+	(*ListAgentsRequest)(nil),                    // 21: netctrl.v1.ListAgentsRequest
+	(*ListAgentsResponse)(nil),                   // 22: netctrl.v1.ListAgentsResponse
+	(*GetAgentStatusesRequest)(nil),              // 23: netctrl.v1.GetAgentStatusesRequest
+	(*GetAgentStatusesResponse)(nil),             // 24: netctrl.v1.GetAgentStatusesResponse
+	(*UnregisterAgentRequest)(nil),               // 25: netctrl.v1.UnregisterAgentRequest
+	(*UnregisterAgentResponse)(nil),              // 26: netctrl.v1.UnregisterAgentResponse
+	(*DecommissionAgentRequest)(nil),             // 27: netctrl.v1.DecommissionAgentRequest
+	(*DecommissionAgentResponse)(nil),            // 28: netctrl.v1.DecommissionAgentResponse
+	(*UpdateAgentConfigRequest)(nil),             // 29: netctrl.v1.UpdateAgentConfigRequest
+	(*UpdateAgentConfigResponse)(nil),            // 30: netctrl.v1.UpdateAgentConfigResponse
+	(*RestartAgentRequest)(nil),                  // 31: netctrl.v1.RestartAgentRequest
+	(*RestartAgentResponse)(nil),                 // 32: netctrl.v1.RestartAgentResponse
+	(*TriggerHardwareCollectionRequest)(nil),     // 33: netctrl.v1.TriggerHardwareCollectionRequest
+	(*TriggerHardwareCollectionResponse)(nil),    // 34: netctrl.v1.TriggerHardwareCollectionResponse
+	(*EnqueueInstructionBySelectorRequest)(nil),  // 35: netctrl.v1.EnqueueInstructionBySelectorRequest
+	(*EnqueueInstructionBySelectorResponse)(nil), // 36: netctrl.v1.EnqueueInstructionBySelectorResponse
+	(*FindOrphanedAgentsRequest)(nil),            // 37: netctrl.v1.FindOrphanedAgentsRequest
+	(*FindOrphanedAgentsResponse)(nil),           // 38: netctrl.v1.FindOrphanedAgentsResponse
+	(*ReapOrphanedAgentsRequest)(nil),            // 39: netctrl.v1.ReapOrphanedAgentsRequest
+	(*ReapOrphanedAgentsResponse)(nil),           // 40: netctrl.v1.ReapOrphanedAgentsResponse
+	(*SetThrottleModeRequest)(nil),               // 41: netctrl.v1.SetThrottleModeRequest
+	(*SetThrottleModeResponse)(nil),              // 42: netctrl.v1.SetThrottleModeResponse
+	(*GetClusterPollStatsRequest)(nil),           // 43: netctrl.v1.GetClusterPollStatsRequest
+	(*GetClusterPollStatsResponse)(nil),          // 44: netctrl.v1.GetClusterPollStatsResponse
+	(*GetClusterInstructionSummaryRequest)(nil),  // 45: netctrl.v1.GetClusterInstructionSummaryRequest
+	(*GetClusterInstructionSummaryResponse)(nil), // 46: netctrl.v1.GetClusterInstructionSummaryResponse
+	(*TailAgentActivityRequest)(nil),             // 47: netctrl.v1.TailAgentActivityRequest
+	(*ActivityEvent)(nil),                        // 48: netctrl.v1.ActivityEvent
+	(*Instruction)(nil),                          // 49: netctrl.v1.Instruction
+	(*HardwareCollectionResult)(nil),             // 50: netctrl.v1.HardwareCollectionResult
+	(*NICCollectionFailure)(nil),                 // 51: netctrl.v1.NICCollectionFailure
+	(*HealthCheckResult)(nil),                    // 52: netctrl.v1.HealthCheckResult
+	(*GatewayProbeResult)(nil),                   // 53: netctrl.v1.GatewayProbeResult
+	(*NetworkConfigResult)(nil),                  // 54: netctrl.v1.NetworkConfigResult
+	(*DecommissionResult)(nil),                   // 55: netctrl.v1.DecommissionResult
+	(*RestartAgentResult)(nil),                   // 56: netctrl.v1.RestartAgentResult
+	(*InstructionResult)(nil),                    // 57: netctrl.v1.InstructionResult
+	(*GetInstructionsRequest)(nil),               // 58: netctrl.v1.GetInstructionsRequest
+	(*PreviewInstructionsRequest)(nil),           // 59: netctrl.v1.PreviewInstructionsRequest
+	(*GetInstructionsResponse)(nil),              // 60: netctrl.v1.GetInstructionsResponse
+	(*SubmitInstructionResultRequest)(nil),       // 61: netctrl.v1.SubmitInstructionResultRequest
+	(*SubmitInstructionResultResponse)(nil),      // 62: netctrl.v1.SubmitInstructionResultResponse
+	(*InstructionResultChunk)(nil),               // 63: netctrl.v1.InstructionResultChunk
+	(*GetServerStatsRequest)(nil),                // 64: netctrl.v1.GetServerStatsRequest
+	(*GetServerStatsResponse)(nil),               // 65: netctrl.v1.GetServerStatsResponse
+	(*MemoryStats)(nil),                          // 66: netctrl.v1.MemoryStats
+	(*DatabasePoolStats)(nil),                    // 67: netctrl.v1.DatabasePoolStats
+	(*GetFleetReportRequest)(nil),                // 68: netctrl.v1.GetFleetReportRequest
+	(*FleetReportCategory)(nil),                  // 69: netctrl.v1.FleetReportCategory
+	(*ListFleetReportAgentsRequest)(nil),         // 70: netctrl.v1.ListFleetReportAgentsRequest
+	(*ListFleetReportAgentsResponse)(nil),        // 71: netctrl.v1.ListFleetReportAgentsResponse
+	(*GetFleetReportResponse)(nil),               // 72: netctrl.v1.GetFleetReportResponse
+	(*GetFleetHardwareSummaryRequest)(nil),       // 73: netctrl.v1.GetFleetHardwareSummaryRequest
+	(*NICModelCount)(nil),                        // 74: netctrl.v1.NICModelCount
+	(*GetFleetHardwareSummaryResponse)(nil),      // 75: netctrl.v1.GetFleetHardwareSummaryResponse
+	nil,                                          // 76: netctrl.v1.Agent.MetricsEntry
+	nil,                                          // 77: netctrl.v1.GetAgentStatusesResponse.StatusesEntry
+	nil,                                          // 78: netctrl.v1.GetInstructionsRequest.MetricsEntry
+	(*timestamppb.Timestamp)(nil),                // 79: google.protobuf.Timestamp
+	(*NetworkConfig)(nil),                        // 80: netctrl.v1.NetworkConfig
+	(*fieldmaskpb.FieldMask)(nil),                // 81: google.protobuf.FieldMask
 }
 var file_v1_agent_proto_depIdxs = []int32{
-	1,  // 0: netctrl.v1.MellanoxPort.state:type_name -> netctrl.v1.PortState
-	2,  // 1: netctrl.v1.MellanoxPort.speed:type_name -> netctrl.v1.PortSpeed
-	4,  // 2: netctrl.v1.MellanoxNIC.ports:type_name -> netctrl.v1.MellanoxPort
-	0,  // 3: netctrl.v1.Agent.status:type_name -> netctrl.v1.AgentStatus
-	23, // 4: netctrl.v1.Agent.last_seen:type_name -> google.protobuf.Timestamp
-	23, // 5: netctrl.v1.Agent.created_at:type_name -> google.protobuf.Timestamp
-	23, // 6: netctrl.v1.Agent.updated_at:type_name -> google.protobuf.Timestamp
-	5,  // 7: netctrl.v1.Agent.network_interfaces:type_name -> netctrl.v1.MellanoxNIC
-	6,  // 8: netctrl.v1.RegisterAgentResponse.agent:type_name -> netctrl.v1.Agent
-	6,  // 9: netctrl.v1.GetAgentResponse.agent:type_name -> netctrl.v1.Agent
-	6,  // 10: netctrl.v1.ListAgentsResponse.agents:type_name -> netctrl.v1.Agent
-	3,  // 11: netctrl.v1.Instruction.type:type_name -> netctrl.v1.InstructionType
-	23, // 12: netctrl.v1.Instruction.created_at:type_name -> google.protobuf.Timestamp
-	5,  // 13: netctrl.v1.HardwareCollectionResult.network_interfaces:type_name -> netctrl.v1.MellanoxNIC
-	3,  // 14: netctrl.v1.InstructionResult.instruction_type:type_name -> netctrl.v1.InstructionType
-	16, // 15: netctrl.v1.InstructionResult.hardware_collection:type_name -> netctrl.v1.HardwareCollectionResult
-	17, // 16: netctrl.v1.InstructionResult.health_check:type_name -> netctrl.v1.HealthCheckResult
-	15, // 17: netctrl.v1.GetInstructionsResponse.instructions:type_name -> netctrl.v1.Instruction
-	23, // 18: netctrl.v1.GetInstructionsResponse.server_time:type_name -> google.protobuf.Timestamp
-	18, // 19: netctrl.v1.SubmitInstructionResultRequest.result:type_name -> netctrl.v1.InstructionResult
-	7,  // 20: netctrl.v1.AgentService.RegisterAgent:input_type -> netctrl.v1.RegisterAgentRequest
-	9,  // 21: netctrl.v1.AgentService.GetAgent:input_type -> netctrl.v1.GetAgentRequest
-	11, // 22: netctrl.v1.AgentService.ListAgents:input_type -> netctrl.v1.ListAgentsRequest
-	13, // 23: netctrl.v1.AgentService.UnregisterAgent:input_type -> netctrl.v1.UnregisterAgentRequest
-	19, // 24: netctrl.v1.AgentService.GetInstructions:input_type -> netctrl.v1.GetInstructionsRequest
-	21, // 25: netctrl.v1.AgentService.SubmitInstructionResult:input_type -> netctrl.v1.SubmitInstructionResultRequest
-	8,  // 26: netctrl.v1.AgentService.RegisterAgent:output_type -> netctrl.v1.RegisterAgentResponse
-	10, // 27: netctrl.v1.AgentService.GetAgent:output_type -> netctrl.v1.GetAgentResponse
-	12, // 28: netctrl.v1.AgentService.ListAgents:output_type -> netctrl.v1.ListAgentsResponse
-	14, // 29: netctrl.v1.AgentService.UnregisterAgent:output_type -> netctrl.v1.UnregisterAgentResponse
-	20, // 30: netctrl.v1.AgentService.GetInstructions:output_type -> netctrl.v1.GetInstructionsResponse
-	22, // 31: netctrl.v1.AgentService.SubmitInstructionResult:output_type -> netctrl.v1.SubmitInstructionResultResponse
-	26, // [26:32] is the sub-list for method output_type
-	20, // [20:26] is the sub-list for method input_type
-	20, // [20:20] is the sub-list for extension type_name
-	20, // [20:20] is the sub-list for extension extendee
-	0,  // [0:20] is the sub-list for field type_name
+	3,   // 0: netctrl.v1.MellanoxPort.state:type_name -> netctrl.v1.PortState
+	4,   // 1: netctrl.v1.MellanoxPort.speed:type_name -> netctrl.v1.PortSpeed
+	9,   // 2: netctrl.v1.MellanoxNIC.ports:type_name -> netctrl.v1.MellanoxPort
+	0,   // 3: netctrl.v1.Agent.status:type_name -> netctrl.v1.AgentStatus
+	79,  // 4: netctrl.v1.Agent.last_seen:type_name -> google.protobuf.Timestamp
+	79,  // 5: netctrl.v1.Agent.created_at:type_name -> google.protobuf.Timestamp
+	79,  // 6: netctrl.v1.Agent.updated_at:type_name -> google.protobuf.Timestamp
+	10,  // 7: netctrl.v1.Agent.network_interfaces:type_name -> netctrl.v1.MellanoxNIC
+	1,   // 8: netctrl.v1.Agent.role:type_name -> netctrl.v1.AgentRole
+	53,  // 9: netctrl.v1.Agent.last_gateway_probe:type_name -> netctrl.v1.GatewayProbeResult
+	79,  // 10: netctrl.v1.Agent.last_gateway_probe_at:type_name -> google.protobuf.Timestamp
+	80,  // 11: netctrl.v1.Agent.applied_network_config:type_name -> netctrl.v1.NetworkConfig
+	76,  // 12: netctrl.v1.Agent.metrics:type_name -> netctrl.v1.Agent.MetricsEntry
+	79,  // 13: netctrl.v1.Agent.metrics_reported_at:type_name -> google.protobuf.Timestamp
+	16,  // 14: netctrl.v1.Agent.last_outcomes:type_name -> netctrl.v1.InstructionOutcome
+	15,  // 15: netctrl.v1.Agent.last_registration:type_name -> netctrl.v1.RegistrationSource
+	14,  // 16: netctrl.v1.Agent.unrecognized_results:type_name -> netctrl.v1.RawInstructionResult
+	79,  // 17: netctrl.v1.Agent.last_healthy_at:type_name -> google.protobuf.Timestamp
+	13,  // 18: netctrl.v1.Agent.install_metadata:type_name -> netctrl.v1.InstallMetadata
+	51,  // 19: netctrl.v1.Agent.failed_nics:type_name -> netctrl.v1.NICCollectionFailure
+	79,  // 20: netctrl.v1.Agent.last_restart_at:type_name -> google.protobuf.Timestamp
+	12,  // 21: netctrl.v1.Agent.config_overrides:type_name -> netctrl.v1.AgentConfigOverrides
+	2,   // 22: netctrl.v1.Agent.poll_interval_source:type_name -> netctrl.v1.PollIntervalSource
+	7,   // 23: netctrl.v1.AgentConfigOverrides.disabled_instructions:type_name -> netctrl.v1.InstructionType
+	79,  // 24: netctrl.v1.InstallMetadata.recorded_at:type_name -> google.protobuf.Timestamp
+	7,   // 25: netctrl.v1.RawInstructionResult.instruction_type:type_name -> netctrl.v1.InstructionType
+	79,  // 26: netctrl.v1.RawInstructionResult.received_at:type_name -> google.protobuf.Timestamp
+	79,  // 27: netctrl.v1.RegistrationSource.registered_at:type_name -> google.protobuf.Timestamp
+	7,   // 28: netctrl.v1.InstructionOutcome.instruction_type:type_name -> netctrl.v1.InstructionType
+	79,  // 29: netctrl.v1.InstructionOutcome.received_at:type_name -> google.protobuf.Timestamp
+	5,   // 30: netctrl.v1.InstructionOutcome.failure_reason:type_name -> netctrl.v1.FailureReason
+	79,  // 31: netctrl.v1.InstructionOutcome.retry_after:type_name -> google.protobuf.Timestamp
+	1,   // 32: netctrl.v1.RegisterAgentRequest.role:type_name -> netctrl.v1.AgentRole
+	13,  // 33: netctrl.v1.RegisterAgentRequest.install_metadata:type_name -> netctrl.v1.InstallMetadata
+	11,  // 34: netctrl.v1.RegisterAgentResponse.agent:type_name -> netctrl.v1.Agent
+	81,  // 35: netctrl.v1.GetAgentRequest.read_mask:type_name -> google.protobuf.FieldMask
+	11,  // 36: netctrl.v1.GetAgentResponse.agent:type_name -> netctrl.v1.Agent
+	81,  // 37: netctrl.v1.ListAgentsRequest.read_mask:type_name -> google.protobuf.FieldMask
+	11,  // 38: netctrl.v1.ListAgentsResponse.agents:type_name -> netctrl.v1.Agent
+	77,  // 39: netctrl.v1.GetAgentStatusesResponse.statuses:type_name -> netctrl.v1.GetAgentStatusesResponse.StatusesEntry
+	11,  // 40: netctrl.v1.DecommissionAgentResponse.agent:type_name -> netctrl.v1.Agent
+	12,  // 41: netctrl.v1.UpdateAgentConfigRequest.overrides:type_name -> netctrl.v1.AgentConfigOverrides
+	11,  // 42: netctrl.v1.UpdateAgentConfigResponse.agent:type_name -> netctrl.v1.Agent
+	11,  // 43: netctrl.v1.RestartAgentResponse.agent:type_name -> netctrl.v1.Agent
+	7,   // 44: netctrl.v1.EnqueueInstructionBySelectorRequest.instruction_type:type_name -> netctrl.v1.InstructionType
+	11,  // 45: netctrl.v1.FindOrphanedAgentsResponse.agents:type_name -> netctrl.v1.Agent
+	7,   // 46: netctrl.v1.GetClusterInstructionSummaryRequest.instruction_type:type_name -> netctrl.v1.InstructionType
+	7,   // 47: netctrl.v1.GetClusterInstructionSummaryResponse.instruction_type:type_name -> netctrl.v1.InstructionType
+	6,   // 48: netctrl.v1.ActivityEvent.type:type_name -> netctrl.v1.ActivityEventType
+	79,  // 49: netctrl.v1.ActivityEvent.timestamp:type_name -> google.protobuf.Timestamp
+	7,   // 50: netctrl.v1.ActivityEvent.instruction_types:type_name -> netctrl.v1.InstructionType
+	7,   // 51: netctrl.v1.ActivityEvent.instruction_type:type_name -> netctrl.v1.InstructionType
+	7,   // 52: netctrl.v1.Instruction.type:type_name -> netctrl.v1.InstructionType
+	79,  // 53: netctrl.v1.Instruction.created_at:type_name -> google.protobuf.Timestamp
+	10,  // 54: netctrl.v1.HardwareCollectionResult.network_interfaces:type_name -> netctrl.v1.MellanoxNIC
+	51,  // 55: netctrl.v1.HardwareCollectionResult.failed_nics:type_name -> netctrl.v1.NICCollectionFailure
+	80,  // 56: netctrl.v1.NetworkConfigResult.applied_config:type_name -> netctrl.v1.NetworkConfig
+	7,   // 57: netctrl.v1.InstructionResult.instruction_type:type_name -> netctrl.v1.InstructionType
+	50,  // 58: netctrl.v1.InstructionResult.hardware_collection:type_name -> netctrl.v1.HardwareCollectionResult
+	52,  // 59: netctrl.v1.InstructionResult.health_check:type_name -> netctrl.v1.HealthCheckResult
+	53,  // 60: netctrl.v1.InstructionResult.gateway_probe:type_name -> netctrl.v1.GatewayProbeResult
+	54,  // 61: netctrl.v1.InstructionResult.network_config:type_name -> netctrl.v1.NetworkConfigResult
+	55,  // 62: netctrl.v1.InstructionResult.decommission:type_name -> netctrl.v1.DecommissionResult
+	56,  // 63: netctrl.v1.InstructionResult.restart_agent:type_name -> netctrl.v1.RestartAgentResult
+	79,  // 64: netctrl.v1.InstructionResult.completed_at:type_name -> google.protobuf.Timestamp
+	5,   // 65: netctrl.v1.InstructionResult.failure_reason:type_name -> netctrl.v1.FailureReason
+	78,  // 66: netctrl.v1.GetInstructionsRequest.metrics:type_name -> netctrl.v1.GetInstructionsRequest.MetricsEntry
+	49,  // 67: netctrl.v1.GetInstructionsResponse.instructions:type_name -> netctrl.v1.Instruction
+	79,  // 68: netctrl.v1.GetInstructionsResponse.server_time:type_name -> google.protobuf.Timestamp
+	57,  // 69: netctrl.v1.SubmitInstructionResultRequest.result:type_name -> netctrl.v1.InstructionResult
+	66,  // 70: netctrl.v1.GetServerStatsResponse.memory:type_name -> netctrl.v1.MemoryStats
+	67,  // 71: netctrl.v1.GetServerStatsResponse.database_pool:type_name -> netctrl.v1.DatabasePoolStats
+	8,   // 72: netctrl.v1.ListFleetReportAgentsRequest.category:type_name -> netctrl.v1.FleetReportCategoryType
+	11,  // 73: netctrl.v1.ListFleetReportAgentsResponse.agents:type_name -> netctrl.v1.Agent
+	69,  // 74: netctrl.v1.GetFleetReportResponse.inactive:type_name -> netctrl.v1.FleetReportCategory
+	69,  // 75: netctrl.v1.GetFleetReportResponse.config_drifted:type_name -> netctrl.v1.FleetReportCategory
+	69,  // 76: netctrl.v1.GetFleetReportResponse.outdated_version:type_name -> netctrl.v1.FleetReportCategory
+	69,  // 77: netctrl.v1.GetFleetReportResponse.missing_hardware:type_name -> netctrl.v1.FleetReportCategory
+	69,  // 78: netctrl.v1.GetFleetReportResponse.poll_interval_drifted:type_name -> netctrl.v1.FleetReportCategory
+	74,  // 79: netctrl.v1.GetFleetHardwareSummaryResponse.models:type_name -> netctrl.v1.NICModelCount
+	0,   // 80: netctrl.v1.GetAgentStatusesResponse.StatusesEntry.value:type_name -> netctrl.v1.AgentStatus
+	17,  // 81: netctrl.v1.AgentService.RegisterAgent:input_type -> netctrl.v1.RegisterAgentRequest
+	19,  // 82: netctrl.v1.AgentService.GetAgent:input_type -> netctrl.v1.GetAgentRequest
+	21,  // 83: netctrl.v1.AgentService.ListAgents:input_type -> netctrl.v1.ListAgentsRequest
+	23,  // 84: netctrl.v1.AgentService.GetAgentStatuses:input_type -> netctrl.v1.GetAgentStatusesRequest
+	25,  // 85: netctrl.v1.AgentService.UnregisterAgent:input_type -> netctrl.v1.UnregisterAgentRequest
+	27,  // 86: netctrl.v1.AgentService.DecommissionAgent:input_type -> netctrl.v1.DecommissionAgentRequest
+	31,  // 87: netctrl.v1.AgentService.RestartAgent:input_type -> netctrl.v1.RestartAgentRequest
+	29,  // 88: netctrl.v1.AgentService.UpdateAgentConfig:input_type -> netctrl.v1.UpdateAgentConfigRequest
+	35,  // 89: netctrl.v1.AgentService.EnqueueInstructionBySelector:input_type -> netctrl.v1.EnqueueInstructionBySelectorRequest
+	33,  // 90: netctrl.v1.AgentService.TriggerHardwareCollection:input_type -> netctrl.v1.TriggerHardwareCollectionRequest
+	58,  // 91: netctrl.v1.AgentService.GetInstructions:input_type -> netctrl.v1.GetInstructionsRequest
+	59,  // 92: netctrl.v1.AgentService.PreviewInstructions:input_type -> netctrl.v1.PreviewInstructionsRequest
+	61,  // 93: netctrl.v1.AgentService.SubmitInstructionResult:input_type -> netctrl.v1.SubmitInstructionResultRequest
+	63,  // 94: netctrl.v1.AgentService.SubmitInstructionResultStream:input_type -> netctrl.v1.InstructionResultChunk
+	37,  // 95: netctrl.v1.AgentService.FindOrphanedAgents:input_type -> netctrl.v1.FindOrphanedAgentsRequest
+	39,  // 96: netctrl.v1.AgentService.ReapOrphanedAgents:input_type -> netctrl.v1.ReapOrphanedAgentsRequest
+	41,  // 97: netctrl.v1.AgentService.SetThrottleMode:input_type -> netctrl.v1.SetThrottleModeRequest
+	43,  // 98: netctrl.v1.AgentService.GetClusterPollStats:input_type -> netctrl.v1.GetClusterPollStatsRequest
+	45,  // 99: netctrl.v1.AgentService.GetClusterInstructionSummary:input_type -> netctrl.v1.GetClusterInstructionSummaryRequest
+	47,  // 100: netctrl.v1.AgentService.TailAgentActivity:input_type -> netctrl.v1.TailAgentActivityRequest
+	64,  // 101: netctrl.v1.AgentService.GetServerStats:input_type -> netctrl.v1.GetServerStatsRequest
+	68,  // 102: netctrl.v1.AgentService.GetFleetReport:input_type -> netctrl.v1.GetFleetReportRequest
+	70,  // 103: netctrl.v1.AgentService.ListFleetReportAgents:input_type -> netctrl.v1.ListFleetReportAgentsRequest
+	73,  // 104: netctrl.v1.AgentService.GetFleetHardwareSummary:input_type -> netctrl.v1.GetFleetHardwareSummaryRequest
+	18,  // 105: netctrl.v1.AgentService.RegisterAgent:output_type -> netctrl.v1.RegisterAgentResponse
+	20,  // 106: netctrl.v1.AgentService.GetAgent:output_type -> netctrl.v1.GetAgentResponse
+	22,  // 107: netctrl.v1.AgentService.ListAgents:output_type -> netctrl.v1.ListAgentsResponse
+	24,  // 108: netctrl.v1.AgentService.GetAgentStatuses:output_type -> netctrl.v1.GetAgentStatusesResponse
+	26,  // 109: netctrl.v1.AgentService.UnregisterAgent:output_type -> netctrl.v1.UnregisterAgentResponse
+	28,  // 110: netctrl.v1.AgentService.DecommissionAgent:output_type -> netctrl.v1.DecommissionAgentResponse
+	32,  // 111: netctrl.v1.AgentService.RestartAgent:output_type -> netctrl.v1.RestartAgentResponse
+	30,  // 112: netctrl.v1.AgentService.UpdateAgentConfig:output_type -> netctrl.v1.UpdateAgentConfigResponse
+	36,  // 113: netctrl.v1.AgentService.EnqueueInstructionBySelector:output_type -> netctrl.v1.EnqueueInstructionBySelectorResponse
+	34,  // 114: netctrl.v1.AgentService.TriggerHardwareCollection:output_type -> netctrl.v1.TriggerHardwareCollectionResponse
+	60,  // 115: netctrl.v1.AgentService.GetInstructions:output_type -> netctrl.v1.GetInstructionsResponse
+	60,  // 116: netctrl.v1.AgentService.PreviewInstructions:output_type -> netctrl.v1.GetInstructionsResponse
+	62,  // 117: netctrl.v1.AgentService.SubmitInstructionResult:output_type -> netctrl.v1.SubmitInstructionResultResponse
+	62,  // 118: netctrl.v1.AgentService.SubmitInstructionResultStream:output_type -> netctrl.v1.SubmitInstructionResultResponse
+	38,  // 119: netctrl.v1.AgentService.FindOrphanedAgents:output_type -> netctrl.v1.FindOrphanedAgentsResponse
+	40,  // 120: netctrl.v1.AgentService.ReapOrphanedAgents:output_type -> netctrl.v1.ReapOrphanedAgentsResponse
+	42,  // 121: netctrl.v1.AgentService.SetThrottleMode:output_type -> netctrl.v1.SetThrottleModeResponse
+	44,  // 122: netctrl.v1.AgentService.GetClusterPollStats:output_type -> netctrl.v1.GetClusterPollStatsResponse
+	46,  // 123: netctrl.v1.AgentService.GetClusterInstructionSummary:output_type -> netctrl.v1.GetClusterInstructionSummaryResponse
+	48,  // 124: netctrl.v1.AgentService.TailAgentActivity:output_type -> netctrl.v1.ActivityEvent
+	65,  // 125: netctrl.v1.AgentService.GetServerStats:output_type -> netctrl.v1.GetServerStatsResponse
+	72,  // 126: netctrl.v1.AgentService.GetFleetReport:output_type -> netctrl.v1.GetFleetReportResponse
+	71,  // 127: netctrl.v1.AgentService.ListFleetReportAgents:output_type -> netctrl.v1.ListFleetReportAgentsResponse
+	75,  // 128: netctrl.v1.AgentService.GetFleetHardwareSummary:output_type -> netctrl.v1.GetFleetHardwareSummaryResponse
+	105, // [105:129] is the sub-list for method output_type
+	81,  // [81:105] is the sub-list for method input_type
+	81,  // [81:81] is the sub-list for extension type_name
+	81,  // [81:81] is the sub-list for extension extendee
+	0,   // [0:81] is the sub-list for field type_name
 }
 
 func init() { file_v1_agent_proto_init() }
@@ -1726,17 +5895,22 @@ func file_v1_agent_proto_init() {
 	if File_v1_agent_proto != nil {
 		return
 	}
-	file_v1_agent_proto_msgTypes[14].OneofWrappers = []any{
+	file_v1_network_proto_init()
+	file_v1_agent_proto_msgTypes[48].OneofWrappers = []any{
 		(*InstructionResult_HardwareCollection)(nil),
 		(*InstructionResult_HealthCheck)(nil),
+		(*InstructionResult_GatewayProbe)(nil),
+		(*InstructionResult_NetworkConfig)(nil),
+		(*InstructionResult_Decommission)(nil),
+		(*InstructionResult_RestartAgent)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_v1_agent_proto_rawDesc), len(file_v1_agent_proto_rawDesc)),
-			NumEnums:      4,
-			NumMessages:   19,
+			NumEnums:      9,
+			NumMessages:   70,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,12 +19,30 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AgentService_RegisterAgent_FullMethodName           = "/netctrl.v1.AgentService/RegisterAgent"
-	AgentService_GetAgent_FullMethodName                = "/netctrl.v1.AgentService/GetAgent"
-	AgentService_ListAgents_FullMethodName              = "/netctrl.v1.AgentService/ListAgents"
-	AgentService_UnregisterAgent_FullMethodName         = "/netctrl.v1.AgentService/UnregisterAgent"
-	AgentService_GetInstructions_FullMethodName         = "/netctrl.v1.AgentService/GetInstructions"
-	AgentService_SubmitInstructionResult_FullMethodName = "/netctrl.v1.AgentService/SubmitInstructionResult"
+	AgentService_RegisterAgent_FullMethodName                 = "/netctrl.v1.AgentService/RegisterAgent"
+	AgentService_GetAgent_FullMethodName                      = "/netctrl.v1.AgentService/GetAgent"
+	AgentService_ListAgents_FullMethodName                    = "/netctrl.v1.AgentService/ListAgents"
+	AgentService_GetAgentStatuses_FullMethodName              = "/netctrl.v1.AgentService/GetAgentStatuses"
+	AgentService_UnregisterAgent_FullMethodName               = "/netctrl.v1.AgentService/UnregisterAgent"
+	AgentService_DecommissionAgent_FullMethodName             = "/netctrl.v1.AgentService/DecommissionAgent"
+	AgentService_RestartAgent_FullMethodName                  = "/netctrl.v1.AgentService/RestartAgent"
+	AgentService_UpdateAgentConfig_FullMethodName             = "/netctrl.v1.AgentService/UpdateAgentConfig"
+	AgentService_EnqueueInstructionBySelector_FullMethodName  = "/netctrl.v1.AgentService/EnqueueInstructionBySelector"
+	AgentService_TriggerHardwareCollection_FullMethodName     = "/netctrl.v1.AgentService/TriggerHardwareCollection"
+	AgentService_GetInstructions_FullMethodName               = "/netctrl.v1.AgentService/GetInstructions"
+	AgentService_PreviewInstructions_FullMethodName           = "/netctrl.v1.AgentService/PreviewInstructions"
+	AgentService_SubmitInstructionResult_FullMethodName       = "/netctrl.v1.AgentService/SubmitInstructionResult"
+	AgentService_SubmitInstructionResultStream_FullMethodName = "/netctrl.v1.AgentService/SubmitInstructionResultStream"
+	AgentService_FindOrphanedAgents_FullMethodName            = "/netctrl.v1.AgentService/FindOrphanedAgents"
+	AgentService_ReapOrphanedAgents_FullMethodName            = "/netctrl.v1.AgentService/ReapOrphanedAgents"
+	AgentService_SetThrottleMode_FullMethodName               = "/netctrl.v1.AgentService/SetThrottleMode"
+	AgentService_GetClusterPollStats_FullMethodName           = "/netctrl.v1.AgentService/GetClusterPollStats"
+	AgentService_GetClusterInstructionSummary_FullMethodName  = "/netctrl.v1.AgentService/GetClusterInstructionSummary"
+	AgentService_TailAgentActivity_FullMethodName             = "/netctrl.v1.AgentService/TailAgentActivity"
+	AgentService_GetServerStats_FullMethodName                = "/netctrl.v1.AgentService/GetServerStats"
+	AgentService_GetFleetReport_FullMethodName                = "/netctrl.v1.AgentService/GetFleetReport"
+	AgentService_ListFleetReportAgents_FullMethodName         = "/netctrl.v1.AgentService/ListFleetReportAgents"
+	AgentService_GetFleetHardwareSummary_FullMethodName       = "/netctrl.v1.AgentService/GetFleetHardwareSummary"
 )
 
 // AgentServiceClient is the client API for AgentService service.
@@ -39,13 +57,66 @@ type AgentServiceClient interface {
 	GetAgent(ctx context.Context, in *GetAgentRequest, opts ...grpc.CallOption) (*GetAgentResponse, error)
 	// ListAgents lists all agents, optionally filtered by cluster
 	ListAgents(ctx context.Context, in *ListAgentsRequest, opts ...grpc.CallOption) (*ListAgentsResponse, error)
+	// GetAgentStatuses returns just the statuses of the given agents, for
+	// pollers that track a known set of agents
+	GetAgentStatuses(ctx context.Context, in *GetAgentStatusesRequest, opts ...grpc.CallOption) (*GetAgentStatusesResponse, error)
 	// UnregisterAgent removes an agent
 	UnregisterAgent(ctx context.Context, in *UnregisterAgentRequest, opts ...grpc.CallOption) (*UnregisterAgentResponse, error)
+	// DecommissionAgent marks an agent for decommission; it is sent a cleanup
+	// instruction and unregistered once it confirms the cleanup succeeded
+	DecommissionAgent(ctx context.Context, in *DecommissionAgentRequest, opts ...grpc.CallOption) (*DecommissionAgentResponse, error)
+	// RestartAgent asks an agent to restart its process on its next poll (admin)
+	RestartAgent(ctx context.Context, in *RestartAgentRequest, opts ...grpc.CallOption) (*RestartAgentResponse, error)
+	// UpdateAgentConfig replaces an agent's config overrides (admin)
+	UpdateAgentConfig(ctx context.Context, in *UpdateAgentConfigRequest, opts ...grpc.CallOption) (*UpdateAgentConfigResponse, error)
+	// EnqueueInstructionBySelector requests an instruction from every agent of
+	// a cluster matching a selector on role and group, e.g. restarting all
+	// spines (admin)
+	EnqueueInstructionBySelector(ctx context.Context, in *EnqueueInstructionBySelectorRequest, opts ...grpc.CallOption) (*EnqueueInstructionBySelectorResponse, error)
+	// TriggerHardwareCollection makes the selected agents collect hardware
+	// inventory again on their next poll, e.g. after a firmware push
+	TriggerHardwareCollection(ctx context.Context, in *TriggerHardwareCollectionRequest, opts ...grpc.CallOption) (*TriggerHardwareCollectionResponse, error)
 	// GetInstructions polls for pending instructions
 	// This serves as instruction delivery and implicit healthcheck
 	GetInstructions(ctx context.Context, in *GetInstructionsRequest, opts ...grpc.CallOption) (*GetInstructionsResponse, error)
+	// PreviewInstructions returns the instructions the agent would receive if
+	// it polled now, without recording a poll or changing its state (admin)
+	PreviewInstructions(ctx context.Context, in *PreviewInstructionsRequest, opts ...grpc.CallOption) (*GetInstructionsResponse, error)
 	// SubmitInstructionResult submits the result of a completed instruction
 	SubmitInstructionResult(ctx context.Context, in *SubmitInstructionResultRequest, opts ...grpc.CallOption) (*SubmitInstructionResultResponse, error)
+	// SubmitInstructionResultStream submits a large instruction result in
+	// chunks, which the server reassembles before processing it like
+	// SubmitInstructionResult. It is only available over gRPC.
+	SubmitInstructionResultStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[InstructionResultChunk, SubmitInstructionResultResponse], error)
+	// FindOrphanedAgents lists agents whose cluster no longer exists
+	FindOrphanedAgents(ctx context.Context, in *FindOrphanedAgentsRequest, opts ...grpc.CallOption) (*FindOrphanedAgentsResponse, error)
+	// ReapOrphanedAgents deletes agents whose cluster no longer exists
+	ReapOrphanedAgents(ctx context.Context, in *ReapOrphanedAgentsRequest, opts ...grpc.CallOption) (*ReapOrphanedAgentsResponse, error)
+	// SetThrottleMode turns fleet-wide throttling on or off; while on, all
+	// agents are told to poll less frequently to protect the backend
+	SetThrottleMode(ctx context.Context, in *SetThrottleModeRequest, opts ...grpc.CallOption) (*SetThrottleModeResponse, error)
+	// GetClusterPollStats reports the observed poll intervals of a cluster's
+	// agents against the configured interval
+	GetClusterPollStats(ctx context.Context, in *GetClusterPollStatsRequest, opts ...grpc.CallOption) (*GetClusterPollStatsResponse, error)
+	// GetClusterInstructionSummary counts how many of a cluster's agents
+	// succeeded or failed their most recent instruction of a given type
+	GetClusterInstructionSummary(ctx context.Context, in *GetClusterInstructionSummaryRequest, opts ...grpc.CallOption) (*GetClusterInstructionSummaryResponse, error)
+	// TailAgentActivity streams an agent's polls, issued instructions and
+	// submitted results as they happen, for live debugging
+	TailAgentActivity(ctx context.Context, in *TailAgentActivityRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ActivityEvent], error)
+	// GetServerStats returns a runtime and storage snapshot of the server, for
+	// operators without access to Prometheus (admin)
+	GetServerStats(ctx context.Context, in *GetServerStatsRequest, opts ...grpc.CallOption) (*GetServerStatsResponse, error)
+	// GetFleetReport summarizes what needs attention across the fleet or one
+	// cluster: inactive agents, config or poll interval drift, outdated
+	// versions and missing hardware collection (admin)
+	GetFleetReport(ctx context.Context, in *GetFleetReportRequest, opts ...grpc.CallOption) (*GetFleetReportResponse, error)
+	// ListFleetReportAgents pages through every agent in one fleet report
+	// category, for drilling into a category beyond its sample (admin)
+	ListFleetReportAgents(ctx context.Context, in *ListFleetReportAgentsRequest, opts ...grpc.CallOption) (*ListFleetReportAgentsResponse, error)
+	// GetFleetHardwareSummary counts NICs by model and firmware across all
+	// clusters, e.g. for procurement (admin)
+	GetFleetHardwareSummary(ctx context.Context, in *GetFleetHardwareSummaryRequest, opts ...grpc.CallOption) (*GetFleetHardwareSummaryResponse, error)
 }
 
 type agentServiceClient struct {
@@ -86,6 +157,16 @@ func (c *agentServiceClient) ListAgents(ctx context.Context, in *ListAgentsReque
 	return out, nil
 }
 
+func (c *agentServiceClient) GetAgentStatuses(ctx context.Context, in *GetAgentStatusesRequest, opts ...grpc.CallOption) (*GetAgentStatusesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAgentStatusesResponse)
+	err := c.cc.Invoke(ctx, AgentService_GetAgentStatuses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *agentServiceClient) UnregisterAgent(ctx context.Context, in *UnregisterAgentRequest, opts ...grpc.CallOption) (*UnregisterAgentResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UnregisterAgentResponse)
@@ -96,6 +177,56 @@ func (c *agentServiceClient) UnregisterAgent(ctx context.Context, in *Unregister
 	return out, nil
 }
 
+func (c *agentServiceClient) DecommissionAgent(ctx context.Context, in *DecommissionAgentRequest, opts ...grpc.CallOption) (*DecommissionAgentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecommissionAgentResponse)
+	err := c.cc.Invoke(ctx, AgentService_DecommissionAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) RestartAgent(ctx context.Context, in *RestartAgentRequest, opts ...grpc.CallOption) (*RestartAgentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestartAgentResponse)
+	err := c.cc.Invoke(ctx, AgentService_RestartAgent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) UpdateAgentConfig(ctx context.Context, in *UpdateAgentConfigRequest, opts ...grpc.CallOption) (*UpdateAgentConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateAgentConfigResponse)
+	err := c.cc.Invoke(ctx, AgentService_UpdateAgentConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) EnqueueInstructionBySelector(ctx context.Context, in *EnqueueInstructionBySelectorRequest, opts ...grpc.CallOption) (*EnqueueInstructionBySelectorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnqueueInstructionBySelectorResponse)
+	err := c.cc.Invoke(ctx, AgentService_EnqueueInstructionBySelector_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) TriggerHardwareCollection(ctx context.Context, in *TriggerHardwareCollectionRequest, opts ...grpc.CallOption) (*TriggerHardwareCollectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TriggerHardwareCollectionResponse)
+	err := c.cc.Invoke(ctx, AgentService_TriggerHardwareCollection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *agentServiceClient) GetInstructions(ctx context.Context, in *GetInstructionsRequest, opts ...grpc.CallOption) (*GetInstructionsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetInstructionsResponse)
@@ -106,6 +237,16 @@ func (c *agentServiceClient) GetInstructions(ctx context.Context, in *GetInstruc
 	return out, nil
 }
 
+func (c *agentServiceClient) PreviewInstructions(ctx context.Context, in *PreviewInstructionsRequest, opts ...grpc.CallOption) (*GetInstructionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetInstructionsResponse)
+	err := c.cc.Invoke(ctx, AgentService_PreviewInstructions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *agentServiceClient) SubmitInstructionResult(ctx context.Context, in *SubmitInstructionResultRequest, opts ...grpc.CallOption) (*SubmitInstructionResultResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SubmitInstructionResultResponse)
@@ -116,6 +257,128 @@ func (c *agentServiceClient) SubmitInstructionResult(ctx context.Context, in *Su
 	return out, nil
 }
 
+func (c *agentServiceClient) SubmitInstructionResultStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[InstructionResultChunk, SubmitInstructionResultResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AgentService_ServiceDesc.Streams[0], AgentService_SubmitInstructionResultStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[InstructionResultChunk, SubmitInstructionResultResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentService_SubmitInstructionResultStreamClient = grpc.ClientStreamingClient[InstructionResultChunk, SubmitInstructionResultResponse]
+
+func (c *agentServiceClient) FindOrphanedAgents(ctx context.Context, in *FindOrphanedAgentsRequest, opts ...grpc.CallOption) (*FindOrphanedAgentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindOrphanedAgentsResponse)
+	err := c.cc.Invoke(ctx, AgentService_FindOrphanedAgents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) ReapOrphanedAgents(ctx context.Context, in *ReapOrphanedAgentsRequest, opts ...grpc.CallOption) (*ReapOrphanedAgentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReapOrphanedAgentsResponse)
+	err := c.cc.Invoke(ctx, AgentService_ReapOrphanedAgents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) SetThrottleMode(ctx context.Context, in *SetThrottleModeRequest, opts ...grpc.CallOption) (*SetThrottleModeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetThrottleModeResponse)
+	err := c.cc.Invoke(ctx, AgentService_SetThrottleMode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) GetClusterPollStats(ctx context.Context, in *GetClusterPollStatsRequest, opts ...grpc.CallOption) (*GetClusterPollStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetClusterPollStatsResponse)
+	err := c.cc.Invoke(ctx, AgentService_GetClusterPollStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) GetClusterInstructionSummary(ctx context.Context, in *GetClusterInstructionSummaryRequest, opts ...grpc.CallOption) (*GetClusterInstructionSummaryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetClusterInstructionSummaryResponse)
+	err := c.cc.Invoke(ctx, AgentService_GetClusterInstructionSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) TailAgentActivity(ctx context.Context, in *TailAgentActivityRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ActivityEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AgentService_ServiceDesc.Streams[1], AgentService_TailAgentActivity_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TailAgentActivityRequest, ActivityEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentService_TailAgentActivityClient = grpc.ServerStreamingClient[ActivityEvent]
+
+func (c *agentServiceClient) GetServerStats(ctx context.Context, in *GetServerStatsRequest, opts ...grpc.CallOption) (*GetServerStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetServerStatsResponse)
+	err := c.cc.Invoke(ctx, AgentService_GetServerStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) GetFleetReport(ctx context.Context, in *GetFleetReportRequest, opts ...grpc.CallOption) (*GetFleetReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFleetReportResponse)
+	err := c.cc.Invoke(ctx, AgentService_GetFleetReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) ListFleetReportAgents(ctx context.Context, in *ListFleetReportAgentsRequest, opts ...grpc.CallOption) (*ListFleetReportAgentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFleetReportAgentsResponse)
+	err := c.cc.Invoke(ctx, AgentService_ListFleetReportAgents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentServiceClient) GetFleetHardwareSummary(ctx context.Context, in *GetFleetHardwareSummaryRequest, opts ...grpc.CallOption) (*GetFleetHardwareSummaryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFleetHardwareSummaryResponse)
+	err := c.cc.Invoke(ctx, AgentService_GetFleetHardwareSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServiceServer is the server API for AgentService service.
 // All implementations must embed UnimplementedAgentServiceServer
 // for forward compatibility.
@@ -128,13 +391,66 @@ type AgentServiceServer interface {
 	GetAgent(context.Context, *GetAgentRequest) (*GetAgentResponse, error)
 	// ListAgents lists all agents, optionally filtered by cluster
 	ListAgents(context.Context, *ListAgentsRequest) (*ListAgentsResponse, error)
+	// GetAgentStatuses returns just the statuses of the given agents, for
+	// pollers that track a known set of agents
+	GetAgentStatuses(context.Context, *GetAgentStatusesRequest) (*GetAgentStatusesResponse, error)
 	// UnregisterAgent removes an agent
 	UnregisterAgent(context.Context, *UnregisterAgentRequest) (*UnregisterAgentResponse, error)
+	// DecommissionAgent marks an agent for decommission; it is sent a cleanup
+	// instruction and unregistered once it confirms the cleanup succeeded
+	DecommissionAgent(context.Context, *DecommissionAgentRequest) (*DecommissionAgentResponse, error)
+	// RestartAgent asks an agent to restart its process on its next poll (admin)
+	RestartAgent(context.Context, *RestartAgentRequest) (*RestartAgentResponse, error)
+	// UpdateAgentConfig replaces an agent's config overrides (admin)
+	UpdateAgentConfig(context.Context, *UpdateAgentConfigRequest) (*UpdateAgentConfigResponse, error)
+	// EnqueueInstructionBySelector requests an instruction from every agent of
+	// a cluster matching a selector on role and group, e.g. restarting all
+	// spines (admin)
+	EnqueueInstructionBySelector(context.Context, *EnqueueInstructionBySelectorRequest) (*EnqueueInstructionBySelectorResponse, error)
+	// TriggerHardwareCollection makes the selected agents collect hardware
+	// inventory again on their next poll, e.g. after a firmware push
+	TriggerHardwareCollection(context.Context, *TriggerHardwareCollectionRequest) (*TriggerHardwareCollectionResponse, error)
 	// GetInstructions polls for pending instructions
 	// This serves as instruction delivery and implicit healthcheck
 	GetInstructions(context.Context, *GetInstructionsRequest) (*GetInstructionsResponse, error)
+	// PreviewInstructions returns the instructions the agent would receive if
+	// it polled now, without recording a poll or changing its state (admin)
+	PreviewInstructions(context.Context, *PreviewInstructionsRequest) (*GetInstructionsResponse, error)
 	// SubmitInstructionResult submits the result of a completed instruction
 	SubmitInstructionResult(context.Context, *SubmitInstructionResultRequest) (*SubmitInstructionResultResponse, error)
+	// SubmitInstructionResultStream submits a large instruction result in
+	// chunks, which the server reassembles before processing it like
+	// SubmitInstructionResult. It is only available over gRPC.
+	SubmitInstructionResultStream(grpc.ClientStreamingServer[InstructionResultChunk, SubmitInstructionResultResponse]) error
+	// FindOrphanedAgents lists agents whose cluster no longer exists
+	FindOrphanedAgents(context.Context, *FindOrphanedAgentsRequest) (*FindOrphanedAgentsResponse, error)
+	// ReapOrphanedAgents deletes agents whose cluster no longer exists
+	ReapOrphanedAgents(context.Context, *ReapOrphanedAgentsRequest) (*ReapOrphanedAgentsResponse, error)
+	// SetThrottleMode turns fleet-wide throttling on or off; while on, all
+	// agents are told to poll less frequently to protect the backend
+	SetThrottleMode(context.Context, *SetThrottleModeRequest) (*SetThrottleModeResponse, error)
+	// GetClusterPollStats reports the observed poll intervals of a cluster's
+	// agents against the configured interval
+	GetClusterPollStats(context.Context, *GetClusterPollStatsRequest) (*GetClusterPollStatsResponse, error)
+	// GetClusterInstructionSummary counts how many of a cluster's agents
+	// succeeded or failed their most recent instruction of a given type
+	GetClusterInstructionSummary(context.Context, *GetClusterInstructionSummaryRequest) (*GetClusterInstructionSummaryResponse, error)
+	// TailAgentActivity streams an agent's polls, issued instructions and
+	// submitted results as they happen, for live debugging
+	TailAgentActivity(*TailAgentActivityRequest, grpc.ServerStreamingServer[ActivityEvent]) error
+	// GetServerStats returns a runtime and storage snapshot of the server, for
+	// operators without access to Prometheus (admin)
+	GetServerStats(context.Context, *GetServerStatsRequest) (*GetServerStatsResponse, error)
+	// GetFleetReport summarizes what needs attention across the fleet or one
+	// cluster: inactive agents, config or poll interval drift, outdated
+	// versions and missing hardware collection (admin)
+	GetFleetReport(context.Context, *GetFleetReportRequest) (*GetFleetReportResponse, error)
+	// ListFleetReportAgents pages through every agent in one fleet report
+	// category, for drilling into a category beyond its sample (admin)
+	ListFleetReportAgents(context.Context, *ListFleetReportAgentsRequest) (*ListFleetReportAgentsResponse, error)
+	// GetFleetHardwareSummary counts NICs by model and firmware across all
+	// clusters, e.g. for procurement (admin)
+	GetFleetHardwareSummary(context.Context, *GetFleetHardwareSummaryRequest) (*GetFleetHardwareSummaryResponse, error)
 	mustEmbedUnimplementedAgentServiceServer()
 }
 
@@ -154,15 +470,69 @@ func (UnimplementedAgentServiceServer) GetAgent(context.Context, *GetAgentReques
 func (UnimplementedAgentServiceServer) ListAgents(context.Context, *ListAgentsRequest) (*ListAgentsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListAgents not implemented")
 }
+func (UnimplementedAgentServiceServer) GetAgentStatuses(context.Context, *GetAgentStatusesRequest) (*GetAgentStatusesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetAgentStatuses not implemented")
+}
 func (UnimplementedAgentServiceServer) UnregisterAgent(context.Context, *UnregisterAgentRequest) (*UnregisterAgentResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method UnregisterAgent not implemented")
 }
+func (UnimplementedAgentServiceServer) DecommissionAgent(context.Context, *DecommissionAgentRequest) (*DecommissionAgentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DecommissionAgent not implemented")
+}
+func (UnimplementedAgentServiceServer) RestartAgent(context.Context, *RestartAgentRequest) (*RestartAgentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RestartAgent not implemented")
+}
+func (UnimplementedAgentServiceServer) UpdateAgentConfig(context.Context, *UpdateAgentConfigRequest) (*UpdateAgentConfigResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateAgentConfig not implemented")
+}
+func (UnimplementedAgentServiceServer) EnqueueInstructionBySelector(context.Context, *EnqueueInstructionBySelectorRequest) (*EnqueueInstructionBySelectorResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EnqueueInstructionBySelector not implemented")
+}
+func (UnimplementedAgentServiceServer) TriggerHardwareCollection(context.Context, *TriggerHardwareCollectionRequest) (*TriggerHardwareCollectionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TriggerHardwareCollection not implemented")
+}
 func (UnimplementedAgentServiceServer) GetInstructions(context.Context, *GetInstructionsRequest) (*GetInstructionsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetInstructions not implemented")
 }
+func (UnimplementedAgentServiceServer) PreviewInstructions(context.Context, *PreviewInstructionsRequest) (*GetInstructionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PreviewInstructions not implemented")
+}
 func (UnimplementedAgentServiceServer) SubmitInstructionResult(context.Context, *SubmitInstructionResultRequest) (*SubmitInstructionResultResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method SubmitInstructionResult not implemented")
 }
+func (UnimplementedAgentServiceServer) SubmitInstructionResultStream(grpc.ClientStreamingServer[InstructionResultChunk, SubmitInstructionResultResponse]) error {
+	return status.Error(codes.Unimplemented, "method SubmitInstructionResultStream not implemented")
+}
+func (UnimplementedAgentServiceServer) FindOrphanedAgents(context.Context, *FindOrphanedAgentsRequest) (*FindOrphanedAgentsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method FindOrphanedAgents not implemented")
+}
+func (UnimplementedAgentServiceServer) ReapOrphanedAgents(context.Context, *ReapOrphanedAgentsRequest) (*ReapOrphanedAgentsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReapOrphanedAgents not implemented")
+}
+func (UnimplementedAgentServiceServer) SetThrottleMode(context.Context, *SetThrottleModeRequest) (*SetThrottleModeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetThrottleMode not implemented")
+}
+func (UnimplementedAgentServiceServer) GetClusterPollStats(context.Context, *GetClusterPollStatsRequest) (*GetClusterPollStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetClusterPollStats not implemented")
+}
+func (UnimplementedAgentServiceServer) GetClusterInstructionSummary(context.Context, *GetClusterInstructionSummaryRequest) (*GetClusterInstructionSummaryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetClusterInstructionSummary not implemented")
+}
+func (UnimplementedAgentServiceServer) TailAgentActivity(*TailAgentActivityRequest, grpc.ServerStreamingServer[ActivityEvent]) error {
+	return status.Error(codes.Unimplemented, "method TailAgentActivity not implemented")
+}
+func (UnimplementedAgentServiceServer) GetServerStats(context.Context, *GetServerStatsRequest) (*GetServerStatsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetServerStats not implemented")
+}
+func (UnimplementedAgentServiceServer) GetFleetReport(context.Context, *GetFleetReportRequest) (*GetFleetReportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetFleetReport not implemented")
+}
+func (UnimplementedAgentServiceServer) ListFleetReportAgents(context.Context, *ListFleetReportAgentsRequest) (*ListFleetReportAgentsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListFleetReportAgents not implemented")
+}
+func (UnimplementedAgentServiceServer) GetFleetHardwareSummary(context.Context, *GetFleetHardwareSummaryRequest) (*GetFleetHardwareSummaryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetFleetHardwareSummary not implemented")
+}
 func (UnimplementedAgentServiceServer) mustEmbedUnimplementedAgentServiceServer() {}
 func (UnimplementedAgentServiceServer) testEmbeddedByValue()                      {}
 
@@ -238,6 +608,24 @@ func _AgentService_ListAgents_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AgentService_GetAgentStatuses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgentStatusesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).GetAgentStatuses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_GetAgentStatuses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).GetAgentStatuses(ctx, req.(*GetAgentStatusesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AgentService_UnregisterAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UnregisterAgentRequest)
 	if err := dec(in); err != nil {
@@ -256,6 +644,96 @@ func _AgentService_UnregisterAgent_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AgentService_DecommissionAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecommissionAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).DecommissionAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_DecommissionAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).DecommissionAgent(ctx, req.(*DecommissionAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_RestartAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestartAgentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).RestartAgent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_RestartAgent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).RestartAgent(ctx, req.(*RestartAgentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_UpdateAgentConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAgentConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).UpdateAgentConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_UpdateAgentConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).UpdateAgentConfig(ctx, req.(*UpdateAgentConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_EnqueueInstructionBySelector_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnqueueInstructionBySelectorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).EnqueueInstructionBySelector(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_EnqueueInstructionBySelector_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).EnqueueInstructionBySelector(ctx, req.(*EnqueueInstructionBySelectorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_TriggerHardwareCollection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TriggerHardwareCollectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).TriggerHardwareCollection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_TriggerHardwareCollection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).TriggerHardwareCollection(ctx, req.(*TriggerHardwareCollectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AgentService_GetInstructions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetInstructionsRequest)
 	if err := dec(in); err != nil {
@@ -274,6 +752,24 @@ func _AgentService_GetInstructions_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AgentService_PreviewInstructions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreviewInstructionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).PreviewInstructions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_PreviewInstructions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).PreviewInstructions(ctx, req.(*PreviewInstructionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AgentService_SubmitInstructionResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SubmitInstructionResultRequest)
 	if err := dec(in); err != nil {
@@ -292,6 +788,186 @@ func _AgentService_SubmitInstructionResult_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AgentService_SubmitInstructionResultStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AgentServiceServer).SubmitInstructionResultStream(&grpc.GenericServerStream[InstructionResultChunk, SubmitInstructionResultResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentService_SubmitInstructionResultStreamServer = grpc.ClientStreamingServer[InstructionResultChunk, SubmitInstructionResultResponse]
+
+func _AgentService_FindOrphanedAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindOrphanedAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).FindOrphanedAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_FindOrphanedAgents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).FindOrphanedAgents(ctx, req.(*FindOrphanedAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_ReapOrphanedAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReapOrphanedAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).ReapOrphanedAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_ReapOrphanedAgents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).ReapOrphanedAgents(ctx, req.(*ReapOrphanedAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_SetThrottleMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetThrottleModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).SetThrottleMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_SetThrottleMode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).SetThrottleMode(ctx, req.(*SetThrottleModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_GetClusterPollStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClusterPollStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).GetClusterPollStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_GetClusterPollStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).GetClusterPollStats(ctx, req.(*GetClusterPollStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_GetClusterInstructionSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClusterInstructionSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).GetClusterInstructionSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_GetClusterInstructionSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).GetClusterInstructionSummary(ctx, req.(*GetClusterInstructionSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_TailAgentActivity_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailAgentActivityRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServiceServer).TailAgentActivity(m, &grpc.GenericServerStream[TailAgentActivityRequest, ActivityEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentService_TailAgentActivityServer = grpc.ServerStreamingServer[ActivityEvent]
+
+func _AgentService_GetServerStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetServerStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).GetServerStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_GetServerStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).GetServerStats(ctx, req.(*GetServerStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_GetFleetReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFleetReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).GetFleetReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_GetFleetReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).GetFleetReport(ctx, req.(*GetFleetReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_ListFleetReportAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFleetReportAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).ListFleetReportAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_ListFleetReportAgents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).ListFleetReportAgents(ctx, req.(*ListFleetReportAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentService_GetFleetHardwareSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFleetHardwareSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).GetFleetHardwareSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_GetFleetHardwareSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).GetFleetHardwareSummary(ctx, req.(*GetFleetHardwareSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AgentService_ServiceDesc is the grpc.ServiceDesc for AgentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -311,19 +987,94 @@ var AgentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListAgents",
 			Handler:    _AgentService_ListAgents_Handler,
 		},
+		{
+			MethodName: "GetAgentStatuses",
+			Handler:    _AgentService_GetAgentStatuses_Handler,
+		},
 		{
 			MethodName: "UnregisterAgent",
 			Handler:    _AgentService_UnregisterAgent_Handler,
 		},
+		{
+			MethodName: "DecommissionAgent",
+			Handler:    _AgentService_DecommissionAgent_Handler,
+		},
+		{
+			MethodName: "RestartAgent",
+			Handler:    _AgentService_RestartAgent_Handler,
+		},
+		{
+			MethodName: "UpdateAgentConfig",
+			Handler:    _AgentService_UpdateAgentConfig_Handler,
+		},
+		{
+			MethodName: "EnqueueInstructionBySelector",
+			Handler:    _AgentService_EnqueueInstructionBySelector_Handler,
+		},
+		{
+			MethodName: "TriggerHardwareCollection",
+			Handler:    _AgentService_TriggerHardwareCollection_Handler,
+		},
 		{
 			MethodName: "GetInstructions",
 			Handler:    _AgentService_GetInstructions_Handler,
 		},
+		{
+			MethodName: "PreviewInstructions",
+			Handler:    _AgentService_PreviewInstructions_Handler,
+		},
 		{
 			MethodName: "SubmitInstructionResult",
 			Handler:    _AgentService_SubmitInstructionResult_Handler,
 		},
+		{
+			MethodName: "FindOrphanedAgents",
+			Handler:    _AgentService_FindOrphanedAgents_Handler,
+		},
+		{
+			MethodName: "ReapOrphanedAgents",
+			Handler:    _AgentService_ReapOrphanedAgents_Handler,
+		},
+		{
+			MethodName: "SetThrottleMode",
+			Handler:    _AgentService_SetThrottleMode_Handler,
+		},
+		{
+			MethodName: "GetClusterPollStats",
+			Handler:    _AgentService_GetClusterPollStats_Handler,
+		},
+		{
+			MethodName: "GetClusterInstructionSummary",
+			Handler:    _AgentService_GetClusterInstructionSummary_Handler,
+		},
+		{
+			MethodName: "GetServerStats",
+			Handler:    _AgentService_GetServerStats_Handler,
+		},
+		{
+			MethodName: "GetFleetReport",
+			Handler:    _AgentService_GetFleetReport_Handler,
+		},
+		{
+			MethodName: "ListFleetReportAgents",
+			Handler:    _AgentService_ListFleetReportAgents_Handler,
+		},
+		{
+			MethodName: "GetFleetHardwareSummary",
+			Handler:    _AgentService_GetFleetHardwareSummary_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubmitInstructionResultStream",
+			Handler:       _AgentService_SubmitInstructionResultStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "TailAgentActivity",
+			Handler:       _AgentService_TailAgentActivity_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "v1/agent.proto",
 }
@@ -36,9 +36,17 @@ type Cluster struct {
 	// Creation timestamp
 	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	// Last update timestamp
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Whether the cluster is cordoned (no new instructions are generated)
+	Cordoned bool `protobuf:"varint,6,opt,name=cordoned,proto3" json:"cordoned,omitempty"`
+	// Network configuration of the cluster
+	NetworkConfig *NetworkConfig `protobuf:"bytes,7,opt,name=network_config,json=networkConfig,proto3" json:"network_config,omitempty"`
+	// Additional named networks of the cluster (e.g., a separate data
+	// network). network_config remains the primary network agents are
+	// configured with.
+	AdditionalNetworks []*NetworkConfig `protobuf:"bytes,8,rep,name=additional_networks,json=additionalNetworks,proto3" json:"additional_networks,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *Cluster) Reset() {
@@ -106,15 +114,40 @@ func (x *Cluster) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Cluster) GetCordoned() bool {
+	if x != nil {
+		return x.Cordoned
+	}
+	return false
+}
+
+func (x *Cluster) GetNetworkConfig() *NetworkConfig {
+	if x != nil {
+		return x.NetworkConfig
+	}
+	return nil
+}
+
+func (x *Cluster) GetAdditionalNetworks() []*NetworkConfig {
+	if x != nil {
+		return x.AdditionalNetworks
+	}
+	return nil
+}
+
 // CreateClusterRequest contains parameters for creating a cluster
 type CreateClusterRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Name of the cluster (required)
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// Description of the cluster
-	Description   string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// Network configuration of the cluster (optional)
+	NetworkConfig *NetworkConfig `protobuf:"bytes,3,opt,name=network_config,json=networkConfig,proto3" json:"network_config,omitempty"`
+	// Additional named networks of the cluster (optional)
+	AdditionalNetworks []*NetworkConfig `protobuf:"bytes,4,rep,name=additional_networks,json=additionalNetworks,proto3" json:"additional_networks,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *CreateClusterRequest) Reset() {
@@ -161,6 +194,20 @@ func (x *CreateClusterRequest) GetDescription() string {
 	return ""
 }
 
+func (x *CreateClusterRequest) GetNetworkConfig() *NetworkConfig {
+	if x != nil {
+		return x.NetworkConfig
+	}
+	return nil
+}
+
+func (x *CreateClusterRequest) GetAdditionalNetworks() []*NetworkConfig {
+	if x != nil {
+		return x.AdditionalNetworks
+	}
+	return nil
+}
+
 // CreateClusterResponse returns the created cluster
 type CreateClusterResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -210,7 +257,12 @@ func (x *CreateClusterResponse) GetCluster() *Cluster {
 type GetClusterRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// ID of the cluster to retrieve
-	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Also return the cluster's agents, sparing a separate ListAgents call
+	IncludeAgents bool `protobuf:"varint,2,opt,name=include_agents,json=includeAgents,proto3" json:"include_agents,omitempty"`
+	// Only include agents with this status; all agents when unspecified.
+	// Ignored unless include_agents is set.
+	AgentStatus   AgentStatus `protobuf:"varint,3,opt,name=agent_status,json=agentStatus,proto3,enum=netctrl.v1.AgentStatus" json:"agent_status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -252,10 +304,30 @@ func (x *GetClusterRequest) GetId() string {
 	return ""
 }
 
+func (x *GetClusterRequest) GetIncludeAgents() bool {
+	if x != nil {
+		return x.IncludeAgents
+	}
+	return false
+}
+
+func (x *GetClusterRequest) GetAgentStatus() AgentStatus {
+	if x != nil {
+		return x.AgentStatus
+	}
+	return AgentStatus_AGENT_STATUS_UNSPECIFIED
+}
+
 // GetClusterResponse returns the requested cluster
 type GetClusterResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Cluster       *Cluster               `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Cluster *Cluster               `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	// Opaque version of the cluster that changes whenever the cluster does;
+	// the gateway returns it as the ETag header and honors If-None-Match.
+	// Empty when agents are included, as they are not covered by it.
+	Etag string `protobuf:"bytes,2,opt,name=etag,proto3" json:"etag,omitempty"`
+	// Agents of the cluster, when include_agents was set
+	Agents        []*Agent `protobuf:"bytes,3,rep,name=agents,proto3" json:"agents,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -297,13 +369,29 @@ func (x *GetClusterResponse) GetCluster() *Cluster {
 	return nil
 }
 
+func (x *GetClusterResponse) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *GetClusterResponse) GetAgents() []*Agent {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
 // ListClustersRequest contains parameters for listing clusters
 type ListClustersRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Optional page size for pagination (future enhancement)
 	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
 	// Optional page token for pagination (future enhancement)
-	PageToken     string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Only return clusters whose name starts with this prefix (case-sensitive)
+	NamePrefix    string `protobuf:"bytes,3,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -352,6 +440,13 @@ func (x *ListClustersRequest) GetPageToken() string {
 	return ""
 }
 
+func (x *ListClustersRequest) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
 // ListClustersResponse returns a list of clusters
 type ListClustersResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -417,9 +512,13 @@ type UpdateClusterRequest struct {
 	// Description of the cluster
 	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
 	// Field mask to specify which fields to update
-	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// Network configuration of the cluster
+	NetworkConfig *NetworkConfig `protobuf:"bytes,5,opt,name=network_config,json=networkConfig,proto3" json:"network_config,omitempty"`
+	// Additional named networks; replaces the cluster's list when non-empty
+	AdditionalNetworks []*NetworkConfig `protobuf:"bytes,6,rep,name=additional_networks,json=additionalNetworks,proto3" json:"additional_networks,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *UpdateClusterRequest) Reset() {
@@ -480,12 +579,29 @@ func (x *UpdateClusterRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
 	return nil
 }
 
+func (x *UpdateClusterRequest) GetNetworkConfig() *NetworkConfig {
+	if x != nil {
+		return x.NetworkConfig
+	}
+	return nil
+}
+
+func (x *UpdateClusterRequest) GetAdditionalNetworks() []*NetworkConfig {
+	if x != nil {
+		return x.AdditionalNetworks
+	}
+	return nil
+}
+
 // UpdateClusterResponse returns the updated cluster
 type UpdateClusterResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Cluster       *Cluster               `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Cluster *Cluster               `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	// Agents whose IP address falls outside the cluster's new CIDR, when the
+	// update changed it
+	OutOfRangeAgentIds []string `protobuf:"bytes,2,rep,name=out_of_range_agent_ids,json=outOfRangeAgentIds,proto3" json:"out_of_range_agent_ids,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *UpdateClusterResponse) Reset() {
@@ -525,6 +641,13 @@ func (x *UpdateClusterResponse) GetCluster() *Cluster {
 	return nil
 }
 
+func (x *UpdateClusterResponse) GetOutOfRangeAgentIds() []string {
+	if x != nil {
+		return x.OutOfRangeAgentIds
+	}
+	return nil
+}
+
 // DeleteClusterRequest contains parameters for deleting a cluster
 type DeleteClusterRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -617,55 +740,845 @@ func (x *DeleteClusterResponse) GetSuccess() bool {
 	return false
 }
 
-var File_v1_cluster_proto protoreflect.FileDescriptor
+// DrainClusterRequest contains parameters for draining a cluster
+type DrainClusterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the cluster to drain
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_v1_cluster_proto_rawDesc = "" +
-	"\n" +
-	"\x10v1/cluster.proto\x12\n" +
-	"netctrl.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a google/protobuf/field_mask.proto\"\xc5\x01\n" +
-	"\aCluster\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x129\n" +
-	"\n" +
-	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
-	"\n" +
-	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"L\n" +
-	"\x14CreateClusterRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\"F\n" +
-	"\x15CreateClusterResponse\x12-\n" +
-	"\acluster\x18\x01 \x01(\v2\x13.netctrl.v1.ClusterR\acluster\"#\n" +
-	"\x11GetClusterRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"C\n" +
-	"\x12GetClusterResponse\x12-\n" +
-	"\acluster\x18\x01 \x01(\v2\x13.netctrl.v1.ClusterR\acluster\"Q\n" +
-	"\x13ListClustersRequest\x12\x1b\n" +
-	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
-	"\n" +
-	"page_token\x18\x02 \x01(\tR\tpageToken\"o\n" +
-	"\x14ListClustersResponse\x12/\n" +
-	"\bclusters\x18\x01 \x03(\v2\x13.netctrl.v1.ClusterR\bclusters\x12&\n" +
-	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x99\x01\n" +
-	"\x14UpdateClusterRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\x12;\n" +
-	"\vupdate_mask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
-	"updateMask\"F\n" +
-	"\x15UpdateClusterResponse\x12-\n" +
-	"\acluster\x18\x01 \x01(\v2\x13.netctrl.v1.ClusterR\acluster\"&\n" +
-	"\x14DeleteClusterRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"1\n" +
-	"\x15DeleteClusterResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess2\xc9\x04\n" +
+func (x *DrainClusterRequest) Reset() {
+	*x = DrainClusterRequest{}
+	mi := &file_v1_cluster_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainClusterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainClusterRequest) ProtoMessage() {}
+
+func (x *DrainClusterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_cluster_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainClusterRequest.ProtoReflect.Descriptor instead.
+func (*DrainClusterRequest) Descriptor() ([]byte, []int) {
+	return file_v1_cluster_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DrainClusterRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// DrainClusterResponse returns the cordoned cluster
+type DrainClusterResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Cluster *Cluster               `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	// Number of agents that will receive a drain instruction
+	AgentCount    int32 `protobuf:"varint,2,opt,name=agent_count,json=agentCount,proto3" json:"agent_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrainClusterResponse) Reset() {
+	*x = DrainClusterResponse{}
+	mi := &file_v1_cluster_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainClusterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainClusterResponse) ProtoMessage() {}
+
+func (x *DrainClusterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_cluster_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainClusterResponse.ProtoReflect.Descriptor instead.
+func (*DrainClusterResponse) Descriptor() ([]byte, []int) {
+	return file_v1_cluster_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DrainClusterResponse) GetCluster() *Cluster {
+	if x != nil {
+		return x.Cluster
+	}
+	return nil
+}
+
+func (x *DrainClusterResponse) GetAgentCount() int32 {
+	if x != nil {
+		return x.AgentCount
+	}
+	return 0
+}
+
+// UncordonClusterRequest contains parameters for uncordoning a cluster
+type UncordonClusterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the cluster to uncordon
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UncordonClusterRequest) Reset() {
+	*x = UncordonClusterRequest{}
+	mi := &file_v1_cluster_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UncordonClusterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UncordonClusterRequest) ProtoMessage() {}
+
+func (x *UncordonClusterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_cluster_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UncordonClusterRequest.ProtoReflect.Descriptor instead.
+func (*UncordonClusterRequest) Descriptor() ([]byte, []int) {
+	return file_v1_cluster_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *UncordonClusterRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// UncordonClusterResponse returns the uncordoned cluster
+type UncordonClusterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cluster       *Cluster               `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UncordonClusterResponse) Reset() {
+	*x = UncordonClusterResponse{}
+	mi := &file_v1_cluster_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UncordonClusterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UncordonClusterResponse) ProtoMessage() {}
+
+func (x *UncordonClusterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_cluster_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UncordonClusterResponse.ProtoReflect.Descriptor instead.
+func (*UncordonClusterResponse) Descriptor() ([]byte, []int) {
+	return file_v1_cluster_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UncordonClusterResponse) GetCluster() *Cluster {
+	if x != nil {
+		return x.Cluster
+	}
+	return nil
+}
+
+// MoveClusterRequest contains parameters for re-keying a cluster
+type MoveClusterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Current ID of the cluster
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// New ID for the cluster; it must follow the server's cluster ID scheme
+	// and not be used by another cluster
+	NewId         string `protobuf:"bytes,2,opt,name=new_id,json=newId,proto3" json:"new_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveClusterRequest) Reset() {
+	*x = MoveClusterRequest{}
+	mi := &file_v1_cluster_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveClusterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveClusterRequest) ProtoMessage() {}
+
+func (x *MoveClusterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_cluster_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveClusterRequest.ProtoReflect.Descriptor instead.
+func (*MoveClusterRequest) Descriptor() ([]byte, []int) {
+	return file_v1_cluster_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *MoveClusterRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MoveClusterRequest) GetNewId() string {
+	if x != nil {
+		return x.NewId
+	}
+	return ""
+}
+
+// MoveClusterResponse returns the re-keyed cluster
+type MoveClusterResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Cluster *Cluster               `protobuf:"bytes,1,opt,name=cluster,proto3" json:"cluster,omitempty"`
+	// Number of agents moved to the new cluster ID
+	AgentCount    int32 `protobuf:"varint,2,opt,name=agent_count,json=agentCount,proto3" json:"agent_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveClusterResponse) Reset() {
+	*x = MoveClusterResponse{}
+	mi := &file_v1_cluster_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveClusterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveClusterResponse) ProtoMessage() {}
+
+func (x *MoveClusterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_cluster_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveClusterResponse.ProtoReflect.Descriptor instead.
+func (*MoveClusterResponse) Descriptor() ([]byte, []int) {
+	return file_v1_cluster_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *MoveClusterResponse) GetCluster() *Cluster {
+	if x != nil {
+		return x.Cluster
+	}
+	return nil
+}
+
+func (x *MoveClusterResponse) GetAgentCount() int32 {
+	if x != nil {
+		return x.AgentCount
+	}
+	return 0
+}
+
+// FindMatchingClustersRequest contains the IP address to match
+type FindMatchingClustersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// IPv4 or IPv6 address (required)
+	IpAddress     string `protobuf:"bytes,1,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindMatchingClustersRequest) Reset() {
+	*x = FindMatchingClustersRequest{}
+	mi := &file_v1_cluster_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindMatchingClustersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindMatchingClustersRequest) ProtoMessage() {}
+
+func (x *FindMatchingClustersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_cluster_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindMatchingClustersRequest.ProtoReflect.Descriptor instead.
+func (*FindMatchingClustersRequest) Descriptor() ([]byte, []int) {
+	return file_v1_cluster_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *FindMatchingClustersRequest) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+// FindMatchingClustersResponse lists the clusters whose primary network
+// CIDR contains the address
+type FindMatchingClustersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Clusters      []*Cluster             `protobuf:"bytes,1,rep,name=clusters,proto3" json:"clusters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindMatchingClustersResponse) Reset() {
+	*x = FindMatchingClustersResponse{}
+	mi := &file_v1_cluster_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindMatchingClustersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindMatchingClustersResponse) ProtoMessage() {}
+
+func (x *FindMatchingClustersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_cluster_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindMatchingClustersResponse.ProtoReflect.Descriptor instead.
+func (*FindMatchingClustersResponse) Descriptor() ([]byte, []int) {
+	return file_v1_cluster_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *FindMatchingClustersResponse) GetClusters() []*Cluster {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
+// ClusterWithAgents is one record of a cluster import
+type ClusterWithAgents struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Cluster ID to keep from the source system, following the server's
+	// cluster ID scheme; generated when empty
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Name of the cluster (required)
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Description of the cluster
+	Description string `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// Network configuration of the cluster (optional)
+	NetworkConfig *NetworkConfig `protobuf:"bytes,4,opt,name=network_config,json=networkConfig,proto3" json:"network_config,omitempty"`
+	// Additional named networks of the cluster (optional)
+	AdditionalNetworks []*NetworkConfig `protobuf:"bytes,5,rep,name=additional_networks,json=additionalNetworks,proto3" json:"additional_networks,omitempty"`
+	// Agents of the cluster
+	Agents        []*ImportedAgent `protobuf:"bytes,6,rep,name=agents,proto3" json:"agents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterWithAgents) Reset() {
+	*x = ClusterWithAgents{}
+	mi := &file_v1_cluster_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterWithAgents) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterWithAgents) ProtoMessage() {}
+
+func (x *ClusterWithAgents) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_cluster_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterWithAgents.ProtoReflect.Descriptor instead.
+func (*ClusterWithAgents) Descriptor() ([]byte, []int) {
+	return file_v1_cluster_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ClusterWithAgents) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ClusterWithAgents) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ClusterWithAgents) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ClusterWithAgents) GetNetworkConfig() *NetworkConfig {
+	if x != nil {
+		return x.NetworkConfig
+	}
+	return nil
+}
+
+func (x *ClusterWithAgents) GetAdditionalNetworks() []*NetworkConfig {
+	if x != nil {
+		return x.AdditionalNetworks
+	}
+	return nil
+}
+
+func (x *ClusterWithAgents) GetAgents() []*ImportedAgent {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+// ImportedAgent is an agent imported with its cluster. Imported agents start
+// out inactive; their role is set when they next register.
+type ImportedAgent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Agent ID (required)
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Hostname of the node
+	Hostname string `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	// IP address of the node
+	IpAddress string `protobuf:"bytes,3,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	// Agent version
+	Version string `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	// Free-form group label
+	Group         string `protobuf:"bytes,5,opt,name=group,proto3" json:"group,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportedAgent) Reset() {
+	*x = ImportedAgent{}
+	mi := &file_v1_cluster_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportedAgent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportedAgent) ProtoMessage() {}
+
+func (x *ImportedAgent) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_cluster_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportedAgent.ProtoReflect.Descriptor instead.
+func (*ImportedAgent) Descriptor() ([]byte, []int) {
+	return file_v1_cluster_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ImportedAgent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ImportedAgent) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *ImportedAgent) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *ImportedAgent) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *ImportedAgent) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+// ImportRecordResult reports the outcome of one imported record
+type ImportRecordResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Zero-based position of the record in the stream
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// ID of the imported cluster, or of the record if it failed
+	ClusterId string `protobuf:"bytes,2,opt,name=cluster_id,json=clusterId,proto3" json:"cluster_id,omitempty"`
+	// Whether the cluster and all of its agents were imported
+	Success bool `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+	// Why the record was not imported
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// Number of agents imported with the cluster
+	AgentsImported int32 `protobuf:"varint,5,opt,name=agents_imported,json=agentsImported,proto3" json:"agents_imported,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ImportRecordResult) Reset() {
+	*x = ImportRecordResult{}
+	mi := &file_v1_cluster_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRecordResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRecordResult) ProtoMessage() {}
+
+func (x *ImportRecordResult) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_cluster_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRecordResult.ProtoReflect.Descriptor instead.
+func (*ImportRecordResult) Descriptor() ([]byte, []int) {
+	return file_v1_cluster_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ImportRecordResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ImportRecordResult) GetClusterId() string {
+	if x != nil {
+		return x.ClusterId
+	}
+	return ""
+}
+
+func (x *ImportRecordResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ImportRecordResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ImportRecordResult) GetAgentsImported() int32 {
+	if x != nil {
+		return x.AgentsImported
+	}
+	return 0
+}
+
+// ImportSummary reports the outcome of a cluster import
+type ImportSummary struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of records imported
+	ClustersImported int32 `protobuf:"varint,1,opt,name=clusters_imported,json=clustersImported,proto3" json:"clusters_imported,omitempty"`
+	// Number of records rejected
+	ClustersFailed int32 `protobuf:"varint,2,opt,name=clusters_failed,json=clustersFailed,proto3" json:"clusters_failed,omitempty"`
+	// Number of agents imported across all records
+	AgentsImported int32 `protobuf:"varint,3,opt,name=agents_imported,json=agentsImported,proto3" json:"agents_imported,omitempty"`
+	// Outcome of each record, in stream order
+	Results       []*ImportRecordResult `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportSummary) Reset() {
+	*x = ImportSummary{}
+	mi := &file_v1_cluster_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportSummary) ProtoMessage() {}
+
+func (x *ImportSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_cluster_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportSummary.ProtoReflect.Descriptor instead.
+func (*ImportSummary) Descriptor() ([]byte, []int) {
+	return file_v1_cluster_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ImportSummary) GetClustersImported() int32 {
+	if x != nil {
+		return x.ClustersImported
+	}
+	return 0
+}
+
+func (x *ImportSummary) GetClustersFailed() int32 {
+	if x != nil {
+		return x.ClustersFailed
+	}
+	return 0
+}
+
+func (x *ImportSummary) GetAgentsImported() int32 {
+	if x != nil {
+		return x.AgentsImported
+	}
+	return 0
+}
+
+func (x *ImportSummary) GetResults() []*ImportRecordResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+var File_v1_cluster_proto protoreflect.FileDescriptor
+
+const file_v1_cluster_proto_rawDesc = "" +
+	"\n" +
+	"\x10v1/cluster.proto\x12\n" +
+	"netctrl.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a google/protobuf/field_mask.proto\x1a\x0ev1/agent.proto\x1a\x10v1/network.proto\"\xef\x02\n" +
+	"\aCluster\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1a\n" +
+	"\bcordoned\x18\x06 \x01(\bR\bcordoned\x12@\n" +
+	"\x0enetwork_config\x18\a \x01(\v2\x19.netctrl.v1.NetworkConfigR\rnetworkConfig\x12J\n" +
+	"\x13additional_networks\x18\b \x03(\v2\x19.netctrl.v1.NetworkConfigR\x12additionalNetworks\"\xda\x01\n" +
+	"\x14CreateClusterRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12@\n" +
+	"\x0enetwork_config\x18\x03 \x01(\v2\x19.netctrl.v1.NetworkConfigR\rnetworkConfig\x12J\n" +
+	"\x13additional_networks\x18\x04 \x03(\v2\x19.netctrl.v1.NetworkConfigR\x12additionalNetworks\"F\n" +
+	"\x15CreateClusterResponse\x12-\n" +
+	"\acluster\x18\x01 \x01(\v2\x13.netctrl.v1.ClusterR\acluster\"\x86\x01\n" +
+	"\x11GetClusterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0einclude_agents\x18\x02 \x01(\bR\rincludeAgents\x12:\n" +
+	"\fagent_status\x18\x03 \x01(\x0e2\x17.netctrl.v1.AgentStatusR\vagentStatus\"\x82\x01\n" +
+	"\x12GetClusterResponse\x12-\n" +
+	"\acluster\x18\x01 \x01(\v2\x13.netctrl.v1.ClusterR\acluster\x12\x12\n" +
+	"\x04etag\x18\x02 \x01(\tR\x04etag\x12)\n" +
+	"\x06agents\x18\x03 \x03(\v2\x11.netctrl.v1.AgentR\x06agents\"r\n" +
+	"\x13ListClustersRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x1f\n" +
+	"\vname_prefix\x18\x03 \x01(\tR\n" +
+	"namePrefix\"o\n" +
+	"\x14ListClustersResponse\x12/\n" +
+	"\bclusters\x18\x01 \x03(\v2\x13.netctrl.v1.ClusterR\bclusters\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xa7\x02\n" +
+	"\x14UpdateClusterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12;\n" +
+	"\vupdate_mask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12@\n" +
+	"\x0enetwork_config\x18\x05 \x01(\v2\x19.netctrl.v1.NetworkConfigR\rnetworkConfig\x12J\n" +
+	"\x13additional_networks\x18\x06 \x03(\v2\x19.netctrl.v1.NetworkConfigR\x12additionalNetworks\"z\n" +
+	"\x15UpdateClusterResponse\x12-\n" +
+	"\acluster\x18\x01 \x01(\v2\x13.netctrl.v1.ClusterR\acluster\x122\n" +
+	"\x16out_of_range_agent_ids\x18\x02 \x03(\tR\x12outOfRangeAgentIds\"&\n" +
+	"\x14DeleteClusterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"1\n" +
+	"\x15DeleteClusterResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"%\n" +
+	"\x13DrainClusterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"f\n" +
+	"\x14DrainClusterResponse\x12-\n" +
+	"\acluster\x18\x01 \x01(\v2\x13.netctrl.v1.ClusterR\acluster\x12\x1f\n" +
+	"\vagent_count\x18\x02 \x01(\x05R\n" +
+	"agentCount\"(\n" +
+	"\x16UncordonClusterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"H\n" +
+	"\x17UncordonClusterResponse\x12-\n" +
+	"\acluster\x18\x01 \x01(\v2\x13.netctrl.v1.ClusterR\acluster\";\n" +
+	"\x12MoveClusterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x15\n" +
+	"\x06new_id\x18\x02 \x01(\tR\x05newId\"e\n" +
+	"\x13MoveClusterResponse\x12-\n" +
+	"\acluster\x18\x01 \x01(\v2\x13.netctrl.v1.ClusterR\acluster\x12\x1f\n" +
+	"\vagent_count\x18\x02 \x01(\x05R\n" +
+	"agentCount\"<\n" +
+	"\x1bFindMatchingClustersRequest\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x01 \x01(\tR\tipAddress\"O\n" +
+	"\x1cFindMatchingClustersResponse\x12/\n" +
+	"\bclusters\x18\x01 \x03(\v2\x13.netctrl.v1.ClusterR\bclusters\"\x9a\x02\n" +
+	"\x11ClusterWithAgents\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12@\n" +
+	"\x0enetwork_config\x18\x04 \x01(\v2\x19.netctrl.v1.NetworkConfigR\rnetworkConfig\x12J\n" +
+	"\x13additional_networks\x18\x05 \x03(\v2\x19.netctrl.v1.NetworkConfigR\x12additionalNetworks\x121\n" +
+	"\x06agents\x18\x06 \x03(\v2\x19.netctrl.v1.ImportedAgentR\x06agents\"\x8a\x01\n" +
+	"\rImportedAgent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bhostname\x18\x02 \x01(\tR\bhostname\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x03 \x01(\tR\tipAddress\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\tR\aversion\x12\x14\n" +
+	"\x05group\x18\x05 \x01(\tR\x05group\"\xa2\x01\n" +
+	"\x12ImportRecordResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x1d\n" +
+	"\n" +
+	"cluster_id\x18\x02 \x01(\tR\tclusterId\x12\x18\n" +
+	"\asuccess\x18\x03 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12'\n" +
+	"\x0fagents_imported\x18\x05 \x01(\x05R\x0eagentsImported\"\xc8\x01\n" +
+	"\rImportSummary\x12+\n" +
+	"\x11clusters_imported\x18\x01 \x01(\x05R\x10clustersImported\x12'\n" +
+	"\x0fclusters_failed\x18\x02 \x01(\x05R\x0eclustersFailed\x12'\n" +
+	"\x0fagents_imported\x18\x03 \x01(\x05R\x0eagentsImported\x128\n" +
+	"\aresults\x18\x04 \x03(\v2\x1e.netctrl.v1.ImportRecordResultR\aresults2\x9d\t\n" +
 	"\x0eClusterService\x12q\n" +
 	"\rCreateCluster\x12 .netctrl.v1.CreateClusterRequest\x1a!.netctrl.v1.CreateClusterResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/api/v1/clusters\x12j\n" +
 	"\n" +
 	"GetCluster\x12\x1d.netctrl.v1.GetClusterRequest\x1a\x1e.netctrl.v1.GetClusterResponse\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/api/v1/clusters/{id}\x12k\n" +
 	"\fListClusters\x12\x1f.netctrl.v1.ListClustersRequest\x1a .netctrl.v1.ListClustersResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/api/v1/clusters\x12v\n" +
 	"\rUpdateCluster\x12 .netctrl.v1.UpdateClusterRequest\x1a!.netctrl.v1.UpdateClusterResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*2\x15/api/v1/clusters/{id}\x12s\n" +
-	"\rDeleteCluster\x12 .netctrl.v1.DeleteClusterRequest\x1a!.netctrl.v1.DeleteClusterResponse\"\x1d\x82\xd3\xe4\x93\x02\x17*\x15/api/v1/clusters/{id}B\x9f\x01\n" +
+	"\rDeleteCluster\x12 .netctrl.v1.DeleteClusterRequest\x1a!.netctrl.v1.DeleteClusterResponse\"\x1d\x82\xd3\xe4\x93\x02\x17*\x15/api/v1/clusters/{id}\x12y\n" +
+	"\fDrainCluster\x12\x1f.netctrl.v1.DrainClusterRequest\x1a .netctrl.v1.DrainClusterResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/api/v1/clusters/{id}/drain\x12\x85\x01\n" +
+	"\x0fUncordonCluster\x12\".netctrl.v1.UncordonClusterRequest\x1a#.netctrl.v1.UncordonClusterResponse\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/api/v1/clusters/{id}/uncordon\x12u\n" +
+	"\vMoveCluster\x12\x1e.netctrl.v1.MoveClusterRequest\x1a\x1f.netctrl.v1.MoveClusterResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/api/v1/clusters/{id}/move\x12\x89\x01\n" +
+	"\x14FindMatchingClusters\x12'.netctrl.v1.FindMatchingClustersRequest\x1a(.netctrl.v1.FindMatchingClustersResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/api/v1/clusters:match\x12L\n" +
+	"\x0eImportClusters\x12\x1d.netctrl.v1.ClusterWithAgents\x1a\x19.netctrl.v1.ImportSummary(\x01B\x9f\x01\n" +
 	"\x0ecom.netctrl.v1B\fClusterProtoP\x01Z6github.com/filanov/netctrl-server/pkg/api/v1;netctrlv1\xa2\x02\x03NXX\xaa\x02\n" +
 	"Netctrl.V1\xca\x02\n" +
 	"Netctrl\\V1\xe2\x02\x16Netctrl\\V1\\GPBMetadata\xea\x02\vNetctrl::V1b\x06proto3"
@@ -682,45 +1595,86 @@ func file_v1_cluster_proto_rawDescGZIP() []byte {
 	return file_v1_cluster_proto_rawDescData
 }
 
-var file_v1_cluster_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_v1_cluster_proto_msgTypes = make([]protoimpl.MessageInfo, 23)
 var file_v1_cluster_proto_goTypes = []any{
-	(*Cluster)(nil),               // 0: netctrl.v1.Cluster
-	(*CreateClusterRequest)(nil),  // 1: netctrl.v1.CreateClusterRequest
-	(*CreateClusterResponse)(nil), // 2: netctrl.v1.CreateClusterResponse
-	(*GetClusterRequest)(nil),     // 3: netctrl.v1.GetClusterRequest
-	(*GetClusterResponse)(nil),    // 4: netctrl.v1.GetClusterResponse
-	(*ListClustersRequest)(nil),   // 5: netctrl.v1.ListClustersRequest
-	(*ListClustersResponse)(nil),  // 6: netctrl.v1.ListClustersResponse
-	(*UpdateClusterRequest)(nil),  // 7: netctrl.v1.UpdateClusterRequest
-	(*UpdateClusterResponse)(nil), // 8: netctrl.v1.UpdateClusterResponse
-	(*DeleteClusterRequest)(nil),  // 9: netctrl.v1.DeleteClusterRequest
-	(*DeleteClusterResponse)(nil), // 10: netctrl.v1.DeleteClusterResponse
-	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
-	(*fieldmaskpb.FieldMask)(nil), // 12: google.protobuf.FieldMask
+	(*Cluster)(nil),                      // 0: netctrl.v1.Cluster
+	(*CreateClusterRequest)(nil),         // 1: netctrl.v1.CreateClusterRequest
+	(*CreateClusterResponse)(nil),        // 2: netctrl.v1.CreateClusterResponse
+	(*GetClusterRequest)(nil),            // 3: netctrl.v1.GetClusterRequest
+	(*GetClusterResponse)(nil),           // 4: netctrl.v1.GetClusterResponse
+	(*ListClustersRequest)(nil),          // 5: netctrl.v1.ListClustersRequest
+	(*ListClustersResponse)(nil),         // 6: netctrl.v1.ListClustersResponse
+	(*UpdateClusterRequest)(nil),         // 7: netctrl.v1.UpdateClusterRequest
+	(*UpdateClusterResponse)(nil),        // 8: netctrl.v1.UpdateClusterResponse
+	(*DeleteClusterRequest)(nil),         // 9: netctrl.v1.DeleteClusterRequest
+	(*DeleteClusterResponse)(nil),        // 10: netctrl.v1.DeleteClusterResponse
+	(*DrainClusterRequest)(nil),          // 11: netctrl.v1.DrainClusterRequest
+	(*DrainClusterResponse)(nil),         // 12: netctrl.v1.DrainClusterResponse
+	(*UncordonClusterRequest)(nil),       // 13: netctrl.v1.UncordonClusterRequest
+	(*UncordonClusterResponse)(nil),      // 14: netctrl.v1.UncordonClusterResponse
+	(*MoveClusterRequest)(nil),           // 15: netctrl.v1.MoveClusterRequest
+	(*MoveClusterResponse)(nil),          // 16: netctrl.v1.MoveClusterResponse
+	(*FindMatchingClustersRequest)(nil),  // 17: netctrl.v1.FindMatchingClustersRequest
+	(*FindMatchingClustersResponse)(nil), // 18: netctrl.v1.FindMatchingClustersResponse
+	(*ClusterWithAgents)(nil),            // 19: netctrl.v1.ClusterWithAgents
+	(*ImportedAgent)(nil),                // 20: netctrl.v1.ImportedAgent
+	(*ImportRecordResult)(nil),           // 21: netctrl.v1.ImportRecordResult
+	(*ImportSummary)(nil),                // 22: netctrl.v1.ImportSummary
+	(*timestamppb.Timestamp)(nil),        // 23: google.protobuf.Timestamp
+	(*NetworkConfig)(nil),                // 24: netctrl.v1.NetworkConfig
+	(AgentStatus)(0),                     // 25: netctrl.v1.AgentStatus
+	(*Agent)(nil),                        // 26: netctrl.v1.Agent
+	(*fieldmaskpb.FieldMask)(nil),        // 27: google.protobuf.FieldMask
 }
 var file_v1_cluster_proto_depIdxs = []int32{
-	11, // 0: netctrl.v1.Cluster.created_at:type_name -> google.protobuf.Timestamp
-	11, // 1: netctrl.v1.Cluster.updated_at:type_name -> google.protobuf.Timestamp
-	0,  // 2: netctrl.v1.CreateClusterResponse.cluster:type_name -> netctrl.v1.Cluster
-	0,  // 3: netctrl.v1.GetClusterResponse.cluster:type_name -> netctrl.v1.Cluster
-	0,  // 4: netctrl.v1.ListClustersResponse.clusters:type_name -> netctrl.v1.Cluster
-	12, // 5: netctrl.v1.UpdateClusterRequest.update_mask:type_name -> google.protobuf.FieldMask
-	0,  // 6: netctrl.v1.UpdateClusterResponse.cluster:type_name -> netctrl.v1.Cluster
-	1,  // 7: netctrl.v1.ClusterService.CreateCluster:input_type -> netctrl.v1.CreateClusterRequest
-	3,  // 8: netctrl.v1.ClusterService.GetCluster:input_type -> netctrl.v1.GetClusterRequest
-	5,  // 9: netctrl.v1.ClusterService.ListClusters:input_type -> netctrl.v1.ListClustersRequest
-	7,  // 10: netctrl.v1.ClusterService.UpdateCluster:input_type -> netctrl.v1.UpdateClusterRequest
-	9,  // 11: netctrl.v1.ClusterService.DeleteCluster:input_type -> netctrl.v1.DeleteClusterRequest
-	2,  // 12: netctrl.v1.ClusterService.CreateCluster:output_type -> netctrl.v1.CreateClusterResponse
-	4,  // 13: netctrl.v1.ClusterService.GetCluster:output_type -> netctrl.v1.GetClusterResponse
-	6,  // 14: netctrl.v1.ClusterService.ListClusters:output_type -> netctrl.v1.ListClustersResponse
-	8,  // 15: netctrl.v1.ClusterService.UpdateCluster:output_type -> netctrl.v1.UpdateClusterResponse
-	10, // 16: netctrl.v1.ClusterService.DeleteCluster:output_type -> netctrl.v1.DeleteClusterResponse
-	12, // [12:17] is the sub-list for method output_type
-	7,  // [7:12] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	23, // 0: netctrl.v1.Cluster.created_at:type_name -> google.protobuf.Timestamp
+	23, // 1: netctrl.v1.Cluster.updated_at:type_name -> google.protobuf.Timestamp
+	24, // 2: netctrl.v1.Cluster.network_config:type_name -> netctrl.v1.NetworkConfig
+	24, // 3: netctrl.v1.Cluster.additional_networks:type_name -> netctrl.v1.NetworkConfig
+	24, // 4: netctrl.v1.CreateClusterRequest.network_config:type_name -> netctrl.v1.NetworkConfig
+	24, // 5: netctrl.v1.CreateClusterRequest.additional_networks:type_name -> netctrl.v1.NetworkConfig
+	0,  // 6: netctrl.v1.CreateClusterResponse.cluster:type_name -> netctrl.v1.Cluster
+	25, // 7: netctrl.v1.GetClusterRequest.agent_status:type_name -> netctrl.v1.AgentStatus
+	0,  // 8: netctrl.v1.GetClusterResponse.cluster:type_name -> netctrl.v1.Cluster
+	26, // 9: netctrl.v1.GetClusterResponse.agents:type_name -> netctrl.v1.Agent
+	0,  // 10: netctrl.v1.ListClustersResponse.clusters:type_name -> netctrl.v1.Cluster
+	27, // 11: netctrl.v1.UpdateClusterRequest.update_mask:type_name -> google.protobuf.FieldMask
+	24, // 12: netctrl.v1.UpdateClusterRequest.network_config:type_name -> netctrl.v1.NetworkConfig
+	24, // 13: netctrl.v1.UpdateClusterRequest.additional_networks:type_name -> netctrl.v1.NetworkConfig
+	0,  // 14: netctrl.v1.UpdateClusterResponse.cluster:type_name -> netctrl.v1.Cluster
+	0,  // 15: netctrl.v1.DrainClusterResponse.cluster:type_name -> netctrl.v1.Cluster
+	0,  // 16: netctrl.v1.UncordonClusterResponse.cluster:type_name -> netctrl.v1.Cluster
+	0,  // 17: netctrl.v1.MoveClusterResponse.cluster:type_name -> netctrl.v1.Cluster
+	0,  // 18: netctrl.v1.FindMatchingClustersResponse.clusters:type_name -> netctrl.v1.Cluster
+	24, // 19: netctrl.v1.ClusterWithAgents.network_config:type_name -> netctrl.v1.NetworkConfig
+	24, // 20: netctrl.v1.ClusterWithAgents.additional_networks:type_name -> netctrl.v1.NetworkConfig
+	20, // 21: netctrl.v1.ClusterWithAgents.agents:type_name -> netctrl.v1.ImportedAgent
+	21, // 22: netctrl.v1.ImportSummary.results:type_name -> netctrl.v1.ImportRecordResult
+	1,  // 23: netctrl.v1.ClusterService.CreateCluster:input_type -> netctrl.v1.CreateClusterRequest
+	3,  // 24: netctrl.v1.ClusterService.GetCluster:input_type -> netctrl.v1.GetClusterRequest
+	5,  // 25: netctrl.v1.ClusterService.ListClusters:input_type -> netctrl.v1.ListClustersRequest
+	7,  // 26: netctrl.v1.ClusterService.UpdateCluster:input_type -> netctrl.v1.UpdateClusterRequest
+	9,  // 27: netctrl.v1.ClusterService.DeleteCluster:input_type -> netctrl.v1.DeleteClusterRequest
+	11, // 28: netctrl.v1.ClusterService.DrainCluster:input_type -> netctrl.v1.DrainClusterRequest
+	13, // 29: netctrl.v1.ClusterService.UncordonCluster:input_type -> netctrl.v1.UncordonClusterRequest
+	15, // 30: netctrl.v1.ClusterService.MoveCluster:input_type -> netctrl.v1.MoveClusterRequest
+	17, // 31: netctrl.v1.ClusterService.FindMatchingClusters:input_type -> netctrl.v1.FindMatchingClustersRequest
+	19, // 32: netctrl.v1.ClusterService.ImportClusters:input_type -> netctrl.v1.ClusterWithAgents
+	2,  // 33: netctrl.v1.ClusterService.CreateCluster:output_type -> netctrl.v1.CreateClusterResponse
+	4,  // 34: netctrl.v1.ClusterService.GetCluster:output_type -> netctrl.v1.GetClusterResponse
+	6,  // 35: netctrl.v1.ClusterService.ListClusters:output_type -> netctrl.v1.ListClustersResponse
+	8,  // 36: netctrl.v1.ClusterService.UpdateCluster:output_type -> netctrl.v1.UpdateClusterResponse
+	10, // 37: netctrl.v1.ClusterService.DeleteCluster:output_type -> netctrl.v1.DeleteClusterResponse
+	12, // 38: netctrl.v1.ClusterService.DrainCluster:output_type -> netctrl.v1.DrainClusterResponse
+	14, // 39: netctrl.v1.ClusterService.UncordonCluster:output_type -> netctrl.v1.UncordonClusterResponse
+	16, // 40: netctrl.v1.ClusterService.MoveCluster:output_type -> netctrl.v1.MoveClusterResponse
+	18, // 41: netctrl.v1.ClusterService.FindMatchingClusters:output_type -> netctrl.v1.FindMatchingClustersResponse
+	22, // 42: netctrl.v1.ClusterService.ImportClusters:output_type -> netctrl.v1.ImportSummary
+	33, // [33:43] is the sub-list for method output_type
+	23, // [23:33] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_v1_cluster_proto_init() }
@@ -728,13 +1682,15 @@ func file_v1_cluster_proto_init() {
 	if File_v1_cluster_proto != nil {
 		return
 	}
+	file_v1_agent_proto_init()
+	file_v1_network_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_v1_cluster_proto_rawDesc), len(file_v1_cluster_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   23,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,11 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ClusterService_CreateCluster_FullMethodName = "/netctrl.v1.ClusterService/CreateCluster"
-	ClusterService_GetCluster_FullMethodName    = "/netctrl.v1.ClusterService/GetCluster"
-	ClusterService_ListClusters_FullMethodName  = "/netctrl.v1.ClusterService/ListClusters"
-	ClusterService_UpdateCluster_FullMethodName = "/netctrl.v1.ClusterService/UpdateCluster"
-	ClusterService_DeleteCluster_FullMethodName = "/netctrl.v1.ClusterService/DeleteCluster"
+	ClusterService_CreateCluster_FullMethodName        = "/netctrl.v1.ClusterService/CreateCluster"
+	ClusterService_GetCluster_FullMethodName           = "/netctrl.v1.ClusterService/GetCluster"
+	ClusterService_ListClusters_FullMethodName         = "/netctrl.v1.ClusterService/ListClusters"
+	ClusterService_UpdateCluster_FullMethodName        = "/netctrl.v1.ClusterService/UpdateCluster"
+	ClusterService_DeleteCluster_FullMethodName        = "/netctrl.v1.ClusterService/DeleteCluster"
+	ClusterService_DrainCluster_FullMethodName         = "/netctrl.v1.ClusterService/DrainCluster"
+	ClusterService_UncordonCluster_FullMethodName      = "/netctrl.v1.ClusterService/UncordonCluster"
+	ClusterService_MoveCluster_FullMethodName          = "/netctrl.v1.ClusterService/MoveCluster"
+	ClusterService_FindMatchingClusters_FullMethodName = "/netctrl.v1.ClusterService/FindMatchingClusters"
+	ClusterService_ImportClusters_FullMethodName       = "/netctrl.v1.ClusterService/ImportClusters"
 )
 
 // ClusterServiceClient is the client API for ClusterService service.
@@ -42,6 +47,20 @@ type ClusterServiceClient interface {
 	UpdateCluster(ctx context.Context, in *UpdateClusterRequest, opts ...grpc.CallOption) (*UpdateClusterResponse, error)
 	// DeleteCluster deletes a cluster by ID
 	DeleteCluster(ctx context.Context, in *DeleteClusterRequest, opts ...grpc.CallOption) (*DeleteClusterResponse, error)
+	// DrainCluster cordons a cluster and instructs its agents to drain
+	DrainCluster(ctx context.Context, in *DrainClusterRequest, opts ...grpc.CallOption) (*DrainClusterResponse, error)
+	// UncordonCluster lifts the cordon from a drained cluster
+	UncordonCluster(ctx context.Context, in *UncordonClusterRequest, opts ...grpc.CallOption) (*UncordonClusterResponse, error)
+	// MoveCluster re-keys a cluster and its agents to a new cluster ID (admin)
+	MoveCluster(ctx context.Context, in *MoveClusterRequest, opts ...grpc.CallOption) (*MoveClusterResponse, error)
+	// FindMatchingClusters lists the clusters whose network CIDR contains an
+	// IP address, e.g. to offer valid clusters for an unassigned agent
+	FindMatchingClusters(ctx context.Context, in *FindMatchingClustersRequest, opts ...grpc.CallOption) (*FindMatchingClustersResponse, error)
+	// ImportClusters creates clusters and their agents from a stream of
+	// records, e.g. when migrating from another system. Each record is imported
+	// as it arrives; an invalid record is reported in the summary without
+	// aborting the import. It is only available over gRPC.
+	ImportClusters(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ClusterWithAgents, ImportSummary], error)
 }
 
 type clusterServiceClient struct {
@@ -102,6 +121,59 @@ func (c *clusterServiceClient) DeleteCluster(ctx context.Context, in *DeleteClus
 	return out, nil
 }
 
+func (c *clusterServiceClient) DrainCluster(ctx context.Context, in *DrainClusterRequest, opts ...grpc.CallOption) (*DrainClusterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DrainClusterResponse)
+	err := c.cc.Invoke(ctx, ClusterService_DrainCluster_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clusterServiceClient) UncordonCluster(ctx context.Context, in *UncordonClusterRequest, opts ...grpc.CallOption) (*UncordonClusterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UncordonClusterResponse)
+	err := c.cc.Invoke(ctx, ClusterService_UncordonCluster_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clusterServiceClient) MoveCluster(ctx context.Context, in *MoveClusterRequest, opts ...grpc.CallOption) (*MoveClusterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MoveClusterResponse)
+	err := c.cc.Invoke(ctx, ClusterService_MoveCluster_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clusterServiceClient) FindMatchingClusters(ctx context.Context, in *FindMatchingClustersRequest, opts ...grpc.CallOption) (*FindMatchingClustersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindMatchingClustersResponse)
+	err := c.cc.Invoke(ctx, ClusterService_FindMatchingClusters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clusterServiceClient) ImportClusters(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ClusterWithAgents, ImportSummary], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ClusterService_ServiceDesc.Streams[0], ClusterService_ImportClusters_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ClusterWithAgents, ImportSummary]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ClusterService_ImportClustersClient = grpc.ClientStreamingClient[ClusterWithAgents, ImportSummary]
+
 // ClusterServiceServer is the server API for ClusterService service.
 // All implementations must embed UnimplementedClusterServiceServer
 // for forward compatibility.
@@ -118,6 +190,20 @@ type ClusterServiceServer interface {
 	UpdateCluster(context.Context, *UpdateClusterRequest) (*UpdateClusterResponse, error)
 	// DeleteCluster deletes a cluster by ID
 	DeleteCluster(context.Context, *DeleteClusterRequest) (*DeleteClusterResponse, error)
+	// DrainCluster cordons a cluster and instructs its agents to drain
+	DrainCluster(context.Context, *DrainClusterRequest) (*DrainClusterResponse, error)
+	// UncordonCluster lifts the cordon from a drained cluster
+	UncordonCluster(context.Context, *UncordonClusterRequest) (*UncordonClusterResponse, error)
+	// MoveCluster re-keys a cluster and its agents to a new cluster ID (admin)
+	MoveCluster(context.Context, *MoveClusterRequest) (*MoveClusterResponse, error)
+	// FindMatchingClusters lists the clusters whose network CIDR contains an
+	// IP address, e.g. to offer valid clusters for an unassigned agent
+	FindMatchingClusters(context.Context, *FindMatchingClustersRequest) (*FindMatchingClustersResponse, error)
+	// ImportClusters creates clusters and their agents from a stream of
+	// records, e.g. when migrating from another system. Each record is imported
+	// as it arrives; an invalid record is reported in the summary without
+	// aborting the import. It is only available over gRPC.
+	ImportClusters(grpc.ClientStreamingServer[ClusterWithAgents, ImportSummary]) error
 	mustEmbedUnimplementedClusterServiceServer()
 }
 
@@ -143,6 +229,21 @@ func (UnimplementedClusterServiceServer) UpdateCluster(context.Context, *UpdateC
 func (UnimplementedClusterServiceServer) DeleteCluster(context.Context, *DeleteClusterRequest) (*DeleteClusterResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeleteCluster not implemented")
 }
+func (UnimplementedClusterServiceServer) DrainCluster(context.Context, *DrainClusterRequest) (*DrainClusterResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DrainCluster not implemented")
+}
+func (UnimplementedClusterServiceServer) UncordonCluster(context.Context, *UncordonClusterRequest) (*UncordonClusterResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UncordonCluster not implemented")
+}
+func (UnimplementedClusterServiceServer) MoveCluster(context.Context, *MoveClusterRequest) (*MoveClusterResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method MoveCluster not implemented")
+}
+func (UnimplementedClusterServiceServer) FindMatchingClusters(context.Context, *FindMatchingClustersRequest) (*FindMatchingClustersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method FindMatchingClusters not implemented")
+}
+func (UnimplementedClusterServiceServer) ImportClusters(grpc.ClientStreamingServer[ClusterWithAgents, ImportSummary]) error {
+	return status.Error(codes.Unimplemented, "method ImportClusters not implemented")
+}
 func (UnimplementedClusterServiceServer) mustEmbedUnimplementedClusterServiceServer() {}
 func (UnimplementedClusterServiceServer) testEmbeddedByValue()                        {}
 
@@ -254,6 +355,85 @@ func _ClusterService_DeleteCluster_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ClusterService_DrainCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterServiceServer).DrainCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClusterService_DrainCluster_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterServiceServer).DrainCluster(ctx, req.(*DrainClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClusterService_UncordonCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UncordonClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterServiceServer).UncordonCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClusterService_UncordonCluster_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterServiceServer).UncordonCluster(ctx, req.(*UncordonClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClusterService_MoveCluster_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveClusterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterServiceServer).MoveCluster(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClusterService_MoveCluster_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterServiceServer).MoveCluster(ctx, req.(*MoveClusterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClusterService_FindMatchingClusters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindMatchingClustersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterServiceServer).FindMatchingClusters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ClusterService_FindMatchingClusters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterServiceServer).FindMatchingClusters(ctx, req.(*FindMatchingClustersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ClusterService_ImportClusters_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ClusterServiceServer).ImportClusters(&grpc.GenericServerStream[ClusterWithAgents, ImportSummary]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ClusterService_ImportClustersServer = grpc.ClientStreamingServer[ClusterWithAgents, ImportSummary]
+
 // ClusterService_ServiceDesc is the grpc.ServiceDesc for ClusterService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -281,7 +461,29 @@ var ClusterService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteCluster",
 			Handler:    _ClusterService_DeleteCluster_Handler,
 		},
+		{
+			MethodName: "DrainCluster",
+			Handler:    _ClusterService_DrainCluster_Handler,
+		},
+		{
+			MethodName: "UncordonCluster",
+			Handler:    _ClusterService_UncordonCluster_Handler,
+		},
+		{
+			MethodName: "MoveCluster",
+			Handler:    _ClusterService_MoveCluster_Handler,
+		},
+		{
+			MethodName: "FindMatchingClusters",
+			Handler:    _ClusterService_FindMatchingClusters_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportClusters",
+			Handler:       _ClusterService_ImportClusters_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "v1/cluster.proto",
 }